@@ -34,11 +34,11 @@ func NewEthClient(
 	localApi *rawapi.NodeApiOverShardApis,
 	logger logging.Logger,
 ) (*DirectClient, error) {
-	ethApi := jsonrpc.NewEthAPI(ctx, localApi, db, true, false)
+	ethApi := jsonrpc.NewEthAPI(ctx, localApi, db, true, false, nil)
 	debugApi := jsonrpc.NewDebugAPI(localApi, logger)
 	dbApi := jsonrpc.NewDbAPI(db, logger)
 	web3Api := jsonrpc.NewWeb3API(localApi)
-	devApi := jsonrpc.NewDevAPI(localApi)
+	devApi := jsonrpc.NewDevAPI(localApi, nil)
 
 	return &DirectClient{
 		ethApi:   ethApi,
@@ -385,7 +385,12 @@ func (c *DirectClient) GetDebugContract(
 	contractAddr types.Address,
 	blockId any,
 ) (*jsonrpc.DebugRPCContract, error) {
-	panic("Not supported")
+	blockRef, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.debugApi.GetContract(ctx, contractAddr, transport.BlockNumberOrHash(blockRef))
 }
 
 func (c *DirectClient) ClientVersion(ctx context.Context) (string, error) {
@@ -395,3 +400,41 @@ func (c *DirectClient) ClientVersion(ctx context.Context) (string, error) {
 func (c *DirectClient) DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uint64, error) {
 	return c.devApi.DoPanicOnShard(ctx, shardId)
 }
+
+func (c *DirectClient) SetBalance(
+	ctx context.Context, address types.Address, balance types.Value,
+) (common.Hash, error) {
+	return c.devApi.SetBalance(ctx, address, balance)
+}
+
+func (c *DirectClient) SetCode(ctx context.Context, address types.Address, code hexutil.Bytes) (common.Hash, error) {
+	return c.devApi.SetCode(ctx, address, code)
+}
+
+func (c *DirectClient) SetStorageAt(
+	ctx context.Context, address types.Address, key common.Hash, value common.Hash,
+) (common.Hash, error) {
+	return c.devApi.SetStorageAt(ctx, address, key, value)
+}
+
+func (c *DirectClient) SetTokens(
+	ctx context.Context, address types.Address, tokens map[types.TokenId]types.Value,
+) (common.Hash, error) {
+	return c.devApi.SetTokens(ctx, address, tokens)
+}
+
+func (c *DirectClient) ImpersonateAccount(ctx context.Context, address types.Address) error {
+	return c.devApi.ImpersonateAccount(ctx, address)
+}
+
+func (c *DirectClient) StopImpersonatingAccount(ctx context.Context, address types.Address) error {
+	return c.devApi.StopImpersonatingAccount(ctx, address)
+}
+
+func (c *DirectClient) Mine(ctx context.Context, shardId types.ShardId, blocks uint64) (types.BlockNumber, error) {
+	return c.devApi.Mine(ctx, shardId, blocks)
+}
+
+func (c *DirectClient) SetNextBlockTimestamp(ctx context.Context, shardId types.ShardId, timestamp uint64) error {
+	return c.devApi.SetNextBlockTimestamp(ctx, shardId, timestamp)
+}
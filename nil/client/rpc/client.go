@@ -81,6 +81,14 @@ const (
 	Debug_getContract                    = "debug_getContract"
 	Web3_clientVersion                   = "web3_clientVersion"
 	Dev_doPanicOnShard                   = "dev_doPanicOnShard"
+	Dev_setBalance                       = "dev_setBalance"
+	Dev_setCode                          = "dev_setCode"
+	Dev_setStorageAt                     = "dev_setStorageAt"
+	Dev_setTokens                        = "dev_setTokens"
+	Dev_impersonateAccount               = "dev_impersonateAccount"
+	Dev_stopImpersonatingAccount         = "dev_stopImpersonatingAccount"
+	Dev_mine                             = "dev_mine"
+	Dev_setNextBlockTimestamp            = "dev_setNextBlockTimestamp"
 )
 
 const (
@@ -943,6 +951,45 @@ func (c *Client) DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uin
 	return 0, err
 }
 
+func (c *Client) SetBalance(ctx context.Context, address types.Address, balance types.Value) (common.Hash, error) {
+	return simpleCall[common.Hash](ctx, c, Dev_setBalance, address, balance)
+}
+
+func (c *Client) SetCode(ctx context.Context, address types.Address, code hexutil.Bytes) (common.Hash, error) {
+	return simpleCall[common.Hash](ctx, c, Dev_setCode, address, code)
+}
+
+func (c *Client) SetStorageAt(
+	ctx context.Context, address types.Address, key common.Hash, value common.Hash,
+) (common.Hash, error) {
+	return simpleCall[common.Hash](ctx, c, Dev_setStorageAt, address, key, value)
+}
+
+func (c *Client) SetTokens(
+	ctx context.Context, address types.Address, tokens map[types.TokenId]types.Value,
+) (common.Hash, error) {
+	return simpleCall[common.Hash](ctx, c, Dev_setTokens, address, tokens)
+}
+
+func (c *Client) ImpersonateAccount(ctx context.Context, address types.Address) error {
+	_, err := c.call(ctx, Dev_impersonateAccount, address)
+	return err
+}
+
+func (c *Client) StopImpersonatingAccount(ctx context.Context, address types.Address) error {
+	_, err := c.call(ctx, Dev_stopImpersonatingAccount, address)
+	return err
+}
+
+func (c *Client) Mine(ctx context.Context, shardId types.ShardId, blocks uint64) (types.BlockNumber, error) {
+	return simpleCall[types.BlockNumber](ctx, c, Dev_mine, shardId, blocks)
+}
+
+func (c *Client) SetNextBlockTimestamp(ctx context.Context, shardId types.ShardId, timestamp uint64) error {
+	_, err := c.call(ctx, Dev_setNextBlockTimestamp, shardId, timestamp)
+	return err
+}
+
 func simpleCall[ReturnType any](ctx context.Context, c *Client, method string, params ...any) (ReturnType, error) {
 	res, err := c.call(ctx, method, params...)
 	var result ReturnType
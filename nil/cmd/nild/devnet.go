@@ -377,9 +377,8 @@ func (c *cluster) writeServerConfig(instanceId int, srv server, only string) err
 	}
 
 	if len(inst.ArchiveNodeIndices) > 0 {
-		cfg.RpcNode = &nilservice.RpcNodeConfig{
-			ArchiveNodeList: getPeers(c.archivers, inst.ArchiveNodeIndices),
-		}
+		cfg.RpcNode = nilservice.NewDefaultRpcNodeConfig()
+		cfg.RpcNode.ArchiveNodeList = getPeers(c.archivers, inst.ArchiveNodeIndices)
 	}
 
 	serialized, err := yaml.Marshal(cfg)
@@ -88,6 +88,16 @@ func addAllowDbClearFlag(fset *pflag.FlagSet, cfg *nildconfig.Config) {
 
 func addRpcNodeFlags(fset *pflag.FlagSet, cfg *nildconfig.Config) {
 	fset.Var(&cfg.RpcNode.ArchiveNodeList, "archive-nodes", "list of archive nodes")
+	fset.IntVar(
+		&cfg.RpcNode.MaxRequestAttempts,
+		"max-request-attempts",
+		cfg.RpcNode.MaxRequestAttempts,
+		"number of peers a read request to a shard is sent to before giving up")
+	fset.DurationVar(
+		&cfg.RpcNode.HedgeDelay,
+		"hedge-delay",
+		cfg.RpcNode.HedgeDelay,
+		"time after which a slow read request is also sent to another peer (disabled if zero)")
 }
 
 func addBasicFlags(fset *pflag.FlagSet, cfg *nildconfig.Config) {
@@ -95,6 +105,16 @@ func addBasicFlags(fset *pflag.FlagSet, cfg *nildconfig.Config) {
 	addAllowDbClearFlag(fset, cfg)
 	fset.Uint32Var(
 		&cfg.CollatorTickPeriodMs, "collator-tick-ms", cfg.CollatorTickPeriodMs, "collator tick period in milliseconds")
+	fset.BoolVar(
+		&cfg.EnableAddressTxIndex,
+		"address-tx-index",
+		cfg.EnableAddressTxIndex,
+		"maintain the index of transactions by address, required by eth_getTransactionsByAddress")
+	fset.IntVar(
+		&cfg.PrefetchWorkers,
+		"prefetch-workers",
+		cfg.PrefetchWorkers,
+		"number of goroutines loading the state of transactions before their execution (disabled if zero)")
 }
 
 func parseArgs() *nildconfig.Config {
@@ -159,6 +179,8 @@ func parseArgs() *nildconfig.Config {
 	runCmd.Flags().StringVar(
 		&cfg.ValidatorKeysPath, "validator-keys-path", cfg.ValidatorKeysPath, "path to write validator keys")
 	runCmd.Flags().BoolVar(&cfg.EnableDevApi, "dev-api", cfg.EnableDevApi, "enable development API")
+	runCmd.Flags().BoolVar(
+		&cfg.Automine, "automine", cfg.Automine, "produce blocks as soon as there are transactions to process")
 
 	addBasicFlags(runCmd.Flags(), cfg)
 	cmdflags.AddNetwork(runCmd.Flags(), cfg.Config.Network)
@@ -203,8 +225,9 @@ func parseArgs() *nildconfig.Config {
 
 	versionCmd := cobrax.VersionCmd(appTitle)
 	devnetCmd := DevnetCommand()
+	verifySnapshotCmd := VerifyStateSnapshotCommand(cfg)
 
-	rootCmd.AddCommand(runCmd, replayCmd, archiveCmd, rpcCmd, devnetCmd, versionCmd)
+	rootCmd.AddCommand(runCmd, replayCmd, archiveCmd, rpcCmd, devnetCmd, verifySnapshotCmd, versionCmd)
 	cobrax.ExitOnHelp(rootCmd)
 
 	check.PanicIfErr(rootCmd.Execute())
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/NilFoundation/nil/nil/cmd/nild/nildconfig"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/spf13/cobra"
+)
+
+func VerifyStateSnapshotCommand(cfg *nildconfig.Config) *cobra.Command {
+	var shards []uint
+	cmd := &cobra.Command{
+		Use:   "verify-state-snapshot",
+		Short: "Rebuild the state tries from the flat state snapshot and compare their roots with the latest blocks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := verifyStateSnapshot(cmd.Context(), cfg, shards); err != nil {
+				return err
+			}
+			os.Exit(0)
+			return nil
+		},
+		SilenceUsage: true,
+	}
+	cmd.Flags().UintSliceVar(&shards, "shards", nil, "shards to verify (all shards of the database if empty)")
+	return cmd
+}
+
+func verifyStateSnapshot(ctx context.Context, cfg *nildconfig.Config, shards []uint) error {
+	logger := logging.NewLogger("nild")
+
+	database, err := openDb(cfg.DB.Path, false, logger)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	tx, err := database.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	shardIds := make([]types.ShardId, 0, len(shards))
+	for _, shard := range shards {
+		shardIds = append(shardIds, types.ShardId(shard))
+	}
+	if len(shardIds) == 0 {
+		for shardId := types.MainShardId; ; shardId++ {
+			if _, err := db.ReadLastBlockHash(tx, shardId); errors.Is(err, db.ErrKeyNotFound) {
+				break
+			} else if err != nil {
+				return err
+			}
+			shardIds = append(shardIds, shardId)
+		}
+	}
+
+	for _, shardId := range shardIds {
+		if err := execution.VerifyStateSnapshot(ctx, tx, shardId); err != nil {
+			return fmt.Errorf("shard %d: %w", shardId, err)
+		}
+		logger.Info().Stringer(logging.FieldShardId, shardId).Msg("State snapshot matches the state tries")
+	}
+	return nil
+}
@@ -82,6 +82,26 @@ func addFlags(cmd *cobra.Command, cfg *cmdConfig) {
 		cfg.MaxConcurrentBatches,
 		"maximum value of batches that proof provider can handle concurrently",
 	)
+	retryPolicies := &cfg.TaskSchedulerConfig.RetryPolicies
+	cmd.Flags().IntVar(
+		&retryPolicies.Default.MaxRetries,
+		"task-max-retries",
+		retryPolicies.Default.MaxRetries,
+		"number of times a task failed with a retryable error is rescheduled")
+	cmd.Flags().DurationVar(
+		&retryPolicies.Default.InitialDelay,
+		"task-retry-initial-delay",
+		retryPolicies.Default.InitialDelay,
+		"delay before the first retry of a failed task, doubled for each next retry")
+	cmd.Flags().DurationVar(
+		&retryPolicies.Default.MaxDelay,
+		"task-retry-max-delay",
+		retryPolicies.Default.MaxDelay,
+		"upper limit of the delay between retries of a failed task")
+	cmd.Flags().Var(
+		retryPolicies,
+		"task-retry-policy",
+		"retry policy of the task type overriding the default one: <TaskType>=<MaxRetries>:<InitialDelay>:<MaxDelay>")
 	logLevel := cmd.Flags().String(
 		"log-level",
 		"info",
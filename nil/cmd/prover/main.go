@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -15,6 +16,7 @@ import (
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/prover"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/prover/tracer"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
 	"github.com/spf13/cobra"
 )
 
@@ -26,7 +28,8 @@ type CommonConfig = prover.Config
 
 type RunConfig struct {
 	*CommonConfig
-	DbPath string
+	DbPath       string
+	CircuitTypes []string
 }
 
 type PrintConfig struct {
@@ -52,11 +55,26 @@ func execute() error {
 	}
 	addCommonFlags(runCmd, commonCfg)
 	runCmd.Flags().StringVar(&runConfig.DbPath, "db-path", "prover.db", "path to database")
+	runCmd.Flags().StringSliceVar(
+		&runConfig.CircuitTypes,
+		"circuit-types",
+		nil,
+		"circuit types the prover is able to handle, all circuits are handled if not set")
+	runCmd.Flags().Var(
+		&runConfig.MemoryClass,
+		"memory-class",
+		"memory class of the prover host: Small|Medium|Large, tasks of any size are accepted if not set")
+	runCmd.Flags().Uint32Var(
+		&runConfig.MaxConcurrency,
+		"max-concurrency",
+		runConfig.MaxConcurrency,
+		"maximum number of tasks executed by the prover simultaneously, 0 means no limit")
 
 	rootCmd.AddCommand(runCmd)
 
 	traceConfig := tracer.TraceConfig{}
 	var marshalModePlaceholder string
+	var traceDbPath string
 	generateTraceCmd := &cobra.Command{
 		Use:   "trace [base_file_name] [shard_id] [block_ids...]",
 		Short: "Collect traces for a block, dump into file",
@@ -79,12 +97,25 @@ func execute() error {
 					return err
 				}
 			}
+			if traceDbPath != "" {
+				return collectLocalTraces(traceDbPath, &traceConfig)
+			}
 			client := prover.NewRPCClient(commonCfg.NilRpcEndpoint, logging.NewLogger("client"))
 			return tracer.CollectTracesToFile(context.Background(), client, &traceConfig)
 		},
 	}
 	addCommonFlags(generateTraceCmd, commonCfg)
 	addMarshalModeFlag(generateTraceCmd, &marshalModePlaceholder)
+	generateTraceCmd.Flags().StringVar(
+		&traceDbPath,
+		"db-path",
+		"",
+		"path to the nild database to read blocks and state from, nil rpc endpoint is used if not set")
+	generateTraceCmd.Flags().BoolVar(
+		&traceConfig.EnableDevCheats,
+		"dev-cheats",
+		false,
+		"apply the dev cheats included into the blocks, must be set for the networks running with the dev API")
 	rootCmd.AddCommand(generateTraceCmd)
 
 	var printConfig PrintConfig
@@ -101,6 +132,20 @@ func execute() error {
 	addMarshalModeFlag(printTraceCmd, &printConfig.MarshalMode)
 	rootCmd.AddCommand(printTraceCmd)
 
+	var validateConfig PrintConfig
+	validateTraceCmd := &cobra.Command{
+		Use:   "validate [file_name]",
+		Short: "Read serialized traces from files, check their consistency and report the first violation",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			validateConfig.BaseFileName = args[0]
+			return validateTrace(&validateConfig)
+		},
+	}
+	addCommonFlags(validateTraceCmd, commonCfg)
+	addMarshalModeFlag(validateTraceCmd, &validateConfig.MarshalMode)
+	rootCmd.AddCommand(validateTraceCmd)
+
 	return rootCmd.Execute()
 }
 
@@ -129,9 +174,19 @@ func addMarshalModeFlag(cmd *cobra.Command, placeholder *string) {
 func run(cfg *RunConfig) error {
 	profiling.Start(profiling.DefaultPort)
 
+	circuitTypes := make([]public.CircuitType, len(cfg.CircuitTypes))
+	for i, name := range cfg.CircuitTypes {
+		if err := circuitTypes[i].Set(name); err != nil {
+			return err
+		}
+	}
+
 	serviceConfig := prover.Config{
 		NilRpcEndpoint:           cfg.NilRpcEndpoint,
 		ProofProviderRpcEndpoint: cfg.ProofProviderRpcEndpoint,
+		CircuitTypes:             circuitTypes,
+		MemoryClass:              cfg.MemoryClass,
+		MaxConcurrency:           cfg.MaxConcurrency,
 	}
 
 	database, err := db.NewBadgerDb(cfg.DbPath)
@@ -156,6 +211,19 @@ func run(cfg *RunConfig) error {
 	return nil
 }
 
+func collectLocalTraces(dbPath string, cfg *tracer.TraceConfig) error {
+	database, err := db.NewBadgerDbReadOnly(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open BadgerDB: %w", err)
+	}
+	defer database.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	return tracer.CollectLocalTracesToFile(ctx, database, cfg)
+}
+
 func readTrace(cfg *PrintConfig) error {
 	mode, err := tracer.MarshalModeFromString(cfg.MarshalMode)
 	if err != nil {
@@ -169,3 +237,25 @@ func readTrace(cfg *PrintConfig) error {
 	fmt.Printf("%+v", blockTraces)
 	return nil
 }
+
+func validateTrace(cfg *PrintConfig) error {
+	mode, err := tracer.MarshalModeFromString(cfg.MarshalMode)
+	if err != nil {
+		return err
+	}
+
+	blockTraces, err := tracer.DeserializeFromFile(cfg.BaseFileName, mode)
+	if err != nil {
+		return err
+	}
+
+	if err := tracer.ValidateTraces(blockTraces); err != nil {
+		var violation *tracer.TraceViolation
+		if errors.As(err, &violation) {
+			fmt.Printf("violating entry: %+v\n", violation.Entry)
+		}
+		return err
+	}
+	fmt.Println("traces are consistent")
+	return nil
+}
@@ -65,6 +65,10 @@ func addFlags(cmd *cobra.Command, cfg *cmdConfig) {
 		"polling-delay",
 		cfg.AggregatorConfig.RpcPollingInterval,
 		"delay between new block polling")
+	cmd.Flags().Var(
+		&cfg.AggregatorConfig.DataAvailabilityMode,
+		"da-mode",
+		"data availability mode for committing batches to L1: Blob|Calldata|Auto")
 	cmd.Flags().StringVar(
 		&cfg.DbPath,
 		"db-path",
@@ -95,6 +99,26 @@ func addFlags(cmd *cobra.Command, cfg *cmdConfig) {
 		"disable-l1",
 		cfg.ProposerParams.DisableL1,
 		"Disable send trancations to L1")
+	retryPolicies := &cfg.TaskSchedulerConfig.RetryPolicies
+	cmd.Flags().IntVar(
+		&retryPolicies.Default.MaxRetries,
+		"task-max-retries",
+		retryPolicies.Default.MaxRetries,
+		"number of times a task failed with a retryable error is rescheduled")
+	cmd.Flags().DurationVar(
+		&retryPolicies.Default.InitialDelay,
+		"task-retry-initial-delay",
+		retryPolicies.Default.InitialDelay,
+		"delay before the first retry of a failed task, doubled for each next retry")
+	cmd.Flags().DurationVar(
+		&retryPolicies.Default.MaxDelay,
+		"task-retry-max-delay",
+		retryPolicies.Default.MaxDelay,
+		"upper limit of the delay between retries of a failed task")
+	cmd.Flags().Var(
+		retryPolicies,
+		"task-retry-policy",
+		"retry policy of the task type overriding the default one: <TaskType>=<MaxRetries>:<InitialDelay>:<MaxDelay>")
 	logLevel := cmd.Flags().String(
 		"log-level",
 		"info",
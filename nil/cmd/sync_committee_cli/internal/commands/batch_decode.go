@@ -1,16 +1,21 @@
 package commands
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"sync"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/calldata"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
 	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 )
 
 type DecodeBatchParams struct {
@@ -18,6 +23,11 @@ type DecodeBatchParams struct {
 	BatchId   public.BatchId
 	BatchFile string
 
+	// DataAvailability defines the layout of the batch input:
+	// concatenated blobs for DataAvailabilityBlob,
+	// the batch data frame passed to commitBatchCalldata for DataAvailabilityCalldata
+	DataAvailability public.DataAvailabilityMode
+
 	OutputFile string
 }
 
@@ -56,7 +66,10 @@ func DecodeBatch(_ context.Context, params *DecodeBatchParams, logger logging.Lo
 			return err
 		}
 		defer inFile.Close()
-		batchSource = inFile
+		batchSource, err = unpackBatchData(inFile, params.DataAvailability)
+		if err != nil {
+			return err
+		}
 	}
 
 	if batchSource == nil {
@@ -85,3 +98,41 @@ func DecodeBatch(_ context.Context, params *DecodeBatchParams, logger logging.Lo
 	}
 	return nil
 }
+
+// unpackBatchData extracts the encoded batch from the data published with the specified data availability mode
+func unpackBatchData(in io.ReadSeeker, mode public.DataAvailabilityMode) (io.ReadSeeker, error) {
+	switch mode {
+	case public.DataAvailabilityCalldata:
+		frame, err := io.ReadAll(in)
+		if err != nil {
+			return nil, err
+		}
+		unpacked, err := calldata.ReadFrame(frame)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(unpacked), nil
+
+	case public.DataAvailabilityBlob:
+		raw, err := io.ReadAll(in)
+		if err != nil {
+			return nil, err
+		}
+		blobSize := len(kzg4844.Blob{})
+		if len(raw)%blobSize != 0 {
+			return nil, fmt.Errorf("batch input size %d is not a multiple of blob size %d", len(raw), blobSize)
+		}
+		blobs := make([]kzg4844.Blob, len(raw)/blobSize)
+		for i := range blobs {
+			copy(blobs[i][:], raw[i*blobSize:])
+		}
+		unpacked, err := io.ReadAll(blob.NewReader(blobs))
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(unpacked), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported data availability mode: %s", mode)
+	}
+}
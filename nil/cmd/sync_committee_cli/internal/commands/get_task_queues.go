@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+)
+
+type GetTaskQueuesParams struct {
+	ExecutorParams
+}
+
+func (p *GetTaskQueuesParams) GetExecutorParams() *ExecutorParams {
+	return &p.ExecutorParams
+}
+
+func GetTaskQueues(ctx context.Context, _ *GetTaskQueuesParams, api public.TaskDebugApi) (CmdOutput, error) {
+	queues, err := api.GetTaskQueues(ctx)
+	if err != nil {
+		return EmptyOutput, fmt.Errorf("failed to get task queues from debug API: %w", err)
+	}
+
+	if len(queues) == 0 {
+		return EmptyOutput, fmt.Errorf("%w: no queued tasks were found", ErrNoDataFound)
+	}
+
+	queuesTable := &table{
+		header: []string{"Type", "CircuitType", "Active", "Pending", "CapableExecutors"},
+		rows:   make([][]string, 0, len(queues)),
+	}
+	for _, queue := range queues {
+		queuesTable.rows = append(queuesTable.rows, []string{
+			queue.TaskType.String(),
+			queue.CircuitType.String(),
+			strconv.FormatUint(uint64(queue.ActiveCount), 10),
+			strconv.FormatUint(uint64(queue.PendingCount), 10),
+			strconv.FormatUint(uint64(queue.CapableExecutors), 10),
+		})
+	}
+
+	return buildTableOutput(queuesTable), nil
+}
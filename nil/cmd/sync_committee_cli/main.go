@@ -37,6 +37,9 @@ func execute() error {
 	}
 	rootCmd.AddCommand(getTaskTreeCmd)
 
+	getTaskQueuesCmd := buildGetTaskQueuesCmd(executorParams, logger)
+	rootCmd.AddCommand(getTaskQueuesCmd)
+
 	decodeBatchCmd := buildDecodeBatchCmd(executorParams, logger)
 	rootCmd.AddCommand(decodeBatchCmd)
 
@@ -112,6 +115,23 @@ func buildGetTaskTreeCmd(commonParam *commands.ExecutorParams, logger logging.Lo
 	return cmd, nil
 }
 
+func buildGetTaskQueuesCmd(commonParam *commands.ExecutorParams, logger logging.Logger) *cobra.Command {
+	cmdParams := &commands.GetTaskQueuesParams{
+		ExecutorParams: *commonParam,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "get_task_queues",
+		Short: "Get the number of tasks per task type and circuit along with the number of executors able to handle them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commands.NewExecutor(os.Stdout, cmdParams, logger).Run(commands.GetTaskQueues)
+		},
+	}
+
+	addCommonFlags(cmd, &cmdParams.ExecutorParams)
+	return cmd
+}
+
 func buildDecodeBatchCmd(_ *commands.ExecutorParams, logger logging.Logger) *cobra.Command {
 	params := &commands.DecodeBatchParams{}
 
@@ -128,7 +148,12 @@ func buildDecodeBatchCmd(_ *commands.ExecutorParams, logger logging.Logger) *cob
 		&params.BatchFile,
 		"batch-file",
 		"",
-		"file with binary content of concatenated blobs of the batch")
+		"file with binary content of concatenated blobs (or commit calldata) of the batch")
+	params.DataAvailability = public.DataAvailabilityBlob
+	cmd.Flags().Var(
+		&params.DataAvailability,
+		"da-mode",
+		"data availability mode the batch was committed with: Blob|Calldata")
 	cmd.Flags().StringVar(&params.OutputFile, "output-file", "", "target file to keep decoded batch data")
 
 	return cmd
@@ -199,6 +199,38 @@ contract RequestResponseTest is NilTokenBase {
         counterValue = abi.decode(returnData, (int32));
     }
 
+    /**
+     * Test Counter's get method via callback request. Check captured context and return data.
+     */
+    function callbackRequestCounterGet(
+        address counter,
+        uint intContext,
+        string memory strContext
+    ) public {
+        Nil.sendCallbackRequest(
+            counter,
+            0,
+            Nil.ASYNC_REQUEST_MIN_GAS,
+            this.responseCounterGet.selector,
+            abi.encode(intContext, strContext),
+            abi.encodeWithSignature("get()")
+        );
+    }
+
+    /**
+     * Callback request with the captured context exceeding the limit. It must fail.
+     */
+    function callbackRequestTooLargeContext(address counter) public {
+        Nil.sendCallbackRequest(
+            counter,
+            0,
+            Nil.ASYNC_REQUEST_MIN_GAS,
+            this.responseCounterGet.selector,
+            new bytes(Nil.CALLBACK_CONTEXT_MAX_SIZE + 1),
+            abi.encodeWithSignature("get()")
+        );
+    }
+
     /**
      * Nested sendRequest: request requestCounterGet which requests Counter.get
      */
@@ -0,0 +1,80 @@
+package collate
+
+import (
+	"reflect"
+
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+type addedNotifier interface {
+	Added() <-chan struct{}
+}
+
+// getPoolAdded returns the channel signaled on new transactions in the pool of the validator or nil if there is none.
+func getPoolAdded(v *Validator) <-chan struct{} {
+	if reflect.ValueOf(v.pool).IsNil() {
+		return nil
+	}
+	if pool, ok := v.pool.(addedNotifier); ok {
+		return pool.Added()
+	}
+	return nil
+}
+
+// Automine makes the collators of a development network produce blocks on demand instead of every tick.
+// A shard produces a block as soon as a transaction arrives into its pool. The committed block triggers
+// the shards receiving its outbound transactions, so blocks are produced until no transactions are left.
+type Automine struct {
+	triggers map[types.ShardId]chan struct{}
+}
+
+func NewAutomine(nShards uint32) *Automine {
+	triggers := make(map[types.ShardId]chan struct{}, nShards)
+	for i := range nShards {
+		triggers[types.ShardId(i)] = make(chan struct{}, 1)
+	}
+	return &Automine{triggers: triggers}
+}
+
+// Trigger makes the shard produce a block. Triggers of a shard waiting for a block are merged.
+func (a *Automine) Trigger(shardId types.ShardId) {
+	ch, ok := a.triggers[shardId]
+	if !ok {
+		return
+	}
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+func (a *Automine) triggered(shardId types.ShardId) <-chan struct{} {
+	return a.triggers[shardId]
+}
+
+// onBlockCommitted triggers the shards which have transactions to process after the block is committed.
+func (a *Automine) onBlockCommitted(
+	shardId types.ShardId, res *execution.BlockGenerationResult, pool TxnPool,
+) {
+	if a == nil {
+		return
+	}
+
+	for _, txn := range res.OutTxns {
+		a.Trigger(txn.To.ShardId())
+	}
+
+	// The block may not fit all transactions of the pool.
+	// The empty block means that the rest can't be processed now.
+	if len(res.InTxns) > 0 && !reflect.ValueOf(pool).IsNil() {
+		if txns, err := pool.Peek(1); err == nil && len(txns) > 0 {
+			a.Trigger(shardId)
+		}
+	}
+
+	// The main shard references the blocks of other shards, so it follows them
+	if !shardId.IsMainShard() && len(res.InTxns) > 0 {
+		a.Trigger(types.MainShardId)
+	}
+}
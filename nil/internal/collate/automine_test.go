@@ -0,0 +1,90 @@
+package collate
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutomineOnBlockCommitted(t *testing.T) {
+	t.Parallel()
+
+	const nShards = 4
+
+	triggered := func(a *Automine) []types.ShardId {
+		var res []types.ShardId
+		for i := range types.ShardId(nShards) {
+			select {
+			case <-a.triggered(i):
+				res = append(res, i)
+			default:
+			}
+		}
+		return res
+	}
+
+	newTxn := func(to types.ShardId) *types.Transaction {
+		return &types.Transaction{TransactionDigest: types.TransactionDigest{To: types.GenerateRandomAddress(to)}}
+	}
+
+	t.Run("Cascade", func(t *testing.T) {
+		t.Parallel()
+
+		a := NewAutomine(nShards)
+		// The block of shard 1 sends transactions to shards 2 and 3, which mine their blocks in turn,
+		// and the main shard follows the block
+		a.onBlockCommitted(1, &execution.BlockGenerationResult{
+			InTxns:  []*types.Transaction{newTxn(1)},
+			OutTxns: []*types.Transaction{newTxn(2), newTxn(3), newTxn(3)},
+		}, &MockTxnPool{})
+		assert.Equal(t, []types.ShardId{types.MainShardId, 2, 3}, triggered(a))
+
+		// The block of shard 3 responds to shard 1
+		a.onBlockCommitted(3, &execution.BlockGenerationResult{
+			InTxns:  []*types.Transaction{newTxn(3)},
+			OutTxns: []*types.Transaction{newTxn(1)},
+		}, &MockTxnPool{})
+		assert.Equal(t, []types.ShardId{types.MainShardId, 1}, triggered(a))
+
+		// The main shard block references the blocks, it doesn't trigger other shards
+		a.onBlockCommitted(types.MainShardId, &execution.BlockGenerationResult{
+			InTxns: []*types.Transaction{newTxn(types.MainShardId)},
+		}, &MockTxnPool{})
+		assert.Empty(t, triggered(a))
+	})
+
+	t.Run("PoolLeftovers", func(t *testing.T) {
+		t.Parallel()
+
+		a := NewAutomine(nShards)
+		pool := &MockTxnPool{}
+		pool.Add(newTxn(1))
+
+		// The block didn't fit all transactions of the pool, the shard mines the next one
+		a.onBlockCommitted(1, &execution.BlockGenerationResult{
+			InTxns: []*types.Transaction{newTxn(1)},
+		}, pool)
+		assert.Equal(t, []types.ShardId{types.MainShardId, 1}, triggered(a))
+
+		// The empty block means that the rest of the pool can't be processed now
+		a.onBlockCommitted(1, &execution.BlockGenerationResult{}, pool)
+		assert.Empty(t, triggered(a))
+
+		// The typed nil pool is skipped
+		a.onBlockCommitted(1, &execution.BlockGenerationResult{
+			InTxns: []*types.Transaction{newTxn(1)},
+		}, (*MockTxnPool)(nil))
+		assert.Equal(t, []types.ShardId{types.MainShardId}, triggered(a))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		var a *Automine
+		a.onBlockCommitted(1, &execution.BlockGenerationResult{
+			OutTxns: []*types.Transaction{newTxn(2)},
+		}, nil)
+	})
+}
@@ -0,0 +1,184 @@
+package collate
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+)
+
+// getSnapshotPool returns the pool of the validator if it's a transaction pool that can be saved.
+func getSnapshotPool(v *Validator) (*txnpool.TxnPool, bool) {
+	pool, ok := v.pool.(*txnpool.TxnPool)
+	return pool, ok && pool != nil
+}
+
+// shardSnapshot is the saved state of a shard. It's kept in memory, since the database doesn't keep
+// the old versions of the values once they are compacted.
+type shardSnapshot struct {
+	lastBlockHash common.Hash
+	// collatorState is nil if the shard had no collator state.
+	collatorState *types.CollatorState
+	pool          *txnpool.Snapshot
+}
+
+type chainSnapshot struct {
+	shards map[types.ShardId]*shardSnapshot
+}
+
+// ChainSnapshots saves the state of all shards of the node and rolls them back to it.
+// It is intended for development networks run by a single node, where it serves evm_snapshot and evm_revert.
+type ChainSnapshots struct {
+	txFabric   db.DB
+	validators []*Validator
+
+	mutex     sync.Mutex
+	nextId    uint64                    // +checklocks:mutex
+	snapshots map[uint64]*chainSnapshot // +checklocks:mutex
+
+	logger logging.Logger
+}
+
+func NewChainSnapshots(txFabric db.DB, validators []*Validator) *ChainSnapshots {
+	return &ChainSnapshots{
+		txFabric:   txFabric,
+		validators: validators,
+		nextId:     1,
+		snapshots:  make(map[uint64]*chainSnapshot),
+		logger:     logging.NewLogger("chain-snapshots"),
+	}
+}
+
+// lockValidators stops the validators from committing blocks until the returned function is called.
+// +checklocksignore: the mutexes of the validators are held until the returned function is called
+func (c *ChainSnapshots) lockValidators() func() {
+	for _, v := range c.validators {
+		v.mutex.Lock()
+	}
+	return func() {
+		for _, v := range c.validators {
+			v.mutex.Unlock()
+		}
+	}
+}
+
+// Snapshot saves the current state of the shards and returns the id of the snapshot.
+// The state is read between the blocks, while the validators are locked.
+func (c *ChainSnapshots) Snapshot(ctx context.Context) (uint64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	unlock := c.lockValidators()
+	defer unlock()
+
+	tx, err := c.txFabric.CreateRoTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	snapshot := &chainSnapshot{
+		shards: make(map[types.ShardId]*shardSnapshot, len(c.validators)),
+	}
+	for _, v := range c.validators {
+		shardId := v.params.ShardId
+
+		lastBlockHash, err := db.ReadLastBlockHash(tx, shardId)
+		if err != nil {
+			return 0, err
+		}
+		shard := &shardSnapshot{lastBlockHash: lastBlockHash}
+
+		collatorState, err := db.ReadCollatorState(tx, shardId)
+		switch {
+		case err == nil:
+			shard.collatorState = &collatorState
+		case !errors.Is(err, db.ErrKeyNotFound):
+			return 0, err
+		}
+
+		if pool, ok := getSnapshotPool(v); ok {
+			shard.pool = pool.Snapshot()
+		}
+		snapshot.shards[shardId] = shard
+	}
+
+	id := c.nextId
+	c.nextId++
+	c.snapshots[id] = snapshot
+
+	c.logger.Info().Uint64("id", id).Msg("Saved chain snapshot")
+	return id, nil
+}
+
+// Revert rolls all shards back to the snapshot with the given id.
+// The snapshot and all the ones saved after it are dropped.
+// It returns false if there is no such snapshot.
+func (c *ChainSnapshots) Revert(ctx context.Context, id uint64) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	snapshot, ok := c.snapshots[id]
+	if !ok {
+		return false, nil
+	}
+
+	unlock := c.lockValidators()
+	defer unlock()
+
+	if err := c.revertBlocks(ctx, snapshot); err != nil {
+		return false, err
+	}
+
+	for _, v := range c.validators {
+		v.resetLastBlockLocked()
+
+		if pool, ok := getSnapshotPool(v); ok {
+			pool.Restore(snapshot.shards[v.params.ShardId].pool)
+		}
+	}
+
+	for snapshotId := range c.snapshots {
+		if snapshotId >= id {
+			delete(c.snapshots, snapshotId)
+		}
+	}
+
+	c.logger.Info().Uint64("id", id).Msg("Reverted chain to snapshot")
+	return true, nil
+}
+
+func (c *ChainSnapshots) revertBlocks(ctx context.Context, snapshot *chainSnapshot) error {
+	rwTx, err := c.txFabric.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer rwTx.Rollback()
+
+	for _, v := range c.validators {
+		shardId := v.params.ShardId
+		shard := snapshot.shards[shardId]
+
+		c.logger.Debug().
+			Stringer(logging.FieldShardId, shardId).
+			Msgf("Switching last block to %s", shard.lastBlockHash)
+		if err := execution.RevertToBlock(rwTx, shardId, shard.lastBlockHash); err != nil {
+			return err
+		}
+
+		if shard.collatorState == nil {
+			continue
+		}
+		if err := db.WriteCollatorState(rwTx, shardId, *shard.collatorState); err != nil {
+			return err
+		}
+	}
+
+	return rwTx.Commit()
+}
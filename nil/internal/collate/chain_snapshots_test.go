@@ -0,0 +1,147 @@
+package collate
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChainSnapshotsTestSuite struct {
+	suite.Suite
+
+	shardId types.ShardId
+	db      db.DB
+	hashes  []common.Hash
+}
+
+func (s *ChainSnapshotsTestSuite) SetupSuite() {
+	s.shardId = types.BaseShardId
+}
+
+func (s *ChainSnapshotsTestSuite) SetupTest() {
+	var err error
+	s.db, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+
+	s.hashes = []common.Hash{
+		execution.GenerateBlockFromTransactions(s.T(), s.T().Context(), s.shardId, 0, common.EmptyHash, s.db, nil),
+	}
+	s.generateBlock()
+}
+
+func (s *ChainSnapshotsTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *ChainSnapshotsTestSuite) generateBlock() {
+	s.T().Helper()
+
+	id := types.BlockNumber(len(s.hashes))
+	prev := s.hashes[len(s.hashes)-1]
+	s.hashes = append(s.hashes,
+		execution.GenerateBlockFromTransactions(s.T(), s.T().Context(), s.shardId, id, prev, s.db, nil))
+}
+
+func (s *ChainSnapshotsTestSuite) newChainSnapshots(pool TxnPool) *ChainSnapshots {
+	params := &Params{
+		BlockGeneratorParams: execution.NewBlockGeneratorParams(s.shardId, 2),
+	}
+	return NewChainSnapshots(s.db, []*Validator{NewValidator(params, nil, s.db, pool, nil)})
+}
+
+func (s *ChainSnapshotsTestSuite) writeCollatorState(blockNumber types.BlockNumber) {
+	s.T().Helper()
+
+	tx, err := s.db.CreateRwTx(s.T().Context())
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	state := types.CollatorState{Neighbors: []types.Neighbor{{ShardId: types.MainShardId, BlockNumber: blockNumber}}}
+	s.Require().NoError(db.WriteCollatorState(tx, s.shardId, state))
+	s.Require().NoError(tx.Commit())
+}
+
+func (s *ChainSnapshotsTestSuite) checkState(lastBlockHash common.Hash, neighborBlock types.BlockNumber) {
+	s.T().Helper()
+
+	tx, err := s.db.CreateRoTx(s.T().Context())
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	hash, err := db.ReadLastBlockHash(tx, s.shardId)
+	s.Require().NoError(err)
+	s.Equal(lastBlockHash, hash)
+
+	state, err := db.ReadCollatorState(tx, s.shardId)
+	s.Require().NoError(err)
+	s.Require().Len(state.Neighbors, 1)
+	s.Equal(neighborBlock, state.Neighbors[0].BlockNumber)
+}
+
+func (s *ChainSnapshotsTestSuite) TestRevert() {
+	ctx := s.T().Context()
+
+	pool, err := txnpool.New(ctx, txnpool.NewConfig(s.shardId), nil)
+	s.Require().NoError(err)
+	snapshots := s.newChainSnapshots(pool)
+
+	s.writeCollatorState(1)
+	id, err := snapshots.Snapshot(ctx)
+	s.Require().NoError(err)
+
+	// The state is kept in memory, since the database drops the old versions of the values on compaction
+	snapshots.mutex.Lock()
+	saved := snapshots.snapshots[id].shards[s.shardId]
+	snapshots.mutex.Unlock()
+	s.Equal(s.hashes[1], saved.lastBlockHash)
+	s.Require().NotNil(saved.collatorState)
+
+	s.generateBlock()
+	s.writeCollatorState(2)
+	laterId, err := snapshots.Snapshot(ctx)
+	s.Require().NoError(err)
+
+	s.generateBlock()
+	s.writeCollatorState(3)
+	s.checkState(s.hashes[3], 3)
+
+	ok, err := snapshots.Revert(ctx, id)
+	s.Require().NoError(err)
+	s.True(ok)
+	s.checkState(s.hashes[1], 1)
+
+	// The snapshots saved after the reverted one are dropped with it
+	ok, err = snapshots.Revert(ctx, laterId)
+	s.Require().NoError(err)
+	s.False(ok)
+	ok, err = snapshots.Revert(ctx, id)
+	s.Require().NoError(err)
+	s.False(ok)
+}
+
+func (s *ChainSnapshotsTestSuite) TestNilPool() {
+	ctx := s.T().Context()
+
+	// The typed nil pool is skipped
+	snapshots := s.newChainSnapshots((*txnpool.TxnPool)(nil))
+	s.writeCollatorState(1)
+	id, err := snapshots.Snapshot(ctx)
+	s.Require().NoError(err)
+
+	s.generateBlock()
+	ok, err := snapshots.Revert(ctx, id)
+	s.Require().NoError(err)
+	s.True(ok)
+	s.checkState(s.hashes[1], 1)
+}
+
+func TestChainSnapshots(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(ChainSnapshotsTestSuite))
+}
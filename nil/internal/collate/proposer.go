@@ -22,7 +22,6 @@ import (
 
 const (
 	defaultMaxInternalTxns               = 1000
-	defaultMaxGasInBlock                 = types.DefaultMaxGasInBlock
 	maxTxnsFromPool                      = 1000
 	defaultMaxForwardTransactionsInBlock = 200
 
@@ -39,6 +38,7 @@ type proposer struct {
 
 	proposal       *execution.ProposalSSZ
 	executionState *execution.ExecutionState
+	maxGasInBlock  types.Gas
 
 	ctx context.Context
 
@@ -46,9 +46,6 @@ type proposer struct {
 }
 
 func newProposer(params *Params, topology ShardTopology, pool TxnPool, logger logging.Logger) *proposer {
-	if params.MaxGasInBlock == 0 {
-		params.MaxGasInBlock = defaultMaxGasInBlock
-	}
 	if params.MaxInternalTransactionsInBlock == 0 {
 		params.MaxInternalTransactionsInBlock = defaultMaxInternalTxns
 	}
@@ -90,34 +87,73 @@ func (p *proposer) GenerateProposal(ctx context.Context, txFabric db.DB) (*execu
 		return nil, fmt.Errorf("failed to create config accessor: %w", err)
 	}
 
+	p.maxGasInBlock = p.params.MaxGasInBlock
+	if p.maxGasInBlock == 0 {
+		if p.maxGasInBlock, err = config.GetBlockGasLimit(configAccessor); err != nil {
+			return nil, fmt.Errorf("failed to get block gas limit: %w", err)
+		}
+	}
+
 	p.executionState, err = execution.NewExecutionState(tx, p.params.ShardId, execution.StateParams{
 		Block:          prevBlock,
 		ConfigAccessor: configAccessor,
 		FeeCalculator:  p.params.FeeCalculator,
 		Mode:           execution.ModeProposal,
+		DevCheats:      p.params.DevCheats,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	// The pool transactions are peeked in advance, so that their state is prefetched
+	// while the transactions from the neighbors are handled.
+	poolTxns, err := p.pool.Peek(maxTxnsFromPool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to peek transactions from pool: %w", err)
+	}
+	if p.params.PrefetchWorkers > 0 {
+		prefetcher := execution.NewPrefetcher(
+			ctx, txFabric, p.executionState, p.params.AccessSets, p.params.PrefetchWorkers)
+		defer prefetcher.Stop()
+
+		for _, txn := range poolTxns {
+			prefetcher.Prefetch(txn.Transaction)
+		}
+	}
+
 	p.logger.Trace().Msg("Collating...")
 
 	if err := p.fetchLastBlockHashes(tx); err != nil {
 		return nil, fmt.Errorf("failed to fetch last block hashes: %w", err)
 	}
+	p.executionState.MainShardHash = p.proposal.MainShardHash
+
+	// The timestamp is set first, so that all transactions of the block observe it
+	if err := p.handleDevTimestamp(); err != nil {
+		return nil, fmt.Errorf("failed to handle dev timestamp: %w", err)
+	}
 
 	if err := p.handleL1Attributes(tx, prevBlockHash); err != nil {
 		// TODO: change to Error severity once Consensus/Proposer increase time intervals
 		p.logger.Trace().Err(err).Msg("Failed to handle L1 attributes")
 	}
 
+	if err := p.handleDevCheats(); err != nil {
+		return nil, fmt.Errorf("failed to handle dev cheats: %w", err)
+	}
+
+	if err := p.handleRequestTimeouts(); err != nil {
+		return nil, fmt.Errorf("failed to handle request timeouts: %w", err)
+	}
+
 	if err := p.handleTransactionsFromNeighbors(tx); err != nil {
 		return nil, fmt.Errorf("failed to handle transactions from neighbors: %w", err)
 	}
 
-	if err := p.handleTransactionsFromPool(); err != nil {
+	if err := p.handleTransactionsFromPool(poolTxns); err != nil {
 		return nil, fmt.Errorf("failed to handle transactions from pool: %w", err)
 	}
+	p.params.AccessSets.Record(p.executionState)
 
 	if rollback := p.executionState.GetRollback(); rollback != nil {
 		// TODO: verify mainBlockId, actually perform rollback
@@ -210,6 +246,65 @@ func (p *proposer) handleL1Attributes(tx db.RoTx, mainShardHash common.Hash) err
 	return nil
 }
 
+// handleDevTimestamp sets the timestamp of the block requested by the development API.
+func (p *proposer) handleDevTimestamp() error {
+	if txn := p.params.DevCheats.TimestampCheat(); txn != nil {
+		return p.applyDevCheat(txn)
+	}
+	return nil
+}
+
+// handleDevCheats applies the pending state modifications of the development API,
+// so that the transactions of the block are executed over the modified state.
+func (p *proposer) handleDevCheats() error {
+	for _, txn := range p.params.DevCheats.Pending() {
+		if err := p.applyDevCheat(txn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *proposer) applyDevCheat(txn *types.Transaction) error {
+	p.executionState.AddInTransaction(txn)
+	if res := p.executionState.HandleDevCheat(txn); res.FatalError != nil {
+		return res.FatalError
+	} else if res.Failed() {
+		p.logger.Warn().Err(res.Error).
+			Stringer(logging.FieldTransactionHash, txn.Hash()).
+			Msg("Failed to apply dev cheat")
+	}
+	p.proposal.SpecialTxns = append(p.proposal.SpecialTxns, txn)
+	return nil
+}
+
+// handleRequestTimeouts fails the requests of the shard's contracts which aren't responded before their deadlines.
+// The failed responses are delivered before other transactions, so a late response to the request is rejected.
+// The stale index entries are skipped, they are removed when the block is committed.
+func (p *proposer) handleRequestTimeouts() error {
+	expired, _, err := p.executionState.GetExpiredRequests(execution.MaxRequestTimeoutsInBlock)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range expired {
+		txn, err := execution.NewRequestTimeoutTransaction(r.Caller, r.RequestId, r.AsyncContext)
+		if err != nil {
+			return err
+		}
+		p.executionState.AddInTransaction(txn)
+		if res := p.executionState.HandleRequestTimeout(p.ctx, txn); res.FatalError != nil {
+			return res.FatalError
+		} else if res.Failed() {
+			p.logger.Debug().Err(res.Error).
+				Stringer(logging.FieldTransactionHash, txn.Hash()).
+				Msg("Timed out request failed")
+		}
+		p.proposal.SpecialTxns = append(p.proposal.SpecialTxns, txn)
+	}
+	return nil
+}
+
 func CreateRollbackCalldata(params *execution.RollbackParams) ([]byte, error) {
 	abi, err := contracts.GetAbi(contracts.NameGovernance)
 	if err != nil {
@@ -285,12 +380,7 @@ func (p *proposer) handleTransaction(txn *types.Transaction, txnHash common.Hash
 	return nil
 }
 
-func (p *proposer) handleTransactionsFromPool() error {
-	poolTxns, err := p.pool.Peek(maxTxnsFromPool)
-	if err != nil {
-		return err
-	}
-
+func (p *proposer) handleTransactionsFromPool(poolTxns []*types.TxnWithHash) error {
 	if len(poolTxns) != 0 {
 		p.logger.Debug().Int("txNum", len(poolTxns)).Msg("Start handling transactions from the pool")
 	}
@@ -327,7 +417,7 @@ func (p *proposer) handleTransactionsFromPool() error {
 		if ok, err := handle(txn); err != nil {
 			return err
 		} else if ok {
-			if p.executionState.GasUsed > p.params.MaxGasInBlock {
+			if p.executionState.GasUsed > p.maxGasInBlock {
 				break
 			}
 
@@ -366,7 +456,7 @@ func (p *proposer) handleTransactionsFromNeighbors(tx db.RoTx) error {
 	})
 
 	checkLimits := func() bool {
-		return p.executionState.GasUsed < p.params.MaxGasInBlock &&
+		return p.executionState.GasUsed < p.maxGasInBlock &&
 			len(p.proposal.InternalTxnRefs) < p.params.MaxInternalTransactionsInBlock &&
 			len(p.proposal.ForwardTxnRefs) < p.params.MaxForwardTransactionsInBlock
 	}
@@ -304,6 +304,85 @@ func (s *ProposerTestSuite) checkReceipt(shardId types.ShardId, m *types.Transac
 	return receipt
 }
 
+func (s *ProposerTestSuite) readExpiredRequests() []db.RequestDeadline {
+	s.T().Helper()
+
+	tx, err := s.db.CreateRoTx(s.T().Context())
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	expired, err := db.ReadExpiredRequests(tx, s.shardId, 100, 10)
+	s.Require().NoError(err)
+	return expired
+}
+
+func (s *ProposerTestSuite) TestRequestTimeouts() {
+	ctx := s.T().Context()
+
+	mainHash := execution.GenerateBlockFromTransactions(s.T(), ctx, types.MainShardId, 0, common.EmptyHash, s.db, nil)
+	for id := range types.BlockNumber(2) {
+		mainHash = execution.GenerateBlockFromTransactions(s.T(), ctx, types.MainShardId, id+1, mainHash, s.db, nil)
+	}
+	execution.GenerateBlockFromTransactions(s.T(), ctx, s.shardId, 0, common.EmptyHash, s.db, nil)
+
+	caller := types.GenerateRandomAddress(s.shardId)
+	pending := []db.RequestDeadline{
+		{Deadline: 2, Caller: caller, RequestId: 1},
+		{Deadline: 2, Caller: caller, RequestId: 2},
+	}
+	stale := []db.RequestDeadline{
+		// The caller doesn't exist
+		{Deadline: 1, Caller: types.GenerateRandomAddress(s.shardId), RequestId: 1},
+		// The request isn't pending
+		{Deadline: 1, Caller: caller, RequestId: 3},
+		// The request id is reused by the request with another deadline
+		{Deadline: 1, Caller: caller, RequestId: 2},
+	}
+
+	s.Run("WriteRequests", func() {
+		tx, err := s.db.CreateRwTx(ctx)
+		s.Require().NoError(err)
+		defer tx.Rollback()
+
+		prevBlock, _, err := db.ReadLastBlock(tx, s.shardId)
+		s.Require().NoError(err)
+		es, err := execution.NewExecutionState(tx, s.shardId, execution.StateParams{
+			Block:          prevBlock,
+			ConfigAccessor: config.GetStubAccessor(),
+		})
+		s.Require().NoError(err)
+		es.MainShardHash = mainHash
+
+		s.Require().NoError(es.CreateAccount(caller))
+		acc, err := es.GetAccount(caller)
+		s.Require().NoError(err)
+		for _, r := range pending {
+			acc.SetAsyncContext(r.RequestId, &types.AsyncContext{ResponseProcessingGas: 10_000, Deadline: r.Deadline})
+		}
+		blockRes, err := es.Commit(prevBlock.Id+1, nil)
+		s.Require().NoError(err)
+		s.Require().NoError(execution.PostprocessBlock(tx, s.shardId, blockRes, execution.ModeVerify))
+
+		for _, r := range stale {
+			s.Require().NoError(db.WriteRequestDeadline(tx, s.shardId, r))
+		}
+		s.Require().NoError(tx.Commit())
+	})
+
+	p := newTestProposer(s.newParams(), &MockTxnPool{})
+	proposal := s.generateProposal(p)
+
+	// The stale requests don't hold back the pending ones,
+	// the index isn't changed by the proposal, they are removed when the block is committed
+	s.Require().Len(proposal.InternalTxns, len(pending))
+	for i, txn := range proposal.InternalTxns {
+		s.True(execution.IsRequestTimeout(txn))
+		s.Equal(pending[i].Caller, txn.To)
+		s.Equal(uint64(pending[i].RequestId), txn.RequestId)
+	}
+	s.Len(s.readExpiredRequests(), len(pending)+len(stale))
+}
+
 func TestProposer(t *testing.T) {
 	t.Parallel()
 
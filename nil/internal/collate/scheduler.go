@@ -2,6 +2,7 @@ package collate
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -27,6 +28,7 @@ type Consensus interface {
 type Params struct {
 	execution.BlockGeneratorParams
 
+	// MaxGasInBlock overrides the block gas limit from the config params if set.
 	MaxGasInBlock                  types.Gas
 	MaxInternalTransactionsInBlock int
 	MaxForwardTransactionsInBlock  int
@@ -37,6 +39,9 @@ type Params struct {
 	Topology ShardTopology
 
 	L1Fetcher rollup.L1BlockFetcher
+
+	// Automine makes the collator produce blocks on demand instead of every tick, may be nil
+	Automine *Automine
 }
 
 type Scheduler struct {
@@ -50,6 +55,9 @@ type Scheduler struct {
 	logger logging.Logger
 
 	l1Fetcher rollup.L1BlockFetcher
+
+	// collateMutex serializes the blocks produced by the run loop and the ones mined on request
+	collateMutex sync.Mutex
 }
 
 func NewScheduler(
@@ -82,6 +90,10 @@ func (s *Scheduler) Run(ctx context.Context, consensus Consensus) error {
 	// Enable handler for blocks relaying
 	SetRequestHandler(ctx, s.networkManager, s.params.ShardId, s.txFabric, s.logger)
 
+	if s.params.Automine != nil {
+		return s.runAutomine(ctx)
+	}
+
 	tickPeriodMs := s.params.CollatorTickPeriod.Milliseconds()
 	for {
 		var toRoundStartMs int64
@@ -95,16 +107,59 @@ func (s *Scheduler) Run(ctx context.Context, consensus Consensus) error {
 			s.logger.Info().Msg("Stopping collation...")
 			return nil
 		case <-time.After(time.Duration(toRoundStartMs) * time.Millisecond):
-			if err := s.doCollate(ctx); err != nil {
-				if ctx.Err() != nil {
-					continue
-				}
-				s.logger.Error().Err(err).Msg("Failed to collate")
-			}
+			s.collate(ctx)
 		}
 	}
 }
 
+// runAutomine produces blocks when the shard is triggered or transactions arrive into the pool.
+func (s *Scheduler) runAutomine(ctx context.Context) error {
+	poolAdded := getPoolAdded(s.validator)
+
+	// The transactions left in the pool after a restart are processed at once
+	s.params.Automine.Trigger(s.params.ShardId)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info().Msg("Stopping collation...")
+			return nil
+		case <-s.params.Automine.triggered(s.params.ShardId):
+			s.collate(ctx)
+		case <-poolAdded:
+			s.collate(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) collate(ctx context.Context) {
+	s.collateMutex.Lock()
+	defer s.collateMutex.Unlock()
+
+	if err := s.doCollate(ctx); err != nil && ctx.Err() == nil {
+		s.logger.Error().Err(err).Msg("Failed to collate")
+	}
+}
+
+// Mine produces n blocks one after another and returns the number of the last block of the shard.
+// It's intended for development networks, where tests produce blocks on demand.
+func (s *Scheduler) Mine(ctx context.Context, n uint64) (types.BlockNumber, error) {
+	s.collateMutex.Lock()
+	defer s.collateMutex.Unlock()
+
+	for range n {
+		if err := s.doCollate(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	block, _, err := s.validator.GetLastBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return block.Id, nil
+}
+
 func (s *Scheduler) doCollate(ctx context.Context) error {
 	if s.params.DisableConsensus {
 		proposal, err := s.validator.BuildProposal(ctx)
@@ -0,0 +1,118 @@
+package collate
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchedulerTestSuite struct {
+	suite.Suite
+
+	shardId types.ShardId
+	db      db.DB
+}
+
+func (s *SchedulerTestSuite) SetupSuite() {
+	s.shardId = types.BaseShardId
+}
+
+func (s *SchedulerTestSuite) SetupTest() {
+	var err error
+	s.db, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+
+	ctx := s.T().Context()
+	execution.GenerateBlockFromTransactions(s.T(), ctx, types.MainShardId, 0, common.EmptyHash, s.db, nil)
+	execution.GenerateBlockFromTransactions(s.T(), ctx, s.shardId, 0, common.EmptyHash, s.db, nil)
+}
+
+func (s *SchedulerTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *SchedulerTestSuite) newScheduler(cheats *execution.DevCheats, automine *Automine) *Scheduler {
+	params := &Params{
+		BlockGeneratorParams: execution.NewBlockGeneratorParams(s.shardId, 2),
+		Topology:             new(TrivialShardTopology),
+		Automine:             automine,
+	}
+	params.DisableConsensus = true
+	params.DevCheats = cheats
+	return NewScheduler(NewValidator(params, nil, s.db, &MockTxnPool{}, nil), s.db, nil, nil)
+}
+
+// readProposal returns the block with the given number and the proposal reproducing it.
+func (s *SchedulerTestSuite) readProposal(id types.BlockNumber) (*types.Block, common.Hash, *execution.Proposal) {
+	s.T().Helper()
+
+	tx, err := s.db.CreateRoTx(s.T().Context())
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	hash, err := db.ReadBlockHashByNumber(tx, s.shardId, id)
+	s.Require().NoError(err)
+	data, err := execution.NewStateAccessor().Access(tx, s.shardId).GetBlock().WithInTransactions().ByHash(hash)
+	s.Require().NoError(err)
+
+	block := data.Block()
+	proposal := &execution.Proposal{
+		PrevBlockId:   block.Id - 1,
+		PrevBlockHash: block.PrevBlock,
+		MainShardHash: block.MainShardHash,
+	}
+	proposal.InternalTxns, proposal.ExternalTxns = execution.SplitInTransactions(data.InTransactions())
+	return block, hash, proposal
+}
+
+func (s *SchedulerTestSuite) TestMine() {
+	ctx := s.T().Context()
+
+	cheats := execution.NewDevCheats()
+	automine := NewAutomine(2)
+	scheduler := s.newScheduler(cheats, automine)
+
+	id, err := scheduler.Mine(ctx, 2)
+	s.Require().NoError(err)
+	s.Equal(types.BlockNumber(2), id)
+
+	// The empty blocks don't trigger other shards
+	select {
+	case <-automine.triggered(types.MainShardId):
+		s.Fail("main shard is triggered")
+	default:
+	}
+
+	s.Run("Timestamp", func() {
+		const ts = 1_000_000
+		cheats.SetNextBlockTimestamp(ts)
+
+		id, err := scheduler.Mine(ctx, 2)
+		s.Require().NoError(err)
+		s.Equal(types.BlockNumber(4), id)
+
+		// The timestamp is carried by the first transaction of the block, the following blocks continue from it
+		block, hash, proposal := s.readProposal(3)
+		s.Equal(uint64(ts), block.Timestamp)
+		s.Require().NotEmpty(proposal.InternalTxns)
+		s.True(execution.IsDevCheat(proposal.InternalTxns[0]))
+		next, _, _ := s.readProposal(4)
+		s.Equal(uint64(ts+1), next.Timestamp)
+
+		// Another validator reproduces the block without the timestamp set by its dev API
+		verifier := s.newScheduler(execution.NewDevCheats(), nil).Validator()
+		gotHash, err := verifier.buildBlockHashByProposal(ctx, proposal)
+		s.Require().NoError(err)
+		s.Equal(hash, gotHash)
+	})
+}
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(SchedulerTestSuite))
+}
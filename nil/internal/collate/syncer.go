@@ -187,7 +187,7 @@ func (s *Syncer) Run(ctx context.Context) error {
 		return nil
 	}
 
-	sub, err := s.networkManager.PubSub().Subscribe(s.topic)
+	sub, err := s.networkManager.PubSub().Subscribe(s.topic, s.validator.validateTopicBlock)
 	if err != nil {
 		return fmt.Errorf("failed to subscribe to %s: %w", s.topic, err)
 	}
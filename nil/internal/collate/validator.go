@@ -20,6 +20,8 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/signer"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	"google.golang.org/protobuf/proto"
 )
 
 type invalidSignatureError struct {
@@ -263,6 +265,8 @@ func (s *Validator) onBlockCommitUnlocked(
 				Msgf("Failed to remove %d committed transactions from pool", len(proposal.ExternalTxns))
 		}
 	}
+	s.params.DevCheats.OnCommitted(res.Block, proposal.InternalTxns)
+	s.params.Automine.onBlockCommitted(s.params.ShardId, res, s.pool)
 
 	s.notify(res.Block.Id)
 }
@@ -373,6 +377,43 @@ func (s *Validator) validateProposal(ctx context.Context, proposal *execution.Pr
 	return s.validateProposalUnlocked(ctx, proposal)
 }
 
+// validateTopicBlock is a topic validator checking the structure and the signature of the blocks
+// received from the network before they are relayed to the other peers.
+// The blocks older than the last one are ignored.
+func (s *Validator) validateTopicBlock(ctx context.Context, msg network.PubSubMessage) network.ValidationResult {
+	var pbBlock pb.RawFullBlock
+	if err := proto.Unmarshal(msg.Data, &pbBlock); err != nil {
+		return network.ValidationReject
+	}
+	block, err := unmarshalBlockSSZ(&pbBlock)
+	if err != nil {
+		return network.ValidationReject
+	}
+
+	// The peers lagging behind fetch the old blocks on their own, so only the fresh ones are relayed.
+	lastBlock, _, err := s.GetLastBlock(ctx)
+	if err != nil {
+		return network.ValidationIgnore
+	}
+	if lastBlock != nil && block.Id < lastBlock.Id {
+		return network.ValidationIgnore
+	}
+
+	if s.params.DisableConsensus {
+		return network.ValidationAccept
+	}
+	if err := s.blockVerifier.VerifyBlock(ctx, block.Block); err != nil {
+		if errors.Is(err, signer.ErrInvalidBlockSignature) {
+			return network.ValidationReject
+		}
+		// The validators of the block may be unknown yet, the block is verified during the replay then.
+		s.logger.Trace().Err(err).
+			Stringer(logging.FieldBlockNumber, block.Id).
+			Msg("Could not verify block signature before relaying")
+	}
+	return network.ValidationAccept
+}
+
 func (s *Validator) ReplayBlock(ctx context.Context, block *types.BlockWithExtractedData) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -477,6 +518,12 @@ func (s *Validator) setLastBlockUnlocked(block *types.Block, hash common.Hash) {
 	s.lastBlockHash = hash
 }
 
+// resetLastBlockLocked makes the validator read the last block from the database on the next access.
+// +checklocksignore: the mutex is held by ChainSnapshots.lockValidators
+func (s *Validator) resetLastBlockLocked() {
+	s.setLastBlockUnlocked(nil, common.EmptyHash)
+}
+
 func (s *Validator) Subscribe() (uint64, <-chan types.BlockNumber) {
 	s.subsMutex.Lock()
 	defer s.subsMutex.Unlock()
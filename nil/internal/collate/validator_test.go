@@ -0,0 +1,95 @@
+package collate
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+)
+
+type ValidatorTestSuite struct {
+	suite.Suite
+
+	shardId types.ShardId
+	db      db.DB
+}
+
+func (s *ValidatorTestSuite) SetupSuite() {
+	s.shardId = types.BaseShardId
+}
+
+func (s *ValidatorTestSuite) SetupTest() {
+	var err error
+	s.db, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+
+	hash := execution.GenerateBlockFromTransactions(s.T(), s.T().Context(), s.shardId, 0, common.EmptyHash, s.db, nil)
+	execution.GenerateBlockFromTransactions(s.T(), s.T().Context(), s.shardId, 1, hash, s.db, nil)
+}
+
+func (s *ValidatorTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *ValidatorTestSuite) newValidator(disableConsensus bool) *Validator {
+	params := &Params{
+		BlockGeneratorParams: execution.NewBlockGeneratorParams(s.shardId, 2),
+	}
+	params.DisableConsensus = disableConsensus
+	return NewValidator(params, nil, s.db, nil, nil)
+}
+
+func (s *ValidatorTestSuite) blockMessage(id types.BlockNumber) network.PubSubMessage {
+	s.T().Helper()
+
+	block := &types.BlockWithExtractedData{
+		Block: &types.Block{
+			BlockData: types.BlockData{
+				Id:        id,
+				PrevBlock: common.EmptyHash,
+			},
+		},
+	}
+	pbBlock, err := marshalBlockSSZ(block)
+	s.Require().NoError(err)
+	data, err := proto.Marshal(pbBlock)
+	s.Require().NoError(err)
+	return network.PubSubMessage{Data: data}
+}
+
+func (s *ValidatorTestSuite) TestValidateTopicBlock() {
+	ctx := s.T().Context()
+
+	s.Run("Accept", func() {
+		v := s.newValidator(true)
+		s.Equal(network.ValidationAccept, v.validateTopicBlock(ctx, s.blockMessage(1)))
+		s.Equal(network.ValidationAccept, v.validateTopicBlock(ctx, s.blockMessage(2)))
+	})
+
+	s.Run("RejectMalformed", func() {
+		v := s.newValidator(true)
+		s.Equal(network.ValidationReject, v.validateTopicBlock(ctx, network.PubSubMessage{Data: []byte{1, 2, 3}}))
+	})
+
+	s.Run("AcceptUnverified", func() {
+		// The validators of the block are unknown, so it is verified during the replay
+		v := s.newValidator(false)
+		s.Equal(network.ValidationAccept, v.validateTopicBlock(ctx, s.blockMessage(2)))
+	})
+
+	s.Run("IgnoreStale", func() {
+		v := s.newValidator(true)
+		s.Equal(network.ValidationIgnore, v.validateTopicBlock(ctx, s.blockMessage(0)))
+	})
+}
+
+func TestValidator(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(ValidatorTestSuite))
+}
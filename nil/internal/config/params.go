@@ -17,15 +17,17 @@ import (
 const ValidatorPubkeySize = 128
 
 const (
-	NameValidators = "curr_validators"
-	NameGasPrice   = "gas_price"
-	NameL1Block    = "l1block"
+	NameValidators    = "curr_validators"
+	NameGasPrice      = "gas_price"
+	NameL1Block       = "l1block"
+	NameBlockGasLimit = "block_gas_limit"
 )
 
 var ParamsList = []IConfigParam{
 	new(ParamValidators),
 	new(ParamGasPrice),
 	new(ParamL1BlockInfo),
+	new(ParamBlockGasLimit),
 }
 
 type Pubkey [ValidatorPubkeySize]byte
@@ -111,6 +113,20 @@ func (p *ParamL1BlockInfo) Accessor() *ParamAccessor {
 	return CreateAccessor[ParamL1BlockInfo]()
 }
 
+type ParamBlockGasLimit struct {
+	GasLimit uint64 `json:"gasLimit" yaml:"gasLimit"`
+}
+
+var _ IConfigParam = new(ParamBlockGasLimit)
+
+func (p *ParamBlockGasLimit) Name() string {
+	return NameBlockGasLimit
+}
+
+func (p *ParamBlockGasLimit) Accessor() *ParamAccessor {
+	return CreateAccessor[ParamBlockGasLimit]()
+}
+
 func CreateAccessor[T any, paramPtr IConfigParamPointer[T]]() *ParamAccessor {
 	return &ParamAccessor{
 		func(c ConfigAccessor) (any, error) {
@@ -247,6 +263,30 @@ func SetParamL1Block(c ConfigAccessor, params *ParamL1BlockInfo) error {
 	return setParamImpl(c, params)
 }
 
+func GetParamBlockGasLimit(c ConfigAccessor) (*ParamBlockGasLimit, error) {
+	return getParamImpl[ParamBlockGasLimit](c)
+}
+
+func SetParamBlockGasLimit(c ConfigAccessor, params *ParamBlockGasLimit) error {
+	return setParamImpl(c, params)
+}
+
+// GetBlockGasLimit returns the gas limit of the blocks.
+// The default limit is returned if the parameter is not set.
+func GetBlockGasLimit(c ConfigAccessor) (types.Gas, error) {
+	param, err := getParamImpl[ParamBlockGasLimit](c)
+	if errors.Is(err, ErrParamNotFound) {
+		return types.DefaultMaxGasInBlock, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if param.GasLimit == 0 {
+		return types.DefaultMaxGasInBlock, nil
+	}
+	return types.Gas(param.GasLimit), nil
+}
+
 func GetParamNShards(c ConfigAccessor) (uint32, error) {
 	param, err := getParamImpl[ParamGasPrice](c)
 	if err != nil {
@@ -46,7 +46,7 @@ func (i *backendIBFT) setupTransport(ctx context.Context) error {
 
 	// Subscribe to the newly created topic
 	protocol := i.getProto()
-	sub, err := topic.Subscribe(protocol)
+	sub, err := topic.Subscribe(protocol, nil)
 	if err != nil {
 		return err
 	}
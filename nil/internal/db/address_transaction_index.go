@@ -0,0 +1,146 @@
+package db
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// AddressTransactionDirection tells whether an indexed transaction was received or sent by the address.
+type AddressTransactionDirection uint8
+
+const (
+	AddressTransactionInbound AddressTransactionDirection = iota
+	AddressTransactionOutbound
+)
+
+// AddressTransactionCursorSize is the size of a pagination cursor, it is the position of a transaction
+// in the address index: block number, direction and transaction index.
+const AddressTransactionCursorSize = 8 + 1 + 8
+
+var ErrInvalidAddressTransactionCursor = errors.New("invalid address transaction cursor")
+
+type AddressTransaction struct {
+	BlockId   types.BlockNumber
+	Direction AddressTransactionDirection
+	Index     types.TransactionIndex
+	Hash      common.Hash
+}
+
+// AddressTransactionIndexState describes which blocks of a shard are covered by the address transaction index.
+// Blocks starting from BackfillTarget+1 are indexed during postprocessing, blocks up to BackfillTarget
+// are indexed by the backfill, which is done for all blocks below NextBackfillBlock.
+type AddressTransactionIndexState struct {
+	NextBackfillBlock types.BlockNumber
+	BackfillTarget    types.BlockNumber
+}
+
+// IsIndexed checks that all blocks in the range [from, to] are covered by the index.
+func (s AddressTransactionIndexState) IsIndexed(from, to types.BlockNumber) bool {
+	if s.NextBackfillBlock > s.BackfillTarget {
+		return true
+	}
+	return to < s.NextBackfillBlock || from > s.BackfillTarget
+}
+
+func addressTransactionPosition(
+	blockId types.BlockNumber, direction AddressTransactionDirection, index types.TransactionIndex,
+) []byte {
+	pos := make([]byte, 0, AddressTransactionCursorSize)
+	pos = binary.BigEndian.AppendUint64(pos, uint64(blockId))
+	pos = append(pos, byte(direction))
+	return binary.BigEndian.AppendUint64(pos, uint64(index))
+}
+
+func makeAddressTransactionKey(address types.Address, position []byte) []byte {
+	return append(bytes.Clone(address.Bytes()), position...)
+}
+
+func WriteAddressTransaction(tx RwTx, shardId types.ShardId, address types.Address, txn AddressTransaction) error {
+	key := makeAddressTransactionKey(address, addressTransactionPosition(txn.BlockId, txn.Direction, txn.Index))
+	return tx.PutToShard(shardId, AddressTransactionIndex, key, txn.Hash.Bytes())
+}
+
+func DeleteAddressTransaction(tx RwTx, shardId types.ShardId, address types.Address, txn AddressTransaction) error {
+	key := makeAddressTransactionKey(address, addressTransactionPosition(txn.BlockId, txn.Direction, txn.Index))
+	return tx.DeleteFromShard(shardId, AddressTransactionIndex, key)
+}
+
+// ReadAddressTransactions returns up to limit transactions of the address from blocks [from, to]
+// in the order of their execution. Reading starts from the cursor position if the cursor is set.
+// The returned cursor points to the first transaction of the next page, it is nil if there are no more transactions.
+func ReadAddressTransactions(
+	tx RoTx,
+	shardId types.ShardId,
+	address types.Address,
+	from, to types.BlockNumber,
+	cursor []byte,
+	limit uint64,
+) ([]AddressTransaction, []byte, error) {
+	start := addressTransactionPosition(from, AddressTransactionInbound, 0)
+	if cursor != nil {
+		if len(cursor) != AddressTransactionCursorSize {
+			return nil, nil, fmt.Errorf("%w: expected %d bytes, got %d",
+				ErrInvalidAddressTransactionCursor, AddressTransactionCursorSize, len(cursor))
+		}
+		if bytes.Compare(cursor, start) > 0 {
+			start = cursor
+		}
+	}
+	end := addressTransactionPosition(to, math.MaxUint8, math.MaxUint64)
+
+	iter, err := tx.RangeByShard(
+		shardId, AddressTransactionIndex, makeAddressTransactionKey(address, start), makeAddressTransactionKey(address, end))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer iter.Close()
+
+	res := make([]AddressTransaction, 0)
+	for iter.HasNext() {
+		key, value, err := iter.Next()
+		if err != nil {
+			return nil, nil, err
+		}
+		position := key[types.AddrSize:]
+		if uint64(len(res)) == limit {
+			return res, position, nil
+		}
+		res = append(res, AddressTransaction{
+			BlockId:   types.BlockNumber(binary.BigEndian.Uint64(position[:8])),
+			Direction: AddressTransactionDirection(position[8]),
+			Index:     types.TransactionIndex(binary.BigEndian.Uint64(position[9:])),
+			Hash:      common.BytesToHash(value),
+		})
+	}
+	return res, nil, nil
+}
+
+func ReadAddressTransactionIndexState(tx RoTx, shardId types.ShardId) (AddressTransactionIndexState, error) {
+	value, err := tx.Get(addressTransactionIndexStateTable, shardId.Bytes())
+	if err != nil {
+		return AddressTransactionIndexState{}, err
+	}
+	if len(value) != 16 {
+		return AddressTransactionIndexState{}, fmt.Errorf("invalid address transaction index state size %d", len(value))
+	}
+	return AddressTransactionIndexState{
+		NextBackfillBlock: types.BlockNumber(binary.BigEndian.Uint64(value[:8])),
+		BackfillTarget:    types.BlockNumber(binary.BigEndian.Uint64(value[8:])),
+	}, nil
+}
+
+func WriteAddressTransactionIndexState(tx RwTx, shardId types.ShardId, state AddressTransactionIndexState) error {
+	value := binary.BigEndian.AppendUint64(nil, uint64(state.NextBackfillBlock))
+	value = binary.BigEndian.AppendUint64(value, uint64(state.BackfillTarget))
+	return tx.Put(addressTransactionIndexStateTable, shardId.Bytes(), value)
+}
+
+func DeleteAddressTransactionIndexState(tx RwTx, shardId types.ShardId) error {
+	return tx.Delete(addressTransactionIndexStateTable, shardId.Bytes())
+}
@@ -77,6 +77,12 @@ func NewBadgerDb(pathToDb string) (*badgerDB, error) {
 	return newBadgerDb(&opts)
 }
 
+// NewBadgerDbReadOnly opens existing database without write access, e.g. to read a database of a stopped node
+func NewBadgerDbReadOnly(pathToDb string) (*badgerDB, error) {
+	opts := badger.DefaultOptions(pathToDb).WithReadOnly(true).WithLogger(nil)
+	return newBadgerDb(&opts)
+}
+
 func NewBadgerDbInMemory() (*badgerDB, error) {
 	opts := badger.DefaultOptions("").WithInMemory(true).WithLogger(nil)
 	return newBadgerDb(&opts)
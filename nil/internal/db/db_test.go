@@ -589,6 +589,50 @@ func (s *SuiteBadgerDb) TestStreamLoad() {
 	})
 }
 
+func (s *SuiteBadgerDb) TestRequestDeadlines() {
+	caller := types.ShardAndHexToAddress(1, "12")
+	requests := []RequestDeadline{
+		{Deadline: 10, Caller: caller, RequestId: 2},
+		{Deadline: 5, Caller: caller, RequestId: 1},
+		{Deadline: 11, Caller: caller, RequestId: 3},
+		{Deadline: 10, Caller: types.ShardAndHexToAddress(1, "13"), RequestId: 1},
+	}
+
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	for _, r := range requests {
+		s.Require().NoError(WriteRequestDeadline(tx, 1, r))
+	}
+	s.Require().NoError(WriteRequestDeadline(tx, 2, RequestDeadline{Deadline: 1, Caller: caller, RequestId: 4}))
+
+	res, err := ReadExpiredRequests(tx, 1, 4, 10)
+	s.Require().NoError(err)
+	s.Empty(res)
+
+	res, err = ReadExpiredRequests(tx, 1, 10, 10)
+	s.Require().NoError(err)
+	s.Equal([]RequestDeadline{requests[1], requests[0], requests[3]}, res)
+
+	res, err = ReadExpiredRequests(tx, 1, 10, 2)
+	s.Require().NoError(err)
+	s.Equal([]RequestDeadline{requests[1], requests[0]}, res)
+
+	s.Require().NoError(DeleteRequestDeadline(tx, 1, requests[1]))
+	res, err = ReadExpiredRequests(tx, 1, 100, 10)
+	s.Require().NoError(err)
+	s.Equal([]RequestDeadline{requests[0], requests[3], requests[2]}, res)
+
+	// The iteration stops when the callback returns false
+	var visited []RequestDeadline
+	s.Require().NoError(ForEachExpiredRequest(tx, 1, 100, func(r RequestDeadline) (bool, error) {
+		visited = append(visited, r)
+		return r.Caller != requests[3].Caller, nil
+	}))
+	s.Equal([]RequestDeadline{requests[0], requests[3]}, visited)
+}
+
 func TestSuiteBadgerDb(t *testing.T) {
 	t.Parallel()
 
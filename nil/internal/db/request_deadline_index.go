@@ -0,0 +1,85 @@
+package db
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const requestDeadlineKeySize = 8 + types.AddrSize + 8
+
+// RequestDeadline is a pending request of a contract of the shard, which fails if the response isn't received
+// until the main shard reaches the deadline.
+type RequestDeadline struct {
+	Deadline  types.BlockNumber
+	Caller    types.Address
+	RequestId types.TransactionIndex
+}
+
+// The requests are ordered by the deadline, so that the expired ones are read by a single range.
+func makeRequestDeadlineKey(r RequestDeadline) []byte {
+	key := make([]byte, 0, requestDeadlineKeySize)
+	key = binary.BigEndian.AppendUint64(key, uint64(r.Deadline))
+	key = append(key, r.Caller.Bytes()...)
+	return binary.BigEndian.AppendUint64(key, uint64(r.RequestId))
+}
+
+func WriteRequestDeadline(tx RwTx, shardId types.ShardId, r RequestDeadline) error {
+	return tx.PutToShard(shardId, RequestDeadlineIndex, makeRequestDeadlineKey(r), []byte{})
+}
+
+func DeleteRequestDeadline(tx RwTx, shardId types.ShardId, r RequestDeadline) error {
+	return tx.DeleteFromShard(shardId, RequestDeadlineIndex, makeRequestDeadlineKey(r))
+}
+
+// ForEachExpiredRequest calls f for the requests of the shard with the deadline not above the main shard height
+// in the order of their deadlines, until f returns false.
+func ForEachExpiredRequest(
+	tx RoTx, shardId types.ShardId, height types.BlockNumber, f func(RequestDeadline) (bool, error),
+) error {
+	// The keys of the deadline height+1 are greater than its bytes, so the range ends right before them
+	end := binary.BigEndian.AppendUint64(nil, uint64(height+1))
+	iter, err := tx.RangeByShard(shardId, RequestDeadlineIndex, nil, end)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for iter.HasNext() {
+		key, _, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if len(key) != requestDeadlineKeySize {
+			return fmt.Errorf("invalid request deadline key size %d", len(key))
+		}
+		next, err := f(RequestDeadline{
+			Deadline:  types.BlockNumber(binary.BigEndian.Uint64(key[:8])),
+			Caller:    types.BytesToAddress(key[8 : 8+types.AddrSize]),
+			RequestId: types.TransactionIndex(binary.BigEndian.Uint64(key[8+types.AddrSize:])),
+		})
+		if err != nil || !next {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadExpiredRequests returns up to limit requests of the shard with the deadline not above the main shard height
+// in the order of their deadlines.
+func ReadExpiredRequests(
+	tx RoTx, shardId types.ShardId, height types.BlockNumber, limit uint64,
+) ([]RequestDeadline, error) {
+	res := make([]RequestDeadline, 0)
+	if limit == 0 {
+		return res, nil
+	}
+	if err := ForEachExpiredRequest(tx, shardId, height, func(r RequestDeadline) (bool, error) {
+		res = append(res, r)
+		return uint64(len(res)) < limit, nil
+	}); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
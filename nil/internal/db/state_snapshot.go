@@ -0,0 +1,262 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// The state snapshot is a flat copy of the latest state of a shard: contracts by address,
+// storage slots by address and key, token balances by address and token id.
+// It matches the contracts trie with the root stored by WriteStateSnapshotRoot.
+
+func makeStateSnapshotAccountKey(address types.Address, key []byte) []byte {
+	return append(bytes.Clone(address.Bytes()), key...)
+}
+
+// ReadStateSnapshotRoot returns the contracts trie root the snapshot of the shard matches.
+// A missing root means that the snapshot is empty, i.e., it matches the empty state.
+func ReadStateSnapshotRoot(tx RoTx, shardId types.ShardId) (common.Hash, error) {
+	value, err := tx.Get(stateSnapshotRootTable, shardId.Bytes())
+	if errors.Is(err, ErrKeyNotFound) {
+		return common.EmptyHash, nil
+	}
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return common.BytesToHash(value), nil
+}
+
+func WriteStateSnapshotRoot(tx RwTx, shardId types.ShardId, root common.Hash) error {
+	return tx.Put(stateSnapshotRootTable, shardId.Bytes(), root.Bytes())
+}
+
+// DeleteStateSnapshotRoot marks the snapshot of the shard as not matching any state.
+func DeleteStateSnapshotRoot(tx RwTx, shardId types.ShardId) error {
+	return tx.Delete(stateSnapshotRootTable, shardId.Bytes())
+}
+
+// StateSnapshotGenerator is the progress of the generation of the snapshot of a shard.
+// The snapshot is generated in batches, it has no root until the generation is done.
+type StateSnapshotGenerator struct {
+	// Root is the contracts trie root the generated part of the snapshot matches.
+	Root common.Hash
+	// Cleared is set when the previous snapshot is removed and the contracts are being generated.
+	Cleared bool
+	// Next is the contracts trie key the generation continues from.
+	Next common.Hash
+}
+
+const stateSnapshotGeneratorSize = 2*common.HashSize + 1
+
+// ReadStateSnapshotGenerator returns the progress of the snapshot generation, nil if it's not in progress.
+func ReadStateSnapshotGenerator(tx RoTx, shardId types.ShardId) (*StateSnapshotGenerator, error) {
+	value, err := tx.Get(stateSnapshotGeneratorTable, shardId.Bytes())
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(value) != stateSnapshotGeneratorSize {
+		return nil, fmt.Errorf("invalid state snapshot generator size %d", len(value))
+	}
+	return &StateSnapshotGenerator{
+		Root:    common.BytesToHash(value[:common.HashSize]),
+		Cleared: value[common.HashSize] != 0,
+		Next:    common.BytesToHash(value[common.HashSize+1:]),
+	}, nil
+}
+
+func WriteStateSnapshotGenerator(tx RwTx, shardId types.ShardId, gen *StateSnapshotGenerator) error {
+	value := make([]byte, 0, stateSnapshotGeneratorSize)
+	value = append(value, gen.Root.Bytes()...)
+	if gen.Cleared {
+		value = append(value, 1)
+	} else {
+		value = append(value, 0)
+	}
+	value = append(value, gen.Next.Bytes()...)
+	return tx.Put(stateSnapshotGeneratorTable, shardId.Bytes(), value)
+}
+
+func DeleteStateSnapshotGenerator(tx RwTx, shardId types.ShardId) error {
+	return tx.Delete(stateSnapshotGeneratorTable, shardId.Bytes())
+}
+
+func ReadSnapshotContract(tx RoTx, shardId types.ShardId, address types.Address) (*types.SmartContract, error) {
+	data, err := tx.GetFromShard(shardId, StateSnapshotContracts, address.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	contract := new(types.SmartContract)
+	if err := contract.UnmarshalSSZ(data); err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
+
+func WriteSnapshotContract(tx RwTx, shardId types.ShardId, contract *types.SmartContract) error {
+	return writeRawKeyEncodable(tx, StateSnapshotContracts, shardId, contract.Address.Bytes(), contract)
+}
+
+// ForEachSnapshotContract calls f for the contracts of the snapshot ordered by address.
+func ForEachSnapshotContract(tx RoTx, shardId types.ShardId, f func(*types.SmartContract) error) error {
+	iter, err := tx.RangeByShard(shardId, StateSnapshotContracts, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for iter.HasNext() {
+		_, value, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		contract := new(types.SmartContract)
+		if err := contract.UnmarshalSSZ(value); err != nil {
+			return err
+		}
+		if err := f(contract); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ReadSnapshotStorage(
+	tx RoTx, shardId types.ShardId, address types.Address, key common.Hash,
+) (common.Hash, error) {
+	value, err := tx.GetFromShard(shardId, StateSnapshotStorage, makeStateSnapshotAccountKey(address, key.Bytes()))
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return common.BytesToHash(value), nil
+}
+
+// WriteSnapshotStorage sets the storage slot of the address, the slot is removed if the value is empty.
+func WriteSnapshotStorage(
+	tx RwTx, shardId types.ShardId, address types.Address, key common.Hash, value common.Hash,
+) error {
+	dbKey := makeStateSnapshotAccountKey(address, key.Bytes())
+	if value == common.EmptyHash {
+		return tx.DeleteFromShard(shardId, StateSnapshotStorage, dbKey)
+	}
+	return tx.PutToShard(shardId, StateSnapshotStorage, dbKey, value.Bytes())
+}
+
+// ReadSnapshotStorageEntries returns all non-empty storage slots of the address.
+func ReadSnapshotStorageEntries(
+	tx RoTx, shardId types.ShardId, address types.Address,
+) (map[common.Hash]common.Hash, error) {
+	res := make(map[common.Hash]common.Hash)
+	err := iterateSnapshotAccount(tx, shardId, StateSnapshotStorage, address, func(key, value []byte) error {
+		res[common.BytesToHash(key)] = common.BytesToHash(value)
+		return nil
+	})
+	return res, err
+}
+
+func ReadSnapshotToken(
+	tx RoTx, shardId types.ShardId, address types.Address, id types.TokenId,
+) (types.Value, error) {
+	var res types.Value
+	value, err := tx.GetFromShard(shardId, StateSnapshotTokens, makeStateSnapshotAccountKey(address, id[:]))
+	if err != nil {
+		return res, err
+	}
+	return res, res.UnmarshalSSZ(value)
+}
+
+// WriteSnapshotToken sets the token balance of the address, the token is removed if the balance is zero.
+func WriteSnapshotToken(
+	tx RwTx, shardId types.ShardId, address types.Address, id types.TokenId, value types.Value,
+) error {
+	dbKey := makeStateSnapshotAccountKey(address, id[:])
+	if value.IsZero() {
+		return tx.DeleteFromShard(shardId, StateSnapshotTokens, dbKey)
+	}
+	return writeRawKeyEncodable(tx, StateSnapshotTokens, shardId, dbKey, &value)
+}
+
+// ReadSnapshotTokens returns all non-zero token balances of the address.
+func ReadSnapshotTokens(
+	tx RoTx, shardId types.ShardId, address types.Address,
+) (map[types.TokenId]types.Value, error) {
+	res := make(map[types.TokenId]types.Value)
+	err := iterateSnapshotAccount(tx, shardId, StateSnapshotTokens, address, func(key, value []byte) error {
+		var balance types.Value
+		if err := balance.UnmarshalSSZ(value); err != nil {
+			return err
+		}
+		res[types.TokenId(types.BytesToAddress(key))] = balance
+		return nil
+	})
+	return res, err
+}
+
+func iterateSnapshotAccount(
+	tx RoTx,
+	shardId types.ShardId,
+	table ShardedTableName,
+	address types.Address,
+	f func(key, value []byte) error,
+) error {
+	iter, err := tx.RangeByShard(shardId, table, address.Bytes(), nil)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for iter.HasNext() {
+		key, value, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if !bytes.HasPrefix(key, address.Bytes()) {
+			break
+		}
+		if err := f(key[types.AddrSize:], value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClearStateSnapshotBatch removes up to limit entries of the snapshot of the shard and returns their number.
+// The snapshot is empty when it's less than the limit.
+func ClearStateSnapshotBatch(tx RwTx, shardId types.ShardId, limit int) (int, error) {
+	deleted := 0
+	for _, table := range []ShardedTableName{StateSnapshotContracts, StateSnapshotStorage, StateSnapshotTokens} {
+		if deleted == limit {
+			break
+		}
+
+		iter, err := tx.RangeByShard(shardId, table, nil, nil)
+		if err != nil {
+			return deleted, err
+		}
+		keys := make([][]byte, 0, limit-deleted)
+		for len(keys) < limit-deleted && iter.HasNext() {
+			key, _, err := iter.Next()
+			if err != nil {
+				iter.Close()
+				return deleted, err
+			}
+			keys = append(keys, bytes.Clone(key))
+		}
+		iter.Close()
+
+		for _, key := range keys {
+			if err := tx.DeleteFromShard(shardId, table, key); err != nil {
+				return deleted, err
+			}
+		}
+		deleted += len(keys)
+	}
+	return deleted, nil
+}
@@ -29,13 +29,23 @@ const (
 		"BlockHashAndInTransactionIndexByTransactionHash")
 	BlockHashAndOutTransactionIndexByTransactionHash = ShardedTableName(
 		"BlockHashAndOutTransactionIndexByTransactionHash")
-	AsyncCallContextTable = ShardedTableName("AsyncCallContext")
+	AsyncCallContextTable   = ShardedTableName("AsyncCallContext")
+	AddressTransactionIndex = ShardedTableName("AddressTransactionIndex")
+	RpcResponseCacheTable   = ShardedTableName("RpcResponseCache")
+	StateSnapshotContracts  = ShardedTableName("StateSnapshotContracts")
+	StateSnapshotStorage    = ShardedTableName("StateSnapshotStorage")
+	StateSnapshotTokens     = ShardedTableName("StateSnapshotTokens")
+	RequestDeadlineIndex    = ShardedTableName("RequestDeadlineIndex")
 
 	collatorStateTable          = TableName("CollatorState")
 	errorByTransactionHashTable = TableName("ErrorByTransactionHash")
 	schemeVersionTable          = TableName("SchemeVersion")
 	LastBlockTable              = TableName("LastBlock")
 
+	addressTransactionIndexStateTable = TableName("AddressTransactionIndexState")
+	stateSnapshotRootTable            = TableName("StateSnapshotRoot")
+	stateSnapshotGeneratorTable       = TableName("StateSnapshotGenerator")
+
 	DHTTable = TableName("DHT")
 )
 
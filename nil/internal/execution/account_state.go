@@ -3,6 +3,7 @@ package execution
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
@@ -10,6 +11,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/tracing"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
 )
 
 type AccountStateReader struct {
@@ -18,13 +20,16 @@ type AccountStateReader struct {
 	// TokenTrieReader is a reader for token from the storage. If Tokens doesn't have some token, it will
 	// be fetched from TokenTrieReader.
 	TokenTrieReader *TokenTrieReader
+
+	address  types.Address
+	snapshot *StateSnapshot
 }
 
 func (asr *AccountStateReader) GetTokenBalance(id types.TokenId) types.Value {
 	if res, ok := (*asr.Tokens)[id]; ok {
 		return res
 	}
-	res, err := asr.TokenTrieReader.Fetch(id)
+	res, err := fetchTokenBalance(asr.snapshot, asr.TokenTrieReader, asr.address, id)
 	if errors.Is(err, db.ErrKeyNotFound) {
 		return types.Value{}
 	}
@@ -50,6 +55,10 @@ type AccountState struct {
 	TokenTree   *TokenTrie
 	// AsyncContextTree is a trie that stores the context for each request sent from this account.
 	AsyncContextTree *AsyncContextTrie
+	// snapshot is used to read the committed storage and tokens if it's available.
+	snapshot *StateSnapshot
+	// prefetched holds the committed storage and code loaded by the prefetcher, may be nil.
+	prefetched *PrefetchedState
 	// requestId is a current request id. It is used to generate unique number for each request.
 	requestId uint64
 
@@ -83,7 +92,32 @@ func NewAccountStateReader(account *AccountState) *AccountStateReader {
 	return &AccountStateReader{
 		Tokens:          &account.Tokens,
 		TokenTrieReader: account.TokenTree.BaseMPTReader,
+		address:         account.address,
+		snapshot:        account.snapshot,
+	}
+}
+
+// fetchTokenBalance reads the committed token balance from the snapshot if it's set or from the token trie otherwise.
+func fetchTokenBalance(
+	snapshot *StateSnapshot, trie *TokenTrieReader, addr types.Address, id types.TokenId,
+) (*types.Value, error) {
+	if snapshot != nil {
+		return snapshot.GetTokenBalance(addr, id)
+	}
+	return trie.Fetch(id)
+}
+
+// readCode reads the code through the cache shared with the EVM, which also keeps its JUMPDEST analysis.
+func readCode(tx db.RoTx, shardId types.ShardId, hash common.Hash) (types.Code, error) {
+	if code, ok := vm.SharedCodeCache.GetCode(hash); ok {
+		return code, nil
+	}
+	code, err := db.ReadCode(tx, shardId, hash)
+	if err != nil {
+		return nil, err
 	}
+	vm.SharedCodeCache.AddCode(hash, code)
+	return code, nil
 }
 
 func NewAccountState(
@@ -106,6 +140,10 @@ func NewAccountState(
 		Tokens:       make(map[types.TokenId]types.Value),
 		logger:       logger,
 	}
+	if es, ok := es.(*ExecutionState); ok && es.ShardId == shardId {
+		accountState.snapshot = es.stateSnapshot()
+		accountState.prefetched = es.prefetched
+	}
 
 	if account != nil {
 		accountState.Balance = account.Balance
@@ -113,10 +151,14 @@ func NewAccountState(
 		accountState.StorageTree.SetRootHash(account.StorageRoot)
 		accountState.CodeHash = account.CodeHash
 		accountState.AsyncContextTree.SetRootHash(account.AsyncContextRoot)
-		var err error
-		accountState.Code, err = db.ReadCode(es.GetRwTx(), shardId, account.CodeHash)
-		if err != nil {
-			return nil, err
+		if code, ok := accountState.prefetched.getCode(account.CodeHash); ok {
+			accountState.Code = code
+		} else {
+			var err error
+			accountState.Code, err = readCode(es.GetRwTx(), shardId, account.CodeHash)
+			if err != nil {
+				return nil, err
+			}
 		}
 		accountState.ExtSeqno = account.ExtSeqno
 		accountState.Seqno = account.Seqno
@@ -126,6 +168,20 @@ func NewAccountState(
 	return accountState, nil
 }
 
+// tracingHooks returns the hooks of the execution state, nil if state changes aren't traced.
+func (as *AccountState) tracingHooks() *tracing.Hooks {
+	if es, ok := as.db.(*ExecutionState); ok {
+		return es.EvmTracingHooks
+	}
+	return nil
+}
+
+func (as *AccountState) traceBalanceChange(prev types.Value, reason tracing.BalanceChangeReason) {
+	if hooks := as.tracingHooks(); hooks != nil && hooks.OnBalanceChange != nil {
+		hooks.OnBalanceChange(as.address, prev.ToBig(), as.Balance.ToBig(), reason)
+	}
+}
+
 func (as *AccountState) empty() bool {
 	return as.Seqno == 0 && as.Balance.IsZero() && len(as.Code) == 0
 }
@@ -143,7 +199,9 @@ func (as *AccountState) AddBalance(amount types.Value, reason tracing.BalanceCha
 
 	as.logger.Debug().Stringer("address", as.address).Stringer("reason", reason).
 		Msgf("Balance change: adding balance %s + %s = %s", as.Balance, amount, newBalance)
+	prev := as.Balance
 	as.SetBalance(newBalance)
+	as.traceBalanceChange(prev, reason)
 	return nil
 }
 
@@ -160,7 +218,9 @@ func (as *AccountState) SubBalance(amount types.Value, reason tracing.BalanceCha
 
 	as.logger.Debug().Stringer("address", as.address).Stringer("reason", reason).
 		Msgf("Balance change: withdrawing balance %s - %s = %s", as.Balance, amount, newBalance)
+	prev := as.Balance
 	as.SetBalance(newBalance)
+	as.traceBalanceChange(prev, reason)
 	return nil
 }
 
@@ -217,7 +277,7 @@ func (as *AccountState) GetTokenBalance(id types.TokenId) *types.Value {
 		return &value
 	}
 
-	prev, err := as.TokenTree.Fetch(id)
+	prev, err := fetchTokenBalance(as.snapshot, as.TokenTree.BaseMPTReader, as.address, id)
 	if errors.Is(err, db.ErrKeyNotFound) {
 		return nil
 	}
@@ -234,6 +294,9 @@ func (as *AccountState) SetSeqno(seqno types.Seqno) {
 		account: &as.address,
 		prev:    as.Seqno,
 	})
+	if hooks := as.tracingHooks(); hooks != nil && hooks.OnNonceChange != nil {
+		hooks.OnNonceChange(as.address, uint64(as.Seqno), uint64(seqno))
+	}
 	as.Seqno = seqno
 }
 
@@ -252,6 +315,9 @@ func (as *AccountState) SetCode(codeHash common.Hash, code []byte) {
 		prevhash: as.CodeHash[:],
 		prevcode: prevcode,
 	})
+	if hooks := as.tracingHooks(); hooks != nil && hooks.OnCodeChange != nil {
+		hooks.OnCodeChange(as.address, as.CodeHash, prevcode, codeHash, code)
+	}
 	as.setCode(codeHash, code)
 }
 
@@ -263,13 +329,33 @@ func (as *AccountState) SetAsyncContext(index types.TransactionIndex, ctx *types
 	as.AsyncContext[index] = ctx
 }
 
+// GetAsyncContext returns the context of the pending request without removing it.
+// It returns db.ErrKeyNotFound if there is no such request or it's already responded.
+func (as *AccountState) GetAsyncContext(index types.TransactionIndex) (*types.AsyncContext, error) {
+	if ctx, exists := as.AsyncContext[index]; exists {
+		return ctx, nil
+	}
+	if slices.Contains(as.AsyncContextRemoved, index) {
+		return nil, db.ErrKeyNotFound
+	}
+	return as.AsyncContextTree.Fetch(index)
+}
+
 func (as *AccountState) GetAndRemoveAsyncContext(index types.TransactionIndex) (*types.AsyncContext, error) {
 	ctx, exists := as.AsyncContext[index]
 	if exists {
 		return ctx, nil
 	}
+	// The request may be responded twice in the same block if it's timed out, the second response is rejected
+	if slices.Contains(as.AsyncContextRemoved, index) {
+		return nil, db.ErrKeyNotFound
+	}
+	ctx, err := as.AsyncContextTree.Fetch(index)
+	if err != nil {
+		return nil, err
+	}
 	as.AsyncContextRemoved = append(as.AsyncContextRemoved, index)
-	return as.AsyncContextTree.Fetch(index)
+	return ctx, nil
 }
 
 func (as *AccountState) setCode(codeHash common.Hash, code []byte) {
@@ -293,6 +379,9 @@ func (as *AccountState) SetState(key common.Hash, value common.Hash) error {
 		key:       key,
 		prevvalue: prev,
 	})
+	if hooks := as.tracingHooks(); hooks != nil && hooks.OnStorageChange != nil {
+		hooks.OnStorageChange(as.address, key, prev, value)
+	}
 	as.setState(key, value)
 	return nil
 }
@@ -317,15 +406,13 @@ func (as *AccountState) setState(key common.Hash, value common.Hash) {
 
 // GetCommittedState retrieves a value from the committed account storage trie.
 func (as *AccountState) GetCommittedState(key common.Hash) (common.Hash, error) {
-	res, err := as.StorageTree.Fetch(key)
-	if errors.Is(err, db.ErrKeyNotFound) {
-		return common.EmptyHash, nil
+	if value, ok := as.prefetched.getState(as.address, key); ok {
+		return value, nil
 	}
-	if err != nil {
-		return common.EmptyHash, err
+	if as.snapshot != nil {
+		return as.snapshot.GetState(as.address, key)
 	}
-
-	return res.Bytes32(), nil
+	return fetchStorageValue(as.StorageTree.BaseMPTReader, key)
 }
 
 func (as *AccountState) Commit() (*types.SmartContract, error) {
@@ -352,7 +439,34 @@ func (as *AccountState) Commit() (*types.SmartContract, error) {
 		return nil, err
 	}
 
+	// The requests with deadlines are indexed, so that the proposer finds the expired ones
+	for k, v := range as.AsyncContext {
+		if v.Deadline == 0 {
+			continue
+		}
+		if err := db.WriteRequestDeadline(as.db.GetRwTx(), as.address.ShardId(), db.RequestDeadline{
+			Deadline:  v.Deadline,
+			Caller:    as.address,
+			RequestId: k,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
 	for _, k := range as.AsyncContextRemoved {
+		ctx, err := as.AsyncContextTree.Fetch(k)
+		if err != nil {
+			return nil, err
+		}
+		if ctx.Deadline != 0 {
+			if err := db.DeleteRequestDeadline(as.db.GetRwTx(), as.address.ShardId(), db.RequestDeadline{
+				Deadline:  ctx.Deadline,
+				Caller:    as.address,
+				RequestId: k,
+			}); err != nil {
+				return nil, err
+			}
+		}
 		if err := as.AsyncContextTree.Delete(k); err != nil {
 			return nil, err
 		}
@@ -374,7 +488,16 @@ func (as *AccountState) Commit() (*types.SmartContract, error) {
 		return nil, err
 	}
 
-	acc := &types.SmartContract{
+	if err := db.WriteCode(as.db.GetRwTx(), as.address.ShardId(), as.CodeHash, as.Code); err != nil {
+		return nil, err
+	}
+
+	return as.smartContract(), nil
+}
+
+// smartContract returns the account as it's stored in the contracts trie.
+func (as *AccountState) smartContract() *types.SmartContract {
+	return &types.SmartContract{
 		Address:          as.address,
 		Balance:          as.Balance,
 		StorageRoot:      as.StorageTree.RootHash(),
@@ -385,10 +508,4 @@ func (as *AccountState) Commit() (*types.SmartContract, error) {
 		Seqno:            as.Seqno,
 		RequestId:        as.requestId,
 	}
-
-	if err := db.WriteCode(as.db.GetRwTx(), as.address.ShardId(), as.CodeHash, as.Code); err != nil {
-		return nil, err
-	}
-
-	return acc, nil
 }
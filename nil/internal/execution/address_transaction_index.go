@@ -0,0 +1,266 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// addressIndexBackfillBatchSize is the number of blocks indexed by the backfill in a single DB transaction
+const addressIndexBackfillBatchSize = 100
+
+// indexAddressTransactions puts incoming transactions of the block into the index of their recipients
+// and outgoing transactions into the index of their senders.
+// Forwarded transactions are skipped since their senders belong to other shards.
+func indexAddressTransactions(
+	tx db.RwTx,
+	shardId types.ShardId,
+	blockId types.BlockNumber,
+	inTxns []*types.Transaction,
+	inTxnHashes []common.Hash,
+	outTxns []*types.Transaction,
+	outTxnHashes []common.Hash,
+) error {
+	return forEachAddressTransaction(
+		shardId, blockId, inTxns, inTxnHashes, outTxns, outTxnHashes,
+		func(address types.Address, txn db.AddressTransaction) error {
+			return db.WriteAddressTransaction(tx, shardId, address, txn)
+		})
+}
+
+// unindexAddressTransactions removes transactions of the block from the index.
+func unindexAddressTransactions(
+	tx db.RwTx,
+	shardId types.ShardId,
+	blockId types.BlockNumber,
+	inTxns []*types.Transaction,
+	inTxnHashes []common.Hash,
+	outTxns []*types.Transaction,
+	outTxnHashes []common.Hash,
+) error {
+	return forEachAddressTransaction(
+		shardId, blockId, inTxns, inTxnHashes, outTxns, outTxnHashes,
+		func(address types.Address, txn db.AddressTransaction) error {
+			return db.DeleteAddressTransaction(tx, shardId, address, txn)
+		})
+}
+
+func forEachAddressTransaction(
+	shardId types.ShardId,
+	blockId types.BlockNumber,
+	inTxns []*types.Transaction,
+	inTxnHashes []common.Hash,
+	outTxns []*types.Transaction,
+	outTxnHashes []common.Hash,
+	fn func(address types.Address, txn db.AddressTransaction) error,
+) error {
+	for i, txn := range inTxns {
+		if err := fn(txn.To, db.AddressTransaction{
+			BlockId:   blockId,
+			Direction: db.AddressTransactionInbound,
+			Index:     types.TransactionIndex(i),
+			Hash:      inTxnHashes[i],
+		}); err != nil {
+			return err
+		}
+	}
+	for i, txn := range outTxns {
+		if txn.From.ShardId() != shardId {
+			continue
+		}
+		if err := fn(txn.From, db.AddressTransaction{
+			BlockId:   blockId,
+			Direction: db.AddressTransactionOutbound,
+			Index:     types.TransactionIndex(i),
+			Hash:      outTxnHashes[i],
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DisableAddressTransactionIndex marks the address transaction index of the shard as unavailable.
+// It must be called on startup of a node which doesn't maintain the index,
+// so that the index is rebuilt from scratch once it is enabled again.
+func DisableAddressTransactionIndex(ctx context.Context, database db.DB, shardId types.ShardId) error {
+	tx, err := database.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := db.DeleteAddressTransactionIndexState(tx, shardId); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// BackfillAddressTransactionIndex indexes transactions of the blocks which were produced
+// before the address transaction index was enabled. Blocks produced after the start of the backfill
+// are expected to be indexed during postprocessing.
+// Progress is saved after each batch of blocks, so the backfill resumes from where it stopped after a restart.
+func BackfillAddressTransactionIndex(
+	ctx context.Context, database db.DB, shardId types.ShardId, logger logging.Logger,
+) error {
+	state, err := initAddressTransactionIndexState(ctx, database, shardId)
+	if err != nil {
+		return fmt.Errorf("failed to initialize address transaction index state: %w", err)
+	}
+
+	logger.Info().
+		Stringer(logging.FieldShardId, shardId).
+		Stringer("from", state.NextBackfillBlock).
+		Stringer("to", state.BackfillTarget).
+		Msg("Backfilling address transaction index...")
+
+	for state.NextBackfillBlock <= state.BackfillTarget {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if state, err = backfillAddressTransactionIndexBatch(ctx, database, shardId, state); err != nil {
+			return fmt.Errorf("failed to backfill address transaction index: %w", err)
+		}
+	}
+
+	logger.Info().
+		Stringer(logging.FieldShardId, shardId).
+		Msg("Address transaction index is backfilled")
+	return nil
+}
+
+// initAddressTransactionIndexState reads the backfill state or starts a new backfill up to the last block.
+// Here and in the backfill reads and writes are done in separate DB transactions, so that writes
+// don't conflict with collators which may concurrently update the data read (e.g., trie nodes shared between blocks).
+func initAddressTransactionIndexState(
+	ctx context.Context, database db.DB, shardId types.ShardId,
+) (db.AddressTransactionIndexState, error) {
+	roTx, err := database.CreateRoTx(ctx)
+	if err != nil {
+		return db.AddressTransactionIndexState{}, err
+	}
+	defer roTx.Rollback()
+
+	state, err := db.ReadAddressTransactionIndexState(roTx, shardId)
+	if err == nil {
+		return state, nil
+	}
+	if !errors.Is(err, db.ErrKeyNotFound) {
+		return db.AddressTransactionIndexState{}, err
+	}
+
+	// The genesis block has no transactions, so the backfill starts from the next one.
+	// Nothing to backfill if the shard has no blocks yet.
+	state = db.AddressTransactionIndexState{NextBackfillBlock: 1}
+	lastBlock, _, err := db.ReadLastBlock(roTx, shardId)
+	if err == nil {
+		state = db.AddressTransactionIndexState{NextBackfillBlock: 1, BackfillTarget: lastBlock.Id}
+	} else if !errors.Is(err, db.ErrKeyNotFound) {
+		return db.AddressTransactionIndexState{}, err
+	}
+
+	return state, writeAddressTransactionIndexState(ctx, database, shardId, state, nil)
+}
+
+type blockTransactions struct {
+	blockId      types.BlockNumber
+	inTxns       []*types.Transaction
+	inTxnHashes  []common.Hash
+	outTxns      []*types.Transaction
+	outTxnHashes []common.Hash
+}
+
+func backfillAddressTransactionIndexBatch(
+	ctx context.Context, database db.DB, shardId types.ShardId, state db.AddressTransactionIndexState,
+) (db.AddressTransactionIndexState, error) {
+	roTx, err := database.CreateRoTx(ctx)
+	if err != nil {
+		return state, err
+	}
+	defer roTx.Rollback()
+
+	blocks := make([]*blockTransactions, 0, addressIndexBackfillBatchSize)
+	for range addressIndexBackfillBatchSize {
+		if state.NextBackfillBlock > state.BackfillTarget {
+			break
+		}
+		block, err := readBlockTransactions(roTx, shardId, state.NextBackfillBlock)
+		if err != nil {
+			return state, fmt.Errorf("block %d: %w", state.NextBackfillBlock, err)
+		}
+		blocks = append(blocks, block)
+		state.NextBackfillBlock++
+	}
+
+	return state, writeAddressTransactionIndexState(ctx, database, shardId, state, blocks)
+}
+
+func writeAddressTransactionIndexState(
+	ctx context.Context,
+	database db.DB,
+	shardId types.ShardId,
+	state db.AddressTransactionIndexState,
+	blocks []*blockTransactions,
+) error {
+	tx, err := database.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, block := range blocks {
+		if err := indexAddressTransactions(
+			tx, shardId, block.blockId, block.inTxns, block.inTxnHashes, block.outTxns, block.outTxnHashes,
+		); err != nil {
+			return err
+		}
+	}
+	if err := db.WriteAddressTransactionIndexState(tx, shardId, state); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func readBlockTransactions(tx db.RoTx, shardId types.ShardId, blockId types.BlockNumber) (*blockTransactions, error) {
+	block, err := db.ReadBlockByNumber(tx, shardId, blockId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &blockTransactions{blockId: blockId}
+	res.inTxns, res.inTxnHashes, err = readTransactionTrie(tx, shardId, block.InTransactionsRoot)
+	if err != nil {
+		return nil, err
+	}
+	res.outTxns, res.outTxnHashes, err = readTransactionTrie(tx, shardId, block.OutTransactionsRoot)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func readTransactionTrie(
+	tx db.RoTx, shardId types.ShardId, root common.Hash,
+) ([]*types.Transaction, []common.Hash, error) {
+	reader := NewDbTransactionTrieReader(tx, shardId)
+	reader.SetRootHash(root)
+	entries, err := reader.Entries()
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	txns := make([]*types.Transaction, len(entries))
+	hashes := make([]common.Hash, len(entries))
+	for i, entry := range entries {
+		txns[i] = entry.Val
+		hashes[i] = entry.Val.Hash()
+	}
+	return txns, hashes, nil
+}
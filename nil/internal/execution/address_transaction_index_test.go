@@ -0,0 +1,70 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitAddressTransactionIndexState(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	readState := func(shardId types.ShardId) db.AddressTransactionIndexState {
+		t.Helper()
+
+		tx, err := database.CreateRoTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		state, err := db.ReadAddressTransactionIndexState(tx, shardId)
+		require.NoError(t, err)
+		return state
+	}
+
+	t.Run("NoBlocks", func(t *testing.T) {
+		state, err := initAddressTransactionIndexState(ctx, database, types.MainShardId)
+		require.NoError(t, err)
+		assert.Equal(t, db.AddressTransactionIndexState{NextBackfillBlock: 1}, state)
+		assert.Equal(t, state, readState(types.MainShardId))
+		assert.True(t, state.IsIndexed(0, 10))
+	})
+
+	t.Run("SkipGenesis", func(t *testing.T) {
+		const shardId = types.BaseShardId
+
+		tx, err := database.CreateRwTx(ctx)
+		require.NoError(t, err)
+		block := &types.Block{BlockData: types.BlockData{Id: 5}}
+		hash := block.Hash(shardId)
+		require.NoError(t, db.WriteBlock(tx, shardId, hash, block))
+		require.NoError(t, db.WriteLastBlockHash(tx, shardId, hash))
+		require.NoError(t, tx.Commit())
+
+		state, err := initAddressTransactionIndexState(ctx, database, shardId)
+		require.NoError(t, err)
+		assert.Equal(t, db.AddressTransactionIndexState{NextBackfillBlock: 1, BackfillTarget: 5}, state)
+		assert.Equal(t, state, readState(shardId))
+		assert.False(t, state.IsIndexed(1, 5))
+		assert.True(t, state.IsIndexed(6, 10))
+
+		// The saved state is resumed
+		tx, err = database.CreateRwTx(ctx)
+		require.NoError(t, err)
+		saved := db.AddressTransactionIndexState{NextBackfillBlock: 3, BackfillTarget: 5}
+		require.NoError(t, db.WriteAddressTransactionIndexState(tx, shardId, saved))
+		require.NoError(t, tx.Commit())
+
+		state, err = initAddressTransactionIndexState(ctx, database, shardId)
+		require.NoError(t, err)
+		assert.Equal(t, saved, state)
+	})
+}
@@ -23,6 +23,22 @@ type BlockGeneratorParams struct {
 	DisableConsensus bool
 	FeeCalculator    FeeCalculator
 	ExecutionMode    string
+
+	// IndexAddressTransactions enables maintaining the address transaction index during block postprocessing
+	IndexAddressTransactions bool
+
+	// NewLiveTracingHooks creates the hooks of the tracers collecting the data of the committed blocks.
+	// It's called for every block generator, so the hooks don't have to be safe for concurrent use.
+	NewLiveTracingHooks func() *tracing.Hooks
+
+	// PrefetchWorkers is the number of goroutines loading the state of the transactions before their execution.
+	// Prefetching is disabled if it's zero.
+	PrefetchWorkers int
+	// AccessSets keeps the storage slots accessed by the contracts to prefetch them, may be nil
+	AccessSets *AccessSets
+
+	// DevCheats holds the state modifications of the development API, they are rejected if it's nil
+	DevCheats *DevCheats
 }
 
 func NewBlockGeneratorParams(shardId types.ShardId, nShards uint32) BlockGeneratorParams {
@@ -43,6 +59,9 @@ type BlockGenerator struct {
 	logger   logging.Logger
 	mh       *MetricsHandler
 	counters *BlockGeneratorCounters
+
+	// stateDiff collects the changes made by the transactions if it's set
+	stateDiff *BlockStateDiff
 }
 
 type BlockGenerationResult struct {
@@ -76,11 +95,16 @@ func NewBlockGenerator(
 		ConfigAccessor: configAccessor,
 		FeeCalculator:  params.FeeCalculator,
 		Mode:           params.ExecutionMode,
+		DevCheats:      params.DevCheats,
 	})
 	if err != nil {
 		return nil, err
 	}
 	executionState.EvmTracingHooks = params.EvmTracingHooks
+	// Blocks built for verification are never committed, so they aren't traced by the live tracers
+	if params.NewLiveTracingHooks != nil && params.ExecutionMode != ModeVerify {
+		executionState.EvmTracingHooks = tracing.CombineHooks(params.EvmTracingHooks, params.NewLiveTracingHooks())
+	}
 
 	return NewBlockGeneratorWithEs(ctx, params, txFabric, rwTx, executionState)
 }
@@ -234,6 +258,10 @@ func (g *BlockGenerator) prepareExecutionState(proposal *Proposal, gasPrices []t
 		return err
 	}
 
+	if hooks := g.executionState.EvmTracingHooks; hooks != nil && hooks.OnBlockStart != nil {
+		hooks.OnBlockStart(tracing.BlockEvent{ShardId: g.params.ShardId, Id: proposal.PrevBlockId + 1})
+	}
+
 	if err := g.updateGasPrices(gasPrices); err != nil {
 		return fmt.Errorf("failed to update gas prices: %w", err)
 	}
@@ -242,6 +270,15 @@ func (g *BlockGenerator) prepareExecutionState(proposal *Proposal, gasPrices []t
 	g.executionState.PatchLevel = proposal.PatchLevel
 	g.executionState.RollbackCounter = proposal.RollbackCounter
 
+	// The generator may be created without the database (e.g., for tracing), then the state isn't prefetched
+	if g.params.PrefetchWorkers > 0 && g.txFabric != nil {
+		prefetcher := NewPrefetcher(g.ctx, g.txFabric, g.executionState, g.params.AccessSets, g.params.PrefetchWorkers)
+		defer prefetcher.Stop()
+
+		prefetcher.Prefetch(proposal.InternalTxns...)
+		prefetcher.Prefetch(proposal.ExternalTxns...)
+	}
+
 	for _, txn := range proposal.InternalTxns {
 		if err := g.handleTxn(txn); err != nil {
 			return err
@@ -254,6 +291,8 @@ func (g *BlockGenerator) prepareExecutionState(proposal *Proposal, gasPrices []t
 		}
 	}
 
+	g.params.AccessSets.Record(g.executionState)
+
 	for _, txn := range proposal.ForwardTxns {
 		g.executionState.AppendForwardTransaction(txn)
 	}
@@ -273,13 +312,21 @@ func (g *BlockGenerator) handleTxn(txn *types.Transaction) error {
 		g.counters.ExecTransactions++
 	}
 
+	journalStart := g.executionState.journal.length()
 	txnHash := g.executionState.AddInTransaction(txn)
 
 	var res *ExecutionResult
-	if txn.IsInternal() {
+	switch {
+	case IsDevCheat(txn):
+		res = g.executionState.HandleDevCheat(txn)
+		g.counters.InternalTransactions++
+	case IsRequestTimeout(txn):
+		res = g.executionState.HandleRequestTimeout(g.ctx, txn)
+		g.counters.InternalTransactions++
+	case txn.IsInternal():
 		res = g.handleInternalInTransaction(txn)
 		g.counters.InternalTransactions++
-	} else {
+	default:
 		res = g.handleExternalTransaction(txn)
 		g.counters.ExternalTransactions++
 	}
@@ -294,6 +341,14 @@ func (g *BlockGenerator) handleTxn(txn *types.Transaction) error {
 	g.addReceipt(res)
 	g.counters.CoinsUsed = g.counters.CoinsUsed.Add(res.CoinsUsed())
 
+	if g.stateDiff != nil {
+		diff, err := g.executionState.stateDiff(journalStart)
+		if err != nil {
+			return err
+		}
+		g.stateDiff.Transactions[txnHash] = diff
+	}
+
 	return nil
 }
 
@@ -404,11 +459,26 @@ func (g *BlockGenerator) finalize(
 }
 
 func (g *BlockGenerator) Finalize(blockRes *BlockGenerationResult, params *types.ConsensusParams) error {
+	err := g.commitBlock(blockRes, params)
+	if hooks := g.executionState.EvmTracingHooks; hooks != nil && hooks.OnBlockEnd != nil {
+		hooks.OnBlockEnd(tracing.BlockEvent{
+			ShardId: g.params.ShardId,
+			Id:      blockRes.Block.Id,
+			Block:   blockRes.Block,
+			Hash:    blockRes.BlockHash,
+		}, err)
+	}
+	return err
+}
+
+func (g *BlockGenerator) commitBlock(blockRes *BlockGenerationResult, params *types.ConsensusParams) error {
 	if err := g.executionState.CommitBlock(blockRes, params); err != nil {
 		return err
 	}
 
-	if err := PostprocessBlock(g.rwTx, g.params.ShardId, blockRes, g.params.ExecutionMode); err != nil {
+	if err := postprocessBlock(
+		g.rwTx, g.params.ShardId, blockRes, g.params.ExecutionMode, g.params.IndexAddressTransactions,
+	); err != nil {
 		return err
 	}
 
@@ -0,0 +1,255 @@
+package execution
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// DevCheatKind is the first byte of the data of a dev cheat transaction, it defines the modification of the state.
+type DevCheatKind byte
+
+const (
+	DevCheatSetBalance DevCheatKind = iota + 1
+	DevCheatSetCode
+	DevCheatSetStorage
+	DevCheatSetTokens
+	// DevCheatSetTimestamp sets the timestamp of the block, it has to be the first transaction of the block
+	DevCheatSetTimestamp
+)
+
+// DevCheats holds the state modifications requested by the development API for a shard
+// and the accounts whose external transactions are accepted without signature verification.
+// The modifications are applied by the system transactions that the proposer includes into the next block.
+// It's safe for concurrent use and may be nil, in which case the dev cheats are disabled.
+type DevCheats struct {
+	mutex        sync.Mutex
+	pending      []*types.Transaction   // +checklocks:mutex
+	impersonated map[types.Address]bool // +checklocks:mutex
+	nextSeqno    types.Seqno            // +checklocks:mutex
+	// nextTimestamp is the timestamp of the next block, it's ignored if it's zero
+	nextTimestamp uint64 // +checklocks:mutex
+}
+
+func NewDevCheats() *DevCheats {
+	return &DevCheats{
+		impersonated: make(map[types.Address]bool),
+		// The seqno makes the hashes of the same modifications unique, also after a restart of the node
+		nextSeqno: types.Seqno(time.Now().UnixNano()),
+	}
+}
+
+// IsDevCheat returns true if the transaction modifies the state on behalf of the development API.
+func IsDevCheat(txn *types.Transaction) bool {
+	return txn.IsInternal() && txn.From == types.DevCheatsAddress
+}
+
+// +checklocks:c.mutex
+func (c *DevCheats) newTxnLocked(address types.Address, kind DevCheatKind) *types.Transaction {
+	txn := &types.Transaction{
+		TransactionDigest: types.TransactionDigest{
+			Flags: types.NewTransactionFlags(types.TransactionFlagInternal),
+			To:    address,
+			Seqno: c.nextSeqno,
+			Data:  types.Code{byte(kind)},
+		},
+		From: types.DevCheatsAddress,
+	}
+	c.nextSeqno++
+	return txn
+}
+
+func (c *DevCheats) add(address types.Address, kind DevCheatKind, fill func(txn *types.Transaction)) common.Hash {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	txn := c.newTxnLocked(address, kind)
+	fill(txn)
+
+	c.pending = append(c.pending, txn)
+	return txn.Hash()
+}
+
+// SetBalance schedules setting the balance of the account. It returns the hash of the transaction applying it.
+func (c *DevCheats) SetBalance(address types.Address, balance types.Value) common.Hash {
+	return c.add(address, DevCheatSetBalance, func(txn *types.Transaction) {
+		txn.Value = balance
+	})
+}
+
+// SetCode schedules replacing the code of the account.
+func (c *DevCheats) SetCode(address types.Address, code types.Code) common.Hash {
+	return c.add(address, DevCheatSetCode, func(txn *types.Transaction) {
+		txn.Data = append(txn.Data, code...)
+	})
+}
+
+// SetStorageAt schedules setting the value of the storage slot of the account.
+func (c *DevCheats) SetStorageAt(address types.Address, key common.Hash, value common.Hash) common.Hash {
+	return c.add(address, DevCheatSetStorage, func(txn *types.Transaction) {
+		txn.Data = append(append(txn.Data, key.Bytes()...), value.Bytes()...)
+	})
+}
+
+// SetTokens schedules setting the balances of the given tokens of the account. Other tokens aren't changed.
+func (c *DevCheats) SetTokens(address types.Address, tokens []types.TokenBalance) common.Hash {
+	return c.add(address, DevCheatSetTokens, func(txn *types.Transaction) {
+		txn.Token = tokens
+	})
+}
+
+// Impersonate makes external transactions to the account accepted without signature verification
+// or stops it if impersonate is false.
+func (c *DevCheats) Impersonate(address types.Address, impersonate bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if impersonate {
+		c.impersonated[address] = true
+	} else {
+		delete(c.impersonated, address)
+	}
+}
+
+func (c *DevCheats) IsImpersonated(address types.Address) bool {
+	if c == nil {
+		return false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.impersonated[address]
+}
+
+// SetNextBlockTimestamp sets the timestamp of the next block. The following blocks continue from it.
+func (c *DevCheats) SetNextBlockTimestamp(ts uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.nextTimestamp = ts
+}
+
+// TimestampCheat returns the transaction setting the timestamp of the next block or nil if it isn't set.
+// The timestamp is carried by the block, so that the block is reproduced with it on replay.
+func (c *DevCheats) TimestampCheat() *types.Transaction {
+	if c == nil {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.nextTimestamp == 0 {
+		return nil
+	}
+	txn := c.newTxnLocked(types.DevCheatsAddress, DevCheatSetTimestamp)
+	txn.Data = binary.BigEndian.AppendUint64(txn.Data, c.nextTimestamp)
+	return txn
+}
+
+// Pending returns the transactions which aren't committed yet.
+func (c *DevCheats) Pending() []*types.Transaction {
+	if c == nil {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]*types.Transaction(nil), c.pending...)
+}
+
+// OnCommitted removes the transactions committed in the block from the pending ones
+// and resets the timestamp of the next block if the block got it.
+func (c *DevCheats) OnCommitted(block *types.Block, txns []*types.Transaction) {
+	if c == nil {
+		return
+	}
+
+	committed := make(map[common.Hash]struct{})
+	for _, txn := range txns {
+		if IsDevCheat(txn) {
+			committed[txn.Hash()] = struct{}{}
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.nextTimestamp != 0 && c.nextTimestamp == block.Timestamp {
+		c.nextTimestamp = 0
+	}
+	if len(committed) == 0 {
+		return
+	}
+
+	pending := c.pending[:0]
+	for _, txn := range c.pending {
+		if _, ok := committed[txn.Hash()]; !ok {
+			pending = append(pending, txn)
+		}
+	}
+	clear(c.pending[len(pending):])
+	c.pending = pending
+}
+
+// HandleDevCheat applies the state modification of the dev cheat transaction.
+func (es *ExecutionState) HandleDevCheat(txn *types.Transaction) *ExecutionResult {
+	if es.devCheats == nil {
+		return NewExecutionResult().SetError(
+			types.NewWrapError(types.ErrorValidation, errors.New("dev cheats are disabled")))
+	}
+	if err := es.applyDevCheat(txn); err != nil {
+		return NewExecutionResult().SetError(types.KeepOrWrapError(types.ErrorValidation, err))
+	}
+	return NewExecutionResult()
+}
+
+func (es *ExecutionState) applyDevCheat(txn *types.Transaction) error {
+	if len(txn.Data) == 0 {
+		return errors.New("empty dev cheat")
+	}
+	if DevCheatKind(txn.Data[0]) == DevCheatSetTimestamp {
+		return es.applyTimestampCheat(txn.Data[1:])
+	}
+	acc, err := es.getOrNewAccount(txn.To)
+	if err != nil {
+		return err
+	}
+
+	payload := txn.Data[1:]
+	switch kind := DevCheatKind(txn.Data[0]); kind {
+	case DevCheatSetBalance:
+		acc.SetBalance(txn.Value)
+	case DevCheatSetCode:
+		acc.SetCode(types.Code(payload).Hash(), payload)
+	case DevCheatSetStorage:
+		if len(payload) != 2*common.HashSize {
+			return fmt.Errorf("invalid storage cheat size %d", len(payload))
+		}
+		key, value := payload[:common.HashSize], payload[common.HashSize:]
+		return acc.SetState(common.BytesToHash(key), common.BytesToHash(value))
+	case DevCheatSetTokens:
+		for _, token := range txn.Token {
+			acc.SetTokenBalance(token.Token, token.Balance)
+		}
+	default:
+		return fmt.Errorf("unknown dev cheat %d", kind)
+	}
+	return nil
+}
+
+// applyTimestampCheat sets the timestamp of the block. Only the first transaction of the block may set it,
+// so that all transactions of the block observe the same timestamp.
+func (es *ExecutionState) applyTimestampCheat(payload []byte) error {
+	if len(es.InTransactions) != 1 {
+		return errors.New("timestamp cheat is not the first transaction of the block")
+	}
+	if len(payload) != 8 {
+		return fmt.Errorf("invalid timestamp cheat size %d", len(payload))
+	}
+	es.Timestamp = binary.BigEndian.Uint64(payload)
+	return nil
+}
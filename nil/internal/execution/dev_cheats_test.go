@@ -0,0 +1,134 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDevCheats(t *testing.T) {
+	t.Parallel()
+
+	const shardId = types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	addr := types.GenerateRandomAddress(shardId)
+	tokenId := types.TokenId(types.EthFaucetAddress)
+	key := common.IntToHash(1)
+	code := types.Code("some code")
+
+	cheats := NewDevCheats()
+	hash1 := cheats.SetBalance(addr, types.NewValueFromUint64(100))
+	hash2 := cheats.SetBalance(addr, types.NewValueFromUint64(100))
+	assert.NotEqual(t, hash1, hash2)
+	cheats.SetCode(addr, code)
+	cheats.SetStorageAt(addr, key, common.IntToHash(42))
+	cheats.SetTokens(addr, []types.TokenBalance{{Token: tokenId, Balance: types.NewValueFromUint64(7)}})
+
+	pending := cheats.Pending()
+	require.Len(t, pending, 5)
+
+	// The cheats are rejected if they are disabled
+	es, err := NewExecutionState(tx, shardId, StateParams{ConfigAccessor: config.GetStubAccessor()})
+	require.NoError(t, err)
+	res := es.HandleDevCheat(pending[0])
+	require.True(t, res.Failed())
+	assert.Equal(t, types.ErrorValidation, res.Error.Code())
+
+	es, err = NewExecutionState(tx, shardId, StateParams{
+		ConfigAccessor: config.GetStubAccessor(),
+		DevCheats:      cheats,
+	})
+	require.NoError(t, err)
+	for _, txn := range pending {
+		require.True(t, IsDevCheat(txn))
+		res := es.HandleDevCheat(txn)
+		require.False(t, res.Failed(), res.Error)
+	}
+
+	balance, err := es.GetBalance(addr)
+	require.NoError(t, err)
+	assert.Equal(t, types.NewValueFromUint64(100), balance)
+	gotCode, _, err := es.GetCode(addr)
+	require.NoError(t, err)
+	assert.Equal(t, code, types.Code(gotCode))
+	value, err := es.GetState(addr, key)
+	require.NoError(t, err)
+	assert.Equal(t, common.IntToHash(42), value)
+	acc, err := es.GetAccount(addr)
+	require.NoError(t, err)
+	assert.Equal(t, types.NewValueFromUint64(7), *acc.GetTokenBalance(tokenId))
+
+	cheats.OnCommitted(&types.Block{}, pending[:2])
+	assert.Len(t, cheats.Pending(), 3)
+
+	assert.False(t, cheats.IsImpersonated(addr))
+	cheats.Impersonate(addr, true)
+	assert.True(t, cheats.IsImpersonated(addr))
+	cheats.Impersonate(addr, false)
+	assert.False(t, cheats.IsImpersonated(addr))
+}
+
+func TestDevCheatTimestamp(t *testing.T) {
+	t.Parallel()
+
+	const shardId = types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	cheats := NewDevCheats()
+	require.Nil(t, cheats.TimestampCheat())
+	cheats.SetNextBlockTimestamp(1000)
+	txn := cheats.TimestampCheat()
+	require.NotNil(t, txn)
+
+	newState := func() *ExecutionState {
+		t.Helper()
+
+		es, err := NewExecutionState(tx, shardId, StateParams{
+			ConfigAccessor: config.GetStubAccessor(),
+			DevCheats:      NewDevCheats(),
+		})
+		require.NoError(t, err)
+		return es
+	}
+
+	// The timestamp is set by the first transaction of the block
+	es := newState()
+	es.AddInTransaction(txn)
+	res := es.HandleDevCheat(txn)
+	require.False(t, res.Failed(), res.Error)
+	assert.Equal(t, uint64(1000), es.Timestamp)
+
+	// The transactions before it would observe another timestamp
+	es = newState()
+	cheats.SetBalance(types.GenerateRandomAddress(shardId), types.NewValueFromUint64(1))
+	first := cheats.Pending()[0]
+	es.AddInTransaction(first)
+	require.False(t, es.HandleDevCheat(first).Failed())
+	es.AddInTransaction(txn)
+	res = es.HandleDevCheat(txn)
+	require.True(t, res.Failed())
+	assert.Equal(t, uint64(0), es.Timestamp)
+
+	cheats.OnCommitted(&types.Block{BlockData: types.BlockData{Timestamp: 1000}}, nil)
+	assert.Nil(t, cheats.TimestampCheat())
+}
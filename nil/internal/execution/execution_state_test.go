@@ -384,6 +384,59 @@ func TestAccountState(t *testing.T) {
 	assert.Equal(t, balance, acc.Balance)
 }
 
+func TestEstimateVerifyExternalGas(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	es, err := NewExecutionState(tx, types.BaseShardId, StateParams{ConfigAccessor: config.GetStubAccessor()})
+	require.NoError(t, err)
+	es.BaseFee = types.DefaultGasPrice
+
+	// The account copies the calldata to the memory and accepts any signature
+	code := []byte{
+		byte(vm.CALLDATASIZE),
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.CALLDATACOPY),
+		byte(vm.PUSH1), 1,
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	}
+	addr := types.GenerateRandomAddress(types.BaseShardId)
+	require.NoError(t, es.CreateAccount(addr))
+	require.NoError(t, es.SetCode(addr, code))
+	require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(1_000_000_000_000)))
+	account, err := es.GetAccount(addr)
+	require.NoError(t, err)
+
+	txn := types.NewEmptyTransaction()
+	txn.To = addr
+	txn.From = addr
+	txn.Data = []byte{1, 2, 3, 4}
+	txn.MaxFeePerGas = types.MaxFeePerGasDefault
+
+	gas, err := es.EstimateVerifyExternalGas(txn, account)
+	require.NoError(t, err)
+
+	// The estimation of the unsigned transaction matches the verification of the signed one
+	signed := *txn
+	signed.Signature = make(types.Signature, common.SignatureSize)
+	signed.Signature[0] = 1
+	res := es.CallVerifyExternal(&signed, account)
+	require.False(t, res.Failed(), res.Error)
+	assert.Equal(t, res.GasUsed, gas)
+}
+
 func (s *SuiteExecutionState) TestTransactionStatus() {
 	shardId := types.ShardId(5)
 	var vmErrStub *types.VmError
@@ -706,3 +759,72 @@ func BenchmarkBlockGeneration(b *testing.B) {
 		tx.Rollback()
 	}
 }
+
+// BenchmarkSmartAccountBlock generates a block of SmartAccount transactions with the code cache
+// kept between the blocks and with the cache dropped before every block.
+func BenchmarkSmartAccountBlock(b *testing.B) {
+	ctx := b.Context()
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(b, err)
+	defer database.Close()
+	logging.SetupGlobalLogger("error")
+
+	GenerateZeroState(b, types.MainShardId, database)
+	GenerateZeroState(b, types.BaseShardId, database)
+
+	payload := &types.InternalTransactionPayload{
+		To:        types.MainSmartAccountAddress,
+		FeeCredit: types.Gas(100_000).ToValue(types.DefaultGasPrice),
+		Kind:      types.ExecutionTransactionKind,
+	}
+	payloadData, err := payload.MarshalSSZ()
+	require.NoError(b, err)
+	callData, err := contracts.NewCallData(contracts.NameSmartAccount, "send", payloadData)
+	require.NoError(b, err)
+
+	txn := NewExecutionTransaction(types.MainSmartAccountAddress, types.MainSmartAccountAddress, 0, callData)
+	txn.Flags = types.NewTransactionFlags(types.TransactionFlagInternal)
+	txn.RefundTo = types.MainSmartAccountAddress
+
+	proposal := &Proposal{}
+	for range 1000 {
+		proposal.InternalTxns = append(proposal.InternalTxns, txn)
+	}
+
+	readLastBlockHash := func(b *testing.B) common.Hash {
+		b.Helper()
+
+		tx, err := database.CreateRoTx(ctx)
+		require.NoError(b, err)
+		defer tx.Rollback()
+
+		hash, err := db.ReadLastBlockHash(tx, types.BaseShardId)
+		require.NoError(b, err)
+		return hash
+	}
+
+	params := NewBlockGeneratorParams(types.BaseShardId, 2)
+	run := func(b *testing.B, purge bool) {
+		b.Helper()
+
+		for range b.N {
+			if purge {
+				vm.SharedCodeCache.Purge()
+			}
+
+			proposal.PrevBlockHash = readLastBlockHash(b)
+
+			gen, err := NewBlockGenerator(ctx, params, database, nil)
+			require.NoError(b, err)
+			_, err = gen.GenerateBlock(proposal, &types.ConsensusParams{})
+			require.NoError(b, err)
+		}
+	}
+
+	b.Run("Cached", func(b *testing.B) {
+		run(b, false)
+	})
+	b.Run("Uncached", func(b *testing.B) {
+		run(b, true)
+	})
+}
@@ -16,6 +16,7 @@ type IRevertableExecutionState interface {
 	SetRefund(value uint64)
 	DeleteLog(txHash common.Hash)
 	SetTransientNoJournal(addr types.Address, key common.Hash, prevValue common.Hash)
+	SetRequestTimeoutNoJournal(caller types.Address, timeout uint64)
 	DeleteOutTransaction(index int, txnHash common.Hash)
 }
 
@@ -126,6 +127,10 @@ type (
 		account   *types.Address
 		requestId types.TransactionIndex
 	}
+	requestTimeoutChange struct {
+		account *types.Address
+		prev    uint64
+	}
 )
 
 func (ch createAccountChange) revert(s IRevertableExecutionState) {
@@ -184,6 +189,10 @@ func (ch asyncContextChange) revert(s IRevertableExecutionState) {
 	reverter{s}.revertAsyncContextChange(*ch.account, ch.requestId)
 }
 
+func (ch requestTimeoutChange) revert(s IRevertableExecutionState) {
+	reverter{s}.revertRequestTimeoutChange(*ch.account, ch.prev)
+}
+
 type reverter struct {
 	es IRevertableExecutionState
 }
@@ -283,3 +292,7 @@ func (w reverter) revertAsyncContextChange(addr types.Address, requestId types.T
 		delete(account.AsyncContext, requestId)
 	}
 }
+
+func (w reverter) revertRequestTimeoutChange(addr types.Address, prevTimeout uint64) {
+	w.es.SetRequestTimeoutNoJournal(addr, prevTimeout)
+}
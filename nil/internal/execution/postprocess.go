@@ -11,18 +11,33 @@ import (
 )
 
 func PostprocessBlock(tx db.RwTx, shardId types.ShardId, blockResult *BlockGenerationResult, mode string) error {
+	return postprocessBlock(tx, shardId, blockResult, mode, false)
+}
+
+// PostprocessBlockWithAddressIndex does the same as PostprocessBlock and
+// also puts transactions of the block into the address transaction index.
+func PostprocessBlockWithAddressIndex(
+	tx db.RwTx, shardId types.ShardId, blockResult *BlockGenerationResult, mode string,
+) error {
+	return postprocessBlock(tx, shardId, blockResult, mode, true)
+}
+
+func postprocessBlock(
+	tx db.RwTx, shardId types.ShardId, blockResult *BlockGenerationResult, mode string, indexAddresses bool,
+) error {
 	if blockResult.Block == nil {
 		return errors.New("block is not set")
 	}
-	postprocessor := blockPostprocessor{tx, shardId, blockResult, mode}
+	postprocessor := blockPostprocessor{tx, shardId, blockResult, mode, indexAddresses}
 	return postprocessor.Postprocess()
 }
 
 type blockPostprocessor struct {
-	tx          db.RwTx
-	shardId     types.ShardId
-	blockResult *BlockGenerationResult
-	execMode    string
+	tx             db.RwTx
+	shardId        types.ShardId
+	blockResult    *BlockGenerationResult
+	execMode       string
+	indexAddresses bool
 }
 
 func (pp *blockPostprocessor) Postprocess() error {
@@ -30,6 +45,7 @@ func (pp *blockPostprocessor) Postprocess() error {
 		pp.fillLastBlockTable,
 		pp.fillBlockHashByNumberIndex,
 		pp.fillBlockHashAndTransactionIndexByTransactionHash,
+		pp.fillAddressTransactionIndex,
 	} {
 		if err := postpocessor(); err != nil {
 			return err
@@ -82,3 +98,17 @@ func (pp *blockPostprocessor) fillBlockHashAndTransactionIndexByTransactionHash(
 	}
 	return fill(pp.blockResult.OutTxnHashes, db.BlockHashAndOutTransactionIndexByTransactionHash)
 }
+
+func (pp *blockPostprocessor) fillAddressTransactionIndex() error {
+	if !pp.indexAddresses {
+		return nil
+	}
+	return indexAddressTransactions(
+		pp.tx,
+		pp.shardId,
+		pp.blockResult.Block.Id,
+		pp.blockResult.InTxns,
+		pp.blockResult.InTxnHashes,
+		pp.blockResult.OutTxns,
+		pp.blockResult.OutTxnHashes)
+}
@@ -0,0 +1,305 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+const (
+	// DefaultPrefetchWorkers is the default number of goroutines loading the state of transactions before execution.
+	DefaultPrefetchWorkers = 4
+	// DefaultAccessSetsSize is the number of contracts whose accessed storage slots are remembered.
+	DefaultAccessSetsSize = 4096
+
+	// maxAccessSetSize limits the number of storage slots prefetched for a contract.
+	maxAccessSetSize = 256
+	// prefetchQueueSize limits the number of accounts waiting to be prefetched.
+	prefetchQueueSize = 1024
+)
+
+// AccessSets keeps the storage slots accessed by the contracts during their previous executions,
+// so that the slots are prefetched when the contracts are called again.
+// It's safe for concurrent use and may be nil, in which case nothing is recorded.
+type AccessSets struct {
+	sets *lru.Cache[types.Address, []common.Hash]
+}
+
+func NewAccessSets(size int) *AccessSets {
+	sets, err := lru.New[types.Address, []common.Hash](size)
+	check.PanicIfErr(err)
+	return &AccessSets{sets: sets}
+}
+
+func (s *AccessSets) get(addr types.Address) []common.Hash {
+	if s == nil {
+		return nil
+	}
+	slots, _ := s.sets.Get(addr)
+	return slots
+}
+
+// Record remembers the storage slots accessed by the accounts of the shard in the execution state.
+func (s *AccessSets) Record(es *ExecutionState) {
+	if s == nil {
+		return
+	}
+	for addr, acc := range es.Accounts {
+		if addr.ShardId() != es.ShardId || len(acc.State) == 0 {
+			continue
+		}
+		slots := make([]common.Hash, 0, min(len(acc.State), maxAccessSetSize))
+		for key := range acc.State {
+			if len(slots) == maxAccessSetSize {
+				break
+			}
+			slots = append(slots, key)
+		}
+		s.sets.Add(addr, slots)
+	}
+}
+
+// PrefetchedState is a read-only cache of the committed state filled by the Prefetcher.
+// A value that isn't found in it is loaded by the execution state as usual.
+type PrefetchedState struct {
+	mutex sync.RWMutex
+	// contracts holds nil for the accounts that don't exist
+	contracts map[types.Address]*types.SmartContract
+	code      map[common.Hash]types.Code
+	storage   map[types.Address]map[common.Hash]common.Hash
+}
+
+func newPrefetchedState() *PrefetchedState {
+	return &PrefetchedState{
+		contracts: make(map[types.Address]*types.SmartContract),
+		code:      make(map[common.Hash]types.Code),
+		storage:   make(map[types.Address]map[common.Hash]common.Hash),
+	}
+}
+
+func (s *PrefetchedState) getContract(addr types.Address) (*types.SmartContract, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	contract, ok := s.contracts[addr]
+	return contract, ok
+}
+
+func (s *PrefetchedState) getCode(hash common.Hash) (types.Code, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	code, ok := s.code[hash]
+	return code, ok
+}
+
+func (s *PrefetchedState) getState(addr types.Address, key common.Hash) (common.Hash, bool) {
+	if s == nil {
+		return common.EmptyHash, false
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	value, ok := s.storage[addr][key]
+	return value, ok
+}
+
+func (s *PrefetchedState) setAccount(
+	addr types.Address, contract *types.SmartContract, code types.Code, storage map[common.Hash]common.Hash,
+) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.contracts[addr] = contract
+	if code != nil {
+		s.code[contract.CodeHash] = code
+	}
+	if len(storage) > 0 {
+		s.storage[addr] = storage
+	}
+}
+
+// Prefetcher concurrently loads the contracts, code and storage accessed by transactions before they are executed.
+// The values are read at the block the execution state is built on and shared with it via PrefetchedState,
+// so the execution doesn't wait for the disk reads of the accounts already loaded by the prefetcher.
+// The loaded trie nodes also stay in the node cache for the execution.
+type Prefetcher struct {
+	txFabric   db.DB
+	shardId    types.ShardId
+	root       common.Hash
+	accessSets *AccessSets
+	state      *PrefetchedState
+
+	mutex     sync.Mutex
+	requested map[types.Address]struct{}
+	queue     chan types.Address
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	logger logging.Logger
+}
+
+// NewPrefetcher starts the prefetching workers and makes the execution state read the prefetched values.
+// It must be created before the execution state loads any account. Stop must be called when the execution is done.
+func NewPrefetcher(
+	ctx context.Context,
+	txFabric db.DB,
+	es *ExecutionState,
+	accessSets *AccessSets,
+	workers int,
+) *Prefetcher {
+	ctx, cancel := context.WithCancel(ctx)
+	p := &Prefetcher{
+		txFabric:   txFabric,
+		shardId:    es.ShardId,
+		root:       es.ContractTree.RootHash(),
+		accessSets: accessSets,
+		state:      newPrefetchedState(),
+		requested:  make(map[types.Address]struct{}),
+		queue:      make(chan types.Address, prefetchQueueSize),
+		cancel:     cancel,
+		logger: logging.NewLogger("prefetcher").With().
+			Stringer(logging.FieldShardId, es.ShardId).
+			Logger(),
+	}
+	es.prefetched = p.state
+
+	p.wg.Add(workers)
+	for range workers {
+		go p.worker(ctx)
+	}
+	return p
+}
+
+// Prefetch schedules loading of the accounts of the shard the transactions are sent to and from.
+// It doesn't block: the accounts that don't fit the queue are loaded by the execution.
+func (p *Prefetcher) Prefetch(txns ...*types.Transaction) {
+	for _, txn := range txns {
+		p.enqueue(txn.To)
+		p.enqueue(txn.From)
+	}
+}
+
+func (p *Prefetcher) enqueue(addr types.Address) {
+	if addr.ShardId() != p.shardId {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if _, ok := p.requested[addr]; ok {
+		return
+	}
+	select {
+	case p.queue <- addr:
+		p.requested[addr] = struct{}{}
+	default:
+	}
+}
+
+// Stop cancels the prefetching and waits for the workers to finish.
+// The values loaded so far stay available to the execution state.
+func (p *Prefetcher) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *Prefetcher) worker(ctx context.Context) {
+	defer p.wg.Done()
+
+	tx, err := p.txFabric.CreateRoTx(ctx)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("Failed to create transaction for prefetching")
+		return
+	}
+	defer tx.Rollback()
+
+	snapshot, err := NewStateSnapshot(tx, p.shardId, p.root)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("Failed to open state snapshot for prefetching")
+		return
+	}
+	contractTrie := NewDbContractTrieReader(tx, p.shardId)
+	contractTrie.SetRootHash(p.root)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case addr := <-p.queue:
+			if err := p.prefetchAccount(tx, snapshot, contractTrie, addr); err != nil {
+				p.logger.Debug().Err(err).Stringer(logging.FieldTransactionTo, addr).Msg("Failed to prefetch account")
+			}
+		}
+	}
+}
+
+func (p *Prefetcher) prefetchAccount(
+	tx db.RoTx, snapshot *StateSnapshot, contractTrie *ContractTrieReader, addr types.Address,
+) error {
+	var contract *types.SmartContract
+	var err error
+	if snapshot != nil {
+		contract, err = snapshot.GetContract(addr)
+	} else {
+		contract, err = contractTrie.Fetch(addr.Hash())
+	}
+	// The absence is cached only if the lookup proves it, a missing trie node leaves the account to the execution
+	if errors.Is(err, db.ErrKeyNotFound) && !errors.Is(err, mpt.ErrMissingNode) {
+		p.state.setAccount(addr, nil, nil, nil)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	code, err := readCode(tx, p.shardId, contract.CodeHash)
+	if err != nil {
+		return err
+	}
+
+	slots := p.accessSets.get(addr)
+	storage := make(map[common.Hash]common.Hash, len(slots))
+	storageTrie := NewDbStorageTrieReader(tx, p.shardId)
+	storageTrie.SetRootHash(contract.StorageRoot)
+	for _, key := range slots {
+		if snapshot != nil {
+			storage[key], err = snapshot.GetState(addr, key)
+		} else {
+			storage[key], err = fetchStorageValue(storageTrie, key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	p.state.setAccount(addr, contract, code, storage)
+	return nil
+}
+
+func fetchStorageValue(trie *StorageTrieReader, key common.Hash) (common.Hash, error) {
+	res, err := trie.Fetch(key)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return common.EmptyHash, nil
+	}
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return res.Bytes32(), nil
+}
@@ -0,0 +1,108 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefetcher(t *testing.T) {
+	t.Parallel()
+
+	const shardId = types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	addr := types.GenerateRandomAddress(shardId)
+	missing := types.GenerateRandomAddress(shardId)
+	key := common.IntToHash(1)
+	code := types.Code("some code")
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	es, err := NewExecutionState(tx, shardId, StateParams{ConfigAccessor: config.GetStubAccessor()})
+	require.NoError(t, err)
+	require.NoError(t, es.CreateAccount(addr))
+	require.NoError(t, es.SetCode(addr, code))
+	require.NoError(t, es.SetState(addr, key, common.IntToHash(42)))
+	res, err := es.Commit(0, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.WriteLastBlockHash(tx, shardId, res.BlockHash))
+	require.NoError(t, tx.Commit())
+
+	accessSets := NewAccessSets(16)
+	accessSets.Record(es)
+	assert.Equal(t, []common.Hash{key}, accessSets.get(addr))
+
+	tx, err = database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	es, err = NewExecutionState(tx, shardId, StateParams{Block: res.Block, ConfigAccessor: config.GetStubAccessor()})
+	require.NoError(t, err)
+
+	prefetcher := NewPrefetcher(t.Context(), database, es, accessSets, 2)
+	txn := &types.Transaction{TransactionDigest: types.TransactionDigest{To: addr}, From: missing}
+	prefetcher.Prefetch(txn)
+	require.Eventually(t, func() bool {
+		_, okAddr := es.prefetched.getContract(addr)
+		_, okMissing := es.prefetched.getContract(missing)
+		return okAddr && okMissing
+	}, 5*time.Second, 10*time.Millisecond)
+	prefetcher.Stop()
+
+	value, ok := es.prefetched.getState(addr, key)
+	require.True(t, ok)
+	assert.Equal(t, common.IntToHash(42), value)
+
+	account, err := es.GetAccount(addr)
+	require.NoError(t, err)
+	assert.Equal(t, code, account.Code)
+	value, err = es.GetState(addr, key)
+	require.NoError(t, err)
+	assert.Equal(t, common.IntToHash(42), value)
+
+	account, err = es.GetAccount(missing)
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	// The prefetched values are dropped once the block is built
+	_, err = es.BuildBlock(1)
+	require.NoError(t, err)
+	assert.Nil(t, es.prefetched)
+}
+
+func TestPrefetcherMissingTrieNode(t *testing.T) {
+	t.Parallel()
+
+	const shardId = types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRoTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	p := &Prefetcher{shardId: shardId, state: newPrefetchedState()}
+	contractTrie := NewDbContractTrieReader(tx, shardId)
+	contractTrie.SetRootHash(common.HexToHash("0x1234"))
+
+	// The lookup fails without proving that the account is absent, so nothing is cached
+	addr := types.GenerateRandomAddress(shardId)
+	require.ErrorIs(t, p.prefetchAccount(tx, nil, contractTrie, addr), mpt.ErrMissingNode)
+	_, ok := p.state.getContract(addr)
+	assert.False(t, ok)
+}
@@ -63,7 +63,8 @@ type ProposalSSZ struct {
 
 	ExternalTxns []*types.Transaction `ssz-max:"4096"`
 
-	// SpecialTxns are internal transactions produced by the collator. They appear only on the main shard.
+	// SpecialTxns are internal transactions produced by the collator. They appear only on the main shard,
+	// except for the dev cheats applied on any shard of development networks.
 	SpecialTxns []*types.Transaction `ssz-max:"4096"`
 }
 
@@ -0,0 +1,170 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// MaxRequestTimeoutsInBlock is the maximum number of the requests timed out by a single block.
+const MaxRequestTimeoutsInBlock = 100
+
+// ExpiredRequest is a pending request of a contract of the shard, which isn't responded before its deadline.
+type ExpiredRequest struct {
+	db.RequestDeadline
+	AsyncContext *types.AsyncContext
+}
+
+// IsRequestTimeout returns true if the transaction is the failed response to the request timed out by its deadline.
+func IsRequestTimeout(txn *types.Transaction) bool {
+	return txn.IsInternal() && txn.From == types.RequestTimeoutAddress
+}
+
+// NewRequestTimeoutTransaction returns the failed response to the request of the caller, which isn't responded
+// before its deadline. It's processed as the ordinary response: the execution of the await request is resumed
+// or the callback is called, the gas reserved for the response pays for it.
+func NewRequestTimeoutTransaction(
+	caller types.Address, requestId types.TransactionIndex, asyncContext *types.AsyncContext,
+) (*types.Transaction, error) {
+	data, err := (&types.AsyncResponsePayload{Success: false}).MarshalSSZ()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := types.NewEmptyTransaction()
+	txn.Flags = types.NewTransactionFlags(types.TransactionFlagInternal, types.TransactionFlagResponse)
+	txn.To = caller
+	txn.RefundTo = caller
+	txn.MaxFeePerGas = types.MaxFeePerGasDefault
+	txn.Data = data
+	txn.From = types.RequestTimeoutAddress
+	txn.RequestId = uint64(requestId)
+	if len(asyncContext.RequestChain) > 0 {
+		txn.RequestChain = asyncContext.RequestChain
+	}
+	return txn, nil
+}
+
+// HandleRequestTimeout fails the expired request of the contract with the synthesized response.
+func (es *ExecutionState) HandleRequestTimeout(ctx context.Context, txn *types.Transaction) *ExecutionResult {
+	if err := es.validateRequestTimeout(txn); err != nil {
+		return NewExecutionResult().SetError(types.KeepOrWrapError(types.ErrorValidation, err))
+	}
+	return es.HandleTransaction(ctx, txn, NewTransactionPayer(txn, es))
+}
+
+// validateRequestTimeout checks that the request is pending and expired, and the transaction is its failed response.
+func (es *ExecutionState) validateRequestTimeout(txn *types.Transaction) error {
+	acc, err := es.GetAccount(txn.To)
+	if err != nil {
+		return err
+	}
+	if acc == nil {
+		return fmt.Errorf("account %s doesn't exist", txn.To)
+	}
+	requestId := types.TransactionIndex(txn.RequestId)
+	asyncContext, err := acc.GetAsyncContext(requestId)
+	if err != nil {
+		return fmt.Errorf("request %d of %s isn't pending: %w", requestId, txn.To, err)
+	}
+
+	height, err := es.MainShardHeight()
+	if err != nil {
+		return err
+	}
+	if asyncContext.Deadline == 0 || asyncContext.Deadline > height {
+		return fmt.Errorf("request %d of %s isn't expired", requestId, txn.To)
+	}
+
+	expected, err := NewRequestTimeoutTransaction(txn.To, requestId, asyncContext)
+	if err != nil {
+		return err
+	}
+	if txn.Hash() != expected.Hash() {
+		return errors.New("transaction doesn't match the timed out request")
+	}
+	return nil
+}
+
+// GetExpiredRequests returns up to limit pending requests of the shard's contracts expired by the main shard height
+// in the order of their deadlines. It also returns the stale index entries met on the way, i.e. the entries of
+// the requests which aren't pending with the indexed deadline. They are left behind by the blocks reverted
+// in the development mode, and the id of a reverted request may be reused by another one.
+func (es *ExecutionState) GetExpiredRequests(limit int) ([]ExpiredRequest, []db.RequestDeadline, error) {
+	if es.ShardId.IsMainShard() && es.PrevBlock.Empty() ||
+		!es.ShardId.IsMainShard() && es.MainShardHash.Empty() {
+		// The main shard has no blocks yet, so no deadline is reached
+		return nil, nil, nil
+	}
+	height, err := es.MainShardHeight()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var expired []ExpiredRequest
+	var stale []db.RequestDeadline
+	if limit <= 0 {
+		return expired, stale, nil
+	}
+	if err := db.ForEachExpiredRequest(es.tx, es.ShardId, height, func(r db.RequestDeadline) (bool, error) {
+		asyncContext, err := es.getPendingRequest(r)
+		if err != nil {
+			return false, err
+		}
+		if asyncContext == nil {
+			stale = append(stale, r)
+			return true, nil
+		}
+		expired = append(expired, ExpiredRequest{RequestDeadline: r, AsyncContext: asyncContext})
+		return len(expired) < limit, nil
+	}); err != nil {
+		return nil, nil, err
+	}
+	return expired, stale, nil
+}
+
+// getPendingRequest returns the context of the request indexed by its deadline
+// or nil if the request isn't pending with this deadline.
+func (es *ExecutionState) getPendingRequest(r db.RequestDeadline) (*types.AsyncContext, error) {
+	acc, err := es.GetAccount(r.Caller)
+	if err != nil {
+		return nil, err
+	}
+	if acc == nil {
+		return nil, nil
+	}
+	asyncContext, err := acc.GetAsyncContext(r.RequestId)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if asyncContext.Deadline != r.Deadline {
+		return nil, nil
+	}
+	return asyncContext, nil
+}
+
+// deleteStaleRequestDeadlines removes the stale entries of the deadline index on the block commit,
+// so that they don't hold back the expired requests. The requests are checked against the state
+// of the committed block, thus the ones responded by the block are removed as well.
+func (es *ExecutionState) deleteStaleRequestDeadlines() error {
+	_, stale, err := es.GetExpiredRequests(MaxRequestTimeoutsInBlock)
+	if err != nil {
+		return err
+	}
+	for _, r := range stale {
+		if err := db.DeleteRequestDeadline(es.tx, es.ShardId, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isResponseToUnknownRequest(res *ExecutionResult) bool {
+	return res.Error != nil && res.Error.Code() == types.ErrorResponseToUnknownRequest
+}
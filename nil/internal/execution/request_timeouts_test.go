@@ -0,0 +1,244 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	const shardId = types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	mainBlock := &types.Block{BlockData: types.BlockData{Id: 5}}
+	mainBlockHash := mainBlock.Hash(types.MainShardId)
+	require.NoError(t, db.WriteBlock(tx, types.MainShardId, mainBlockHash, mainBlock))
+
+	newState := func(block *types.Block) *ExecutionState {
+		t.Helper()
+
+		es, err := NewExecutionState(tx, shardId, StateParams{Block: block, ConfigAccessor: config.GetStubAccessor()})
+		require.NoError(t, err)
+		es.BaseFee = types.DefaultGasPrice
+		es.MainShardHash = mainBlockHash
+		return es
+	}
+
+	caller := types.GenerateRandomAddress(shardId)
+	responder := types.GenerateRandomAddress(types.ShardId(2))
+
+	es := newState(nil)
+	require.NoError(t, es.CreateAccount(caller))
+	acc, err := es.GetAccount(caller)
+	require.NoError(t, err)
+	acc.SetAsyncContext(1, &types.AsyncContext{
+		Data:                  types.NewCallbackContextData([4]byte{1, 2, 3, 4}, nil),
+		ResponseProcessingGas: 50_000,
+		Deadline:              5,
+	})
+	acc.SetAsyncContext(2, &types.AsyncContext{
+		Data:                  types.NewCallbackContextData([4]byte{1, 2, 3, 4}, nil),
+		ResponseProcessingGas: 50_000,
+		Deadline:              6,
+	})
+	blockRes, err := es.Commit(0, nil)
+	require.NoError(t, err)
+
+	expired, err := db.ReadExpiredRequests(tx, shardId, 5, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []db.RequestDeadline{{Deadline: 5, Caller: caller, RequestId: 1}}, expired)
+
+	es = newState(blockRes.Block)
+	height, err := es.MainShardHeight()
+	require.NoError(t, err)
+	assert.Equal(t, types.BlockNumber(5), height)
+
+	// The deadlines can't be checked against the unknown main shard block
+	unknown := newState(blockRes.Block)
+	unknown.MainShardHash = common.EmptyHash
+	_, err = unknown.MainShardHeight()
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+	acc, err = es.GetAccount(caller)
+	require.NoError(t, err)
+
+	t.Run("NotExpired", func(t *testing.T) {
+		asyncContext, err := acc.GetAsyncContext(2)
+		require.NoError(t, err)
+		txn, err := NewRequestTimeoutTransaction(caller, 2, asyncContext)
+		require.NoError(t, err)
+
+		es.AddInTransaction(txn)
+		res := es.HandleRequestTimeout(t.Context(), txn)
+		require.True(t, res.Failed())
+		assert.Equal(t, types.ErrorValidation, res.Error.Code())
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		asyncContext, err := acc.GetAsyncContext(1)
+		require.NoError(t, err)
+		txn, err := NewRequestTimeoutTransaction(caller, 1, asyncContext)
+		require.NoError(t, err)
+		require.True(t, IsRequestTimeout(txn))
+
+		es.AddInTransaction(txn)
+		res := es.HandleRequestTimeout(t.Context(), txn)
+		require.False(t, res.Failed(), res.Error)
+
+		_, err = acc.GetAsyncContext(1)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+		// The timeout can't be delivered twice
+		es.AddInTransactionWithHash(txn, types.GenerateRandomAddress(shardId).Hash())
+		res = es.HandleRequestTimeout(t.Context(), txn)
+		require.True(t, res.Failed())
+		assert.Equal(t, types.ErrorValidation, res.Error.Code())
+	})
+
+	t.Run("LateResponse", func(t *testing.T) {
+		data, err := (&types.AsyncResponsePayload{Success: true}).MarshalSSZ()
+		require.NoError(t, err)
+		txn := types.NewEmptyTransaction()
+		txn.Flags = types.NewTransactionFlags(types.TransactionFlagInternal, types.TransactionFlagResponse)
+		txn.From = responder
+		txn.To = caller
+		txn.RefundTo = responder
+		txn.MaxFeePerGas = types.MaxFeePerGasDefault
+		txn.RequestId = 1
+		txn.Data = data
+
+		es.AddInTransaction(txn)
+		res := es.HandleTransaction(t.Context(), txn, NewTransactionPayer(txn, es))
+		require.NoError(t, res.FatalError)
+		require.True(t, res.Failed())
+		assert.Equal(t, types.ErrorResponseToUnknownRequest, res.Error.Code())
+	})
+
+	_, err = acc.Commit()
+	require.NoError(t, err)
+
+	expired, err = db.ReadExpiredRequests(tx, shardId, 100, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []db.RequestDeadline{{Deadline: 6, Caller: caller, RequestId: 2}}, expired)
+
+	// The stale entries are removed when the block is committed
+	for _, r := range []db.RequestDeadline{
+		{Deadline: 1, Caller: types.GenerateRandomAddress(shardId), RequestId: 1},
+		{Deadline: 5, Caller: caller, RequestId: 2},
+	} {
+		require.NoError(t, db.WriteRequestDeadline(tx, shardId, r))
+	}
+	_, err = newState(blockRes.Block).Commit(blockRes.Block.Id+1, nil)
+	require.NoError(t, err)
+
+	expired, err = db.ReadExpiredRequests(tx, shardId, 100, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []db.RequestDeadline{{Deadline: 6, Caller: caller, RequestId: 2}}, expired)
+}
+
+func TestRequestTimeoutRevert(t *testing.T) {
+	t.Parallel()
+
+	const timeout = 5
+
+	selector := crypto.Keccak256([]byte("precompileSetRequestTimeout(uint256)"))[:4]
+
+	// The inner contract sets the request timeout and then stops or reverts
+	innerCode := func(revert bool) []byte {
+		code := []byte{byte(vm.PUSH4)}
+		code = append(code, selector...)
+		code = append(code,
+			byte(vm.PUSH1), 0xe0,
+			byte(vm.SHL),
+			byte(vm.PUSH1), 0,
+			byte(vm.MSTORE), // selector
+			byte(vm.PUSH1), timeout,
+			byte(vm.PUSH1), 4,
+			byte(vm.MSTORE),   // timeout
+			byte(vm.PUSH1), 0, // retSize
+			byte(vm.PUSH1), 0, // retOffset
+			byte(vm.PUSH1), 36, // argSize
+			byte(vm.PUSH1), 0, // argOffset
+			byte(vm.PUSH1), 0, // value
+			byte(vm.PUSH20),
+		)
+		code = append(code, vm.SetRequestTimeoutAddress.Bytes()...)
+		code = append(code, byte(vm.GAS), byte(vm.CALL), byte(vm.POP))
+		if revert {
+			return append(code, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.REVERT))
+		}
+		return append(code, byte(vm.STOP))
+	}
+
+	// The outer contract calls the inner one and ignores its failure
+	outerCode := func(inner types.Address) []byte {
+		code := []byte{
+			byte(vm.PUSH1), 0, // retSize
+			byte(vm.PUSH1), 0, // retOffset
+			byte(vm.PUSH1), 0, // argSize
+			byte(vm.PUSH1), 0, // argOffset
+			byte(vm.PUSH1), 0, // value
+			byte(vm.PUSH20),
+		}
+		code = append(code, inner.Bytes()...)
+		return append(code, byte(vm.GAS), byte(vm.CALL), byte(vm.POP), byte(vm.STOP))
+	}
+
+	check := func(t *testing.T, revert bool, expected uint64) {
+		t.Helper()
+
+		database, err := db.NewBadgerDbInMemory()
+		require.NoError(t, err)
+		defer database.Close()
+
+		tx, err := database.CreateRwTx(t.Context())
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		state, err := NewExecutionState(tx, types.BaseShardId, StateParams{ConfigAccessor: config.GetStubAccessor()})
+		require.NoError(t, err)
+
+		outer := types.GenerateRandomAddress(types.BaseShardId)
+		inner := types.GenerateRandomAddress(types.BaseShardId)
+		require.NoError(t, state.CreateAccount(outer))
+		require.NoError(t, state.SetCode(outer, outerCode(inner)))
+		require.NoError(t, state.CreateAccount(inner))
+		require.NoError(t, state.SetCode(inner, innerCode(revert)))
+
+		require.NoError(t, state.newVm(true, outer, nil))
+		defer state.resetVm()
+
+		_, _, err = state.evm.Call(vm.AccountRef(outer), outer, nil, 1_000_000, new(uint256.Int))
+		require.NoError(t, err)
+		assert.Equal(t, expected, state.requestTimeouts[inner])
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		check(t, false, timeout)
+	})
+
+	t.Run("Revert", func(t *testing.T) {
+		t.Parallel()
+
+		check(t, true, 0)
+	})
+}
@@ -0,0 +1,128 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// RevertToBlock makes the block with the given hash the last block of the shard.
+// The blocks following it are removed from the indexes filled by the postprocessing,
+// while their data stays in the database unreachable from the last block.
+func RevertToBlock(tx db.RwTx, shardId types.ShardId, hash common.Hash) error {
+	currHash, err := db.ReadLastBlockHash(tx, shardId)
+	if err != nil {
+		return err
+	}
+
+	var responses []*types.Transaction
+	for currHash != hash {
+		block, err := db.ReadBlock(tx, shardId, currHash)
+		if err != nil {
+			return err
+		}
+		if block.Id == 0 {
+			return fmt.Errorf("block %s is not found in the chain of shard %d", hash, shardId)
+		}
+		inTxns, err := removeBlockFromIndexes(tx, shardId, block)
+		if err != nil {
+			return fmt.Errorf("failed to remove block %d from indexes: %w", block.Id, err)
+		}
+		for _, txn := range inTxns {
+			if txn.IsResponse() {
+				responses = append(responses, txn)
+			}
+		}
+		currHash = block.PrevBlock
+	}
+
+	if err := restoreRequestDeadlines(tx, shardId, hash, responses); err != nil {
+		return fmt.Errorf("failed to restore request deadlines: %w", err)
+	}
+	return db.WriteLastBlockHash(tx, shardId, hash)
+}
+
+// removeBlockFromIndexes removes the block from the indexes and returns its incoming transactions.
+func removeBlockFromIndexes(tx db.RwTx, shardId types.ShardId, block *types.Block) ([]*types.Transaction, error) {
+	inTxns, inTxnHashes, err := readTransactionTrie(tx, shardId, block.InTransactionsRoot)
+	if err != nil {
+		return nil, err
+	}
+	outTxns, outTxnHashes, err := readTransactionTrie(tx, shardId, block.OutTransactionsRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.DeleteFromShard(shardId, db.BlockHashByNumberIndex, block.Id.Bytes()); err != nil {
+		return nil, err
+	}
+	for _, hash := range inTxnHashes {
+		if err := tx.DeleteFromShard(shardId, db.BlockHashAndInTransactionIndexByTransactionHash, hash.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	for _, hash := range outTxnHashes {
+		if err := tx.DeleteFromShard(shardId, db.BlockHashAndOutTransactionIndexByTransactionHash, hash.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if err := unindexAddressTransactions(tx, shardId, block.Id, inTxns, inTxnHashes, outTxns, outTxnHashes); err != nil {
+		return nil, err
+	}
+	return inTxns, nil
+}
+
+// restoreRequestDeadlines writes back the deadlines of the requests responded in the reverted blocks,
+// since they are pending again in the state of the block with the given hash.
+// The deadlines of the requests sent in the reverted blocks are left behind, they are removed on the next commit.
+func restoreRequestDeadlines(
+	tx db.RwTx, shardId types.ShardId, hash common.Hash, responses []*types.Transaction,
+) error {
+	if len(responses) == 0 {
+		return nil
+	}
+
+	block, err := db.ReadBlock(tx, shardId, hash)
+	if err != nil {
+		return err
+	}
+	contractTrie := NewDbContractTrieReader(tx, shardId)
+	contractTrie.SetRootHash(block.SmartContractsRoot)
+
+	for _, txn := range responses {
+		contract, err := contractTrie.Fetch(txn.To.Hash())
+		if errors.Is(err, db.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		asyncContextTrie := NewDbAsyncContextTrieReader(tx, shardId)
+		asyncContextTrie.SetRootHash(contract.AsyncContextRoot)
+		requestId := types.TransactionIndex(txn.RequestId)
+		asyncContext, err := asyncContextTrie.Fetch(requestId)
+		if errors.Is(err, db.ErrKeyNotFound) {
+			// The request is sent in the reverted blocks too
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if asyncContext.Deadline == 0 {
+			continue
+		}
+
+		if err := db.WriteRequestDeadline(tx, shardId, db.RequestDeadline{
+			Deadline:  asyncContext.Deadline,
+			Caller:    txn.To,
+			RequestId: requestId,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevertToBlock(t *testing.T) {
+	t.Parallel()
+
+	const shardId = types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	hash0 := GenerateBlockFromTransactionsWithoutExecution(t, t.Context(), shardId, 0, common.EmptyHash, database)
+	txn1 := NewExecutionTransaction(types.MainSmartAccountAddress, types.MainSmartAccountAddress, 0, nil)
+	txn2 := NewExecutionTransaction(types.MainSmartAccountAddress, types.MainSmartAccountAddress, 1, nil)
+	hash1 := GenerateBlockFromTransactionsWithoutExecution(t, t.Context(), shardId, 1, hash0, database, txn1)
+	hash2 := GenerateBlockFromTransactionsWithoutExecution(t, t.Context(), shardId, 2, hash1, database, txn2)
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	require.NoError(t, RevertToBlock(tx, shardId, hash1))
+
+	lastHash, err := db.ReadLastBlockHash(tx, shardId)
+	require.NoError(t, err)
+	assert.Equal(t, hash1, lastHash)
+
+	_, err = db.ReadBlockHashByNumber(tx, shardId, 2)
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+	hash, err := db.ReadBlockHashByNumber(tx, shardId, 1)
+	require.NoError(t, err)
+	assert.Equal(t, hash1, hash)
+
+	exists, err := tx.ExistsInShard(shardId, db.BlockHashAndInTransactionIndexByTransactionHash, txn2.Hash().Bytes())
+	require.NoError(t, err)
+	assert.False(t, exists)
+	exists, err = tx.ExistsInShard(shardId, db.BlockHashAndInTransactionIndexByTransactionHash, txn1.Hash().Bytes())
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	// The block can't be reverted to if it isn't in the chain
+	require.Error(t, RevertToBlock(tx, shardId, hash2))
+	require.NoError(t, tx.Commit())
+
+	// The reverted block can be produced again
+	newHash := GenerateBlockFromTransactionsWithoutExecution(t, t.Context(), shardId, 2, hash1, database, txn2)
+	assert.Equal(t, hash2, newHash)
+}
+
+func TestRevertRequestDeadlines(t *testing.T) {
+	t.Parallel()
+
+	const shardId = types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	caller := types.GenerateRandomAddress(shardId)
+	asyncContext := &types.AsyncContext{
+		Data:                  types.NewCallbackContextData([4]byte{1, 2, 3, 4}, nil),
+		ResponseProcessingGas: 50_000,
+		Deadline:              5,
+	}
+
+	commitBlock := func(blockId types.BlockNumber, prevHash common.Hash, f func(es *ExecutionState)) common.Hash {
+		t.Helper()
+
+		tx, err := database.CreateRwTx(t.Context())
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		prevBlock, err := db.ReadBlock(tx, shardId, prevHash)
+		require.NoError(t, err)
+		es, err := NewExecutionState(tx, shardId, StateParams{Block: prevBlock, ConfigAccessor: config.GetStubAccessor()})
+		require.NoError(t, err)
+		f(es)
+
+		blockRes, err := es.Commit(blockId, nil)
+		require.NoError(t, err)
+		require.NoError(t, PostprocessBlock(tx, shardId, blockRes, ModeVerify))
+		require.NoError(t, tx.Commit())
+		return blockRes.BlockHash
+	}
+
+	readExpired := func() []db.RequestDeadline {
+		t.Helper()
+
+		tx, err := database.CreateRoTx(t.Context())
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		expired, err := db.ReadExpiredRequests(tx, shardId, 100, 10)
+		require.NoError(t, err)
+		return expired
+	}
+
+	hash0 := GenerateBlockFromTransactionsWithoutExecution(t, t.Context(), shardId, 0, common.EmptyHash, database)
+	hash1 := commitBlock(1, hash0, func(es *ExecutionState) {
+		require.NoError(t, es.CreateAccount(caller))
+		acc, err := es.GetAccount(caller)
+		require.NoError(t, err)
+		acc.SetAsyncContext(1, asyncContext)
+	})
+	expected := []db.RequestDeadline{{Deadline: 5, Caller: caller, RequestId: 1}}
+	require.Equal(t, expected, readExpired())
+
+	// The request is responded, so its deadline is removed
+	commitBlock(2, hash1, func(es *ExecutionState) {
+		txn, err := NewRequestTimeoutTransaction(caller, 1, asyncContext)
+		require.NoError(t, err)
+		es.AddInTransaction(txn)
+		es.AddReceipt(NewExecutionResult())
+
+		acc, err := es.GetAccount(caller)
+		require.NoError(t, err)
+		_, err = acc.GetAndRemoveAsyncContext(1)
+		require.NoError(t, err)
+	})
+	require.Empty(t, readExpired())
+
+	// The request is pending again after the revert
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+	require.NoError(t, RevertToBlock(tx, shardId, hash1))
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, expected, readExpired())
+}
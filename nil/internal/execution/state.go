@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"slices"
 	"sort"
 	"unicode/utf8"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
 	"github.com/NilFoundation/nil/nil/internal/tracing"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/internal/vm"
@@ -87,6 +89,9 @@ type ExecutionState struct {
 	PatchLevel      uint32
 	RollbackCounter uint32
 
+	// Timestamp of the block, it's only set on development networks
+	Timestamp uint64
+
 	InTransactionHash common.Hash
 	Logs              map[common.Hash][]*types.Log
 	DebugLogs         map[common.Hash][]*types.DebugLog
@@ -128,6 +133,10 @@ type ExecutionState struct {
 	// wasAwaitCall is true if the VM execution ended with sending a awaitCall transaction
 	wasAwaitCall bool
 
+	// requestTimeouts holds the timeouts in main shard blocks of the next requests of the contracts
+	// set during the execution of the current transaction
+	requestTimeouts map[types.Address]uint64
+
 	configAccessor config.ConfigAccessor
 
 	// txnFeeCredit holds the total fee credit for the inbound transaction. It can be changed during execution, thus we
@@ -142,6 +151,14 @@ type ExecutionState struct {
 	// filled in if a rollback was requested by a transaction
 	rollback *RollbackParams
 
+	// snapshotDiff holds the changes of the block to be applied to the snapshot on commit.
+	snapshotDiff *stateSnapshotDiff
+	// prefetched holds the committed state loaded by the prefetcher, it's valid until the block is built.
+	prefetched *PrefetchedState
+
+	// devCheats is set if the state modifications of the development API are allowed
+	devCheats *DevCheats
+
 	logger logging.Logger
 }
 
@@ -271,6 +288,9 @@ func NewEVMBlockContext(es *ExecutionState) (*vm.BlockContext, error) {
 		time = header.Id.Uint64()
 		rollbackCounter = header.RollbackCounter
 	}
+	if es.Timestamp != 0 {
+		time = es.Timestamp
+	}
 	return &vm.BlockContext{
 		GetHash:     getHashFn(es, header),
 		BlockNumber: currentBlockId,
@@ -288,13 +308,17 @@ type StateParams struct {
 	ConfigAccessor config.ConfigAccessor
 	FeeCalculator  FeeCalculator
 	Mode           string
+	DevCheats      *DevCheats
 }
 
 func NewExecutionState(tx any, shardId types.ShardId, params StateParams) (*ExecutionState, error) {
 	var resTx db.RwTx
 	isReadOnly := false
-	if rwTx, ok := tx.(db.RwTx); ok {
+	if rwTx, ok := tx.(*mpt.BufferedTx); ok {
 		resTx = rwTx
+	} else if rwTx, ok := tx.(db.RwTx); ok {
+		// The trie nodes are written to the database on the block commit
+		resTx = mpt.NewBufferedTx(rwTx)
 	} else if roTx, ok := tx.(db.RoTx); ok {
 		isReadOnly = true
 		resTx = &db.RwWrapper{RoTx: roTx}
@@ -349,17 +373,37 @@ func NewExecutionState(tx any, shardId types.ShardId, params StateParams) (*Exec
 
 		FeeCalculator: feeCalculator,
 
+		devCheats: params.DevCheats,
+
 		logger: logger,
 	}
 
+	if params.Block != nil && params.Block.Timestamp != 0 {
+		// The timestamps are only set on development networks, they are advanced by one with every block
+		res.Timestamp = params.Block.Timestamp + 1
+	}
+
 	return res, res.initTries()
 }
 
 type DbContractAccessor struct {
 	*ContractTrie
+
+	// snapshot is the flat state of the shard, it's set while the snapshot matches the trie root.
+	snapshot *StateSnapshot
+}
+
+func (ca *DbContractAccessor) SetRootHash(root common.Hash) {
+	ca.ContractTrie.SetRootHash(root)
+	if ca.snapshot != nil && ca.snapshot.root != root {
+		ca.snapshot = nil
+	}
 }
 
 func (ca *DbContractAccessor) GetContract(addr types.Address) (*types.SmartContract, error) {
+	if ca.snapshot != nil {
+		return ca.snapshot.GetContract(addr)
+	}
 	return ca.Fetch(addr.Hash())
 }
 
@@ -375,7 +419,13 @@ func (ca *DbContractAccessor) UpdateContracts(contracts map[types.Address]*Accou
 		keys = append(keys, addr.Hash())
 		values = append(values, smartContract)
 	}
-	return ca.UpdateBatch(keys, values)
+	if err := ca.UpdateBatch(keys, values); err != nil {
+		return err
+	}
+	if ca.snapshot != nil && ca.snapshot.root != ca.RootHash() {
+		ca.snapshot = nil
+	}
+	return nil
 }
 
 func (es *ExecutionState) initTries() error {
@@ -384,7 +434,8 @@ func (es *ExecutionState) initTries() error {
 		return err
 	}
 
-	es.ContractTree = &DbContractAccessor{NewDbContractTrie(es.tx, es.ShardId)}
+	contractTree := &DbContractAccessor{ContractTrie: NewDbContractTrie(es.tx, es.ShardId)}
+	es.ContractTree = contractTree
 	es.InTransactionTree = NewDbTransactionTrie(es.tx, es.ShardId)
 	es.OutTransactionTree = NewDbTransactionTrie(es.tx, es.ShardId)
 	es.ReceiptTree = NewDbReceiptTrie(es.tx, es.ShardId)
@@ -392,7 +443,8 @@ func (es *ExecutionState) initTries() error {
 		es.ContractTree.SetRootHash(data.Block().SmartContractsRoot)
 	}
 
-	return nil
+	contractTree.snapshot, err = NewStateSnapshot(es.tx, es.ShardId, es.ContractTree.RootHash())
+	return err
 }
 
 func (es *ExecutionState) GetConfigAccessor() config.ConfigAccessor {
@@ -418,7 +470,16 @@ func (es *ExecutionState) GetAccount(addr types.Address) (*AccountState, error)
 		return acc, nil
 	}
 
-	data, err := es.ContractTree.GetContract(addr)
+	var data *types.SmartContract
+	var err error
+	if prefetched, ok := es.prefetched.getContract(addr); ok {
+		data = prefetched
+		if data == nil {
+			err = db.ErrKeyNotFound
+		}
+	} else {
+		data, err = es.ContractTree.GetContract(addr)
+	}
 	if errors.Is(err, db.ErrKeyNotFound) {
 		return nil, nil
 	}
@@ -466,6 +527,9 @@ func (es *ExecutionState) AddLog(log *types.Log) error {
 		return errors.New("too many logs")
 	}
 	es.Logs[es.InTransactionHash] = append(es.Logs[es.InTransactionHash], log)
+	if es.EvmTracingHooks != nil && es.EvmTracingHooks.OnLog != nil {
+		es.EvmTracingHooks.OnLog(log)
+	}
 	return nil
 }
 
@@ -898,10 +962,15 @@ func (es *ExecutionState) AddOutRequestTransaction(
 		// Stop vm execution and save its state after the current instruction (call of precompile) is finished.
 		es.evm.StopAndDumpState(responseProcessingGas)
 	} else {
+		deadline, err := es.requestDeadline(caller)
+		if err != nil {
+			return nil, err
+		}
 		acc.SetAsyncContext(types.TransactionIndex(txn.RequestId), &types.AsyncContext{
 			IsAwait:               false,
 			Data:                  payload.RequestContext,
 			ResponseProcessingGas: responseProcessingGas,
+			Deadline:              deadline,
 		})
 	}
 
@@ -1148,9 +1217,11 @@ func (es *ExecutionState) HandleTransaction(
 			bounced = true
 			responseWasSent = true
 		}
-	} else if txn.IsResponse() && !es.wasAwaitCall && len(txn.RequestChain) > 0 {
+	} else if txn.IsResponse() && !es.wasAwaitCall && len(txn.RequestChain) > 0 &&
+		!isResponseToUnknownRequest(res) {
 		// There is pending requests in the chain, so we need to send response to them.
 		// But we don't send response if a new request was sent during the execution.
+		// The requests of the chain are already responded if the request of the response was timed out.
 		if err := es.SendResponseTransaction(txn, res); err != nil {
 			return NewExecutionResult().SetFatal(fmt.Errorf("SendResponseTransaction failed: %w\n", err))
 		}
@@ -1258,6 +1329,10 @@ func (es *ExecutionState) TryProcessResponse(
 		return nil, nil, NewExecutionResult().SetFatal(err)
 	}
 	asyncContext, err := acc.GetAndRemoveAsyncContext(types.TransactionIndex(transaction.RequestId))
+	if errors.Is(err, db.ErrKeyNotFound) {
+		// The request has failed by its timeout before the response arrived
+		return nil, nil, NewExecutionResult().SetError(types.NewError(types.ErrorResponseToUnknownRequest))
+	}
 	if err != nil {
 		return nil, nil, NewExecutionResult().SetFatal(fmt.Errorf("failed to get async context: %w", err))
 	}
@@ -1280,11 +1355,11 @@ func (es *ExecutionState) TryProcessResponse(
 		restoreState.ReturnData = responsePayload.ReturnData
 		restoreState.Result = responsePayload.Success
 	} else {
-		if len(asyncContext.Data) < 4 {
+		callback, contextData, ok := asyncContext.Callback()
+		if !ok {
 			return nil, nil, NewExecutionResult().SetError(
 				types.NewError(types.ErrorAwaitCallTooShortContextData))
 		}
-		contextData := asyncContext.Data[4:]
 		bytesTy, _ := abi.NewType("bytes", "", nil)
 		boolTy, _ := abi.NewType("bool", "", nil)
 		args := abi.Arguments{
@@ -1295,7 +1370,7 @@ func (es *ExecutionState) TryProcessResponse(
 		if callData, err = args.Pack(responsePayload.Success, responsePayload.ReturnData, contextData); err != nil {
 			return nil, nil, NewExecutionResult().SetFatal(err)
 		}
-		callData = append(asyncContext.Data[:4], callData...)
+		callData = append(slices.Clone(callback), callData...)
 	}
 
 	return callData, restoreState, nil
@@ -1419,6 +1494,14 @@ func GetOutTransactions(es *ExecutionState) ([]*types.Transaction, []common.Hash
 }
 
 func (es *ExecutionState) BuildBlock(blockId types.BlockNumber) (*BlockGenerationResult, error) {
+	if contracts, ok := es.ContractTree.(*DbContractAccessor); ok {
+		es.snapshotDiff = es.collectStateSnapshotDiff(contracts)
+	}
+	// The prefetched values don't match the state after the block
+	es.prefetched = nil
+	for _, acc := range es.Accounts {
+		acc.prefetched = nil
+	}
 	if err := es.ContractTree.UpdateContracts(es.Accounts); err != nil {
 		return nil, err
 	}
@@ -1546,7 +1629,7 @@ func (es *ExecutionState) BuildBlock(blockId types.BlockNumber) (*BlockGeneratio
 			PatchLevel:          es.PatchLevel,
 			RollbackCounter:     es.RollbackCounter,
 			// TODO(@klonD90): remove this field after changing explorer
-			Timestamp: 0,
+			Timestamp: es.Timestamp,
 		},
 		LogsBloom: types.CreateBloom(es.Receipts),
 	}
@@ -1580,6 +1663,10 @@ func (es *ExecutionState) CommitBlock(src *BlockGenerationResult, params *types.
 		block.ConsensusParams = *params
 	}
 
+	if err := es.FlushTrieNodes(); err != nil {
+		return fmt.Errorf("failed to flush trie nodes: %w", err)
+	}
+
 	if TraceBlocksEnabled {
 		blocksTracer.Trace(es, block, blockHash)
 	}
@@ -1594,6 +1681,14 @@ func (es *ExecutionState) CommitBlock(src *BlockGenerationResult, params *types.
 		return err
 	}
 
+	if err := es.deleteStaleRequestDeadlines(); err != nil {
+		return fmt.Errorf("failed to delete stale request deadlines: %w", err)
+	}
+
+	if err := es.updateStateSnapshot(block.SmartContractsRoot); err != nil {
+		return fmt.Errorf("failed to update state snapshot: %w", err)
+	}
+
 	es.logger.Trace().
 		Stringer(logging.FieldShardId, es.ShardId).
 		Stringer(logging.FieldBlockNumber, block.Id).
@@ -1603,6 +1698,15 @@ func (es *ExecutionState) CommitBlock(src *BlockGenerationResult, params *types.
 	return nil
 }
 
+// FlushTrieNodes writes the trie nodes stored during the execution to the database transaction.
+// It's done on the block commit, so the nodes of the blocks that are only built are never written.
+func (es *ExecutionState) FlushTrieNodes() error {
+	if buffered, ok := es.tx.(*mpt.BufferedTx); ok {
+		return buffered.Flush()
+	}
+	return nil
+}
+
 func (es *ExecutionState) CalculateGasForwarding(initialAvailValue types.Value) (types.Value, error) {
 	if len(es.OutTransactions) == 0 {
 		return types.NewZeroValue(), nil
@@ -1692,14 +1796,7 @@ func (es *ExecutionState) CallVerifyExternal(
 	transaction *types.Transaction,
 	account *AccountState,
 ) (res *ExecutionResult) {
-	methodSignature := "verifyExternal(uint256,bytes)"
-	methodSelector := crypto.Keccak256([]byte(methodSignature))[:4]
-	argSpec := vm.VerifySignatureArgs()[1:] // skip first arg (pubkey)
-	hash, err := transaction.SigningHash()
-	if err != nil {
-		return NewExecutionResult().SetFatal(fmt.Errorf("transaction.SigningHash() failed: %w", err))
-	}
-	argData, err := argSpec.Pack(hash.Big(), ([]byte)(transaction.Signature))
+	calldata, err := verifyExternalCallData(transaction, transaction.Signature)
 	if err != nil {
 		es.logger.Error().Err(err).Msg("failed to pack arguments")
 		return NewExecutionResult().SetFatal(err)
@@ -1709,8 +1806,6 @@ func (es *ExecutionState) CallVerifyExternal(
 		return NewExecutionResult().SetError(types.KeepOrWrapError(types.ErrorBaseFeeTooHigh, err))
 	}
 
-	calldata := append(methodSelector, argData...) //nolint:gocritic
-
 	if err := es.newVm(transaction.IsInternal(), transaction.From, nil); err != nil {
 		return NewExecutionResult().SetFatal(fmt.Errorf("newVm failed: %w", err))
 	}
@@ -1748,6 +1843,49 @@ func (es *ExecutionState) CallVerifyExternal(
 	return res
 }
 
+// EstimateVerifyExternalGas returns the gas the account spends on the verification of the external transaction.
+// The transaction doesn't have to be signed: the verification takes the same gas for any signature of the proper size.
+func (es *ExecutionState) EstimateVerifyExternalGas(
+	transaction *types.Transaction,
+	account *AccountState,
+) (types.Gas, error) {
+	signature := transaction.Signature
+	if len(signature) != common.SignatureSize {
+		signature = make(types.Signature, common.SignatureSize)
+	}
+	calldata, err := verifyExternalCallData(transaction, signature)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := es.newVm(transaction.IsInternal(), transaction.From, nil); err != nil {
+		return 0, fmt.Errorf("newVm failed: %w", err)
+	}
+	defer es.resetVm()
+
+	_, leftOverGas, err := es.evm.StaticCall(
+		(vm.AccountRef)(account.address), account.address, calldata, ExternalTransactionVerificationMaxGas.Uint64())
+	if err != nil {
+		return 0, types.KeepOrWrapError(types.ErrorExternalVerificationFailed, err)
+	}
+	return ExternalTransactionVerificationMaxGas.Sub(types.Gas(leftOverGas)), nil
+}
+
+func verifyExternalCallData(transaction *types.Transaction, signature types.Signature) ([]byte, error) {
+	methodSignature := "verifyExternal(uint256,bytes)"
+	methodSelector := crypto.Keccak256([]byte(methodSignature))[:4]
+	argSpec := vm.VerifySignatureArgs()[1:] // skip first arg (pubkey)
+	hash, err := transaction.SigningHash()
+	if err != nil {
+		return nil, fmt.Errorf("transaction.SigningHash() failed: %w", err)
+	}
+	argData, err := argSpec.Pack(hash.Big(), ([]byte)(signature))
+	if err != nil {
+		return nil, err
+	}
+	return append(methodSelector, argData...), nil
+}
+
 func (es *ExecutionState) AddToken(addr types.Address, tokenId types.TokenId, amount types.Value) error {
 	es.logger.Debug().
 		Stringer("addr", addr).
@@ -1874,19 +2012,89 @@ func (es *ExecutionState) SaveVmState(state *types.EvmState, continuationGasCred
 		return err
 	}
 
-	acc, err := es.GetAccount(es.GetInTransaction().To)
+	caller := es.GetInTransaction().To
+	acc, err := es.GetAccount(caller)
 	check.PanicIfErr(err)
 
+	deadline, err := es.requestDeadline(caller)
+	if err != nil {
+		return err
+	}
+
 	es.logger.Debug().Int("size", len(data)).Msg("Save vm state")
 
 	acc.SetAsyncContext(types.TransactionIndex(outTxn.RequestId), &types.AsyncContext{
 		IsAwait:               true,
 		Data:                  data,
 		ResponseProcessingGas: continuationGasCredit,
+		Deadline:              deadline,
+		RequestChain:          outTxn.RequestChain,
 	})
 	return nil
 }
 
+// SetRequestTimeout sets the timeout of the next request sent by the contract within the current transaction.
+// Zero timeout means that the request waits for the response forever.
+// The timeout is journaled, so it's dropped if the call frame setting it reverts.
+func (es *ExecutionState) SetRequestTimeout(caller types.Address, timeout uint64) {
+	prev := es.requestTimeouts[caller]
+	if prev == timeout {
+		return
+	}
+	es.AppendToJournal(requestTimeoutChange{
+		account: &caller,
+		prev:    prev,
+	})
+	es.SetRequestTimeoutNoJournal(caller, timeout)
+}
+
+// SetRequestTimeoutNoJournal is a lower level setter for the request timeout. It
+// is called during a revert to prevent modifications to the journal.
+func (es *ExecutionState) SetRequestTimeoutNoJournal(caller types.Address, timeout uint64) {
+	if timeout == 0 {
+		delete(es.requestTimeouts, caller)
+		return
+	}
+	if es.requestTimeouts == nil {
+		es.requestTimeouts = make(map[types.Address]uint64)
+	}
+	es.requestTimeouts[caller] = timeout
+}
+
+// requestDeadline returns the deadline of the request sent by the contract or zero if the timeout isn't set.
+// The timeout applies to a single request, so it's reset.
+func (es *ExecutionState) requestDeadline(caller types.Address) (types.BlockNumber, error) {
+	timeout := es.requestTimeouts[caller]
+	if timeout == 0 {
+		return 0, nil
+	}
+	es.SetRequestTimeout(caller, 0)
+
+	height, err := es.MainShardHeight()
+	if err != nil {
+		return 0, err
+	}
+	return height + types.BlockNumber(timeout), nil
+}
+
+// MainShardHeight returns the number of the main shard block the current block is based on.
+// For the main shard it's the number of the current block.
+func (es *ExecutionState) MainShardHeight() (types.BlockNumber, error) {
+	if es.ShardId.IsMainShard() {
+		block, err := db.ReadBlock(es.tx, es.ShardId, es.PrevBlock)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read the previous main shard block %s: %w", es.PrevBlock, err)
+		}
+		return block.Id + 1, nil
+	}
+
+	block, err := db.ReadBlock(es.tx, types.MainShardId, es.MainShardHash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the main shard block %s: %w", es.MainShardHash, err)
+	}
+	return block.Id, nil
+}
+
 func (es *ExecutionState) newVm(internal bool, origin types.Address, state *vm.EvmRestoreData) error {
 	blockContext, err := NewEVMBlockContext(es)
 	if err != nil {
@@ -1894,6 +2102,9 @@ func (es *ExecutionState) newVm(internal bool, origin types.Address, state *vm.E
 	}
 	es.evm = vm.NewEVM(blockContext, es, origin, es.GasPrice, state)
 	es.evm.IsAsyncCall = internal
+	// The request timeouts apply within a transaction. They are dropped here rather than on the VM reset,
+	// since the revert of the whole transaction happens after it and restores the timeouts set by the transaction.
+	es.requestTimeouts = nil
 
 	es.evm.Config.Tracer = es.EvmTracingHooks
 
@@ -1989,7 +2200,7 @@ func (es *ExecutionState) DeleteOutTransaction(index int, txnHash common.Hash) {
 }
 
 func (es *ExecutionState) preTxHookCall(txn *types.Transaction) {
-	if es.EvmTracingHooks != nil && es.EvmTracingHooks.OnTxEnd != nil {
+	if es.EvmTracingHooks != nil && es.EvmTracingHooks.OnTxStart != nil {
 		es.EvmTracingHooks.OnTxStart(es.evm.GetVMContext(), txn)
 	}
 }
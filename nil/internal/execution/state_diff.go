@@ -0,0 +1,256 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// BlockStateDiff holds the changes of the accounts made by a block and by each of its transactions.
+type BlockStateDiff struct {
+	Block        types.StateDiff
+	Transactions map[common.Hash]types.StateDiff
+}
+
+// ErrStateDiffUnavailable is returned for the zero-state blocks, since they have no previous state to diff with.
+var ErrStateDiffUnavailable = errors.New("state diff is unavailable for the zero-state block")
+
+// accountChanges keeps the values of the changed account fields before the first change.
+type accountChanges struct {
+	balance  *types.Value
+	seqno    *types.Seqno
+	extSeqno *types.Seqno
+	code     *types.Code
+	tokens   map[types.TokenId]types.Value
+	storage  map[common.Hash]common.Hash
+}
+
+// CollectBlockStateDiff re-executes the transactions of the block on top of the state of the previous block
+// and returns the changes made by the block and by each of its transactions.
+// Nothing is written to the database, so a read-only transaction is enough.
+// The dev cheats are applied only if they are enabled, as it's done by the validators of the node.
+func CollectBlockStateDiff(
+	ctx context.Context,
+	tx db.RoTx,
+	shardId types.ShardId,
+	block *types.BlockWithExtractedData,
+	enableDevCheats bool,
+) (*BlockStateDiff, error) {
+	if block.Id == 0 {
+		return nil, ErrStateDiffUnavailable
+	}
+	prevBlock, err := db.ReadBlock(tx, shardId, block.PrevBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previous block %s: %w", block.PrevBlock, err)
+	}
+
+	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, prevBlock, shardId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config accessor: %w", err)
+	}
+
+	var devCheats *DevCheats
+	if enableDevCheats {
+		devCheats = NewDevCheats()
+	}
+
+	// The wrapper makes the state writable, so the execution isn't relaxed as it's done for eth_call
+	rwTx := &db.RwWrapper{RoTx: tx}
+	es, err := NewExecutionState(rwTx, shardId, StateParams{
+		Block:          prevBlock,
+		ConfigAccessor: configAccessor,
+		Mode:           ModeVerify,
+		DevCheats:      devCheats,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	params := NewBlockGeneratorParams(shardId, 0)
+	params.ExecutionMode = ModeVerify
+	gen, err := NewBlockGeneratorWithEs(ctx, params, nil, rwTx, es)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal := &Proposal{
+		PrevBlockId:     block.Id - 1,
+		PrevBlockHash:   block.PrevBlock,
+		PatchLevel:      block.PatchLevel,
+		RollbackCounter: block.RollbackCounter,
+		MainShardHash:   block.MainShardHash,
+		ShardHashes:     block.ChildBlocks,
+	}
+	proposal.InternalTxns, proposal.ExternalTxns = SplitInTransactions(block.InTransactions)
+	proposal.ForwardTxns, _ = SplitOutTransactions(block.OutTransactions, shardId)
+
+	var gasPrices []types.Uint256
+	if shardId.IsMainShard() {
+		if gasPricesBytes, ok := block.Config[config.NameGasPrice]; ok {
+			param := &config.ParamGasPrice{}
+			if err := param.UnmarshalSSZ(gasPricesBytes); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal gas prices: %w", err)
+			}
+			gasPrices = param.Shards
+		}
+	}
+
+	gen.stateDiff = &BlockStateDiff{Transactions: make(map[common.Hash]types.StateDiff)}
+	if err := gen.prepareExecutionState(proposal, gasPrices); err != nil {
+		return nil, err
+	}
+	if gen.stateDiff.Block, err = es.stateDiff(0); err != nil {
+		return nil, err
+	}
+	return gen.stateDiff, nil
+}
+
+// stateDiff returns the changes of the accounts made by the journal entries starting from the given index.
+// The changes reverted since then aren't included, because reverting drops their entries.
+func (es *ExecutionState) stateDiff(journalStart int) (types.StateDiff, error) {
+	changes := make(map[types.Address]*accountChanges)
+	get := func(addr types.Address) *accountChanges {
+		c, ok := changes[addr]
+		if !ok {
+			c = &accountChanges{
+				tokens:  make(map[types.TokenId]types.Value),
+				storage: make(map[common.Hash]common.Hash),
+			}
+			changes[addr] = c
+		}
+		return c
+	}
+
+	for _, entry := range es.journal.entries[journalStart:] {
+		switch entry := entry.(type) {
+		case balanceChange:
+			if c := get(*entry.account); c.balance == nil {
+				c.balance = &entry.prev
+			}
+		case selfDestructChange:
+			if c := get(*entry.account); c.balance == nil {
+				c.balance = &entry.prevbalance
+			}
+		case tokenChange:
+			c := get(*entry.account)
+			if _, ok := c.tokens[entry.id]; !ok {
+				c.tokens[entry.id] = entry.prev
+			}
+		case seqnoChange:
+			if c := get(*entry.account); c.seqno == nil {
+				c.seqno = &entry.prev
+			}
+		case extSeqnoChange:
+			if c := get(*entry.account); c.extSeqno == nil {
+				c.extSeqno = &entry.prev
+			}
+		case codeChange:
+			if c := get(*entry.account); c.code == nil {
+				code := types.Code(entry.prevcode)
+				c.code = &code
+			}
+		case storageChange:
+			c := get(*entry.account)
+			if _, ok := c.storage[entry.key]; !ok {
+				c.storage[entry.key] = entry.prevvalue
+			}
+		}
+	}
+
+	res := make(types.StateDiff, 0, len(changes))
+	for _, addr := range slices.SortedFunc(maps.Keys(changes), compareAddresses) {
+		account, err := es.GetAccount(addr)
+		if err != nil {
+			return nil, err
+		}
+		if account == nil {
+			return nil, fmt.Errorf("changed account %s is missing", addr)
+		}
+
+		diff, err := changes[addr].diff(account)
+		if err != nil {
+			return nil, err
+		}
+		if diff != nil {
+			res = append(res, diff)
+		}
+	}
+	return res, nil
+}
+
+// diff compares the values before the changes with the current ones. It returns nil if nothing is changed.
+func (c *accountChanges) diff(account *AccountState) (*types.AccountDiff, error) {
+	res := &types.AccountDiff{Address: account.address}
+	changed := false
+
+	if c.balance != nil && !c.balance.Eq(account.Balance) {
+		res.Balance = &types.Diff[types.Value]{From: valueOrZero(*c.balance), To: valueOrZero(account.Balance)}
+		changed = true
+	}
+	if c.seqno != nil && *c.seqno != account.Seqno {
+		res.Seqno = &types.Diff[types.Seqno]{From: *c.seqno, To: account.Seqno}
+		changed = true
+	}
+	if c.extSeqno != nil && *c.extSeqno != account.ExtSeqno {
+		res.ExtSeqno = &types.Diff[types.Seqno]{From: *c.extSeqno, To: account.ExtSeqno}
+		changed = true
+	}
+	if c.code != nil && !bytes.Equal(*c.code, account.Code) {
+		res.Code = &types.Diff[types.Code]{From: *c.code, To: account.Code}
+		changed = true
+	}
+
+	for id, prev := range c.tokens {
+		next := types.Value0
+		if value := account.GetTokenBalance(id); value != nil {
+			next = *value
+		}
+		if prev.Eq(next) {
+			continue
+		}
+		if res.Tokens == nil {
+			res.Tokens = make(map[types.TokenId]types.Diff[types.Value])
+		}
+		res.Tokens[id] = types.Diff[types.Value]{From: valueOrZero(prev), To: valueOrZero(next)}
+		changed = true
+	}
+
+	for key, prev := range c.storage {
+		next, err := account.GetState(key)
+		if err != nil {
+			return nil, err
+		}
+		if prev == next {
+			continue
+		}
+		if res.Storage == nil {
+			res.Storage = make(map[common.Hash]types.Diff[common.Hash])
+		}
+		res.Storage[key] = types.Diff[common.Hash]{From: prev, To: next}
+		changed = true
+	}
+
+	if !changed {
+		return nil, nil
+	}
+	return res, nil
+}
+
+func compareAddresses(a, b types.Address) int {
+	return bytes.Compare(a.Bytes(), b.Bytes())
+}
+
+func valueOrZero(v types.Value) types.Value {
+	if v.Uint256 == nil {
+		return types.Value0
+	}
+	return v
+}
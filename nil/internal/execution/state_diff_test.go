@@ -0,0 +1,155 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDiff(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	state, err := NewExecutionState(tx, types.BaseShardId, StateParams{
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	require.NoError(t, err)
+
+	addr := types.GenerateRandomAddress(types.BaseShardId)
+	key1 := common.IntToHash(1)
+	key2 := common.IntToHash(2)
+	token := types.TokenId(types.GenerateRandomAddress(types.BaseShardId))
+	code := []byte{0x60, 0x00}
+
+	require.NoError(t, state.CreateAccount(addr))
+	require.NoError(t, state.SetBalance(addr, types.NewValueFromUint64(100)))
+	require.NoError(t, state.SetState(addr, key1, common.IntToHash(42)))
+	// The slot restored to the initial value isn't changed
+	require.NoError(t, state.SetState(addr, key2, common.IntToHash(43)))
+	require.NoError(t, state.SetState(addr, key2, common.EmptyHash))
+	require.NoError(t, state.SetExtSeqno(addr, 2))
+	require.NoError(t, state.SetCode(addr, code))
+
+	account, err := state.GetAccount(addr)
+	require.NoError(t, err)
+	account.SetTokenBalance(token, types.NewValueFromUint64(7))
+
+	// The reverted changes aren't included
+	snapshot := state.Snapshot()
+	require.NoError(t, state.SetSeqno(addr, 3))
+	state.RevertToSnapshot(snapshot)
+
+	middle := state.journal.length()
+	require.NoError(t, state.SetBalance(addr, types.NewValueFromUint64(150)))
+
+	diff, err := state.stateDiff(0)
+	require.NoError(t, err)
+	require.Len(t, diff, 1)
+	assert.Equal(t, &types.AccountDiff{
+		Address:  addr,
+		Balance:  &types.Diff[types.Value]{From: types.Value0, To: types.NewValueFromUint64(150)},
+		ExtSeqno: &types.Diff[types.Seqno]{From: 0, To: 2},
+		Code:     &types.Diff[types.Code]{From: nil, To: code},
+		Tokens: map[types.TokenId]types.Diff[types.Value]{
+			token: {From: types.Value0, To: types.NewValueFromUint64(7)},
+		},
+		Storage: map[common.Hash]types.Diff[common.Hash]{
+			key1: {From: common.EmptyHash, To: common.IntToHash(42)},
+		},
+	}, diff[0])
+
+	diff, err = state.stateDiff(middle)
+	require.NoError(t, err)
+	assert.Equal(t, types.StateDiff{{
+		Address: addr,
+		Balance: &types.Diff[types.Value]{From: types.NewValueFromUint64(100), To: types.NewValueFromUint64(150)},
+	}}, diff)
+
+	diff, err = state.stateDiff(state.journal.length())
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestCollectBlockStateDiff(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	shardId := types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	from := types.GenerateRandomAddress(shardId)
+	to := types.GenerateRandomAddress(shardId)
+
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	state, err := NewExecutionState(tx, shardId, StateParams{
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, state.CreateAccount(from))
+	require.NoError(t, state.SetBalance(from, types.NewValueFromUint64(1_000_000_000)))
+	zeroState, err := state.Commit(0, nil)
+	require.NoError(t, err)
+	require.NoError(t, PostprocessBlock(tx, shardId, zeroState, ModeVerify))
+	require.NoError(t, tx.Commit())
+
+	txn := &types.Transaction{
+		TransactionDigest: types.TransactionDigest{
+			Flags:        types.NewTransactionFlags(types.TransactionFlagInternal),
+			To:           to,
+			FeeCredit:    types.GasToValue(100_000),
+			MaxFeePerGas: types.MaxFeePerGasDefault,
+		},
+		From:     from,
+		RefundTo: from,
+		Value:    types.NewValueFromUint64(500),
+	}
+
+	gen, err := NewBlockGenerator(ctx, NewBlockGeneratorParams(shardId, 2), database, zeroState.Block)
+	require.NoError(t, err)
+	defer gen.Rollback()
+	res, err := gen.GenerateBlock(&Proposal{
+		PrevBlockHash: zeroState.BlockHash,
+		InternalTxns:  []*types.Transaction{txn},
+	}, &types.ConsensusParams{})
+	require.NoError(t, err)
+
+	roTx, err := database.CreateRoTx(ctx)
+	require.NoError(t, err)
+	defer roTx.Rollback()
+
+	t.Run("ZeroState", func(t *testing.T) {
+		_, err := CollectBlockStateDiff(ctx, roTx, shardId, &types.BlockWithExtractedData{Block: zeroState.Block}, false)
+		require.ErrorIs(t, err, ErrStateDiffUnavailable)
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		diff, err := CollectBlockStateDiff(ctx, roTx, shardId, &types.BlockWithExtractedData{
+			Block:          res.Block,
+			InTransactions: []*types.Transaction{txn},
+		}, false)
+		require.NoError(t, err)
+
+		expected := types.StateDiff{{
+			Address: to,
+			Balance: &types.Diff[types.Value]{From: types.Value0, To: types.NewValueFromUint64(500)},
+			Seqno:   &types.Diff[types.Seqno]{From: 0, To: 1},
+		}}
+		assert.Equal(t, expected, diff.Block)
+		assert.Equal(t, map[common.Hash]types.StateDiff{txn.Hash(): expected}, diff.Transactions)
+	})
+}
@@ -0,0 +1,379 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+var (
+	ErrStateSnapshotMismatch   = errors.New("state snapshot doesn't match the state tries")
+	ErrStateSnapshotGenerating = errors.New("state snapshot is not generated yet")
+)
+
+// StateSnapshot is a flat view of the latest state of a shard.
+// It reads contracts, storage slots and token balances with a single lookup instead of walking the tries.
+type StateSnapshot struct {
+	tx      db.RoTx
+	shardId types.ShardId
+	// root is the contracts trie root the snapshot matches.
+	root common.Hash
+}
+
+// NewStateSnapshot returns the snapshot of the shard if it matches the contracts trie with the given root,
+// nil otherwise. The snapshot is maintained only for the latest block, so the tries are used for older ones.
+func NewStateSnapshot(tx db.RoTx, shardId types.ShardId, root common.Hash) (*StateSnapshot, error) {
+	snapshotRoot, err := db.ReadStateSnapshotRoot(tx, shardId)
+	if err != nil {
+		return nil, err
+	}
+	if snapshotRoot != root {
+		return nil, nil
+	}
+	return &StateSnapshot{tx: tx, shardId: shardId, root: root}, nil
+}
+
+func (s *StateSnapshot) GetContract(addr types.Address) (*types.SmartContract, error) {
+	return db.ReadSnapshotContract(s.tx, s.shardId, addr)
+}
+
+func (s *StateSnapshot) GetState(addr types.Address, key common.Hash) (common.Hash, error) {
+	res, err := db.ReadSnapshotStorage(s.tx, s.shardId, addr, key)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return common.EmptyHash, nil
+	}
+	return res, err
+}
+
+func (s *StateSnapshot) GetTokenBalance(addr types.Address, id types.TokenId) (*types.Value, error) {
+	res, err := db.ReadSnapshotToken(s.tx, s.shardId, addr, id)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// stateSnapshotBatchSize limits the number of the snapshot entries removed or generated on a block commit,
+// so that the generation of the snapshot of a large state is spread over many blocks.
+var stateSnapshotBatchSize = 10_000
+
+// stateSnapshotVerifyBatchSize limits the number of the contracts whose tries are rebuilt in a single
+// transaction of the scratch database.
+var stateSnapshotVerifyBatchSize = 1_000
+
+// stateSnapshotDiff holds the storage and tokens of the accounts before they are committed,
+// since committing drops the empty values that have to be removed from the snapshot.
+type stateSnapshotDiff struct {
+	// root is the contracts trie root of the previous block.
+	root common.Hash
+	// complete is set if the snapshot matched the previous block.
+	complete bool
+
+	storage map[types.Address]Storage
+	tokens  map[types.Address]map[types.TokenId]types.Value
+}
+
+func (es *ExecutionState) collectStateSnapshotDiff(contracts *DbContractAccessor) *stateSnapshotDiff {
+	diff := &stateSnapshotDiff{
+		root:     contracts.RootHash(),
+		complete: contracts.snapshot != nil,
+		storage:  make(map[types.Address]Storage, len(es.Accounts)),
+		tokens:   make(map[types.Address]map[types.TokenId]types.Value, len(es.Accounts)),
+	}
+	for addr, acc := range es.Accounts {
+		diff.storage[addr] = maps.Clone(acc.State)
+		diff.tokens[addr] = maps.Clone(acc.Tokens)
+	}
+	return diff
+}
+
+// stateSnapshot returns the snapshot the state is read through, nil if the tries are read.
+func (es *ExecutionState) stateSnapshot() *StateSnapshot {
+	if contracts, ok := es.ContractTree.(*DbContractAccessor); ok {
+		return contracts.snapshot
+	}
+	return nil
+}
+
+// updateStateSnapshot brings the snapshot to the state with the given contracts root.
+// The changes of the block are applied if the snapshot matched the previous block,
+// otherwise (e.g., after a rollback or for a database created without the snapshot)
+// the next batch of it is generated.
+func (es *ExecutionState) updateStateSnapshot(root common.Hash) error {
+	if es.snapshotDiff == nil {
+		// The contracts aren't read from the database (e.g., they are traced), there is no snapshot to maintain.
+		return nil
+	}
+	if es.snapshotDiff.complete {
+		if err := es.applyStateSnapshotDiff(func(types.Address) bool { return true }); err != nil {
+			return err
+		}
+		return db.WriteStateSnapshotRoot(es.tx, es.ShardId, root)
+	}
+	return es.continueStateSnapshotGeneration(root)
+}
+
+// applyStateSnapshotDiff writes the changes of the block for the accounts accepted by the filter.
+func (es *ExecutionState) applyStateSnapshotDiff(filter func(types.Address) bool) error {
+	for addr, acc := range es.Accounts {
+		if !filter(addr) {
+			continue
+		}
+		if err := db.WriteSnapshotContract(es.tx, es.ShardId, acc.smartContract()); err != nil {
+			return err
+		}
+		for key, value := range es.snapshotDiff.storage[addr] {
+			if err := db.WriteSnapshotStorage(es.tx, es.ShardId, addr, key, value); err != nil {
+				return err
+			}
+		}
+		for id, value := range es.snapshotDiff.tokens[addr] {
+			if err := db.WriteSnapshotToken(es.tx, es.ShardId, addr, id, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// continueStateSnapshotGeneration makes the next step of the snapshot generation on a block commit.
+// The previous snapshot is removed first, then the contracts are written in the order of the trie keys.
+// The generated part is kept up to date with the changes of the blocks, the rest is read from the latest trie.
+// The generation starts over if the generated part doesn't match the previous block (e.g., after a rollback).
+func (es *ExecutionState) continueStateSnapshotGeneration(root common.Hash) error {
+	gen, err := db.ReadStateSnapshotGenerator(es.tx, es.ShardId)
+	if err != nil {
+		return err
+	}
+	if gen != nil && gen.Root == es.snapshotDiff.root {
+		if gen.Cleared {
+			if err := es.applyStateSnapshotDiff(func(addr types.Address) bool {
+				return bytes.Compare(addr.Hash().Bytes(), gen.Next.Bytes()) < 0
+			}); err != nil {
+				return err
+			}
+		}
+	} else {
+		es.logger.Info().
+			Stringer("root", root).
+			Msg("State snapshot doesn't match the previous block, regenerating it...")
+		gen = &db.StateSnapshotGenerator{}
+		if err := db.DeleteStateSnapshotRoot(es.tx, es.ShardId); err != nil {
+			return err
+		}
+	}
+
+	budget := stateSnapshotBatchSize
+	if !gen.Cleared {
+		deleted, err := db.ClearStateSnapshotBatch(es.tx, es.ShardId, budget)
+		if err != nil {
+			return fmt.Errorf("failed to clear state snapshot: %w", err)
+		}
+		budget -= deleted
+		gen.Cleared = budget > 0
+	}
+	if gen.Cleared && budget > 0 {
+		done, err := generateStateSnapshotBatch(es.tx, es.ShardId, root, gen, budget)
+		if err != nil {
+			return err
+		}
+		if done {
+			es.logger.Info().Stringer("root", root).Msg("State snapshot is generated")
+			if err := db.DeleteStateSnapshotGenerator(es.tx, es.ShardId); err != nil {
+				return err
+			}
+			return db.WriteStateSnapshotRoot(es.tx, es.ShardId, root)
+		}
+	}
+
+	gen.Root = root
+	return db.WriteStateSnapshotGenerator(es.tx, es.ShardId, gen)
+}
+
+// generateStateSnapshotBatch writes the contracts of the trie with the given root starting from gen.Next
+// until about limit entries are written. It returns true if all the remaining contracts are written,
+// otherwise gen.Next is moved to the first contract that isn't written.
+func generateStateSnapshotBatch(
+	tx db.RwTx, shardId types.ShardId, root common.Hash, gen *db.StateSnapshotGenerator, limit int,
+) (bool, error) {
+	contractTrie := NewDbContractTrieReader(tx, shardId)
+	contractTrie.SetRootHash(root)
+
+	written := 0
+	for key, value := range contractTrie.IterateFrom(gen.Next.Bytes()) {
+		if written >= limit {
+			gen.Next = common.BytesToHash(key)
+			return false, nil
+		}
+
+		contract := new(types.SmartContract)
+		if err := contract.UnmarshalSSZ(value); err != nil {
+			return false, fmt.Errorf("failed to read contract: %w", err)
+		}
+		n, err := writeStateSnapshotContract(tx, shardId, contract)
+		if err != nil {
+			return false, err
+		}
+		written += n
+	}
+	return true, nil
+}
+
+// writeStateSnapshotContract copies the contract with its storage and tokens from the tries to the snapshot
+// and returns the number of the written entries.
+func writeStateSnapshotContract(tx db.RwTx, shardId types.ShardId, contract *types.SmartContract) (int, error) {
+	if err := db.WriteSnapshotContract(tx, shardId, contract); err != nil {
+		return 0, err
+	}
+
+	storageTrie := NewDbStorageTrieReader(tx, shardId)
+	storageTrie.SetRootHash(contract.StorageRoot)
+	storage, err := storageTrie.Entries()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read storage of %s: %w", contract.Address, err)
+	}
+	for _, kv := range storage {
+		if err := db.WriteSnapshotStorage(tx, shardId, contract.Address, kv.Key, kv.Val.Bytes32()); err != nil {
+			return 0, err
+		}
+	}
+
+	tokenTrie := NewDbTokenTrieReader(tx, shardId)
+	tokenTrie.SetRootHash(contract.TokenRoot)
+	tokens, err := tokenTrie.Entries()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read tokens of %s: %w", contract.Address, err)
+	}
+	for _, kv := range tokens {
+		if err := db.WriteSnapshotToken(tx, shardId, contract.Address, kv.Key, *kv.Val); err != nil {
+			return 0, err
+		}
+	}
+
+	return 1 + len(storage) + len(tokens), nil
+}
+
+// VerifyStateSnapshot rebuilds the state tries of the shard from its snapshot in a scratch database
+// and checks that their roots match the snapshot contracts and the latest block of the shard.
+// The tries are rebuilt in batches of contracts, each in its own scratch transaction.
+func VerifyStateSnapshot(ctx context.Context, tx db.RoTx, shardId types.ShardId) error {
+	block, _, err := db.ReadLastBlock(tx, shardId)
+	if err != nil {
+		return fmt.Errorf("failed to read the last block: %w", err)
+	}
+	gen, err := db.ReadStateSnapshotGenerator(tx, shardId)
+	if err != nil {
+		return err
+	}
+	if gen != nil {
+		return fmt.Errorf("%w: the snapshot is being generated", ErrStateSnapshotGenerating)
+	}
+	root, err := db.ReadStateSnapshotRoot(tx, shardId)
+	if err != nil {
+		return err
+	}
+	if root != block.SmartContractsRoot {
+		return fmt.Errorf("%w: snapshot root %s, last block %d root %s",
+			ErrStateSnapshotMismatch, root, block.Id, block.SmartContractsRoot)
+	}
+
+	scratch, err := db.NewBadgerDbInMemory()
+	if err != nil {
+		return err
+	}
+	defer scratch.Close()
+
+	contractsRoot := common.EmptyHash
+	batch := make([]*types.SmartContract, 0, stateSnapshotVerifyBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		var err error
+		contractsRoot, err = verifyStateSnapshotBatch(ctx, tx, scratch, shardId, contractsRoot, batch)
+		batch = batch[:0]
+		return err
+	}
+
+	if err := db.ForEachSnapshotContract(tx, shardId, func(contract *types.SmartContract) error {
+		batch = append(batch, contract)
+		if len(batch) < stateSnapshotVerifyBatchSize {
+			return nil
+		}
+		return flush()
+	}); err != nil {
+		return fmt.Errorf("failed to read snapshot contracts: %w", err)
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	if contractsRoot != root {
+		return fmt.Errorf("%w: contracts root is %s, expected %s", ErrStateSnapshotMismatch, contractsRoot, root)
+	}
+	return nil
+}
+
+// verifyStateSnapshotBatch checks the storage and token roots of the contracts and adds them
+// to the contracts trie with the given root in the scratch database. It returns the new root of the trie.
+func verifyStateSnapshotBatch(
+	ctx context.Context,
+	tx db.RoTx,
+	scratch db.DB,
+	shardId types.ShardId,
+	contractsRoot common.Hash,
+	contracts []*types.SmartContract,
+) (common.Hash, error) {
+	scratchTx, err := scratch.CreateRwTx(ctx)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	defer scratchTx.Rollback()
+
+	keys := make([]common.Hash, 0, len(contracts))
+	for _, contract := range contracts {
+		storage, err := db.ReadSnapshotStorageEntries(tx, shardId, contract.Address)
+		if err != nil {
+			return common.EmptyHash, err
+		}
+		storageTrie := NewDbStorageTrie(scratchTx, shardId)
+		if err := UpdateFromMap(storageTrie, storage, func(v common.Hash) *types.Uint256 {
+			return (*types.Uint256)(v.Uint256())
+		}); err != nil {
+			return common.EmptyHash, err
+		}
+		if storageTrie.RootHash() != contract.StorageRoot {
+			return common.EmptyHash, fmt.Errorf("%w: storage root of %s is %s, expected %s",
+				ErrStateSnapshotMismatch, contract.Address, storageTrie.RootHash(), contract.StorageRoot)
+		}
+
+		tokens, err := db.ReadSnapshotTokens(tx, shardId, contract.Address)
+		if err != nil {
+			return common.EmptyHash, err
+		}
+		tokenTrie := NewDbTokenTrie(scratchTx, shardId)
+		if err := UpdateFromMap(tokenTrie, tokens, func(v types.Value) *types.Value { return &v }); err != nil {
+			return common.EmptyHash, err
+		}
+		if tokenTrie.RootHash() != contract.TokenRoot {
+			return common.EmptyHash, fmt.Errorf("%w: token root of %s is %s, expected %s",
+				ErrStateSnapshotMismatch, contract.Address, tokenTrie.RootHash(), contract.TokenRoot)
+		}
+
+		keys = append(keys, contract.Address.Hash())
+	}
+
+	contractTrie := NewDbContractTrie(scratchTx, shardId)
+	contractTrie.SetRootHash(contractsRoot)
+	if err := contractTrie.UpdateBatch(keys, contracts); err != nil {
+		return common.EmptyHash, err
+	}
+	root := contractTrie.RootHash()
+	return root, scratchTx.Commit()
+}
@@ -0,0 +1,180 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateSnapshot(t *testing.T) {
+	t.Parallel()
+
+	const shardId = types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	addr := types.GenerateRandomAddress(shardId)
+	key1 := common.IntToHash(1)
+	key2 := common.IntToHash(2)
+	token := types.TokenId(types.GenerateRandomAddress(shardId))
+
+	commit := func(es *ExecutionState, blockId types.BlockNumber) *types.Block {
+		t.Helper()
+
+		res, err := es.Commit(blockId, nil)
+		require.NoError(t, err)
+		require.NoError(t, db.WriteLastBlockHash(tx, shardId, res.BlockHash))
+		require.NoError(t, VerifyStateSnapshot(t.Context(), tx, shardId))
+		return res.Block
+	}
+
+	es, err := NewExecutionState(tx, shardId, StateParams{ConfigAccessor: config.GetStubAccessor()})
+	require.NoError(t, err)
+	require.NotNil(t, es.stateSnapshot())
+
+	require.NoError(t, es.CreateAccount(addr))
+	require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(100)))
+	require.NoError(t, es.SetState(addr, key1, common.IntToHash(42)))
+	require.NoError(t, es.SetState(addr, key2, common.IntToHash(43)))
+	account, err := es.GetAccount(addr)
+	require.NoError(t, err)
+	account.SetTokenBalance(token, types.NewValueFromUint64(7))
+	block := commit(es, 0)
+
+	contract, err := db.ReadSnapshotContract(tx, shardId, addr)
+	require.NoError(t, err)
+	assert.Equal(t, types.NewValueFromUint64(100), contract.Balance)
+	storage, err := db.ReadSnapshotStorageEntries(tx, shardId, addr)
+	require.NoError(t, err)
+	assert.Equal(t, map[common.Hash]common.Hash{key1: common.IntToHash(42), key2: common.IntToHash(43)}, storage)
+
+	// The next block reads the state from the snapshot, the removed values are dropped from it
+	es, err = NewExecutionState(tx, shardId, StateParams{Block: block, ConfigAccessor: config.GetStubAccessor()})
+	require.NoError(t, err)
+	require.NotNil(t, es.stateSnapshot())
+
+	value, err := es.GetState(addr, key1)
+	require.NoError(t, err)
+	assert.Equal(t, common.IntToHash(42), value)
+	require.NoError(t, es.SetState(addr, key2, common.EmptyHash))
+	account, err = es.GetAccount(addr)
+	require.NoError(t, err)
+	assert.Equal(t, types.NewValueFromUint64(7), *account.GetTokenBalance(token))
+	account.SetTokenBalance(token, types.Value0)
+	block = commit(es, 1)
+
+	storage, err = db.ReadSnapshotStorageEntries(tx, shardId, addr)
+	require.NoError(t, err)
+	assert.Equal(t, map[common.Hash]common.Hash{key1: common.IntToHash(42)}, storage)
+	tokens, err := db.ReadSnapshotTokens(tx, shardId, addr)
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	t.Run("Mismatch", func(t *testing.T) {
+		require.NoError(t, db.WriteSnapshotStorage(tx, shardId, addr, key1, common.IntToHash(1)))
+		require.ErrorIs(t, VerifyStateSnapshot(t.Context(), tx, shardId), ErrStateSnapshotMismatch)
+
+		require.NoError(t, db.WriteSnapshotStorage(tx, shardId, addr, key1, common.IntToHash(42)))
+		require.NoError(t, VerifyStateSnapshot(t.Context(), tx, shardId))
+	})
+
+	t.Run("TracedContracts", func(t *testing.T) {
+		// The replaced contracts tree is read instead of the snapshot
+		es, err := NewExecutionState(tx, shardId, StateParams{Block: block, ConfigAccessor: config.GetStubAccessor()})
+		require.NoError(t, err)
+		require.NotNil(t, es.stateSnapshot())
+
+		es.ContractTree = &DbContractAccessor{ContractTrie: NewDbContractTrie(tx, shardId)}
+		assert.Nil(t, es.stateSnapshot())
+		account, err := es.GetAccount(addr)
+		require.NoError(t, err)
+		assert.Nil(t, account)
+	})
+
+	t.Run("Regenerate", func(t *testing.T) {
+		// The state built on an older block doesn't use the snapshot and regenerates it on commit
+		es, err := NewExecutionState(tx, shardId, StateParams{ConfigAccessor: config.GetStubAccessor()})
+		require.NoError(t, err)
+		require.Nil(t, es.stateSnapshot())
+
+		require.NoError(t, es.CreateAccount(addr))
+		commit(es, 0)
+
+		storage, err := db.ReadSnapshotStorageEntries(tx, shardId, addr)
+		require.NoError(t, err)
+		assert.Empty(t, storage)
+	})
+}
+
+func TestStateSnapshotGeneration(t *testing.T) { //nolint:paralleltest // changes the batch sizes
+	const shardId = types.BaseShardId
+
+	defer func(batchSize, verifyBatchSize int) {
+		stateSnapshotBatchSize = batchSize
+		stateSnapshotVerifyBatchSize = verifyBatchSize
+	}(stateSnapshotBatchSize, stateSnapshotVerifyBatchSize)
+	stateSnapshotBatchSize = 4
+	stateSnapshotVerifyBatchSize = 2
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	addrs := make([]types.Address, 5)
+	es, err := NewExecutionState(tx, shardId, StateParams{ConfigAccessor: config.GetStubAccessor()})
+	require.NoError(t, err)
+	for i := range addrs {
+		addrs[i] = types.GenerateRandomAddress(shardId)
+		require.NoError(t, es.CreateAccount(addrs[i]))
+		require.NoError(t, es.SetState(addrs[i], common.IntToHash(1), common.IntToHash(i+1)))
+		require.NoError(t, es.SetState(addrs[i], common.IntToHash(2), common.IntToHash(i+1)))
+	}
+	res, err := es.Commit(0, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.WriteLastBlockHash(tx, shardId, res.BlockHash))
+	require.NoError(t, VerifyStateSnapshot(t.Context(), tx, shardId))
+
+	// Drop the snapshot, so that it's generated from the tries while the blocks change the state
+	require.NoError(t, db.DeleteStateSnapshotRoot(tx, shardId))
+	block := res.Block
+	for i := 1; ; i++ {
+		require.Less(t, i, 20, "the snapshot isn't generated")
+
+		es, err := NewExecutionState(tx, shardId, StateParams{Block: block, ConfigAccessor: config.GetStubAccessor()})
+		require.NoError(t, err)
+		require.Nil(t, es.stateSnapshot())
+
+		addr := addrs[i%len(addrs)]
+		require.NoError(t, es.SetState(addr, common.IntToHash(1), common.IntToHash(100+i)))
+		require.NoError(t, es.SetState(addr, common.IntToHash(2), common.EmptyHash))
+		res, err := es.Commit(types.BlockNumber(i), nil)
+		require.NoError(t, err)
+		require.NoError(t, db.WriteLastBlockHash(tx, shardId, res.BlockHash))
+		block = res.Block
+
+		gen, err := db.ReadStateSnapshotGenerator(tx, shardId)
+		require.NoError(t, err)
+		if gen == nil {
+			break
+		}
+		require.ErrorIs(t, VerifyStateSnapshot(t.Context(), tx, shardId), ErrStateSnapshotGenerating)
+	}
+	require.NoError(t, VerifyStateSnapshot(t.Context(), tx, shardId))
+
+	es, err = NewExecutionState(tx, shardId, StateParams{Block: block, ConfigAccessor: config.GetStubAccessor()})
+	require.NoError(t, err)
+	require.NotNil(t, es.stateSnapshot())
+}
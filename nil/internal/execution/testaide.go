@@ -34,7 +34,7 @@ func init() {
 	check.PanicIfErr(err)
 }
 
-func GenerateZeroState(t *testing.T, shardId types.ShardId, txFabric db.DB) *types.Block {
+func GenerateZeroState(t testing.TB, shardId types.ShardId, txFabric db.DB) *types.Block {
 	t.Helper()
 
 	g, err := NewBlockGenerator(t.Context(),
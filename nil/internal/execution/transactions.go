@@ -185,6 +185,10 @@ func validateExternalExecutionTransaction(es *ExecutionState, transaction *types
 		return NewExecutionResult().SetError(types.NewWrapError(types.ErrorSeqnoGap, err))
 	}
 
+	if es.devCheats.IsImpersonated(to) {
+		return NewExecutionResult()
+	}
+
 	return es.CallVerifyExternal(transaction, account)
 }
 
@@ -216,3 +220,27 @@ func ValidateExternalTransaction(es *ExecutionState, transaction *types.Transact
 		return validateExternalExecutionTransaction(es, transaction)
 	}
 }
+
+// VerifyExternalTransactionAuth checks the signature or the other auth data of the external transaction
+// with the verifyExternal method of the receiver. Unlike ValidateExternalTransaction, the seqno is not checked,
+// so that the transactions queued in the pool can be verified too.
+// The transactions to the absent contracts are not verified.
+func VerifyExternalTransactionAuth(es *ExecutionState, transaction *types.Transaction) *ExecutionResult {
+	check.PanicIfNot(transaction.IsExternal())
+
+	if !transaction.IsExecution() || es.devCheats.IsImpersonated(transaction.To) {
+		return NewExecutionResult()
+	}
+
+	if exists, err := es.ContractExists(transaction.To); err != nil {
+		return NewExecutionResult().SetFatal(err)
+	} else if !exists {
+		return NewExecutionResult()
+	}
+
+	account, err := es.GetAccount(transaction.To)
+	if err != nil {
+		return NewExecutionResult().SetFatal(err)
+	}
+	return es.CallVerifyExternal(transaction, account)
+}
@@ -30,8 +30,9 @@ type MainKeys struct {
 }
 
 type ConfigParams struct {
-	Validators config.ParamValidators `yaml:"validators,omitempty"`
-	GasPrice   config.ParamGasPrice   `yaml:"gasPrice"`
+	Validators    config.ParamValidators    `yaml:"validators,omitempty"`
+	GasPrice      config.ParamGasPrice      `yaml:"gasPrice"`
+	BlockGasLimit config.ParamBlockGasLimit `yaml:"blockGasLimit,omitempty"`
 }
 
 type ZeroStateConfig struct {
@@ -152,6 +153,10 @@ func (es *ExecutionState) GenerateZeroState(stateConfig *ZeroStateConfig) error
 		if err != nil {
 			return err
 		}
+		err = config.SetParamBlockGasLimit(cfgAccessor, &stateConfig.ConfigParams.BlockGasLimit)
+		if err != nil {
+			return err
+		}
 	}
 
 	if len(stateConfig.ConfigParams.GasPrice.Shards) != 0 {
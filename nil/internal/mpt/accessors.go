@@ -1,6 +1,8 @@
 package mpt
 
 import (
+	"context"
+
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
@@ -17,14 +19,34 @@ type DbGetter struct {
 	tx        db.RoTx
 	shardId   types.ShardId
 	tableName db.ShardedTableName
+	cache     *NodeCache
 }
 
 func NewDbGetter(tx db.RoTx, shardId types.ShardId, tableName db.ShardedTableName) *DbGetter {
-	return &DbGetter{tx, shardId, tableName}
+	return &DbGetter{tx, shardId, tableName, sharedNodeCache}
 }
 
+// Get looks up the node in the buffer of the transaction and the node cache before reading it from the database.
 func (g *DbGetter) Get(key []byte) ([]byte, error) {
-	return g.tx.GetFromShard(g.shardId, g.tableName, key)
+	if buffered, ok := g.tx.(*BufferedTx); ok {
+		if data, ok := buffered.getNode(g.shardId, g.tableName, key); ok {
+			return data, nil
+		}
+	}
+	if g.cache != nil {
+		if data, ok := g.cache.get(key); ok {
+			return data, nil
+		}
+	}
+
+	data, err := g.tx.GetFromShard(g.shardId, g.tableName, key)
+	if err != nil {
+		return nil, err
+	}
+	if g.cache != nil {
+		g.cache.add(key, data)
+	}
+	return data, nil
 }
 
 type DbSetter struct {
@@ -37,7 +59,17 @@ func NewDbSetter(tx db.RwTx, shardId types.ShardId, tableName db.ShardedTableNam
 	return &DbSetter{tx, shardId, tableName}
 }
 
+// Set buffers the node if the transaction is buffered, otherwise it's written to the database immediately.
+// The node isn't cached, since the transaction may be rolled back; it's cached once it's read.
 func (s *DbSetter) Set(key, value []byte) error {
+	nodeMetrics.nodesStored.Add(context.Background(), 1)
+
+	if buffered, ok := s.tx.(*BufferedTx); ok {
+		buffered.setNode(s.shardId, s.tableName, key, value)
+		return nil
+	}
+
+	nodeMetrics.nodesWritten.Add(context.Background(), 1)
 	return s.tx.PutToShard(s.shardId, s.tableName, key, value)
 }
 
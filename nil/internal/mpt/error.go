@@ -5,4 +5,7 @@ import "errors"
 var (
 	ErrInvalidAction  = errors.New("invalid action")
 	ErrInvalidArgSize = errors.New("invalid arg size for batch update")
+	// ErrMissingNode is returned along with db.ErrKeyNotFound when a node of the trie isn't found in the storage,
+	// so the lookup doesn't prove that the key is absent.
+	ErrMissingNode = errors.New("missing trie node")
 )
@@ -706,6 +706,9 @@ func (m *Reader) getNode(ref Reference) (Node, error) {
 		return DecodeNode(ref)
 	}
 	data, err := m.getter.Get(ref)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrMissingNode, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1,14 +1,24 @@
 package mpt
 
 import (
+	"cmp"
 	"iter"
 )
 
 func (m *Reader) Iterate() iter.Seq2[[]byte, []byte] {
+	return m.IterateFrom(nil)
+}
+
+// IterateFrom iterates over the entries of the trie with the keys not less than start in the order of the keys.
+func (m *Reader) IterateFrom(start []byte) iter.Seq2[[]byte, []byte] {
 	type Yield = func([]byte, []byte) bool
 	return func(yield Yield) {
-		var iter func(ref Reference, path *Path)
-		iter = func(ref Reference, path *Path) {
+		startPath := newPath(start, false)
+		stopped := false
+
+		// bounded is set while the path is a prefix of the start, so the entries less than the start are skipped
+		var iter func(ref Reference, path *Path, bounded bool)
+		iter = func(ref Reference, path *Path, bounded bool) {
 			node, err := m.getNode(ref)
 			if err != nil {
 				return
@@ -17,11 +27,19 @@ func (m *Reader) Iterate() iter.Seq2[[]byte, []byte] {
 			if npath != nil {
 				path = path.Combine(npath)
 			}
+			if bounded {
+				if c := comparePrefix(path, startPath); c < 0 {
+					return
+				} else if c > 0 || path.Size() >= startPath.Size() {
+					bounded = false
+				}
+			}
 			data := node.Data()
-			if len(data) > 0 {
+			if len(data) > 0 && !bounded {
 				// note: even though we access path.Data directly here is ok
 				// cause every key in the mpt is []byte, i.e. it consists of even number of nibbles
 				if !yield(path.Data, data) {
+					stopped = true
 					return
 				}
 			}
@@ -29,16 +47,29 @@ func (m *Reader) Iterate() iter.Seq2[[]byte, []byte] {
 			case *BranchNode:
 				for i, br := range node.Branches {
 					if len(br) > 0 {
-						iter(br, path.Combine(newPath([]byte{byte(i)}, true)))
+						iter(br, path.Combine(newPath([]byte{byte(i)}, true)), bounded)
+						if stopped {
+							return
+						}
 					}
 				}
 				return
 			case *ExtensionNode:
-				iter(node.NextRef, path)
+				iter(node.NextRef, path, bounded)
 			}
 		}
 		if m.root.IsValid() {
-			iter(m.root, newPath(nil, false))
+			iter(m.root, newPath(nil, false), len(start) > 0)
+		}
+	}
+}
+
+// comparePrefix compares the paths by as many first nibbles as the shorter one has.
+func comparePrefix(a, b *Path) int {
+	for i := range min(a.Size(), b.Size()) {
+		if c := cmp.Compare(a.At(i), b.At(i)); c != 0 {
+			return c
 		}
 	}
+	return 0
 }
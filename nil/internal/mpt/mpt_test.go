@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -121,6 +122,40 @@ func TestIterate(t *testing.T) {
 	require.Len(t, keys, i)
 }
 
+func TestIterateFrom(t *testing.T) {
+	t.Parallel()
+
+	trie := NewInMemMPT()
+	keys := [][]byte{[]byte("do"), []byte("dog"), []byte("doge"), []byte("horse")}
+	for _, key := range keys {
+		require.NoError(t, trie.Set(key, key))
+	}
+
+	collect := func(start []byte, limit int) [][]byte {
+		t.Helper()
+
+		res := make([][]byte, 0)
+		for k := range trie.IterateFrom(start) {
+			if len(res) == limit {
+				break
+			}
+			res = append(res, k)
+		}
+		return res
+	}
+
+	assert.Equal(t, keys, collect(nil, len(keys)))
+	assert.Equal(t, keys, collect([]byte("a"), len(keys)))
+	assert.Equal(t, keys, collect([]byte("do"), len(keys)))
+	assert.Equal(t, keys[1:], collect([]byte("doa"), len(keys)))
+	assert.Equal(t, keys[3:], collect([]byte("dogf"), len(keys)))
+	assert.Equal(t, keys[3:], collect([]byte("e"), len(keys)))
+	assert.Empty(t, collect([]byte("i"), len(keys)))
+
+	// The iteration can be stopped
+	assert.Equal(t, keys[1:3], collect([]byte("dog"), 2))
+}
+
 func TestInsertGetLots(t *testing.T) {
 	t.Parallel()
 
@@ -257,6 +292,24 @@ func TestSmallRootHash(t *testing.T) {
 	assert.Equal(t, value, getValue(t, trie2, key))
 }
 
+func TestMissingNode(t *testing.T) {
+	t.Parallel()
+
+	trie := NewInMemMPT()
+	require.NoError(t, trie.Set([]byte("key"), []byte("value_0000000000000000000000000000000000000000")))
+
+	// The key is proven to be absent
+	_, err := trie.Get([]byte("wrong_key"))
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+	require.NotErrorIs(t, err, ErrMissingNode)
+
+	// The trie can't be walked without its root node
+	trie.SetRootHash(common.HexToHash("0x1234"))
+	_, err = trie.Get([]byte("key"))
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+	require.ErrorIs(t, err, ErrMissingNode)
+}
+
 func TestInsertBatch(t *testing.T) {
 	t.Parallel()
 
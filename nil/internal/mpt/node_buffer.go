@@ -0,0 +1,78 @@
+package mpt
+
+import (
+	"context"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+type bufferedNodeKey struct {
+	shardId   types.ShardId
+	tableName db.ShardedTableName
+	key       string
+}
+
+// BufferedTx is a transaction that keeps the nodes stored by the database tries created over it in memory
+// until Flush is called. So the nodes of the blocks that are built but never committed don't reach the database,
+// and a node stored several times is written once.
+type BufferedTx struct {
+	db.RwTx
+
+	mutex sync.RWMutex
+	nodes map[bufferedNodeKey][]byte
+}
+
+var _ db.RwTx = (*BufferedTx)(nil)
+
+func NewBufferedTx(tx db.RwTx) *BufferedTx {
+	return &BufferedTx{
+		RwTx:  tx,
+		nodes: make(map[bufferedNodeKey][]byte),
+	}
+}
+
+func (tx *BufferedTx) getNode(shardId types.ShardId, tableName db.ShardedTableName, key []byte) ([]byte, bool) {
+	tx.mutex.RLock()
+	defer tx.mutex.RUnlock()
+
+	data, ok := tx.nodes[bufferedNodeKey{shardId, tableName, string(key)}]
+	return data, ok
+}
+
+func (tx *BufferedTx) setNode(shardId types.ShardId, tableName db.ShardedTableName, key, data []byte) {
+	tx.mutex.Lock()
+	defer tx.mutex.Unlock()
+
+	tx.nodes[bufferedNodeKey{shardId, tableName, string(key)}] = data
+}
+
+// Flush writes the buffered nodes to the underlying transaction.
+func (tx *BufferedTx) Flush() error {
+	tx.mutex.Lock()
+	defer tx.mutex.Unlock()
+
+	for k, data := range tx.nodes {
+		if err := tx.RwTx.PutToShard(k.shardId, k.tableName, []byte(k.key), data); err != nil {
+			return err
+		}
+	}
+	nodeMetrics.nodesWritten.Add(context.Background(), int64(len(tx.nodes)))
+	clear(tx.nodes)
+	return nil
+}
+
+func (tx *BufferedTx) Commit() error {
+	if err := tx.Flush(); err != nil {
+		return err
+	}
+	return tx.RwTx.Commit()
+}
+
+func (tx *BufferedTx) CommitWithTs() (db.Timestamp, error) {
+	if err := tx.Flush(); err != nil {
+		return 0, err
+	}
+	return tx.RwTx.CommitWithTs()
+}
@@ -0,0 +1,66 @@
+package mpt
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// DefaultNodeCacheSize is the number of nodes kept by the cache shared by the database tries.
+// The nodes are a few hundred bytes at most, so the cache takes up to about a hundred megabytes.
+const DefaultNodeCacheSize = 1 << 18
+
+// NodeCache keeps the recently used encoded nodes by their keys.
+// The key of a node is derived from its content, so the cached nodes never become stale
+// and the cache can be shared by all tries and transactions.
+type NodeCache struct {
+	nodes *lru.Cache[common.Hash, []byte]
+}
+
+var sharedNodeCache = NewNodeCache(DefaultNodeCacheSize)
+
+func NewNodeCache(size int) *NodeCache {
+	nodes, err := lru.New[common.Hash, []byte](size)
+	check.PanicIfErr(err)
+	return &NodeCache{nodes: nodes}
+}
+
+func (c *NodeCache) get(key []byte) ([]byte, bool) {
+	data, ok := c.nodes.Get(common.BytesToHash(key))
+	if ok {
+		nodeMetrics.cacheHits.Add(context.Background(), 1)
+	} else {
+		nodeMetrics.cacheMisses.Add(context.Background(), 1)
+	}
+	return data, ok
+}
+
+func (c *NodeCache) add(key, data []byte) {
+	c.nodes.Add(common.BytesToHash(key), data)
+}
+
+type metrics struct {
+	// Hit rate of the node cache
+	cacheHits   metric.Int64Counter
+	cacheMisses metric.Int64Counter
+
+	// Write amplification: nodes stored by the tries vs. nodes written to the database
+	nodesStored  metric.Int64Counter
+	nodesWritten metric.Int64Counter
+}
+
+var nodeMetrics = newMetrics()
+
+func newMetrics() *metrics {
+	meter := telemetry.NewMeter("github.com/NilFoundation/nil/nil/internal/mpt")
+	return &metrics{
+		cacheHits:    telemetry.Int64Counter(meter, "node_cache_hits"),
+		cacheMisses:  telemetry.Int64Counter(meter, "node_cache_misses"),
+		nodesStored:  telemetry.Int64Counter(meter, "nodes_stored"),
+		nodesWritten: telemetry.Int64Counter(meter, "nodes_written"),
+	}
+}
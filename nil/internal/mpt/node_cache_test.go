@@ -0,0 +1,141 @@
+package mpt
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDbTrie(tx db.RwTx, cache *NodeCache) *MerklePatriciaTrie {
+	return NewMPT(
+		&DbSetter{tx, types.BaseShardId, db.StorageTrieTable},
+		NewReader(&DbGetter{tx, types.BaseShardId, db.StorageTrieTable, cache}))
+}
+
+func TestBufferedTx(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	buffered := NewBufferedTx(tx)
+	cache := NewNodeCache(16)
+	trie := newDbTrie(buffered, cache)
+	for _, kv := range generateTestCase(newRandGen(), 100, 8, 32, "abcdefgh") {
+		require.NoError(t, trie.Set(kv.key, kv.value))
+	}
+	root := trie.RootHash()
+
+	// The nodes are available through the buffered transaction only, the stored nodes aren't cached
+	assert.NotEmpty(t, buffered.nodes)
+	assert.Zero(t, cache.nodes.Len())
+	_, err = tx.GetFromShard(types.BaseShardId, db.StorageTrieTable, root.Bytes())
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+	require.NoError(t, buffered.Flush())
+	assert.Empty(t, buffered.nodes)
+
+	// The nodes are read from the database without the cache after flushing
+	reader := newDbTrie(tx, nil)
+	reader.SetRootHash(root)
+	for k, v := range trie.Iterate() {
+		assert.Equal(t, v, getValue(t, reader, k))
+	}
+	// The nodes read by the trie from the database are cached
+	assert.NotZero(t, cache.nodes.Len())
+}
+
+// BenchmarkBlockGeneration accesses the storage trie block by block like the block generator does:
+// each block opens a transaction, the transactions of the block read random slots,
+// and the modified slots are written in a batch on commit.
+func BenchmarkBlockGeneration(b *testing.B) {
+	const (
+		numSlots       = 100000
+		readsPerBlock  = 1000
+		writesPerBlock = 100
+	)
+
+	run := func(b *testing.B, cache *NodeCache, buffer bool) {
+		b.Helper()
+
+		database, err := db.NewBadgerDb(b.TempDir())
+		require.NoError(b, err)
+		defer database.Close()
+
+		gen := newRandGen()
+		slot := func(i uint64) []byte {
+			return common.IntToHash(int(i)).Bytes()
+		}
+		value := func() []byte {
+			return binary.LittleEndian.AppendUint64(nil, gen.Uint64())
+		}
+
+		// Fill the trie in chunks to keep the transactions small
+		const chunkSize = 10000
+		var root common.Hash
+		for i := uint64(0); i < numSlots; i += chunkSize {
+			tx, err := database.CreateRwTx(b.Context())
+			require.NoError(b, err)
+			trie := newDbTrie(tx, nil)
+			trie.SetRootHash(root)
+			keys := make([][]byte, 0, chunkSize)
+			values := make([][]byte, 0, chunkSize)
+			for j := i; j < i+chunkSize; j++ {
+				keys = append(keys, slot(j))
+				values = append(values, value())
+			}
+			require.NoError(b, trie.SetBatch(keys, values))
+			root = trie.RootHash()
+			require.NoError(b, tx.Commit())
+		}
+
+		b.ResetTimer()
+		for range b.N {
+			tx, err := database.CreateRwTx(b.Context())
+			require.NoError(b, err)
+
+			var trieTx db.RwTx = tx
+			if buffer {
+				trieTx = NewBufferedTx(tx)
+			}
+			trie := newDbTrie(trieTx, cache)
+			trie.SetRootHash(root)
+			for range readsPerBlock {
+				_, err := trie.Get(slot(gen.Uint64N(numSlots)))
+				require.NoError(b, err)
+			}
+
+			keys := make([][]byte, 0, writesPerBlock)
+			values := make([][]byte, 0, writesPerBlock)
+			for range writesPerBlock {
+				keys = append(keys, slot(gen.Uint64N(numSlots)))
+				values = append(values, value())
+			}
+			require.NoError(b, trie.SetBatch(keys, values))
+			root = trie.RootHash()
+			require.NoError(b, trieTx.Commit())
+		}
+	}
+
+	b.Run("no cache", func(b *testing.B) {
+		run(b, nil, false)
+	})
+
+	b.Run("cache", func(b *testing.B) {
+		run(b, NewNodeCache(DefaultNodeCacheSize), false)
+	})
+
+	b.Run("cache and buffer", func(b *testing.B) {
+		run(b, NewNodeCache(DefaultNodeCacheSize), true)
+	})
+}
@@ -14,6 +14,8 @@ type reputationChangeReason string
 
 const (
 	ReputationChangeInvalidBlockSignature = reputationChangeReason("invalid block signature")
+	ReputationChangeRequestFailed         = reputationChangeReason("request failed")
+	ReputationChangeInvalidGossipMessage  = reputationChangeReason("invalid gossip message")
 )
 
 type ReputationChangeSettings = map[reputationChangeReason]Reputation
@@ -21,6 +23,8 @@ type ReputationChangeSettings = map[reputationChangeReason]Reputation
 func DefaultReputationChangeSettings() ReputationChangeSettings {
 	return ReputationChangeSettings{
 		ReputationChangeInvalidBlockSignature: -100,
+		ReputationChangeRequestFailed:         -10,
+		ReputationChangeInvalidGossipMessage:  -20,
 	}
 }
 
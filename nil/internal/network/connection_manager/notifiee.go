@@ -127,6 +127,16 @@ func (n *notifiee) ReportPeer(peer peer.ID, reputationChangeReason reputationCha
 	}
 }
 
+func (n *notifiee) Reputation(peer peer.ID) Reputation {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if pi, ok := n.peerInfos[peer]; ok {
+		return pi.reputation
+	}
+	return 0
+}
+
 func (n *notifiee) isBanned(pi *peerInfo) bool {
 	return pi.reputation < n.config.ReputationBanThreshold
 }
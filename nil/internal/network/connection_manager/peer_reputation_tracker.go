@@ -7,6 +7,7 @@ import (
 
 type PeerReputationTracker interface {
 	ReportPeer(peer.ID, reputationChangeReason)
+	Reputation(peer.ID) Reputation
 }
 
 func TryGetPeerReputationTracker(host host.Host) PeerReputationTracker {
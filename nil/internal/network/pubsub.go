@@ -7,6 +7,7 @@ import (
 	"sync/atomic"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
+	cm "github.com/NilFoundation/nil/nil/internal/network/connection_manager"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
@@ -14,6 +15,22 @@ import (
 
 const subscriptionChannelSize = 100
 
+// ValidationResult is the verdict of a topic validator on a received message.
+type ValidationResult = pubsub.ValidationResult
+
+const (
+	// ValidationAccept delivers the message to the subscribers and relays it to the other peers.
+	ValidationAccept = pubsub.ValidationAccept
+	// ValidationReject drops the message and penalizes the peer that has sent it.
+	ValidationReject = pubsub.ValidationReject
+	// ValidationIgnore drops the message without penalizing the peer.
+	ValidationIgnore = pubsub.ValidationIgnore
+)
+
+// TopicValidator checks a message received from the other peers before it is delivered to the subscribers
+// and relayed further. It is run for every message, so it should perform only cheap checks.
+type TopicValidator func(ctx context.Context, msg PubSubMessage) ValidationResult
+
 type PubSub struct {
 	impl   *pubsub.PubSub // +checklocksignore: mu is not required, it just happens to be held always.
 	prefix string
@@ -22,6 +39,8 @@ type PubSub struct {
 	topics map[string]*pubsub.Topic // +checklocks:mu
 	self   PeerID
 
+	reputationTracker cm.PeerReputationTracker
+
 	meter         telemetry.Meter
 	published     telemetry.Counter
 	publishedSize telemetry.Counter
@@ -42,6 +61,8 @@ type Subscription struct {
 	impl *pubsub.Subscription
 	self PeerID
 
+	unregisterValidator func()
+
 	received     telemetry.Counter
 	receivedSize telemetry.Counter
 	logger       logging.Logger
@@ -50,7 +71,9 @@ type Subscription struct {
 
 // newPubSub creates a new PubSub instance. It must be closed after use.
 func newPubSub(ctx context.Context, h Host, conf *Config, logger logging.Logger) (*PubSub, error) {
-	impl, err := pubsub.NewGossipSub(ctx, h)
+	reputationTracker := cm.TryGetPeerReputationTracker(h)
+	impl, err := pubsub.NewGossipSub(ctx, h,
+		pubsub.WithPeerScore(newPeerScoreParams(reputationTracker), newPeerScoreThresholds()))
 	if err != nil {
 		return nil, err
 	}
@@ -66,13 +89,14 @@ func newPubSub(ctx context.Context, h Host, conf *Config, logger logging.Logger)
 	}
 
 	return &PubSub{
-		prefix:        conf.Prefix,
-		impl:          impl,
-		topics:        make(map[string]*pubsub.Topic),
-		self:          h.ID(),
-		meter:         meter,
-		published:     published,
-		publishedSize: publishedSize,
+		prefix:            conf.Prefix,
+		impl:              impl,
+		topics:            make(map[string]*pubsub.Topic),
+		self:              h.ID(),
+		reputationTracker: reputationTracker,
+		meter:             meter,
+		published:         published,
+		publishedSize:     publishedSize,
 		logger: logger.With().
 			Str(logging.FieldComponent, "pub-sub").
 			Logger(),
@@ -130,14 +154,26 @@ func (ps *PubSub) Publish(ctx context.Context, topic string, data []byte) error
 }
 
 // Subscribe subscribes to the given topic. The subscription must be closed after use.
-func (ps *PubSub) Subscribe(topic string) (*Subscription, error) {
+// If the validator is not nil, the messages received from the other peers are delivered and relayed
+// only if it accepts them. Only one subscription with a validator is allowed per topic.
+func (ps *PubSub) Subscribe(topic string, validator TopicValidator) (*Subscription, error) {
 	t, err := ps.getTopic(topic)
 	if err != nil {
 		return nil, err
 	}
 
+	var unregisterValidator func()
+	if validator != nil {
+		if unregisterValidator, err = ps.registerValidator(t.String(), validator); err != nil {
+			return nil, err
+		}
+	}
+
 	impl, err := t.Subscribe()
 	if err != nil {
+		if unregisterValidator != nil {
+			unregisterValidator()
+		}
 		return nil, err
 	}
 
@@ -156,11 +192,41 @@ func (ps *PubSub) Subscribe(topic string) (*Subscription, error) {
 		Logger()
 	logger.Debug().Msg("Subscribed to topic")
 	return &Subscription{
-		impl:         impl,
-		self:         ps.self,
-		received:     received,
-		receivedSize: receivedSize,
-		logger:       logger,
+		impl:                impl,
+		self:                ps.self,
+		unregisterValidator: unregisterValidator,
+		received:            received,
+		receivedSize:        receivedSize,
+		logger:              logger,
+	}, nil
+}
+
+func (ps *PubSub) registerValidator(topic string, validator TopicValidator) (func(), error) {
+	logger := ps.logger.With().Str(logging.FieldTopic, topic).Logger()
+
+	validatorEx := pubsub.ValidatorEx(func(ctx context.Context, from PeerID, msg *pubsub.Message) ValidationResult {
+		// Own messages are trusted, so they are never dropped
+		if from == ps.self {
+			return ValidationAccept
+		}
+
+		res := validator(ctx, PubSubMessage{Data: msg.Data, ReceivedFrom: msg.ReceivedFrom})
+		if res == ValidationReject {
+			logger.Debug().Stringer(logging.FieldPeerId, from).Msg("Rejected invalid message")
+			if ps.reputationTracker != nil {
+				ps.reputationTracker.ReportPeer(from, cm.ReputationChangeInvalidGossipMessage)
+			}
+		}
+		return res
+	})
+	if err := ps.impl.RegisterTopicValidator(topic, validatorEx); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		if err := ps.impl.UnregisterTopicValidator(topic); err != nil {
+			logger.Warn().Err(err).Msg("Failed to unregister topic validator")
+		}
 	}, nil
 }
 
@@ -189,6 +255,9 @@ func (ps *PubSub) getTopic(topic string) (*pubsub.Topic, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := t.SetScoreParams(newTopicScoreParams()); err != nil {
+		return nil, errors.Join(err, t.Close())
+	}
 
 	ps.topics[topic] = t
 	return t, nil
@@ -243,4 +312,7 @@ func (s *Subscription) Counters() *SubscriptionCounters {
 
 func (s *Subscription) Close() {
 	s.impl.Cancel()
+	if s.unregisterValidator != nil {
+		s.unregisterValidator()
+	}
 }
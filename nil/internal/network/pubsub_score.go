@@ -0,0 +1,62 @@
+package network
+
+import (
+	"time"
+
+	cm "github.com/NilFoundation/nil/nil/internal/network/connection_manager"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+// newPeerScoreParams returns the gossipsub peer score parameters.
+// The reputation of the peer is used as its application-specific score,
+// so the peers misbehaving in the other protocols are not welcome in the mesh either.
+func newPeerScoreParams(reputationTracker cm.PeerReputationTracker) *pubsub.PeerScoreParams {
+	return &pubsub.PeerScoreParams{
+		Topics: make(map[string]*pubsub.TopicScoreParams),
+		AppSpecificScore: func(p PeerID) float64 {
+			if reputationTracker == nil {
+				return 0
+			}
+			return float64(reputationTracker.Reputation(p))
+		},
+		AppSpecificWeight: 1,
+
+		BehaviourPenaltyWeight:    -10,
+		BehaviourPenaltyThreshold: 6,
+		BehaviourPenaltyDecay:     0.9,
+
+		DecayInterval: time.Second,
+		DecayToZero:   0.01,
+		RetainScore:   time.Hour,
+	}
+}
+
+// newPeerScoreThresholds returns the scores below which the peers are gradually excluded from the gossip.
+func newPeerScoreThresholds() *pubsub.PeerScoreThresholds {
+	return &pubsub.PeerScoreThresholds{
+		GossipThreshold:             -500,
+		PublishThreshold:            -1000,
+		GraylistThreshold:           -2500,
+		AcceptPXThreshold:           100,
+		OpportunisticGraftThreshold: 5,
+	}
+}
+
+// newTopicScoreParams returns the score parameters of a topic.
+// Every invalid message penalizes the peer much more than a valid one rewards it.
+func newTopicScoreParams() *pubsub.TopicScoreParams {
+	return &pubsub.TopicScoreParams{
+		TopicWeight: 1,
+
+		TimeInMeshWeight:  0.01,
+		TimeInMeshQuantum: time.Second,
+		TimeInMeshCap:     100,
+
+		FirstMessageDeliveriesWeight: 1,
+		FirstMessageDeliveriesDecay:  0.5,
+		FirstMessageDeliveriesCap:    20,
+
+		InvalidMessageDeliveriesWeight: -100,
+		InvalidMessageDeliveriesDecay:  0.5,
+	}
+}
@@ -1,6 +1,8 @@
 package network
 
 import (
+	"bytes"
+	"context"
 	"testing"
 	"time"
 
@@ -57,7 +59,7 @@ func (s *PubSubSuite) TestSingleHost() {
 	defer manager.Close()
 
 	topic := "test"
-	sub, err := manager.PubSub().Subscribe(topic)
+	sub, err := manager.PubSub().Subscribe(topic, nil)
 	s.Require().NoError(err)
 	defer sub.Close()
 
@@ -81,7 +83,7 @@ func (s *PubSubSuite) TestTwoHosts() {
 	const topic = "test"
 	msg := []byte("hello")
 
-	sub, err := m1.PubSub().Subscribe(topic)
+	sub, err := m1.PubSub().Subscribe(topic, nil)
 	s.Require().NoError(err)
 	defer sub.Close()
 	ch := sub.Start(s.context, true)
@@ -93,6 +95,49 @@ func (s *PubSubSuite) TestTwoHosts() {
 	s.receive(ch, msg)
 }
 
+func (s *PubSubSuite) TestValidator() {
+	m1 := s.newManager()
+	defer m1.Close()
+	m2 := s.newManager()
+	defer m2.Close()
+
+	ConnectManagers(s.T(), m1, m2)
+
+	const topic = "test"
+	valid := []byte("valid")
+	invalid := []byte("invalid")
+
+	validator := func(_ context.Context, msg PubSubMessage) ValidationResult {
+		if bytes.Equal(msg.Data, invalid) {
+			return ValidationReject
+		}
+		return ValidationAccept
+	}
+	sub, err := m1.PubSub().Subscribe(topic, validator)
+	s.Require().NoError(err)
+	defer sub.Close()
+	ch := sub.Start(s.context, true)
+
+	// Only one validator is allowed per topic
+	_, err = m1.PubSub().Subscribe(topic, validator)
+	s.Require().Error(err)
+
+	s.Eventually(func() bool {
+		return len(s.listPeers(m2, topic)) > 0
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// Own messages are not validated
+	s.Require().NoError(m1.PubSub().Publish(s.context, topic, invalid))
+
+	s.Require().NoError(m2.PubSub().Publish(s.context, topic, invalid))
+	s.Require().NoError(m2.PubSub().Publish(s.context, topic, valid))
+	s.receive(ch, valid)
+
+	peerReputationTracker := TryGetPeerReputationTracker(m1)
+	s.Require().NotNil(peerReputationTracker)
+	s.Negative(peerReputationTracker.Reputation(m2.host.ID()))
+}
+
 func (s *PubSubSuite) TestComplexScenario() {
 	// todo: this test often fails in CI but works locally
 	s.T().SkipNow()
@@ -131,7 +176,7 @@ func (s *PubSubSuite) TestComplexScenario() {
 
 	s.Run("Subscribe all to topic 1", func() {
 		for i := range n {
-			sub, err := managers[i].PubSub().Subscribe(topic1)
+			sub, err := managers[i].PubSub().Subscribe(topic1, nil)
 			s.Require().NoError(err)
 			topic1Subs[i] = sub
 
@@ -187,7 +232,7 @@ func (s *PubSubSuite) TestComplexScenario() {
 
 		s.Run("Subscribe a single peer to topic 2", func() {
 			var err error
-			sub, err = managers[subscriber].PubSub().Subscribe(topic2)
+			sub, err = managers[subscriber].PubSub().Subscribe(topic2, nil)
 			s.Require().NoError(err)
 		})
 		defer sub.Close()
@@ -10,7 +10,11 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
 
-var errBlockVerify = errors.New("failed to verify block")
+var (
+	errBlockVerify = errors.New("failed to verify block")
+
+	ErrInvalidBlockSignature = errors.New("invalid block signature")
+)
 
 type BlockVerifier struct {
 	shardId types.ShardId
@@ -31,7 +35,7 @@ func (b *BlockVerifier) VerifyBlock(ctx context.Context, block *types.Block) err
 	}
 
 	if err := block.VerifySignature(params.PublicKeys.Keys(), b.shardId); err != nil {
-		return fmt.Errorf("%w: failed to verify signature: %w", errBlockVerify, err)
+		return fmt.Errorf("%w: %w: %w", errBlockVerify, ErrInvalidBlockSignature, err)
 	}
 	return nil
 }
@@ -0,0 +1,257 @@
+package tracing
+
+import (
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/params"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// CombineHooks returns hooks which call all the given hooks in order. Nil hooks are skipped.
+func CombineHooks(hooks ...*Hooks) *Hooks {
+	var nonNil []*Hooks
+	for _, h := range hooks {
+		if h != nil {
+			nonNil = append(nonNil, h)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	}
+
+	res := &Hooks{}
+	for _, h := range nonNil {
+		res.OnTxStart = combineTxStart(res.OnTxStart, h.OnTxStart)
+		res.OnTxEnd = combineTxEnd(res.OnTxEnd, h.OnTxEnd)
+		res.OnEnter = combineEnter(res.OnEnter, h.OnEnter)
+		res.OnExit = combineExit(res.OnExit, h.OnExit)
+		res.OnOpcode = combineOpcode(res.OnOpcode, h.OnOpcode)
+		res.OnFault = combineFault(res.OnFault, h.OnFault)
+		res.OnGasChange = combineGasChange(res.OnGasChange, h.OnGasChange)
+		res.OnBlockchainInit = combineBlockchainInit(res.OnBlockchainInit, h.OnBlockchainInit)
+		res.OnClose = combineNoArgs(res.OnClose, h.OnClose)
+		res.OnBlockStart = combineBlockStart(res.OnBlockStart, h.OnBlockStart)
+		res.OnBlockEnd = combineBlockEnd(res.OnBlockEnd, h.OnBlockEnd)
+		res.OnSkippedBlock = combineBlockStart(res.OnSkippedBlock, h.OnSkippedBlock)
+		res.OnSystemCallStart = combineNoArgs(res.OnSystemCallStart, h.OnSystemCallStart)
+		res.OnSystemCallEnd = combineNoArgs(res.OnSystemCallEnd, h.OnSystemCallEnd)
+		res.OnBalanceChange = combineBalanceChange(res.OnBalanceChange, h.OnBalanceChange)
+		res.OnNonceChange = combineNonceChange(res.OnNonceChange, h.OnNonceChange)
+		res.OnCodeChange = combineCodeChange(res.OnCodeChange, h.OnCodeChange)
+		res.OnStorageChange = combineStorageChange(res.OnStorageChange, h.OnStorageChange)
+		res.OnLog = combineLog(res.OnLog, h.OnLog)
+	}
+	return res
+}
+
+func combineTxStart(first, second TxStartHook) TxStartHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(env *VMContext, tx *types.Transaction) {
+		first(env, tx)
+		second(env, tx)
+	}
+}
+
+func combineTxEnd(first, second TxEndHook) TxEndHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(env *VMContext, tx *types.Transaction, err types.ExecError) {
+		first(env, tx, err)
+		second(env, tx, err)
+	}
+}
+
+func combineEnter(first, second EnterHook) EnterHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(depth int, typ byte, from types.Address, to types.Address, input []byte, gas uint64, value *big.Int) {
+		first(depth, typ, from, to, input, gas, value)
+		second(depth, typ, from, to, input, gas, value)
+	}
+}
+
+func combineExit(first, second ExitHook) ExitHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+		first(depth, output, gasUsed, err, reverted)
+		second(depth, output, gasUsed, err, reverted)
+	}
+}
+
+func combineOpcode(first, second OpcodeHook) OpcodeHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(pc uint64, op byte, gas, cost uint64, scope OpContext, rData []byte, depth int, err error) {
+		first(pc, op, gas, cost, scope, rData, depth, err)
+		second(pc, op, gas, cost, scope, rData, depth, err)
+	}
+}
+
+func combineFault(first, second FaultHook) FaultHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(pc uint64, op byte, gas, cost uint64, scope OpContext, depth int, err error) {
+		first(pc, op, gas, cost, scope, depth, err)
+		second(pc, op, gas, cost, scope, depth, err)
+	}
+}
+
+func combineGasChange(first, second GasChangeHook) GasChangeHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(old, neu uint64, reason GasChangeReason) {
+		first(old, neu, reason)
+		second(old, neu, reason)
+	}
+}
+
+func combineBlockchainInit(first, second BlockchainInitHook) BlockchainInitHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(chainConfig *params.ChainConfig) {
+		first(chainConfig)
+		second(chainConfig)
+	}
+}
+
+func combineNoArgs(first, second func()) func() {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func() {
+		first()
+		second()
+	}
+}
+
+func combineBlockStart(first, second BlockStartHook) BlockStartHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(event BlockEvent) {
+		first(event)
+		second(event)
+	}
+}
+
+func combineBlockEnd(first, second BlockEndHook) BlockEndHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(event BlockEvent, err error) {
+		first(event, err)
+		second(event, err)
+	}
+}
+
+func combineBalanceChange(first, second BalanceChangeHook) BalanceChangeHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(addr types.Address, prev, next *big.Int, reason BalanceChangeReason) {
+		first(addr, prev, next, reason)
+		second(addr, prev, next, reason)
+	}
+}
+
+func combineNonceChange(first, second NonceChangeHook) NonceChangeHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(addr types.Address, prev, next uint64) {
+		first(addr, prev, next)
+		second(addr, prev, next)
+	}
+}
+
+func combineCodeChange(first, second CodeChangeHook) CodeChangeHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(addr types.Address, prevCodeHash common.Hash, prevCode []byte, codeHash common.Hash, code []byte) {
+		first(addr, prevCodeHash, prevCode, codeHash, code)
+		second(addr, prevCodeHash, prevCode, codeHash, code)
+	}
+}
+
+func combineStorageChange(first, second StorageChangeHook) StorageChangeHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(addr types.Address, slot common.Hash, prev, next common.Hash) {
+		first(addr, slot, prev, next)
+		second(addr, slot, prev, next)
+	}
+}
+
+func combineLog(first, second LogHook) LogHook {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(log *types.Log) {
+		first(log)
+		second(log)
+	}
+}
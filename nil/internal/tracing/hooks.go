@@ -61,13 +61,13 @@ type VMContext struct {
 	StateDB     StateDB
 }
 
-// BlockEvent is emitted upon tracing an incoming block.
-// It contains the block as well as consensus related information.
+// BlockEvent is emitted upon tracing a generated block.
 type BlockEvent struct {
+	ShardId types.ShardId
+	Id      types.BlockNumber
+	// Block and Hash are set only after the block is generated
 	Block *types.Block
-	TD    *big.Int
-	// Finalized *types.Header
-	// Safe      *types.Header
+	Hash  common.Hash
 }
 
 type (
@@ -122,12 +122,12 @@ type (
 	// CloseHook is called when the blockchain closes.
 	CloseHook = func()
 
-	// BlockStartHook is called before executing `block`.
-	// `td` is the total difficulty prior to `block`.
+	// BlockStartHook is called before executing the transactions of a block.
 	BlockStartHook = func(event BlockEvent)
 
-	// BlockEndHook is called after executing a block.
-	BlockEndHook = func(err error)
+	// BlockEndHook is called after the block is committed or failed to be committed.
+	// Blocks which are built only to be verified are not committed, so the hook isn't called for them.
+	BlockEndHook = func(event BlockEvent, err error)
 
 	// SkippedBlockHook indicates a block was skipped during processing
 	// due to it being known previously. This can happen e.g. when recovering
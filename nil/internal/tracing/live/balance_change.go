@@ -0,0 +1,65 @@
+package live
+
+import (
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const BalanceChangeTracerName = "balance-change"
+
+type balanceChange struct {
+	TxHash  *common.Hash  `json:"txHash,omitempty"`
+	Address types.Address `json:"address"`
+	Prev    *hexutil.Big  `json:"prev"`
+	Next    *hexutil.Big  `json:"next"`
+	Reason  string        `json:"reason"`
+}
+
+// balanceChangeTracer collects the balance changes of a block in the order they are made.
+type balanceChangeTracer struct {
+	changes revertibleChanges[*balanceChange]
+}
+
+var _ Tracer = (*balanceChangeTracer)(nil)
+
+func newBalanceChangeTracer() Tracer {
+	return &balanceChangeTracer{}
+}
+
+func (t *balanceChangeTracer) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnTxStart: func(_ *tracing.VMContext, txn *types.Transaction) {
+			t.changes.startTx(txn.Hash())
+		},
+		OnTxEnd: func(*tracing.VMContext, *types.Transaction, types.ExecError) {
+			t.changes.endTx()
+		},
+		OnEnter: func(int, byte, types.Address, types.Address, []byte, uint64, *big.Int) {
+			t.changes.enter()
+		},
+		OnExit: func(_ int, _ []byte, _ uint64, _ error, reverted bool) {
+			t.changes.exit(reverted)
+		},
+		OnBalanceChange: t.onBalanceChange,
+	}
+}
+
+func (t *balanceChangeTracer) Result() any {
+	return t.changes.changes
+}
+
+func (t *balanceChangeTracer) onBalanceChange(
+	addr types.Address, prev, next *big.Int, reason tracing.BalanceChangeReason,
+) {
+	t.changes.add(&balanceChange{
+		TxHash:  t.changes.txHash,
+		Address: addr,
+		Prev:    (*hexutil.Big)(new(big.Int).Set(prev)),
+		Next:    (*hexutil.Big)(new(big.Int).Set(next)),
+		Reason:  reason.String(),
+	})
+}
@@ -0,0 +1,117 @@
+package live
+
+import (
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+)
+
+const CallTreeTracerName = "call-tree"
+
+type callFrame struct {
+	Type     string         `json:"type"`
+	From     types.Address  `json:"from"`
+	To       types.Address  `json:"to"`
+	Value    *hexutil.Big   `json:"value,omitempty"`
+	Gas      hexutil.Uint64 `json:"gas"`
+	GasUsed  hexutil.Uint64 `json:"gasUsed"`
+	Input    hexutil.Bytes  `json:"input,omitempty"`
+	Output   hexutil.Bytes  `json:"output,omitempty"`
+	Error    string         `json:"error,omitempty"`
+	Reverted bool           `json:"reverted,omitempty"`
+	Calls    []*callFrame   `json:"calls,omitempty"`
+}
+
+type transactionCalls struct {
+	Hash  common.Hash  `json:"hash"`
+	Calls []*callFrame `json:"calls"`
+}
+
+// callTreeTracer collects the trees of the calls made by the transactions of a block.
+type callTreeTracer struct {
+	transactions []*transactionCalls
+	stack        []*callFrame
+}
+
+var _ Tracer = (*callTreeTracer)(nil)
+
+func newCallTreeTracer() Tracer {
+	return &callTreeTracer{}
+}
+
+func (t *callTreeTracer) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnTxStart: t.onTxStart,
+		OnTxEnd:   t.onTxEnd,
+		OnEnter:   t.onEnter,
+		OnExit:    t.onExit,
+	}
+}
+
+func (t *callTreeTracer) Result() any {
+	return t.transactions
+}
+
+func (t *callTreeTracer) onTxStart(_ *tracing.VMContext, txn *types.Transaction) {
+	t.stack = nil
+
+	// External transactions are verified before the execution, the calls of both belong to the same transaction
+	hash := txn.Hash()
+	if n := len(t.transactions); n > 0 && t.transactions[n-1].Hash == hash {
+		return
+	}
+	t.transactions = append(t.transactions, &transactionCalls{Hash: hash})
+}
+
+func (t *callTreeTracer) onTxEnd(*tracing.VMContext, *types.Transaction, types.ExecError) {
+	t.stack = nil
+}
+
+func (t *callTreeTracer) onEnter(
+	_ int, typ byte, from types.Address, to types.Address, input []byte, gas uint64, value *big.Int,
+) {
+	// Calls out of transactions, e.g. the ones deploying zero-state contracts, aren't traced
+	if len(t.transactions) == 0 {
+		return
+	}
+
+	frame := &callFrame{
+		Type:  vm.OpCode(typ).String(),
+		From:  from,
+		To:    to,
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if value != nil && value.Sign() != 0 {
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+
+	if len(t.stack) == 0 {
+		txn := t.transactions[len(t.transactions)-1]
+		txn.Calls = append(txn.Calls, frame)
+	} else {
+		parent := t.stack[len(t.stack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+	t.stack = append(t.stack, frame)
+}
+
+func (t *callTreeTracer) onExit(_ int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if len(t.stack) == 0 {
+		return
+	}
+
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	frame.Output = common.CopyBytes(output)
+	frame.GasUsed = hexutil.Uint64(gasUsed)
+	frame.Reverted = reverted
+	if err != nil {
+		frame.Error = err.Error()
+	}
+}
@@ -0,0 +1,44 @@
+package live
+
+import "github.com/NilFoundation/nil/nil/common"
+
+// revertibleChanges collects state changes dropping the ones made by reverted calls,
+// since their effect on the state is reverted too.
+type revertibleChanges[T any] struct {
+	changes []T
+	// frames keeps the number of changes at the start of each call in progress
+	frames []int
+
+	txHash *common.Hash
+}
+
+func (c *revertibleChanges[T]) add(change T) {
+	c.changes = append(c.changes, change)
+}
+
+func (c *revertibleChanges[T]) enter() {
+	c.frames = append(c.frames, len(c.changes))
+}
+
+func (c *revertibleChanges[T]) exit(reverted bool) {
+	if len(c.frames) == 0 {
+		return
+	}
+	start := c.frames[len(c.frames)-1]
+	c.frames = c.frames[:len(c.frames)-1]
+	if reverted {
+		c.changes = c.changes[:start]
+	}
+}
+
+// startTx sets the transaction the following changes belong to.
+// The changes made out of transactions (e.g. buying gas before the execution) aren't attributed to them.
+func (c *revertibleChanges[T]) startTx(hash common.Hash) {
+	c.txHash = &hash
+	c.frames = nil
+}
+
+func (c *revertibleChanges[T]) endTx() {
+	c.txHash = nil
+	c.frames = nil
+}
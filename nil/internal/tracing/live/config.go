@@ -0,0 +1,22 @@
+package live
+
+// UnixSocketPrefix marks an output served on a unix socket rather than written to a file.
+const UnixSocketPrefix = "unix://"
+
+// TracerConfig configures a live tracer and its output.
+type TracerConfig struct {
+	// Name is the name of a registered tracer, e.g. "call-tree".
+	Name string `yaml:"name"`
+	// Output is the path of the file the traces are written to,
+	// or "unix://<path>" to stream them to the clients connected to the unix socket.
+	Output string `yaml:"output"`
+	// MaxSizeMb is the size of the output file in megabytes after which it's rotated, 100 by default.
+	MaxSizeMb int `yaml:"maxSizeMb,omitempty"`
+	// MaxBackups is the number of rotated output files kept, zero keeps all of them.
+	MaxBackups int `yaml:"maxBackups,omitempty"`
+}
+
+// Config configures the tracers which write the execution data of every committed block as JSON lines.
+type Config struct {
+	Tracers []TracerConfig `yaml:"tracers,omitempty"`
+}
@@ -0,0 +1,157 @@
+package live
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	// socketWriteTimeout is the time after which a client which doesn't read the traces is disconnected
+	socketWriteTimeout = time.Second
+	// socketQueueSize is the number of writes buffered for a client, it's disconnected if the queue is full
+	socketQueueSize = 1024
+)
+
+func newOutput(config TracerConfig, logger logging.Logger) (io.WriteCloser, error) {
+	if config.Output == "" {
+		return nil, errors.New("output is not set")
+	}
+	if path, ok := strings.CutPrefix(config.Output, UnixSocketPrefix); ok {
+		return newSocketOutput(path, logger)
+	}
+	return &lumberjack.Logger{
+		Filename:   config.Output,
+		MaxSize:    config.MaxSizeMb,
+		MaxBackups: config.MaxBackups,
+	}, nil
+}
+
+// socketOutput streams the written data to all the clients connected to the unix socket.
+// The data written while there are no clients is dropped.
+// Each client is served by its own goroutine, so that Write never waits for the clients;
+// the ones which don't keep up and overflow their queues are disconnected.
+type socketOutput struct {
+	listener net.Listener
+	logger   logging.Logger
+
+	mu        sync.Mutex
+	consumers map[*socketConsumer]struct{} // +checklocks:mu
+}
+
+// socketConsumer is a client connected to the socket along with the queue of the data to send to it
+type socketConsumer struct {
+	conn  net.Conn
+	queue chan []byte
+}
+
+func newSocketOutput(path string, logger logging.Logger) (*socketOutput, error) {
+	// The socket file is left by the previous run if the node wasn't stopped gracefully
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &socketOutput{
+		listener:  listener,
+		logger:    logger.With().Str("socket", path).Logger(),
+		consumers: make(map[*socketConsumer]struct{}),
+	}
+	go o.accept()
+	return o, nil
+}
+
+func (o *socketOutput) accept() {
+	for {
+		conn, err := o.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				o.logger.Error().Err(err).Msg("Failed to accept trace consumer")
+			}
+			return
+		}
+
+		consumer := &socketConsumer{
+			conn:  conn,
+			queue: make(chan []byte, socketQueueSize),
+		}
+		o.mu.Lock()
+		o.consumers[consumer] = struct{}{}
+		o.mu.Unlock()
+
+		go o.serve(consumer)
+	}
+}
+
+// serve sends the queued data to the consumer until it's disconnected
+func (o *socketOutput) serve(consumer *socketConsumer) {
+	for data := range consumer.queue {
+		if err := consumer.conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout)); err == nil {
+			_, err = consumer.conn.Write(data)
+			if err == nil {
+				continue
+			}
+		}
+
+		o.mu.Lock()
+		o.disconnect(consumer, "Disconnecting trace consumer which failed to receive traces")
+		o.mu.Unlock()
+	}
+}
+
+// disconnect closes the connection of the consumer, its queue is closed to stop the serving goroutine
+// +checklocks:o.mu
+func (o *socketOutput) disconnect(consumer *socketConsumer, reason string) {
+	if _, ok := o.consumers[consumer]; !ok {
+		return
+	}
+	if reason != "" {
+		o.logger.Warn().Msg(reason)
+	}
+	delete(o.consumers, consumer)
+	close(consumer.queue)
+	_ = consumer.conn.Close()
+}
+
+func (o *socketOutput) Write(data []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.consumers) == 0 {
+		return len(data), nil
+	}
+
+	// The caller may reuse the buffer, while the data is sent asynchronously
+	data = bytes.Clone(data)
+	for consumer := range o.consumers {
+		select {
+		case consumer.queue <- data:
+		default:
+			o.disconnect(consumer, "Disconnecting trace consumer which doesn't keep up")
+		}
+	}
+	return len(data), nil
+}
+
+func (o *socketOutput) Close() error {
+	err := o.listener.Close()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for consumer := range o.consumers {
+		o.disconnect(consumer, "")
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package live
+
+import (
+	"bytes"
+	"math/big"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const StorageDiffTracerName = "storage-diff"
+
+type storageChange struct {
+	address types.Address
+	slot    common.Hash
+	prev    common.Hash
+	next    common.Hash
+}
+
+type slotDiff struct {
+	Slot common.Hash `json:"slot"`
+	From common.Hash `json:"from"`
+	To   common.Hash `json:"to"`
+}
+
+type accountStorageDiff struct {
+	Address types.Address `json:"address"`
+	Slots   []slotDiff    `json:"slots"`
+}
+
+// storageDiffTracer collects the storage slots changed by a block along with their values before and after it.
+type storageDiffTracer struct {
+	changes revertibleChanges[storageChange]
+}
+
+var _ Tracer = (*storageDiffTracer)(nil)
+
+func newStorageDiffTracer() Tracer {
+	return &storageDiffTracer{}
+}
+
+func (t *storageDiffTracer) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnTxStart: func(_ *tracing.VMContext, txn *types.Transaction) {
+			t.changes.startTx(txn.Hash())
+		},
+		OnTxEnd: func(*tracing.VMContext, *types.Transaction, types.ExecError) {
+			t.changes.endTx()
+		},
+		OnEnter: func(int, byte, types.Address, types.Address, []byte, uint64, *big.Int) {
+			t.changes.enter()
+		},
+		OnExit: func(_ int, _ []byte, _ uint64, _ error, reverted bool) {
+			t.changes.exit(reverted)
+		},
+		OnStorageChange: func(addr types.Address, slot common.Hash, prev, next common.Hash) {
+			t.changes.add(storageChange{address: addr, slot: slot, prev: prev, next: next})
+		},
+	}
+}
+
+// Result returns the diffs sorted by address and slot. Slots restored to the initial value are omitted.
+func (t *storageDiffTracer) Result() any {
+	type slotKey struct {
+		address types.Address
+		slot    common.Hash
+	}
+
+	diffs := make(map[slotKey]*slotDiff)
+	for _, change := range t.changes.changes {
+		key := slotKey{change.address, change.slot}
+		if diff, ok := diffs[key]; ok {
+			diff.To = change.next
+		} else {
+			diffs[key] = &slotDiff{Slot: change.slot, From: change.prev, To: change.next}
+		}
+	}
+
+	accounts := make(map[types.Address]*accountStorageDiff)
+	for key, diff := range diffs {
+		if diff.From == diff.To {
+			continue
+		}
+		account, ok := accounts[key.address]
+		if !ok {
+			account = &accountStorageDiff{Address: key.address}
+			accounts[key.address] = account
+		}
+		account.Slots = append(account.Slots, *diff)
+	}
+
+	res := make([]*accountStorageDiff, 0, len(accounts))
+	for _, account := range accounts {
+		slices.SortFunc(account.Slots, func(a, b slotDiff) int {
+			return bytes.Compare(a.Slot.Bytes(), b.Slot.Bytes())
+		})
+		res = append(res, account)
+	}
+	slices.SortFunc(res, func(a, b *accountStorageDiff) int {
+		return bytes.Compare(a.Address.Bytes(), b.Address.Bytes())
+	})
+	return res
+}
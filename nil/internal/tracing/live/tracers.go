@@ -0,0 +1,168 @@
+package live
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// Tracer collects the execution data of a block.
+// A new tracer is created for every block generator, so it doesn't have to be safe for concurrent use.
+type Tracer interface {
+	// Hooks returns the hooks collecting the data. The block hooks are handled by the caller.
+	Hooks() *tracing.Hooks
+	// Result returns the data collected for the block, it's marshaled to JSON.
+	Result() any
+}
+
+type Constructor func() Tracer
+
+var (
+	registryMutex sync.Mutex
+	registry      = map[string]Constructor{
+		CallTreeTracerName:      newCallTreeTracer,
+		BalanceChangeTracerName: newBalanceChangeTracer,
+		StorageDiffTracerName:   newStorageDiffTracer,
+	}
+)
+
+// Register makes the tracer available for configs under the given name.
+func Register(name string, constructor Constructor) error {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, ok := registry[name]; ok {
+		return fmt.Errorf("tracer %s is already registered", name)
+	}
+	registry[name] = constructor
+	return nil
+}
+
+func getConstructor(name string) (Constructor, bool) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	constructor, ok := registry[name]
+	return constructor, ok
+}
+
+// record is a line of the output
+type record struct {
+	Tracer      string            `json:"tracer"`
+	ShardId     types.ShardId     `json:"shardId"`
+	BlockNumber types.BlockNumber `json:"blockNumber"`
+	BlockHash   common.Hash       `json:"blockHash"`
+	Data        any               `json:"data"`
+}
+
+type liveTracer struct {
+	name        string
+	constructor Constructor
+
+	mu     sync.Mutex
+	output io.WriteCloser // +checklocks:mu
+}
+
+// Tracers are the configured live tracers along with their outputs.
+type Tracers struct {
+	tracers []*liveTracer
+	logger  logging.Logger
+}
+
+// New creates the tracers and opens their outputs. It returns nil if no tracers are configured.
+func New(config *Config) (*Tracers, error) {
+	if config == nil || len(config.Tracers) == 0 {
+		return nil, nil
+	}
+
+	t := &Tracers{
+		logger: logging.NewLogger("live-tracer"),
+	}
+	outputs := make(map[string]struct{}, len(config.Tracers))
+	for _, tracerConfig := range config.Tracers {
+		constructor, ok := getConstructor(tracerConfig.Name)
+		if !ok {
+			return nil, errors.Join(fmt.Errorf("unknown tracer %q", tracerConfig.Name), t.Close())
+		}
+		if _, ok := outputs[tracerConfig.Output]; ok {
+			return nil, errors.Join(
+				fmt.Errorf("output %s is used by several tracers", tracerConfig.Output), t.Close())
+		}
+		outputs[tracerConfig.Output] = struct{}{}
+
+		output, err := newOutput(tracerConfig, t.logger)
+		if err != nil {
+			return nil, errors.Join(
+				fmt.Errorf("failed to open output of tracer %s: %w", tracerConfig.Name, err), t.Close())
+		}
+		t.tracers = append(t.tracers, &liveTracer{
+			name:        tracerConfig.Name,
+			constructor: constructor,
+			output:      output,
+		})
+	}
+	return t, nil
+}
+
+// NewHooks creates a new instance of every tracer and returns their combined hooks.
+// The data of a block is written once the block is committed.
+func (t *Tracers) NewHooks() *tracing.Hooks {
+	hooks := make([]*tracing.Hooks, 0, 2*len(t.tracers))
+	for _, lt := range t.tracers {
+		tracer := lt.constructor()
+
+		started := false
+		hooks = append(hooks, tracer.Hooks(), &tracing.Hooks{
+			OnBlockStart: func(tracing.BlockEvent) {
+				started = true
+			},
+			OnBlockEnd: func(event tracing.BlockEvent, err error) {
+				if !started || err != nil {
+					return
+				}
+				started = false
+				t.write(lt, record{
+					Tracer:      lt.name,
+					ShardId:     event.ShardId,
+					BlockNumber: event.Id,
+					BlockHash:   event.Hash,
+					Data:        tracer.Result(),
+				})
+			},
+		})
+	}
+	return tracing.CombineHooks(hooks...)
+}
+
+// Tracing failures mustn't affect block generation, so they are only logged
+func (t *Tracers) write(lt *liveTracer, r record) {
+	line, err := json.Marshal(r)
+	if err != nil {
+		t.logger.Error().Err(err).Str("tracer", lt.name).Msg("Failed to marshal block trace")
+		return
+	}
+	line = append(line, '\n')
+
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	if _, err := lt.output.Write(line); err != nil {
+		t.logger.Error().Err(err).Str("tracer", lt.name).Msg("Failed to write block trace")
+	}
+}
+
+func (t *Tracers) Close() error {
+	var errs []error
+	for _, lt := range t.tracers {
+		lt.mu.Lock()
+		errs = append(errs, lt.output.Close())
+		lt.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
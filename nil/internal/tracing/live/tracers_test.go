@@ -0,0 +1,234 @@
+package live
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	addrA = types.ShardAndHexToAddress(types.BaseShardId, "0x0a")
+	addrB = types.ShardAndHexToAddress(types.BaseShardId, "0x0b")
+	slot1 = common.HexToHash("0x01")
+	slot2 = common.HexToHash("0x02")
+)
+
+// executeBlock emulates the execution of a block with a transaction which makes a reverted nested call
+func executeBlock(hooks *tracing.Hooks, blockErr error) {
+	txn := &types.Transaction{TransactionDigest: types.TransactionDigest{To: addrA, Seqno: 1}}
+	value := big.NewInt(5)
+	event := tracing.BlockEvent{ShardId: types.BaseShardId, Id: 7}
+
+	hooks.OnBlockStart(event)
+	// Buying gas happens before the execution
+	hooks.OnBalanceChange(addrB, big.NewInt(100), big.NewInt(90), tracing.BalanceDecreaseGasBuy)
+
+	hooks.OnTxStart(nil, txn)
+	hooks.OnEnter(0, byte(vm.CALL), addrB, addrA, []byte{1}, 1000, value)
+	hooks.OnBalanceChange(addrB, big.NewInt(90), big.NewInt(85), tracing.BalanceChangeTransfer)
+	hooks.OnStorageChange(addrA, slot1, common.EmptyHash, slot2)
+	hooks.OnStorageChange(addrA, slot2, common.EmptyHash, slot1)
+
+	hooks.OnEnter(1, byte(vm.STATICCALL), addrA, addrB, nil, 500, nil)
+	hooks.OnExit(1, []byte{2}, 100, nil, false)
+
+	hooks.OnEnter(1, byte(vm.CALL), addrA, addrB, nil, 300, value)
+	hooks.OnBalanceChange(addrA, big.NewInt(5), big.NewInt(0), tracing.BalanceChangeTransfer)
+	hooks.OnStorageChange(addrA, slot1, slot2, slot1)
+	hooks.OnExit(1, nil, 300, vm.ErrExecutionReverted, true)
+
+	hooks.OnStorageChange(addrA, slot2, slot1, common.EmptyHash)
+	hooks.OnExit(0, []byte{3}, 600, nil, false)
+	hooks.OnTxEnd(nil, txn, nil)
+
+	event.Hash = common.HexToHash("0xff")
+	hooks.OnBlockEnd(event, blockErr)
+}
+
+func TestTracers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CallTree", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := newCallTreeTracer()
+		executeBlock(withNoopHooks(tracer.Hooks()), nil)
+
+		txns, ok := tracer.Result().([]*transactionCalls)
+		require.True(t, ok)
+		require.Len(t, txns, 1)
+		require.Len(t, txns[0].Calls, 1)
+
+		root := txns[0].Calls[0]
+		assert.Equal(t, "CALL", root.Type)
+		assert.Equal(t, addrB, root.From)
+		assert.EqualValues(t, 600, root.GasUsed)
+		assert.Equal(t, []byte{3}, []byte(root.Output))
+		require.Len(t, root.Calls, 2)
+		assert.Equal(t, "STATICCALL", root.Calls[0].Type)
+		assert.Nil(t, root.Calls[0].Value)
+		assert.True(t, root.Calls[1].Reverted)
+		assert.Equal(t, vm.ErrExecutionReverted.Error(), root.Calls[1].Error)
+	})
+
+	t.Run("BalanceChange", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := newBalanceChangeTracer()
+		executeBlock(withNoopHooks(tracer.Hooks()), nil)
+
+		changes, ok := tracer.Result().([]*balanceChange)
+		require.True(t, ok)
+		// The change of the reverted call is dropped
+		require.Len(t, changes, 2)
+		assert.Nil(t, changes[0].TxHash)
+		assert.Equal(t, tracing.BalanceDecreaseGasBuy.String(), changes[0].Reason)
+		require.NotNil(t, changes[1].TxHash)
+		assert.Equal(t, addrB, changes[1].Address)
+		assert.Equal(t, big.NewInt(85), changes[1].Next.ToInt())
+	})
+
+	t.Run("StorageDiff", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := newStorageDiffTracer()
+		executeBlock(withNoopHooks(tracer.Hooks()), nil)
+
+		diffs, ok := tracer.Result().([]*accountStorageDiff)
+		require.True(t, ok)
+		// Slot 2 is restored to the initial value
+		assert.Equal(t, []*accountStorageDiff{{
+			Address: addrA,
+			Slots:   []slotDiff{{Slot: slot1, From: common.EmptyHash, To: slot2}},
+		}}, diffs)
+	})
+}
+
+// withNoopHooks sets the hooks called by executeBlock which aren't used by the tracer
+func withNoopHooks(hooks *tracing.Hooks) *tracing.Hooks {
+	return tracing.CombineHooks(hooks, &tracing.Hooks{
+		OnBlockStart:    func(tracing.BlockEvent) {},
+		OnBlockEnd:      func(tracing.BlockEvent, error) {},
+		OnBalanceChange: func(types.Address, *big.Int, *big.Int, tracing.BalanceChangeReason) {},
+		OnStorageChange: func(types.Address, common.Hash, common.Hash, common.Hash) {},
+	})
+}
+
+func TestTracersOutput(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "calls.jsonl")
+	socketPath := filepath.Join(dir, "balances.sock")
+
+	tracers, err := New(&Config{Tracers: []TracerConfig{
+		{Name: CallTreeTracerName, Output: filePath},
+		{Name: BalanceChangeTracerName, Output: UnixSocketPrefix + socketPath},
+	}})
+	require.NoError(t, err)
+	defer tracers.Close()
+
+	conn, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Wait for the connection to be accepted
+	require.Eventually(t, func() bool {
+		tracers.tracers[1].mu.Lock()
+		defer tracers.tracers[1].mu.Unlock()
+		output, ok := tracers.tracers[1].output.(*socketOutput)
+		require.True(t, ok)
+		output.mu.Lock()
+		defer output.mu.Unlock()
+		return len(output.consumers) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Failed blocks aren't written
+	executeBlock(withNoopHooks(tracers.NewHooks()), errors.New("failed to commit"))
+	executeBlock(withNoopHooks(tracers.NewHooks()), nil)
+
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	var r struct {
+		record
+		Data []*transactionCalls `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(data, &r))
+	assert.Equal(t, CallTreeTracerName, r.Tracer)
+	assert.Equal(t, types.BaseShardId, r.ShardId)
+	assert.EqualValues(t, 7, r.BlockNumber)
+	assert.Equal(t, common.HexToHash("0xff"), r.BlockHash)
+	assert.Len(t, r.Data, 1)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	require.NoError(t, err)
+	assert.Contains(t, string(line), `"tracer":"balance-change"`)
+}
+
+func TestSocketOutputSlowConsumer(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "out.sock")
+	output, err := newSocketOutput(socketPath, logging.NewLogger("test"))
+	require.NoError(t, err)
+	defer output.Close()
+
+	consumerCount := func() int {
+		output.mu.Lock()
+		defer output.mu.Unlock()
+		return len(output.consumers)
+	}
+
+	// The consumer never reads
+	conn, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Eventually(t, func() bool { return consumerCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// Writes don't wait for the consumer, it's disconnected once its queue overflows
+	data := make([]byte, 4096)
+	start := time.Now()
+	for range 2 * socketQueueSize {
+		n, err := output.Write(data)
+		require.NoError(t, err)
+		require.Equal(t, len(data), n)
+	}
+	assert.Less(t, time.Since(start), socketWriteTimeout)
+	assert.Zero(t, consumerCount())
+}
+
+func TestTracersConfig(t *testing.T) {
+	t.Parallel()
+
+	output := filepath.Join(t.TempDir(), "out.jsonl")
+
+	tracers, err := New(&Config{})
+	require.NoError(t, err)
+	assert.Nil(t, tracers)
+
+	_, err = New(&Config{Tracers: []TracerConfig{{Name: "unknown", Output: output}}})
+	require.ErrorContains(t, err, "unknown tracer")
+
+	_, err = New(&Config{Tracers: []TracerConfig{
+		{Name: CallTreeTracerName, Output: output},
+		{Name: StorageDiffTracerName, Output: output},
+	}})
+	require.ErrorContains(t, err, "is used by several tracers")
+
+	require.Error(t, Register(CallTreeTracerName, newCallTreeTracer))
+}
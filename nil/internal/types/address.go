@@ -32,6 +32,8 @@ var (
 	UsdcFaucetAddress       = ShardAndHexToAddress(BaseShardId, "111111111111111111111111111111111115")
 	L1BlockInfoAddress      = ShardAndHexToAddress(MainShardId, "222222222222222222222222222222222222")
 	GovernanceAddress       = ShardAndHexToAddress(MainShardId, "777777777777777777777777777777777777")
+	DevCheatsAddress        = ShardAndHexToAddress(MainShardId, "dec0dedec0dedec0dedec0dedec0dedec0de")
+	RequestTimeoutAddress   = ShardAndHexToAddress(MainShardId, "de1a4ede1a4ede1a4ede1a4ede1a4ede1a4e")
 )
 
 func GetTokenName(addr TokenId) string {
@@ -188,6 +188,15 @@ const (
 	ErrorBaseFeeTooHigh
 	// ErrorMaxFeePerGasIsZero is returned when the MaxFeePerGas is zero. It is not allowed to have zero MaxFeePerGas.
 	ErrorMaxFeePerGasIsZero
+	// ErrorCallbackIsEmpty is returned when the callback request doesn't specify the callback selector.
+	ErrorCallbackIsEmpty
+	// ErrorCallbackContextTooLarge is returned when the context captured by the callback request exceeds the limit.
+	ErrorCallbackContextTooLarge
+	// ErrorRequestTimeoutTooLarge is returned when the timeout of the request exceeds the limit.
+	ErrorRequestTimeoutTooLarge
+	// ErrorResponseToUnknownRequest is returned when the response doesn't match any pending request of the contract,
+	// e.g., the request was already failed by its timeout.
+	ErrorResponseToUnknownRequest
 )
 
 type ExecError interface {
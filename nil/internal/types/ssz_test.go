@@ -76,6 +76,51 @@ func TestSszTransaction(t *testing.T) {
 	require.Equal(t, h2, h)
 }
 
+func TestSszAsyncContext(t *testing.T) {
+	t.Parallel()
+
+	roundTrip := func(t *testing.T, c *AsyncContext) []byte {
+		t.Helper()
+
+		encoded, err := c.MarshalSSZ()
+		require.NoError(t, err)
+		require.Len(t, encoded, c.SizeSSZ())
+
+		var decoded AsyncContext
+		require.NoError(t, decoded.UnmarshalSSZ(encoded))
+		assert.Equal(t, c, &decoded)
+		return encoded
+	}
+
+	t.Run("Legacy", func(t *testing.T) {
+		t.Parallel()
+
+		// The context stored before the deadlines were introduced
+		encoded := hexutil.MustDecode("0x010d0000000a00000000000000aabb")
+
+		var c AsyncContext
+		require.NoError(t, c.UnmarshalSSZ(encoded))
+		assert.Equal(t, AsyncContext{IsAwait: true, Data: []byte{0xaa, 0xbb}, ResponseProcessingGas: 10}, c)
+
+		// The context without a deadline keeps the layout
+		assert.Equal(t, encoded, roundTrip(t, &c))
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		t.Parallel()
+
+		encoded := roundTrip(t, &AsyncContext{
+			Data:                  []byte{0xaa},
+			ResponseProcessingGas: 10,
+			Deadline:              5,
+			RequestChain:          []*AsyncRequestInfo{{Id: 1, Caller: Address{0x01}}},
+		})
+		assert.Equal(t, asyncContextVersion2, encoded[0])
+
+		require.Error(t, new(AsyncContext).UnmarshalSSZ(encoded[:1]))
+	})
+}
+
 func TestSszSmc(t *testing.T) {
 	t.Parallel()
 
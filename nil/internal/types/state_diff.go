@@ -0,0 +1,23 @@
+package types
+
+import "github.com/NilFoundation/nil/nil/common"
+
+// Diff holds the values of an account field before and after a state change.
+type Diff[T any] struct {
+	From T
+	To   T
+}
+
+// AccountDiff holds the changed fields of an account. The unchanged fields are nil or empty.
+type AccountDiff struct {
+	Address  Address
+	Balance  *Diff[Value]
+	Seqno    *Diff[Seqno]
+	ExtSeqno *Diff[Seqno]
+	Code     *Diff[Code]
+	Tokens   map[TokenId]Diff[Value]
+	Storage  map[common.Hash]Diff[common.Hash]
+}
+
+// StateDiff holds the changes of the accounts sorted by address.
+type StateDiff []*AccountDiff
@@ -250,11 +250,121 @@ type AsyncResponsePayload struct {
 }
 
 // AsyncContext contains context of the request. For await requests it contains VM state, which will be restored upon
-// the response. For callback requests it contains captured variables(not implemented yet).
+// the response. For callback requests it contains the selector of the callback followed by the captured context,
+// the callback is called with the response and the context.
+// If the deadline is set, the request fails when the main shard reaches it without the response.
+// The request chain of an await request is kept to pass the failure on to the pending requests of the chain.
 type AsyncContext struct {
-	IsAwait               bool   `json:"isAwait"`
-	Data                  []byte `ssz-max:"10000000" json:"data"`
-	ResponseProcessingGas Gas    `json:"gas"`
+	IsAwait               bool                `json:"isAwait"`
+	Data                  []byte              `ssz-max:"10000000" json:"data"`
+	ResponseProcessingGas Gas                 `json:"gas"`
+	Deadline              BlockNumber         `json:"deadline,omitempty"`
+	RequestChain          []*AsyncRequestInfo `ssz-max:"4096" json:"requestChain,omitempty"`
+}
+
+// CallbackSelectorSize is the size of the selector of the callback method.
+const CallbackSelectorSize = 4
+
+// NewCallbackContextData returns the data of the async context of a callback request.
+func NewCallbackContextData(callback [CallbackSelectorSize]byte, context []byte) []byte {
+	data := make([]byte, 0, CallbackSelectorSize+len(context))
+	return append(append(data, callback[:]...), context...)
+}
+
+// Callback returns the selector of the callback method and the captured context of a callback request.
+// It returns false if the data is too short to contain the selector.
+func (c *AsyncContext) Callback() ([]byte, []byte, bool) {
+	if c.IsAwait || len(c.Data) < CallbackSelectorSize {
+		return nil, nil, false
+	}
+	return c.Data[:CallbackSelectorSize], c.Data[CallbackSelectorSize:], true
+}
+
+// asyncContextV1 is the layout of the contexts stored before the deadlines were introduced.
+// The contexts without a deadline and a request chain are still encoded with it,
+// so the stored contexts and the roots of the context tries are kept.
+type asyncContextV1 struct {
+	IsAwait               bool
+	Data                  []byte `ssz-max:"10000000"`
+	ResponseProcessingGas Gas
+}
+
+// asyncContextV2 is the layout of the contexts with a deadline or a request chain.
+// It is prefixed by asyncContextVersion2, the first byte of asyncContextV1 is a bool, so it is never equal to it.
+type asyncContextV2 struct {
+	IsAwait               bool
+	Data                  []byte `ssz-max:"10000000"`
+	ResponseProcessingGas Gas
+	Deadline              BlockNumber
+	RequestChain          []*AsyncRequestInfo `ssz-max:"4096"`
+}
+
+const asyncContextVersion2 byte = 2
+
+func (c *AsyncContext) isV1() bool {
+	return c.Deadline == 0 && len(c.RequestChain) == 0
+}
+
+func (c *AsyncContext) v1() *asyncContextV1 {
+	return &asyncContextV1{
+		IsAwait:               c.IsAwait,
+		Data:                  c.Data,
+		ResponseProcessingGas: c.ResponseProcessingGas,
+	}
+}
+
+func (c *AsyncContext) v2() *asyncContextV2 {
+	return &asyncContextV2{
+		IsAwait:               c.IsAwait,
+		Data:                  c.Data,
+		ResponseProcessingGas: c.ResponseProcessingGas,
+		Deadline:              c.Deadline,
+		RequestChain:          c.RequestChain,
+	}
+}
+
+// MarshalSSZ ssz marshals the AsyncContext object
+func (c *AsyncContext) MarshalSSZ() ([]byte, error) {
+	return ssz.MarshalSSZ(c)
+}
+
+// MarshalSSZTo ssz marshals the AsyncContext object to a target array
+func (c *AsyncContext) MarshalSSZTo(dst []byte) ([]byte, error) {
+	if c.isV1() {
+		return c.v1().MarshalSSZTo(dst)
+	}
+	return c.v2().MarshalSSZTo(append(dst, asyncContextVersion2))
+}
+
+// SizeSSZ returns the ssz encoded size in bytes for the AsyncContext object
+func (c *AsyncContext) SizeSSZ() int {
+	if c.isV1() {
+		return c.v1().SizeSSZ()
+	}
+	return 1 + c.v2().SizeSSZ()
+}
+
+// UnmarshalSSZ ssz unmarshals the AsyncContext object
+func (c *AsyncContext) UnmarshalSSZ(buf []byte) error {
+	if len(buf) > 0 && buf[0] == asyncContextVersion2 {
+		var v asyncContextV2
+		if err := v.UnmarshalSSZ(buf[1:]); err != nil {
+			return err
+		}
+		*c = AsyncContext(v)
+		return nil
+	}
+
+	var v asyncContextV1
+	if err := v.UnmarshalSSZ(buf); err != nil {
+		return err
+	}
+	*c = AsyncContext{
+		IsAwait:               v.IsAwait,
+		Data:                  v.Data,
+		ResponseProcessingGas: v.ResponseProcessingGas,
+	}
+	return nil
 }
 
 // interfaces
@@ -263,6 +373,8 @@ var (
 	_ common.Hashable = new(ExternalTransaction)
 	_ ssz.Marshaler   = new(Transaction)
 	_ ssz.Unmarshaler = new(Transaction)
+	_ ssz.Marshaler   = new(AsyncContext)
+	_ ssz.Unmarshaler = new(AsyncContext)
 )
 
 func NewEmptyTransaction() *Transaction {
@@ -588,7 +700,7 @@ func (m TransactionFlags) IsResponse() bool {
 	return m.GetBit(TransactionFlagResponse)
 }
 
-//go:generate go run github.com/NilFoundation/fastssz/sszgen --path transaction.go -include ../../common/hexutil/bytes.go,../../common/length.go,address.go,gas.go,value.go,code.go,shard.go,bloom.go,log.go,../../common/hash.go,signature.go,account.go,bitflags.go --objs Transaction,ExternalTransaction,InternalTransactionPayload,TransactionDigest,TransactionFlags,EvmState,AsyncContext,AsyncResponsePayload
+//go:generate go run github.com/NilFoundation/fastssz/sszgen --path transaction.go -include ../../common/hexutil/bytes.go,block.go,../../common/length.go,address.go,gas.go,value.go,code.go,shard.go,bloom.go,log.go,../../common/hash.go,signature.go,account.go,bitflags.go --objs Transaction,ExternalTransaction,InternalTransactionPayload,TransactionDigest,TransactionFlags,EvmState,asyncContextV1,asyncContextV2,AsyncResponsePayload
 
 type TxnWithHash struct {
 	*Transaction
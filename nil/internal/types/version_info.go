@@ -18,13 +18,20 @@ var (
 	_ fastssz.Unmarshaler = new(VersionInfo)
 )
 
+// storedAs is the scheme of the values stored under the name of a structure which decodes them.
+// It keeps the version when the structure is extended with a versioned encoding.
+type storedAs struct {
+	name  string
+	value any
+}
+
 var SchemesInsideDb = []any{
 	SmartContract{},
 	Block{},
 	Transaction{},
 	ExternalTransaction{},
 	InternalTransactionPayload{},
-	AsyncContext{},
+	storedAs{name: "types.AsyncContext", value: asyncContextV1{}},
 	CollatorState{},
 }
 
@@ -33,10 +40,17 @@ const SchemeVersionInfoKey = "SchemeVersionInfo"
 func NewVersionInfo() *VersionInfo {
 	var res []byte
 	for _, curStruct := range SchemesInsideDb {
+		var typeName string
+		if s, ok := curStruct.(storedAs); ok {
+			curStruct, typeName = s.value, s.name
+		}
 		v := reflect.ValueOf(curStruct)
 		for i := range v.NumField() {
 			t := v.Type()
-			res = append(res, common.PoseidonHash([]byte(t.String())).Bytes()...)
+			if typeName == "" {
+				typeName = t.String()
+			}
+			res = append(res, common.PoseidonHash([]byte(typeName)).Bytes()...)
 
 			name := t.Field(i).Name
 			res = append(res, common.PoseidonHash([]byte(name)).Bytes()...)
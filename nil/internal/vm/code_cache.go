@@ -0,0 +1,108 @@
+package vm
+
+import (
+	"context"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// DefaultCodeCacheSize is the number of contracts kept by the shared code cache.
+// The code is limited by params.MaxCodeSize, but most of the contracts are a few kilobytes,
+// so the cache takes up to a few tens of megabytes.
+const DefaultCodeCacheSize = 1 << 12
+
+// codeEntry is the code of a contract with the result of its JUMPDEST analysis, which is done on the first use.
+type codeEntry struct {
+	code []byte
+
+	analysisOnce sync.Once
+	analysis     bitvec
+}
+
+// CodeCache keeps the code of the recently used contracts and its JUMPDEST analysis by the code hash.
+// The hash is derived from the code, so the entries never become stale and the cache is shared
+// by all shards, blocks and calls. The code must not be modified after it's added.
+type CodeCache struct {
+	entries *lru.Cache[common.Hash, *codeEntry]
+}
+
+// SharedCodeCache is used by the contracts executed by the EVM and by the execution state reading the code.
+var SharedCodeCache = NewCodeCache(DefaultCodeCacheSize)
+
+func NewCodeCache(size int) *CodeCache {
+	entries, err := lru.NewWithEvict(size, func(common.Hash, *codeEntry) {
+		codeMetrics.evictions.Add(context.Background(), 1)
+	})
+	check.PanicIfErr(err)
+	return &CodeCache{entries: entries}
+}
+
+// GetCode returns the code by its hash if it's cached.
+func (c *CodeCache) GetCode(hash common.Hash) ([]byte, bool) {
+	entry, ok := c.entries.Get(hash)
+	if ok {
+		codeMetrics.hits.Add(context.Background(), 1)
+		return entry.code, true
+	}
+	codeMetrics.misses.Add(context.Background(), 1)
+	return nil, false
+}
+
+// AddCode caches the code read by its hash.
+func (c *CodeCache) AddCode(hash common.Hash, code []byte) {
+	c.getOrAdd(hash, code)
+}
+
+// Purge drops all entries of the cache.
+func (c *CodeCache) Purge() {
+	c.entries.Purge()
+}
+
+func (c *CodeCache) getOrAdd(hash common.Hash, code []byte) *codeEntry {
+	if entry, ok := c.entries.Get(hash); ok {
+		return entry
+	}
+	entry := &codeEntry{code: code}
+	if prev, ok, _ := c.entries.PeekOrAdd(hash, entry); ok {
+		return prev
+	}
+	return entry
+}
+
+// analysis returns the JUMPDEST analysis of the code, the code is cached if it isn't yet.
+func (c *CodeCache) analysis(hash common.Hash, code []byte) bitvec {
+	entry := c.getOrAdd(hash, code)
+	entry.analysisOnce.Do(func() {
+		codeMetrics.analyses.Add(context.Background(), 1)
+		entry.analysis = codeBitmap(entry.code)
+	})
+	return entry.analysis
+}
+
+type metrics struct {
+	// Hit rate of the code reads
+	hits   metric.Int64Counter
+	misses metric.Int64Counter
+
+	// Contracts evicted from the cache
+	evictions metric.Int64Counter
+	// JUMPDEST analyses done, there is one per contract in the cache if the cache is large enough
+	analyses metric.Int64Counter
+}
+
+var codeMetrics = newMetrics()
+
+func newMetrics() *metrics {
+	meter := telemetry.NewMeter("github.com/NilFoundation/nil/nil/internal/vm")
+	return &metrics{
+		hits:      telemetry.Int64Counter(meter, "code_cache_hits"),
+		misses:    telemetry.Int64Counter(meter, "code_cache_misses"),
+		evictions: telemetry.Int64Counter(meter, "code_cache_evictions"),
+		analyses:  telemetry.Int64Counter(meter, "jumpdest_analyses"),
+	}
+}
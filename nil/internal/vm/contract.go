@@ -110,9 +110,15 @@ func (c *Contract) isCode(udest uint64) bool {
 		// Does parent context have the analysis?
 		analysis, exist := c.jumpdests[c.CodeHash]
 		if !exist {
-			// Do the analysis and save in parent context
-			// We do not need to store it in c.analysis
-			analysis = codeBitmap(c.Code)
+			if c.IsDeployment {
+				// Do the analysis and save in parent context
+				// We do not need to store it in c.analysis
+				analysis = codeBitmap(c.Code)
+			} else {
+				// The code of deployed contracts is shared by the transactions and blocks,
+				// so the analysis is taken from the process-wide cache
+				analysis = SharedCodeCache.analysis(c.CodeHash, c.Code)
+			}
 			c.jumpdests[c.CodeHash] = analysis
 		}
 		// Also stash it in current contract for faster access
@@ -97,6 +97,10 @@ type StateDB interface {
 		isAwait bool,
 	) (*types.Transaction, error)
 
+	// SetRequestTimeout sets the timeout in main shard blocks of the next request sent by the caller
+	// within the current transaction
+	SetRequestTimeout(caller types.Address, timeout uint64)
+
 	// Get current transaction
 	GetInTransaction() *types.Transaction
 
@@ -55,6 +55,11 @@ func (jt *JumpTable) GetNumRequiredStackItems(op OpCode) int {
 	return jt[op].minStack
 }
 
+// GetConstantGas gets static gas charged for an opcode before its dynamic part is calculated
+func (jt *JumpTable) GetConstantGas(op OpCode) uint64 {
+	return jt[op].constantGas
+}
+
 func validate(jt JumpTable) JumpTable {
 	for i, op := range jt {
 		if op == nil {
@@ -84,22 +84,24 @@ type SimplePrecompiledContract interface {
 }
 
 var (
-	SendRawTransactionAddress = types.BytesToAddress([]byte{0xfc})
-	AsyncCallAddress          = types.BytesToAddress([]byte{0xfd})
-	VerifySignatureAddress    = types.BytesToAddress([]byte{0xfe})
-	CheckIsInternalAddress    = types.BytesToAddress([]byte{0xff})
-	ManageTokenAddress        = types.BytesToAddress([]byte{0xd0})
-	TokenBalanceAddress       = types.BytesToAddress([]byte{0xd1})
-	SendTokensAddress         = types.BytesToAddress([]byte{0xd2})
-	TransactionTokensAddress  = types.BytesToAddress([]byte{0xd3})
-	GetGasPriceAddress        = types.BytesToAddress([]byte{0xd4})
-	PoseidonHashAddress       = types.BytesToAddress([]byte{0xd5})
-	AwaitCallAddress          = types.BytesToAddress([]byte{0xd6})
-	ConfigParamAddress        = types.BytesToAddress([]byte{0xd7})
-	SendRequestAddress        = types.BytesToAddress([]byte{0xd8})
-	CheckIsResponseAddress    = types.BytesToAddress([]byte{0xd9})
-	LogAddress                = types.BytesToAddress([]byte{0xda})
-	GovernanceAddress         = types.BytesToAddress([]byte{0xdb})
+	SendRawTransactionAddress  = types.BytesToAddress([]byte{0xfc})
+	AsyncCallAddress           = types.BytesToAddress([]byte{0xfd})
+	VerifySignatureAddress     = types.BytesToAddress([]byte{0xfe})
+	CheckIsInternalAddress     = types.BytesToAddress([]byte{0xff})
+	ManageTokenAddress         = types.BytesToAddress([]byte{0xd0})
+	TokenBalanceAddress        = types.BytesToAddress([]byte{0xd1})
+	SendTokensAddress          = types.BytesToAddress([]byte{0xd2})
+	TransactionTokensAddress   = types.BytesToAddress([]byte{0xd3})
+	GetGasPriceAddress         = types.BytesToAddress([]byte{0xd4})
+	PoseidonHashAddress        = types.BytesToAddress([]byte{0xd5})
+	AwaitCallAddress           = types.BytesToAddress([]byte{0xd6})
+	ConfigParamAddress         = types.BytesToAddress([]byte{0xd7})
+	SendRequestAddress         = types.BytesToAddress([]byte{0xd8})
+	CheckIsResponseAddress     = types.BytesToAddress([]byte{0xd9})
+	LogAddress                 = types.BytesToAddress([]byte{0xda})
+	GovernanceAddress          = types.BytesToAddress([]byte{0xdb})
+	SendCallbackRequestAddress = types.BytesToAddress([]byte{0xdc})
+	SetRequestTimeoutAddress   = types.BytesToAddress([]byte{0xdd})
 )
 
 // PrecompiledContractsPrague contains the set of pre-compiled Ethereum
@@ -126,22 +128,24 @@ var PrecompiledContractsPrague = map[types.Address]PrecompiledContract{
 	types.BytesToAddress([]byte{0x13}): &simple{&bls12381MapG2{}},
 
 	// NilFoundation precompiled contracts
-	SendRawTransactionAddress: &sendRawTransaction{},
-	AsyncCallAddress:          &asyncCall{},
-	VerifySignatureAddress:    &simple{&verifySignature{}},
-	CheckIsInternalAddress:    &checkIsInternal{},
-	ManageTokenAddress:        &manageToken{},
-	TokenBalanceAddress:       &tokenBalance{},
-	SendTokensAddress:         &sendTokenSync{},
-	TransactionTokensAddress:  &getTransactionTokens{},
-	GetGasPriceAddress:        &getGasPrice{},
-	PoseidonHashAddress:       &poseidonHash{},
-	AwaitCallAddress:          &awaitCall{},
-	ConfigParamAddress:        &configParam{},
-	SendRequestAddress:        &sendRequest{},
-	CheckIsResponseAddress:    &checkIsResponse{},
-	LogAddress:                &emitLog{},
-	GovernanceAddress:         &governance{},
+	SendRawTransactionAddress:  &sendRawTransaction{},
+	AsyncCallAddress:           &asyncCall{},
+	VerifySignatureAddress:     &simple{&verifySignature{}},
+	CheckIsInternalAddress:     &checkIsInternal{},
+	ManageTokenAddress:         &manageToken{},
+	TokenBalanceAddress:        &tokenBalance{},
+	SendTokensAddress:          &sendTokenSync{},
+	TransactionTokensAddress:   &getTransactionTokens{},
+	GetGasPriceAddress:         &getGasPrice{},
+	PoseidonHashAddress:        &poseidonHash{},
+	AwaitCallAddress:           &awaitCall{},
+	ConfigParamAddress:         &configParam{},
+	SendRequestAddress:         &sendRequest{},
+	CheckIsResponseAddress:     &checkIsResponse{},
+	LogAddress:                 &emitLog{},
+	GovernanceAddress:          &governance{},
+	SendCallbackRequestAddress: &sendCallbackRequest{},
+	SetRequestTimeoutAddress:   &setRequestTimeout{},
 }
 
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
@@ -209,6 +213,11 @@ const (
 	ForwardFee                   uint64    = 1_000
 	ExtraForwardFeeStep          uint64    = 100
 	MinGasReserveForAsyncRequest types.Gas = 50_000
+	// MaxCallbackContextSize is the maximum size of the context captured by a callback request.
+	// The context is kept in the async context trie of the caller until the response arrives.
+	MaxCallbackContextSize = 4096
+	// MaxRequestTimeout is the maximum timeout of a request in main shard blocks.
+	MaxRequestTimeout = math.MaxUint32
 )
 
 func (c *sendRawTransaction) RequiredGas([]byte, StateDBReadOnly) (uint64, error) {
@@ -642,8 +651,23 @@ func (a *sendRequest) Run(state StateDB, input []byte, value *uint256.Int, calle
 	// Get `callData` argument
 	callData := getBytesArgCopy(args[4], "sendRequest", "callData")
 
+	return addOutRequest(state, caller, dst, tokens, value, responseProcessingGas, context, callData, "sendRequest")
+}
+
+// addOutRequest sends the request, the context is saved to be passed to the callback called with the response.
+func addOutRequest(
+	state StateDB,
+	caller ContractRef,
+	dst types.Address,
+	tokens []types.TokenBalance,
+	value *uint256.Int,
+	responseProcessingGas types.Gas,
+	context []byte,
+	callData []byte,
+	methodName string,
+) ([]byte, error) {
 	if err := withdrawFunds(state, caller.Address(), types.NewValue(value)); err != nil {
-		return []byte("sendRequest failed: withdrawFunds failed"), err
+		return []byte(methodName + " failed: withdrawFunds failed"), err
 	}
 
 	// Internal is required for the transaction
@@ -661,7 +685,7 @@ func (a *sendRequest) Run(state StateDB, input []byte, value *uint256.Int, calle
 
 	setRefundTo(&payload.RefundTo, state.GetInTransaction())
 
-	if _, err = state.AddOutRequestTransaction(caller.Address(), &payload, responseProcessingGas, false); err != nil {
+	if _, err := state.AddOutRequestTransaction(caller.Address(), &payload, responseProcessingGas, false); err != nil {
 		logging.GlobalLogger.Error().Msgf("AddOutRequestTransaction failed: %s", err)
 		return nil, types.NewVmVerboseError(types.ErrorPrecompileStateDbReturnedError, err.Error())
 	}
@@ -672,6 +696,105 @@ func (a *sendRequest) Run(state StateDB, input []byte, value *uint256.Int, calle
 	return res, nil
 }
 
+type sendCallbackRequest struct{}
+
+var _ ReadWritePrecompiledContract = (*sendCallbackRequest)(nil)
+
+func (c *sendCallbackRequest) RequiredGas(input []byte, state StateDBReadOnly) (uint64, error) {
+	dst, err := extractDstAddress(input, "precompileSendCallbackRequest", 0)
+	if err != nil {
+		return math.MaxUint64, err
+	}
+	extraGas := GetExtraGasForOutboundTransaction(state, dst.ShardId())
+
+	return extraGas + estimateGasForAsyncRequest(input, "precompileSendCallbackRequest", 2, 6), nil
+}
+
+// Run sends the request whose response calls the callback of the caller with the captured context:
+// callback(bool success, bytes returnData, bytes context).
+func (a *sendCallbackRequest) Run(
+	state StateDB, input []byte, value *uint256.Int, caller ContractRef,
+) ([]byte, error) {
+	args, err := precompiledArgs("precompileSendCallbackRequest", input, 6)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get `dst` argument
+	dst, ok := args[0].(types.Address)
+	check.PanicIfNotf(ok, "sendCallbackRequest failed: dst argument is not an address")
+
+	// Get `tokens` argument, which is a slice of `TokenBalance`
+	tokens, err := extractTokens(args[1])
+	if err != nil {
+		logging.GlobalLogger.Error().Err(err).Msg("tokens is not a slice of TokenBalance")
+		return nil, types.NewVmVerboseError(types.ErrorPrecompileInvalidTokenArray, err.Error())
+	}
+
+	// Get `responseProcessingGas` argument
+	responseProcessingGas := types.Gas(
+		extractUintParam(args[2], "sendCallbackRequest", "responseProcessingGas").Uint64())
+	if responseProcessingGas < MinGasReserveForAsyncRequest {
+		logging.GlobalLogger.Error().Msgf(
+			"sendCallbackRequest failed: responseProcessingGas is too low (%d)", responseProcessingGas)
+		return nil, types.NewVmError(types.ErrorAwaitCallTooLowResponseProcessingGas)
+	}
+
+	// Get `callback` argument
+	callback, ok := args[3].([types.CallbackSelectorSize]byte)
+	check.PanicIfNotf(ok, "sendCallbackRequest failed: callback argument is not bytes4")
+	if callback == ([types.CallbackSelectorSize]byte{}) {
+		return nil, types.NewVmError(types.ErrorCallbackIsEmpty)
+	}
+
+	// Get `context` argument
+	context := getBytesArgCopy(args[4], "sendCallbackRequest", "context")
+	if len(context) > MaxCallbackContextSize {
+		return nil, types.NewVmVerboseError(types.ErrorCallbackContextTooLarge,
+			fmt.Sprintf("%d > %d", len(context), MaxCallbackContextSize))
+	}
+
+	// Get `callData` argument
+	callData := getBytesArgCopy(args[5], "sendCallbackRequest", "callData")
+
+	return addOutRequest(state, caller, dst, tokens, value, responseProcessingGas,
+		types.NewCallbackContextData(callback, context), callData, "sendCallbackRequest")
+}
+
+type setRequestTimeout struct{}
+
+var _ ReadWritePrecompiledContract = (*setRequestTimeout)(nil)
+
+func (c *setRequestTimeout) RequiredGas([]byte, StateDBReadOnly) (uint64, error) {
+	return 100, nil
+}
+
+// Run sets the timeout of the next request of the caller. If the response isn't received until the main shard
+// advances by the timeout, the request fails: the awaiting execution is resumed or the callback is called
+// with success=false.
+func (a *setRequestTimeout) Run(
+	state StateDB, input []byte, value *uint256.Int, caller ContractRef,
+) ([]byte, error) {
+	args, err := precompiledArgs("precompileSetRequestTimeout", input, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get `timeout` argument
+	timeout := extractUintParam(args[0], "setRequestTimeout", "timeout")
+	if timeout.Cmp(types.NewValueFromUint64(MaxRequestTimeout)) > 0 {
+		return nil, types.NewVmVerboseError(types.ErrorRequestTimeoutTooLarge,
+			fmt.Sprintf("%s > %d", timeout, MaxRequestTimeout))
+	}
+
+	state.SetRequestTimeout(caller.Address(), timeout.Uint64())
+
+	res := make([]byte, 32)
+	res[31] = 1
+
+	return res, nil
+}
+
 type verifySignature struct{}
 
 var _ SimplePrecompiledContract = (*verifySignature)(nil)
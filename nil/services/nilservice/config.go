@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/collate"
@@ -14,9 +15,12 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/tracing/live"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/cometa"
 	"github.com/NilFoundation/nil/nil/services/rollup"
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 )
 
 type RunMode int
@@ -42,6 +46,20 @@ type Config struct {
 	RPCPort        int                   `yaml:"rpcPort,omitempty"`
 	BootstrapPeers network.AddrInfoSlice `yaml:"bootstrapPeers,omitempty"`
 	EnableDevApi   bool                  `yaml:"enableDevApi,omitempty"`
+	RpcAuth        *httpcfg.AuthCfg      `yaml:"rpcAuth,omitempty"`
+	RpcRateLimit   *httpcfg.RateLimitCfg `yaml:"rpcRateLimit,omitempty"`
+
+	// EnableAddressTxIndex enables the index of transactions by address used by eth_getTransactionsByAddress
+	EnableAddressTxIndex bool `yaml:"enableAddressTxIndex,omitempty"`
+
+	// PrefetchWorkers is the number of goroutines loading the state of transactions before their execution
+	PrefetchWorkers int `yaml:"prefetchWorkers,omitempty"`
+
+	// Automine makes the shards produce blocks as soon as they have transactions to process
+	// instead of every collator tick. It's intended for development networks run by a single node.
+	Automine bool `yaml:"automine,omitempty"`
+	// automine is created on the node creation if Automine is set
+	automine *collate.Automine
 
 	// Profiling
 	PprofPort int `yaml:"pprofPort,omitempty"`
@@ -80,6 +98,9 @@ type Config struct {
 	Cometa    *cometa.Config             `yaml:"cometa,omitempty"`
 	RpcNode   *RpcNodeConfig             `yaml:"rpcNode,omitempty"`
 
+	// LiveTracers configures tracers writing the execution data of the committed blocks
+	LiveTracers *live.Config `yaml:"liveTracers,omitempty"`
+
 	L1Fetcher rollup.L1BlockFetcher `yaml:"-"`
 
 	FeeCalculator execution.FeeCalculator `yaml:"-"`
@@ -101,6 +122,7 @@ func NewDefaultConfig() *Config {
 		GracefulShutdown:  true,
 		Topology:          collate.TrivialShardTopologyId,
 		EnableConfigCache: true,
+		PrefetchWorkers:   execution.DefaultPrefetchWorkers,
 
 		Validators: make(map[types.ShardId][]config.ValidatorInfo),
 
@@ -127,10 +149,35 @@ func NewDefaultReplayConfig() *ReplayConfig {
 
 type RpcNodeConfig struct {
 	ArchiveNodeList network.AddrInfoSlice `yaml:"archiveNodeList,omitempty"`
+
+	// MaxRequestAttempts is the number of peers a read request to a shard is sent to before giving up.
+	MaxRequestAttempts int `yaml:"maxRequestAttempts,omitempty"`
+	// HedgeDelay is the time after which a slow read request is also sent to another peer, zero disables hedging.
+	HedgeDelay time.Duration `yaml:"hedgeDelay,omitempty"`
+
+	// ResponseCache configures caching of immutable responses of the shards served by other nodes.
+	ResponseCache *rawapi.ResponseCacheConfig `yaml:"responseCache,omitempty"`
 }
 
 func NewDefaultRpcNodeConfig() *RpcNodeConfig {
-	return &RpcNodeConfig{}
+	return &RpcNodeConfig{
+		MaxRequestAttempts: rawapi.NewDefaultNetworkRoutingConfig().MaxAttempts,
+		ResponseCache:      rawapi.NewDefaultResponseCacheConfig(),
+	}
+}
+
+// NetworkRoutingConfig returns the config of routing requests to the shards served by other nodes.
+// Archive nodes are preferred for requests to historical blocks.
+func (c *RpcNodeConfig) NetworkRoutingConfig() *rawapi.NetworkRoutingConfig {
+	archivePeers := make([]network.PeerID, len(c.ArchiveNodeList))
+	for i, addr := range c.ArchiveNodeList {
+		archivePeers[i] = addr.ID
+	}
+	return &rawapi.NetworkRoutingConfig{
+		ArchivePeers: archivePeers,
+		MaxAttempts:  c.MaxRequestAttempts,
+		HedgeDelay:   c.HedgeDelay,
+	}
 }
 
 func (c *Config) GetMyShards() []uint {
@@ -175,6 +222,12 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.RpcRateLimit != nil {
+		if err := c.RpcRateLimit.Validate(); err != nil {
+			return fmt.Errorf("invalid RPC rate limit config: %w", err)
+		}
+	}
+
 	if c.MyShards != nil && !c.DisableConsensus {
 		if !slices.Contains(c.MyShards, uint(types.MainShardId)) {
 			return errors.New("main shard must be included in MyShards")
@@ -209,17 +262,32 @@ func (c *Config) LoadValidatorPrivateKey() (bls.PrivateKey, error) {
 	return c.ValidatorKeysManager.GetKey()
 }
 
-func (c *Config) BlockGeneratorParams(shardId types.ShardId) execution.BlockGeneratorParams {
+// BlockGeneratorParams returns the params of the block generator of the shard.
+// The hooks of liveTracers (if not nil) are attached to the executed blocks.
+func (c *Config) BlockGeneratorParams(
+	shardId types.ShardId,
+	liveTracers *live.Tracers,
+) execution.BlockGeneratorParams {
 	var verboseTracingHook *tracing.Hooks
 	if c.TraceEVM {
 		verboseTracingHook = execution.VerboseTracingHooks(logging.NewLogger("tracer"))
 	}
-	return execution.BlockGeneratorParams{
+	params := execution.BlockGeneratorParams{
 		ShardId:          shardId,
 		NShards:          c.NShards,
 		EvmTracingHooks:  verboseTracingHook,
 		MainKeysPath:     c.MainKeysPath,
 		DisableConsensus: c.DisableConsensus,
 		FeeCalculator:    c.FeeCalculator,
+
+		IndexAddressTransactions: c.EnableAddressTxIndex,
+		PrefetchWorkers:          c.PrefetchWorkers,
+	}
+	if c.PrefetchWorkers > 0 {
+		params.AccessSets = execution.NewAccessSets(execution.DefaultAccessSetsSize)
+	}
+	if liveTracers != nil {
+		params.NewLiveTracingHooks = liveTracers.NewHooks
 	}
+	return params
 }
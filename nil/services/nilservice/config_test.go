@@ -1,8 +1,10 @@
 package nilservice
 
 import (
+	"math"
 	"testing"
 
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
 	"github.com/stretchr/testify/require"
 )
 
@@ -39,3 +41,29 @@ func TestValidateInvalidNShards(t *testing.T) {
 	cfg.NShards = 2
 	require.NoError(t, cfg.Validate())
 }
+
+func TestValidateRpcRateLimit(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewDefaultConfig()
+	cfg.RpcRateLimit = httpcfg.NewDefaultRateLimitCfg()
+	cfg.RpcRateLimit.MethodCosts = map[string]int{"eth_call": 1000}
+	require.NoError(t, cfg.Validate())
+
+	for _, test := range []struct {
+		modify      func(c *httpcfg.RateLimitCfg)
+		expectedErr string
+	}{
+		{func(c *httpcfg.RateLimitCfg) { c.Rate = 0 }, "rate must be a positive number"},
+		{func(c *httpcfg.RateLimitCfg) { c.Rate = math.NaN() }, "rate must be a positive number"},
+		{func(c *httpcfg.RateLimitCfg) { c.Rate = math.Inf(1) }, "rate must be a positive number"},
+		{func(c *httpcfg.RateLimitCfg) { c.Burst = -1 }, "burst must be positive"},
+		{func(c *httpcfg.RateLimitCfg) { c.MaxBatchSize = -1 }, "max batch size must not be negative"},
+		{func(c *httpcfg.RateLimitCfg) { c.MethodCosts = map[string]int{"eth_call": 0} }, "cost of method eth_call"},
+	} {
+		cfg := NewDefaultConfig()
+		cfg.RpcRateLimit = httpcfg.NewDefaultRateLimitCfg()
+		test.modify(cfg.RpcRateLimit)
+		require.ErrorContains(t, cfg.Validate(), test.expectedErr)
+	}
+}
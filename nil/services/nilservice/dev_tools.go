@@ -0,0 +1,63 @@
+package nilservice
+
+import (
+	"github.com/NilFoundation/nil/nil/internal/collate"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+)
+
+// devTools holds the means of manipulating the chain of development networks.
+// They are only supported by a single node holding all shards.
+type devTools struct {
+	snapshots *collate.ChainSnapshots
+	cheats    map[types.ShardId]*execution.DevCheats
+	miners    map[types.ShardId]*collate.Scheduler
+}
+
+// newDevTools returns nil if the node doesn't support the dev tools.
+func newDevTools(cfg *Config) *devTools {
+	if !cfg.EnableDevApi || cfg.RunMode != NormalRunMode {
+		return nil
+	}
+
+	cheats := make(map[types.ShardId]*execution.DevCheats, cfg.NShards)
+	for i := range cfg.NShards {
+		cheats[types.ShardId(i)] = execution.NewDevCheats()
+	}
+	return &devTools{
+		cheats: cheats,
+		miners: make(map[types.ShardId]*collate.Scheduler, cfg.NShards),
+	}
+}
+
+func (d *devTools) addMiner(shardId types.ShardId, scheduler *collate.Scheduler) {
+	if d == nil {
+		return
+	}
+	d.miners[shardId] = scheduler
+}
+
+// getShards returns the shards manipulated by the dev API.
+func (d *devTools) getShards() map[types.ShardId]jsonrpc.DevShard {
+	if d == nil {
+		return nil
+	}
+
+	shards := make(map[types.ShardId]jsonrpc.DevShard, len(d.cheats))
+	for shardId, cheats := range d.cheats {
+		shard := jsonrpc.DevShard{Cheats: cheats}
+		if miner, ok := d.miners[shardId]; ok {
+			shard.Miner = miner
+		}
+		shards[shardId] = shard
+	}
+	return shards
+}
+
+func (d *devTools) getShardCheats(shardId types.ShardId) *execution.DevCheats {
+	if d == nil {
+		return nil
+	}
+	return d.cheats[shardId]
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/tracing/live"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/admin"
 	"github.com/NilFoundation/nil/nil/services/cometa"
@@ -46,6 +47,7 @@ func startRpcServer(
 	rawApi rawapi.NodeApi,
 	db db.ReadOnlyDB,
 	client client.Client,
+	dev *devTools,
 ) error {
 	logger := logging.NewLogger("RPC")
 
@@ -61,18 +63,24 @@ func startRpcServer(
 		HTTPTimeouts:    httpcfg.DefaultHTTPTimeouts,
 		HttpCORSDomain:  []string{"*"},
 		KeepHeaders:     []string{"Client-Version", "Client-Type", "X-UID"},
+		Auth:            cfg.RpcAuth,
+		RateLimit:       cfg.RpcRateLimit,
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
 	pollBlocksForLogs := cfg.RunMode == NormalRunMode
 
 	var ethApiService any
+	var evmImpl jsonrpc.EvmAPI
 	if cfg.RunMode == NormalRunMode || cfg.RunMode == RpcRunMode {
-		ethImpl := jsonrpc.NewEthAPI(ctx, rawApi, db, pollBlocksForLogs, cfg.LogClientRpcEvents)
+		ethImpl := jsonrpc.NewEthAPI(ctx, rawApi, db, pollBlocksForLogs, cfg.LogClientRpcEvents, cfg.FeeCalculator)
 		defer ethImpl.Shutdown()
 		ethApiService = ethImpl
+		if dev != nil {
+			evmImpl = jsonrpc.NewEvmAPI(dev.snapshots, ethImpl.APIImplRo, logger)
+		}
 	} else {
-		ethImpl := jsonrpc.NewEthAPIRo(ctx, rawApi, db, pollBlocksForLogs, cfg.LogClientRpcEvents)
+		ethImpl := jsonrpc.NewEthAPIRo(ctx, rawApi, db, pollBlocksForLogs, cfg.LogClientRpcEvents, cfg.FeeCalculator)
 		defer ethImpl.Shutdown()
 		ethApiService = ethImpl
 	}
@@ -103,7 +111,7 @@ func startRpcServer(
 	}
 
 	if cfg.EnableDevApi {
-		devImpl := jsonrpc.NewDevAPI(rawApi)
+		devImpl := jsonrpc.NewDevAPI(rawApi, dev.getShards())
 		apiList = append(apiList, transport.API{
 			Namespace: "dev",
 			Public:    true,
@@ -112,6 +120,15 @@ func startRpcServer(
 		})
 	}
 
+	if evmImpl != nil {
+		apiList = append(apiList, transport.API{
+			Namespace: "evm",
+			Public:    true,
+			Service:   evmImpl,
+			Version:   "1.0",
+		})
+	}
+
 	if cfg.Cometa != nil {
 		cmt, err := cometa.NewService(ctx, cfg.Cometa, client)
 		if err != nil {
@@ -178,6 +195,11 @@ func getRawApi(
 		panic("unsupported run mode for raw API")
 	}
 
+	rpcNodeConfig := cfg.RpcNode
+	if rpcNodeConfig == nil {
+		rpcNodeConfig = NewDefaultRpcNodeConfig()
+	}
+	routingConfig := rpcNodeConfig.NetworkRoutingConfig()
 	shardApis := make(map[types.ShardId]rawapi.ShardApi)
 	for shardId := range types.ShardId(cfg.NShards) {
 		var err error
@@ -189,7 +211,12 @@ func getRawApi(
 				shardApis[shardId], err = rawapi.NewLocalRawApiAccessor(shardId, api)
 			}
 		} else {
-			shardApis[shardId], err = rawapi.NewNetworkRawApiAccessor(shardId, networkManager)
+			var cache *rawapi.ResponseCache
+			cache, err = rawapi.NewResponseCache(shardId, rpcNodeConfig.ResponseCache, database)
+			if err != nil {
+				return nil, err
+			}
+			shardApis[shardId], err = rawapi.NewNetworkRawApiAccessor(shardId, networkManager, routingConfig, cache)
 		}
 		if err != nil {
 			return nil, err
@@ -239,7 +266,12 @@ func initSyncers(ctx context.Context, syncers []*collate.Syncer, allowDbDrop boo
 	return nil
 }
 
-func getSyncerConfig(name string, cfg *Config, shardId types.ShardId) *collate.SyncerConfig {
+func getSyncerConfig(
+	name string,
+	cfg *Config,
+	shardId types.ShardId,
+	liveTracers *live.Tracers,
+) *collate.SyncerConfig {
 	collatorTickPeriod := time.Millisecond * time.Duration(cfg.CollatorTickPeriodMs)
 	syncerTimeout := syncTimeoutFactor * collatorTickPeriod
 
@@ -248,7 +280,7 @@ func getSyncerConfig(name string, cfg *Config, shardId types.ShardId) *collate.S
 		ShardId:              shardId,
 		Timeout:              syncerTimeout,
 		BootstrapPeers:       cfg.BootstrapPeers,
-		BlockGeneratorParams: cfg.BlockGeneratorParams(shardId),
+		BlockGeneratorParams: cfg.BlockGeneratorParams(shardId, liveTracers),
 		ZeroStateConfig:      cfg.ZeroState,
 	}
 }
@@ -271,6 +303,7 @@ func createSyncers(
 	validators []*collate.Validator,
 	nm *network.Manager,
 	database db.DB,
+	liveTracers *live.Tracers,
 	logger logging.Logger,
 ) (*syncersResult, error) {
 	res := &syncersResult{
@@ -281,7 +314,7 @@ func createSyncers(
 
 	for i := range cfg.NShards {
 		shardId := types.ShardId(i)
-		syncerConfig := getSyncerConfig(name, cfg, shardId)
+		syncerConfig := getSyncerConfig(name, cfg, shardId, liveTracers)
 		syncer, err := collate.NewSyncer(syncerConfig, validators[i], database, nm)
 		if err != nil {
 			return nil, err
@@ -322,8 +355,42 @@ func createSyncers(
 	return res, nil
 }
 
+// createAddressTransactionIndexFuncs prepares the address transaction index of the node shards.
+// If the index is enabled, it is backfilled in background once syncers are initialized,
+// otherwise it is marked as unavailable, so that it is rebuilt when enabled again.
+func createAddressTransactionIndexFuncs(
+	ctx context.Context,
+	cfg *Config,
+	database db.DB,
+	syncers *syncersResult,
+	logger logging.Logger,
+) ([]concurrent.FuncWithSource, error) {
+	if !cfg.EnableAddressTxIndex {
+		for i := range cfg.NShards {
+			if err := execution.DisableAddressTransactionIndex(ctx, database, types.ShardId(i)); err != nil {
+				return nil, fmt.Errorf("failed to disable address transaction index: %w", err)
+			}
+		}
+		return nil, nil
+	}
+
+	return []concurrent.FuncWithSource{concurrent.WithSource(func(ctx context.Context) error {
+		if err := syncers.Wait(); err != nil { // Wait for syncers initialization
+			return err
+		}
+		for i := range cfg.NShards {
+			if err := execution.BackfillAddressTransactionIndex(ctx, database, types.ShardId(i), logger); err != nil {
+				logger.Error().Err(err).Msg("Address transaction index backfill failed")
+				return err
+			}
+		}
+		return nil
+	})}, nil
+}
+
 type Node struct {
 	NetworkManager *network.Manager
+	liveTracers    *live.Tracers
 	funcs          []concurrent.FuncWithSource
 	logger         logging.Logger
 	ctx            context.Context
@@ -342,6 +409,11 @@ func (i *Node) Close(ctx context.Context) {
 	if i.NetworkManager != nil {
 		i.NetworkManager.Close()
 	}
+	if i.liveTracers != nil {
+		if err := i.liveTracers.Close(); err != nil {
+			i.logger.Error().Err(err).Msg("Failed to close live tracers")
+		}
+	}
 	telemetry.Shutdown(ctx)
 }
 
@@ -351,33 +423,41 @@ func runNormalOrCollatorsOnly(
 	cfg *Config,
 	database db.DB,
 	networkManager *network.Manager,
+	liveTracers *live.Tracers,
 	logger logging.Logger,
-) ([]concurrent.FuncWithSource, map[types.ShardId]txnpool.Pool, error) {
+) ([]concurrent.FuncWithSource, map[types.ShardId]txnpool.Pool, *devTools, error) {
 	if err := cfg.LoadValidatorKeys(); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	if !cfg.SplitShards && len(cfg.ZeroState.GetValidators()) == 0 {
 		if err := initDefaultValidator(cfg); err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	}
 
-	validators, err := createValidators(ctx, cfg, database, networkManager)
+	dev := newDevTools(cfg)
+	validators, err := createValidators(ctx, cfg, database, networkManager, dev, liveTracers)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	syncersResult, err := createSyncers("sync", cfg, validators, networkManager, database, logger)
+	syncersResult, err := createSyncers("sync", cfg, validators, networkManager, database, liveTracers, logger)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	funcs = append(funcs, syncersResult.funcs...)
 
-	shardFuncs, err := createShards(cfg, validators, syncersResult, database, networkManager, logger)
+	indexFuncs, err := createAddressTransactionIndexFuncs(ctx, cfg, database, syncersResult, logger)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	funcs = append(funcs, indexFuncs...)
+
+	shardFuncs, err := createShards(cfg, validators, syncersResult, database, networkManager, dev, logger)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to create collators")
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	txPools := make(map[types.ShardId]txnpool.Pool)
@@ -389,8 +469,12 @@ func runNormalOrCollatorsOnly(
 		}
 	}
 
+	if dev != nil {
+		dev.snapshots = collate.NewChainSnapshots(database, validators)
+	}
+
 	funcs = append(funcs, shardFuncs...)
-	return funcs, txPools, nil
+	return funcs, txPools, dev, nil
 }
 
 func CreateNode(
@@ -439,7 +523,18 @@ func CreateNode(
 		}
 	}
 
+	liveTracers, err := live.New(cfg.LiveTracers)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create live tracers")
+		return nil, err
+	}
+
+	if cfg.Automine && cfg.RunMode == NormalRunMode {
+		cfg.automine = collate.NewAutomine(cfg.NShards)
+	}
+
 	var txnPools map[types.ShardId]txnpool.Pool
+	var dev *devTools
 	if cfg.Network != nil && cfg.RunMode != NormalRunMode {
 		cfg.Network.DHTMode = dht.ModeClient
 	}
@@ -452,7 +547,7 @@ func CreateNode(
 	var syncersResult *syncersResult
 	switch cfg.RunMode {
 	case NormalRunMode, CollatorsOnlyRunMode:
-		funcs, txnPools, err = runNormalOrCollatorsOnly(ctx, funcs, cfg, database, networkManager, logger)
+		funcs, txnPools, dev, err = runNormalOrCollatorsOnly(ctx, funcs, cfg, database, networkManager, liveTracers, logger)
 		if err != nil {
 			return nil, err
 		}
@@ -461,18 +556,24 @@ func CreateNode(
 			logger.Error().Err(err).Msg("Invalid configuration")
 			return nil, err
 		}
-		validators, err := createValidators(ctx, cfg, database, networkManager)
+		validators, err := createValidators(ctx, cfg, database, networkManager, nil, liveTracers)
 		if err != nil {
 			return nil, err
 		}
-		syncersResult, err = createSyncers("archive-sync", cfg, validators, networkManager, database, logger)
+		syncersResult, err = createSyncers("archive-sync", cfg, validators, networkManager, database, liveTracers, logger)
 		if err != nil {
 			return nil, err
 		}
 		funcs = append(funcs, syncersResult.funcs...)
+
+		indexFuncs, err := createAddressTransactionIndexFuncs(ctx, cfg, database, syncersResult, logger)
+		if err != nil {
+			return nil, err
+		}
+		funcs = append(funcs, indexFuncs...)
 	case BlockReplayRunMode:
 		replayer := collate.NewReplayScheduler(database, collate.ReplayParams{
-			BlockGeneratorParams: cfg.BlockGeneratorParams(cfg.Replay.ShardId),
+			BlockGeneratorParams: cfg.BlockGeneratorParams(cfg.Replay.ShardId, liveTracers),
 			Timeout:              time.Millisecond * time.Duration(cfg.CollatorTickPeriodMs),
 			ReplayFirstBlock:     cfg.Replay.BlockIdFirst,
 			ReplayLastBlock:      cfg.Replay.BlockIdLast,
@@ -535,7 +636,7 @@ func CreateNode(
 					return fmt.Errorf("failed to create node client: %w", err)
 				}
 			}
-			if err := startRpcServer(ctx, cfg, rawApi, database, cl); err != nil {
+			if err := startRpcServer(ctx, cfg, rawApi, database, cl, dev); err != nil {
 				logger.Error().Err(err).Msg("RPC server goroutine failed")
 				return err
 			}
@@ -558,6 +659,7 @@ func CreateNode(
 
 	return &Node{
 		NetworkManager: networkManager,
+		liveTracers:    liveTracers,
 		funcs:          funcs,
 		logger:         logger,
 		ctx:            ctx,
@@ -632,18 +734,24 @@ func createValidators(
 	cfg *Config,
 	database db.DB,
 	networkManager *network.Manager,
+	dev *devTools,
+	liveTracers *live.Tracers,
 ) ([]*collate.Validator, error) {
 	collatorTickPeriod := time.Millisecond * time.Duration(cfg.CollatorTickPeriodMs)
 
 	list := make([]*collate.Validator, cfg.NShards)
 	for i := range cfg.NShards {
 		shardId := types.ShardId(i)
-		params := createCollateParams(shardId, cfg, collatorTickPeriod)
+		params := createCollateParams(shardId, cfg, collatorTickPeriod, liveTracers)
+		params.DevCheats = dev.getShardCheats(shardId)
 
 		var err error
 		var txpool *txnpool.TxnPool
 		if cfg.IsShardActive(shardId) {
-			txpool, err = txnpool.New(ctx, txnpool.NewConfig(shardId), networkManager)
+			txnPoolCfg := txnpool.NewConfig(shardId)
+			txnPoolCfg.Database = database
+			txnPoolCfg.DevCheats = params.DevCheats
+			txpool, err = txnpool.New(ctx, txnPoolCfg, networkManager)
 			if err != nil {
 				return nil, err
 			}
@@ -658,6 +766,7 @@ func createShards(
 	cfg *Config,
 	validators []*collate.Validator, syncers *syncersResult,
 	database db.DB, networkManager *network.Manager,
+	dev *devTools,
 	logger logging.Logger,
 ) ([]concurrent.FuncWithSource, error) {
 	funcs := make([]concurrent.FuncWithSource, 0, cfg.NShards)
@@ -688,6 +797,7 @@ func createShards(
 				return nil, err
 			}
 			collator := collate.NewScheduler(validators[i], database, consensus, networkManager)
+			dev.addMiner(shardId, collator)
 
 			funcs = append(funcs, concurrent.WithSource(func(ctx context.Context) error {
 				if err := syncers.Wait(); err != nil { // Wait for syncers initialization
@@ -712,12 +822,18 @@ func createShards(
 	return funcs, nil
 }
 
-func createCollateParams(shard types.ShardId, cfg *Config, collatorTickPeriod time.Duration) *collate.Params {
+func createCollateParams(
+	shard types.ShardId,
+	cfg *Config,
+	collatorTickPeriod time.Duration,
+	liveTracers *live.Tracers,
+) *collate.Params {
 	return &collate.Params{
-		BlockGeneratorParams: cfg.BlockGeneratorParams(shard),
+		BlockGeneratorParams: cfg.BlockGeneratorParams(shard, liveTracers),
 		CollatorTickPeriod:   collatorTickPeriod,
 		Timeout:              collatorTickPeriod,
 		Topology:             collate.GetShardTopologyById(cfg.Topology),
 		L1Fetcher:            cfg.L1Fetcher,
+		Automine:             cfg.automine,
 	}
 }
@@ -69,6 +69,7 @@ type FiltersManager struct {
 	blockSubs map[SubscriptionID]chan<- *types.Block
 	mutex     sync.RWMutex
 	lastHash  common.Hash
+	lastId    types.BlockNumber
 	wg        sync.WaitGroup
 }
 
@@ -168,30 +169,70 @@ func (m *FiltersManager) PollBlocks(delay time.Duration) {
 			continue
 		}
 
+		m.mutex.Lock()
 		if m.lastHash != lastHash {
-			m.mutex.Lock()
-			for currHash := lastHash; m.lastHash != currHash; {
-				block, err := m.processBlockHash(currHash)
-				if err != nil {
-					logger.Warn().Err(err).Msg("processBlockHash failed")
-					continue
-				}
-				for _, ch := range m.blockSubs {
-					// Don't send if the channel is full.
-					// Probably subscriber just disconnected, and it shouldn't block us.
-					if len(ch) < cap(ch) {
-						ch <- block
-					}
-				}
-				currHash = block.PrevBlock
-				if currHash == common.EmptyHash {
-					break
-				}
+			m.processNewBlocksLocked(lastHash)
+		}
+		m.mutex.Unlock()
+	}
+}
+
+// processNewBlocksLocked walks back from the new last block to the last processed one
+// and sends the blocks and their logs to the subscribers.
+func (m *FiltersManager) processNewBlocksLocked(lastHash common.Hash) {
+	var lastId types.BlockNumber
+	for currHash := lastHash; m.lastHash != currHash; {
+		block, receipts, err := m.readBlock(currHash)
+		if err != nil {
+			logger.Warn().Err(err).Msg("readBlock failed")
+			continue
+		}
+		if currHash == lastHash {
+			lastId = block.Id
+		}
+		// The chain was rolled back, so the block and the ones before it are already processed
+		if m.lastHash != common.EmptyHash && block.Id <= m.lastId {
+			break
+		}
+		if err := m.process(block, receipts); err != nil {
+			logger.Warn().Err(err).Msg("process failed")
+			continue
+		}
+		for _, ch := range m.blockSubs {
+			// Don't send if the channel is full.
+			// Probably subscriber just disconnected, and it shouldn't block us.
+			if len(ch) < cap(ch) {
+				ch <- block
 			}
-			m.lastHash = lastHash
-			m.mutex.Unlock()
+		}
+		currHash = block.PrevBlock
+		if currHash == common.EmptyHash {
+			break
 		}
 	}
+	m.lastHash = lastHash
+	m.lastId = lastId
+}
+
+// Rewind makes the current last block the last processed one, so that the blocks
+// produced after a rollback of the chain are processed starting from it.
+func (m *FiltersManager) Rewind() (types.BlockNumber, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	tx, err := m.db.CreateRoTx(m.ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	block, hash, err := db.ReadLastBlock(tx, m.shardId)
+	if err != nil {
+		return 0, err
+	}
+	m.lastHash = hash
+	m.lastId = block.Id
+	return block.Id, nil
 }
 
 // / If FromBlock is set in the filter, then processBlocksRange processes all blocks in the range [FromBlock..ToBlock].
@@ -242,24 +283,23 @@ func (m *FiltersManager) readReceipts(tx db.RoTx, block *types.Block) ([]*types.
 	return reader.Values()
 }
 
-func (m *FiltersManager) processBlockHash(lastHash common.Hash) (*types.Block, error) {
+func (m *FiltersManager) readBlock(hash common.Hash) (*types.Block, types.Receipts, error) {
 	tx, err := m.db.CreateRoTx(m.ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer tx.Rollback()
 
-	block, err := db.ReadBlock(tx, m.shardId, lastHash)
+	block, err := db.ReadBlock(tx, m.shardId, hash)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	receipts, err := m.readReceipts(tx, block)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	return block, m.process(block, receipts)
+	return block, receipts, nil
 }
 
 func (m *FiltersManager) processFilter(block *types.Block, filter *Filter, receipts types.Receipts) error {
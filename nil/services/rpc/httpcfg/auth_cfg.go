@@ -0,0 +1,31 @@
+package httpcfg
+
+// AuthRole lists the RPC namespaces and methods available to a caller.
+type AuthRole struct {
+	// Namespaces allowed entirely, e.g. "eth". "*" allows all namespaces.
+	Namespaces []string `yaml:"namespaces,omitempty"`
+	// Methods allowed individually, e.g. "debug_getBlockByNumber".
+	Methods []string `yaml:"methods,omitempty"`
+}
+
+// AuthApiKey is a static API key passed in the X-Api-Key header.
+// The key is granted the methods of its role and its own allowlist.
+type AuthApiKey struct {
+	Key      string `yaml:"key"`
+	Role     string `yaml:"role,omitempty"`
+	AuthRole `yaml:",inline"`
+}
+
+// AuthCfg configures authentication of RPC callers.
+// Callers are identified either by an API key or by a JWT passed as "Authorization: Bearer <token>".
+// JWTs must be signed with HS256 using the shared secret and carry the "role" claim.
+type AuthCfg struct {
+	// JWTSecretFile is a path to a file with a hex-encoded secret, JWTs are rejected if it is empty.
+	JWTSecretFile string       `yaml:"jwtSecretFile,omitempty"`
+	ApiKeys       []AuthApiKey `yaml:"apiKeys,omitempty"`
+
+	Roles map[string]AuthRole `yaml:"roles,omitempty"`
+
+	// AnonymousRole is the role of callers without credentials, such requests are rejected if it is empty.
+	AnonymousRole string `yaml:"anonymousRole,omitempty"`
+}
@@ -48,4 +48,7 @@ type HttpCfg struct {
 	RPCSlowLogThreshold time.Duration
 
 	KeepHeaders []string // List of headers to pass to the request handler
+
+	Auth      *AuthCfg      // Authentication of callers, disabled if nil
+	RateLimit *RateLimitCfg // Rate limiting of callers, disabled if nil
 }
@@ -0,0 +1,48 @@
+package httpcfg
+
+import (
+	"fmt"
+	"math"
+)
+
+// RateLimitCfg configures token-bucket limiting of requests per client.
+// Clients are identified by their credentials if authentication is enabled, otherwise by IP address.
+// Each call takes the number of tokens equal to the cost of the method.
+type RateLimitCfg struct {
+	// Rate is the number of tokens given to each client per second.
+	Rate float64 `yaml:"rate"`
+	// Burst is the maximum number of tokens a client can accumulate.
+	Burst int `yaml:"burst"`
+	// MethodCosts overrides the default costs of methods.
+	MethodCosts map[string]int `yaml:"methodCosts,omitempty"`
+	// MaxBatchSize is the maximum number of calls in a batch request.
+	MaxBatchSize int `yaml:"maxBatchSize,omitempty"`
+}
+
+func NewDefaultRateLimitCfg() *RateLimitCfg {
+	return &RateLimitCfg{
+		Rate:         100,
+		Burst:        200,
+		MaxBatchSize: 100,
+	}
+}
+
+// Validate checks that the rate, the burst and the method costs are positive.
+// A cost exceeding the burst is allowed, it makes the method unavailable.
+func (c *RateLimitCfg) Validate() error {
+	if !(c.Rate > 0) || math.IsInf(c.Rate, 0) {
+		return fmt.Errorf("rate limit rate must be a positive number, got %v", c.Rate)
+	}
+	if c.Burst <= 0 {
+		return fmt.Errorf("rate limit burst must be positive, got %d", c.Burst)
+	}
+	if c.MaxBatchSize < 0 {
+		return fmt.Errorf("rate limit max batch size must not be negative, got %d", c.MaxBatchSize)
+	}
+	for method, cost := range c.MethodCosts {
+		if cost <= 0 {
+			return fmt.Errorf("cost of method %s must be positive, got %d", method, cost)
+		}
+	}
+	return nil
+}
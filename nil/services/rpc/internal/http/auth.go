@@ -0,0 +1,264 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	apiKeyHeader        = "X-Api-Key"
+	authorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer "
+
+	// namespaceSeparator separates the namespace from the method name, e.g. "eth_getBlockByNumber"
+	namespaceSeparator = "_"
+	allNamespaces      = "*"
+
+	minJwtSecretLength = 32
+
+	// jwtLeeway is the tolerated clock skew between the token issuer and the node
+	jwtLeeway = time.Minute
+)
+
+var errMissingCredentials = errors.New("missing credentials")
+
+type (
+	allowlistCtxKey struct{}
+	clientIdCtxKey  struct{}
+)
+
+// methodAllowlist is the set of methods available to an authenticated caller.
+type methodAllowlist struct {
+	allNamespaces bool
+	namespaces    map[string]struct{}
+	methods       map[string]struct{}
+}
+
+func newMethodAllowlist(roles ...httpcfg.AuthRole) *methodAllowlist {
+	l := &methodAllowlist{
+		namespaces: make(map[string]struct{}),
+		methods:    make(map[string]struct{}),
+	}
+	for _, role := range roles {
+		for _, ns := range role.Namespaces {
+			if ns == allNamespaces {
+				l.allNamespaces = true
+			}
+			l.namespaces[ns] = struct{}{}
+		}
+		for _, method := range role.Methods {
+			l.methods[method] = struct{}{}
+		}
+	}
+	return l
+}
+
+func (l *methodAllowlist) allows(method string) bool {
+	if l.allNamespaces {
+		return true
+	}
+	if _, ok := l.methods[method]; ok {
+		return true
+	}
+	namespace, _, found := strings.Cut(method, namespaceSeparator)
+	if !found {
+		return false
+	}
+	_, ok := l.namespaces[namespace]
+	return ok
+}
+
+// IsMethodAllowed checks that the caller of the request is allowed to call the method.
+// Requests which passed no authentication (e.g., if it is disabled) are allowed to call any method.
+func IsMethodAllowed(ctx context.Context, method string) bool {
+	allowlist, ok := ctx.Value(allowlistCtxKey{}).(*methodAllowlist)
+	if !ok {
+		return true
+	}
+	return allowlist.allows(method)
+}
+
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Role string `json:"role"`
+}
+
+// authHandler authenticates the callers and passes their method allowlists to the request handler.
+type authHandler struct {
+	jwtSecret []byte
+	// API keys are looked up by their hashes, so that the lookup time doesn't depend on the key
+	apiKeys   map[[sha256.Size]byte]*methodAllowlist
+	roles     map[string]*methodAllowlist
+	anonymous *methodAllowlist
+	next      http.Handler
+}
+
+var _ http.Handler = (*authHandler)(nil)
+
+// NewAuthHandler returns a handler which rejects requests of unauthenticated callers
+// and restricts the methods available to the authenticated ones.
+func NewAuthHandler(cfg *httpcfg.AuthCfg, next http.Handler) (http.Handler, error) {
+	h := &authHandler{
+		apiKeys: make(map[[sha256.Size]byte]*methodAllowlist),
+		roles:   make(map[string]*methodAllowlist),
+		next:    next,
+	}
+
+	if cfg.JWTSecretFile != "" {
+		secret, err := readJwtSecret(cfg.JWTSecretFile)
+		if err != nil {
+			return nil, err
+		}
+		h.jwtSecret = secret
+	}
+
+	for name, role := range cfg.Roles {
+		h.roles[name] = newMethodAllowlist(role)
+	}
+
+	for i, key := range cfg.ApiKeys {
+		if key.Key == "" {
+			return nil, fmt.Errorf("API key #%d is empty", i)
+		}
+		roles := []httpcfg.AuthRole{key.AuthRole}
+		if key.Role != "" {
+			role, ok := cfg.Roles[key.Role]
+			if !ok {
+				return nil, fmt.Errorf("API key #%d refers to unknown role %q", i, key.Role)
+			}
+			roles = append(roles, role)
+		}
+		hash := sha256.Sum256([]byte(key.Key))
+		if _, ok := h.apiKeys[hash]; ok {
+			return nil, fmt.Errorf("API key #%d is duplicated", i)
+		}
+		h.apiKeys[hash] = newMethodAllowlist(roles...)
+	}
+
+	if cfg.AnonymousRole != "" {
+		var ok bool
+		if h.anonymous, ok = h.roles[cfg.AnonymousRole]; !ok {
+			return nil, fmt.Errorf("unknown anonymous role %q", cfg.AnonymousRole)
+		}
+	}
+
+	return h, nil
+}
+
+func readJwtSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT secret: %w", err)
+	}
+	secret, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT secret: %w", err)
+	}
+	if len(secret) < minJwtSecretLength {
+		return nil, fmt.Errorf("JWT secret is too short: %d bytes, at least %d required",
+			len(secret), minJwtSecretLength)
+	}
+	return secret, nil
+}
+
+func (h *authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Permit dumb empty requests for remote health-checks (AWS)
+	if r.Method == http.MethodGet && r.ContentLength == 0 && r.URL.RawQuery == "" {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	clientId, allowlist, err := h.authenticate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	ctx := context.WithValue(r.Context(), allowlistCtxKey{}, allowlist)
+	if clientId != "" {
+		ctx = context.WithValue(ctx, clientIdCtxKey{}, clientId)
+	}
+	h.next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// authenticate returns the identity of the caller and the methods available to it.
+// The identity is empty for anonymous callers.
+func (h *authHandler) authenticate(r *http.Request) (string, *methodAllowlist, error) {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		hash := sha256.Sum256([]byte(key))
+		allowlist, ok := h.apiKeys[hash]
+		if !ok {
+			return "", nil, errors.New("invalid API key")
+		}
+		return "apikey:" + hex.EncodeToString(hash[:8]), allowlist, nil
+	}
+
+	if header := r.Header.Get(authorizationHeader); header != "" {
+		token, ok := strings.CutPrefix(header, bearerPrefix)
+		if !ok {
+			return "", nil, errors.New("unsupported authorization scheme")
+		}
+		return h.authenticateJwt(token)
+	}
+
+	if h.anonymous == nil {
+		return "", nil, errMissingCredentials
+	}
+	return "", h.anonymous, nil
+}
+
+func (h *authHandler) authenticateJwt(token string) (string, *methodAllowlist, error) {
+	if h.jwtSecret == nil {
+		return "", nil, errors.New("JWT authentication is disabled")
+	}
+
+	var claims jwtClaims
+	// The time claims are validated separately, since the parser neither requires them nor tolerates clock skew
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithoutClaimsValidation())
+	if _, err := parser.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return h.jwtSecret, nil
+	}); err != nil {
+		return "", nil, fmt.Errorf("invalid JWT: %w", err)
+	}
+	if err := validateJwtTime(&claims.RegisteredClaims, time.Now()); err != nil {
+		return "", nil, fmt.Errorf("invalid JWT: %w", err)
+	}
+
+	allowlist, ok := h.roles[claims.Role]
+	if !ok {
+		return "", nil, fmt.Errorf("invalid JWT: unknown role %q", claims.Role)
+	}
+
+	// Tokens without the subject are identified by their role
+	subject := claims.Subject
+	if subject == "" {
+		subject = "role:" + claims.Role
+	}
+	return "jwt:" + subject, allowlist, nil
+}
+
+// validateJwtTime checks that the token has an expiration time and is valid at the moment, up to jwtLeeway.
+func validateJwtTime(claims *jwt.RegisteredClaims, now time.Time) error {
+	if claims.ExpiresAt == nil {
+		return errors.New("token has no expiration time")
+	}
+	if now.After(claims.ExpiresAt.Add(jwtLeeway)) {
+		return errors.New("token is expired")
+	}
+	if claims.IssuedAt != nil && claims.IssuedAt.After(now.Add(jwtLeeway)) {
+		return errors.New("token is issued in the future")
+	}
+	if claims.NotBefore != nil && claims.NotBefore.After(now.Add(jwtLeeway)) {
+		return errors.New("token is not valid yet")
+	}
+	return nil
+}
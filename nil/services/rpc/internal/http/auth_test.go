@@ -0,0 +1,216 @@
+package http
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMethodAllowlist(t *testing.T) {
+	t.Parallel()
+
+	allowlist := newMethodAllowlist(
+		httpcfg.AuthRole{Namespaces: []string{"eth"}},
+		httpcfg.AuthRole{Methods: []string{"debug_getBlockByNumber"}},
+	)
+	assert.True(t, allowlist.allows("eth_getBlockByNumber"))
+	assert.True(t, allowlist.allows("debug_getBlockByNumber"))
+	assert.False(t, allowlist.allows("debug_getContract"))
+	assert.False(t, allowlist.allows("ethereum"))
+	assert.False(t, allowlist.allows("dev_doPanicOnShard"))
+
+	all := newMethodAllowlist(httpcfg.AuthRole{Namespaces: []string{allNamespaces}})
+	assert.True(t, all.allows("dev_doPanicOnShard"))
+}
+
+func TestAuthHandler(t *testing.T) {
+	t.Parallel()
+
+	secret := make([]byte, minJwtSecretLength)
+	secret[0] = 1
+	secretFile := filepath.Join(t.TempDir(), "jwt.hex")
+	require.NoError(t, os.WriteFile(secretFile, []byte("0x"+hex.EncodeToString(secret)+"\n"), 0o600))
+
+	cfg := &httpcfg.AuthCfg{
+		JWTSecretFile: secretFile,
+		ApiKeys: []httpcfg.AuthApiKey{
+			{Key: "user-key", Role: "public", AuthRole: httpcfg.AuthRole{Methods: []string{"debug_getBlockByNumber"}}},
+			{Key: "admin-key", Role: "admin"},
+		},
+		Roles: map[string]httpcfg.AuthRole{
+			"public": {Namespaces: []string{"eth"}},
+			"admin":  {Namespaces: []string{allNamespaces}},
+		},
+	}
+
+	// The handler replies with the list of methods available to the caller
+	methods := []string{"eth_getBlockByNumber", "debug_getBlockByNumber", "dev_doPanicOnShard"}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var allowed []string
+		for _, method := range methods {
+			if IsMethodAllowed(r.Context(), method) {
+				allowed = append(allowed, method)
+			}
+		}
+		_, err := w.Write([]byte(strings.Join(allowed, ",")))
+		assert.NoError(t, err)
+	})
+
+	newJwt := func(t *testing.T, key []byte, method jwt.SigningMethod, claims jwtClaims) string {
+		t.Helper()
+
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		require.NoError(t, err)
+		return token
+	}
+
+	roleClaims := func(role string) jwtClaims {
+		return jwtClaims{
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+			Role:             role,
+		}
+	}
+
+	check := func(t *testing.T, cfg *httpcfg.AuthCfg, header, value string, expectedCode int, expectedBody string) {
+		t.Helper()
+
+		handler, err := NewAuthHandler(cfg, next)
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "http://url.com", strings.NewReader("{}"))
+		if header != "" {
+			request.Header.Set(header, value)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		confirmStatusCode(t, recorder.Code, expectedCode)
+		if expectedCode == http.StatusOK {
+			assert.Equal(t, expectedBody, recorder.Body.String())
+		}
+	}
+
+	t.Run("ApiKey", func(t *testing.T) {
+		t.Parallel()
+
+		check(t, cfg, apiKeyHeader, "user-key", http.StatusOK, "eth_getBlockByNumber,debug_getBlockByNumber")
+		check(t, cfg, apiKeyHeader, "admin-key", http.StatusOK, strings.Join(methods, ","))
+		check(t, cfg, apiKeyHeader, "bad-key", http.StatusUnauthorized, "")
+	})
+
+	t.Run("Jwt", func(t *testing.T) {
+		t.Parallel()
+
+		token := newJwt(t, secret, jwt.SigningMethodHS256, roleClaims("public"))
+		check(t, cfg, authorizationHeader, bearerPrefix+token, http.StatusOK, "eth_getBlockByNumber")
+
+		token = newJwt(t, secret, jwt.SigningMethodHS256, roleClaims("admin"))
+		check(t, cfg, authorizationHeader, bearerPrefix+token, http.StatusOK, strings.Join(methods, ","))
+		check(t, cfg, authorizationHeader, "Basic "+token, http.StatusUnauthorized, "")
+
+		token = newJwt(t, []byte("another secret of the same length"), jwt.SigningMethodHS256, roleClaims("admin"))
+		check(t, cfg, authorizationHeader, bearerPrefix+token, http.StatusUnauthorized, "")
+
+		token = newJwt(t, secret, jwt.SigningMethodHS512, roleClaims("admin"))
+		check(t, cfg, authorizationHeader, bearerPrefix+token, http.StatusUnauthorized, "")
+
+		token = newJwt(t, secret, jwt.SigningMethodHS256, roleClaims("unknown"))
+		check(t, cfg, authorizationHeader, bearerPrefix+token, http.StatusUnauthorized, "")
+
+		token = newJwt(t, secret, jwt.SigningMethodHS256, jwtClaims{Role: "admin"})
+		check(t, cfg, authorizationHeader, bearerPrefix+token, http.StatusUnauthorized, "")
+	})
+
+	t.Run("JwtTime", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Now()
+		for _, test := range []struct {
+			name     string
+			claims   jwt.RegisteredClaims
+			expected int
+		}{
+			{"NoExpiration", jwt.RegisteredClaims{}, http.StatusUnauthorized},
+			{
+				"Expired",
+				jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(now.Add(-2 * jwtLeeway))},
+				http.StatusUnauthorized,
+			},
+			{
+				"ExpiredWithinLeeway",
+				jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(now.Add(-jwtLeeway / 2))},
+				http.StatusOK,
+			},
+			{
+				"IssuedInFuture",
+				jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+					IssuedAt:  jwt.NewNumericDate(now.Add(2 * jwtLeeway)),
+				},
+				http.StatusUnauthorized,
+			},
+			{
+				"NotValidYet",
+				jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+					NotBefore: jwt.NewNumericDate(now.Add(2 * jwtLeeway)),
+				},
+				http.StatusUnauthorized,
+			},
+			{
+				"NotBeforeWithinLeeway",
+				jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+					IssuedAt:  jwt.NewNumericDate(now.Add(jwtLeeway / 2)),
+					NotBefore: jwt.NewNumericDate(now.Add(jwtLeeway / 2)),
+				},
+				http.StatusOK,
+			},
+		} {
+			t.Run(test.name, func(t *testing.T) {
+				t.Parallel()
+
+				token := newJwt(t, secret, jwt.SigningMethodHS256, jwtClaims{RegisteredClaims: test.claims, Role: "public"})
+				check(t, cfg, authorizationHeader, bearerPrefix+token, test.expected, "eth_getBlockByNumber")
+			})
+		}
+	})
+
+	t.Run("Anonymous", func(t *testing.T) {
+		t.Parallel()
+
+		check(t, cfg, "", "", http.StatusUnauthorized, "")
+
+		anonymousCfg := *cfg
+		anonymousCfg.AnonymousRole = "public"
+		check(t, &anonymousCfg, "", "", http.StatusOK, "eth_getBlockByNumber")
+	})
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewAuthHandler(&httpcfg.AuthCfg{AnonymousRole: "unknown"}, next)
+		require.ErrorContains(t, err, "unknown anonymous role")
+
+		_, err = NewAuthHandler(&httpcfg.AuthCfg{ApiKeys: []httpcfg.AuthApiKey{{Key: "key", Role: "unknown"}}}, next)
+		require.ErrorContains(t, err, "unknown role")
+
+		_, err = NewAuthHandler(&httpcfg.AuthCfg{ApiKeys: []httpcfg.AuthApiKey{{Key: "key"}, {Key: "key"}}}, next)
+		require.ErrorContains(t, err, "duplicated")
+
+		shortSecretFile := filepath.Join(t.TempDir(), "jwt.hex")
+		require.NoError(t, os.WriteFile(shortSecretFile, []byte("0102"), 0o600))
+		_, err = NewAuthHandler(&httpcfg.AuthCfg{JWTSecretFile: shortSecretFile}, next)
+		require.ErrorContains(t, err, "too short")
+	})
+}
@@ -1,7 +1,9 @@
 package http
 
 import (
+	"context"
 	"io"
+	"net"
 	"net/http"
 	"time"
 )
@@ -21,6 +23,19 @@ type (
 	originCtxKey    struct{}
 )
 
+// ClientId returns the identity of the caller of the request: the authenticated one if any,
+// otherwise the remote IP address.
+func ClientId(ctx context.Context) string {
+	if id, ok := ctx.Value(clientIdCtxKey{}).(string); ok {
+		return id
+	}
+	remote, _ := ctx.Value(remoteCtxKey{}).(string)
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		return host
+	}
+	return remote
+}
+
 // HttpServerConn turns a HTTP connection into a Conn.
 type HttpServerConn struct {
 	io.Reader
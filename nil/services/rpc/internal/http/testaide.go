@@ -4,6 +4,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"net/http"
 	"strings"
@@ -156,3 +157,8 @@ func (h *httpServer) enableRPC(srv StoppableSingleRequestServer, config HttpConf
 	})
 	return nil
 }
+
+// WithClientId sets the identity of the caller as if it was authenticated.
+func WithClientId(ctx context.Context, clientId string) context.Context {
+	return context.WithValue(ctx, clientIdCtxKey{}, clientId)
+}
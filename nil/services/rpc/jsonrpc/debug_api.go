@@ -26,6 +26,17 @@ type DebugAPI interface {
 		contractAddr types.Address,
 		blockNrOrHash transport.BlockNumberOrHash,
 	) (*DebugRPCContract, error)
+	GetPendingRequests(
+		ctx context.Context,
+		contractAddr types.Address,
+		blockNrOrHash transport.BlockNumberOrHash,
+	) ([]*RPCPendingRequest, error)
+	GetStateDiff(
+		ctx context.Context,
+		shardId types.ShardId,
+		blockNrOrHash transport.BlockNumberOrHash,
+	) ([]*RPCAccountDiff, error)
+	GetTransactionStateDiff(ctx context.Context, hash common.Hash) ([]*RPCAccountDiff, error)
 }
 
 type DebugAPIImpl struct {
@@ -121,3 +132,41 @@ func (api *DebugAPIImpl) GetContract(
 		AsyncContext: contract.AsyncContext,
 	}, nil
 }
+
+// GetPendingRequests implements debug_getPendingRequests. Returns the requests of the contract
+// waiting for the responses in the order they were sent.
+func (api *DebugAPIImpl) GetPendingRequests(
+	ctx context.Context,
+	contractAddr types.Address,
+	blockNrOrHash transport.BlockNumberOrHash,
+) ([]*RPCPendingRequest, error) {
+	contract, err := api.rawApi.GetContract(ctx, contractAddr, toBlockReference(blockNrOrHash))
+	if err != nil {
+		return nil, err
+	}
+	return NewRPCPendingRequests(contract.AsyncContext), nil
+}
+
+// GetStateDiff implements debug_getStateDiff. Returns the changes of the accounts made by the block.
+// The block is re-executed on top of the state of the previous one to find them.
+func (api *DebugAPIImpl) GetStateDiff(
+	ctx context.Context,
+	shardId types.ShardId,
+	blockNrOrHash transport.BlockNumberOrHash,
+) ([]*RPCAccountDiff, error) {
+	diff, err := api.rawApi.GetStateDiff(ctx, shardId, toBlockReference(blockNrOrHash))
+	if err != nil {
+		return nil, err
+	}
+	return NewRPCStateDiff(diff), nil
+}
+
+// GetTransactionStateDiff implements debug_getTransactionStateDiff.
+// Returns the changes of the accounts made by the transaction.
+func (api *DebugAPIImpl) GetTransactionStateDiff(ctx context.Context, hash common.Hash) ([]*RPCAccountDiff, error) {
+	diff, err := api.rawApi.GetTransactionStateDiff(ctx, types.ShardIdFromHash(hash), hash)
+	if err != nil {
+		return nil, err
+	}
+	return NewRPCStateDiff(diff), nil
+}
@@ -145,6 +145,11 @@ func (suite *SuiteDbgContracts) SetupSuite() {
 	suite.Require().NoError(es.SetBalance(suite.smcAddr, types.NewValueFromUint64(1234)))
 	suite.Require().NoError(es.SetExtSeqno(suite.smcAddr, 567))
 
+	acc, err := es.GetAccount(suite.smcAddr)
+	suite.Require().NoError(err)
+	acc.SetAsyncContext(2, &types.AsyncContext{Data: []byte{1, 2, 3, 4}, ResponseProcessingGas: 50_000, Deadline: 10})
+	acc.SetAsyncContext(1, &types.AsyncContext{IsAwait: true, ResponseProcessingGas: 60_000})
+
 	blockRes, err := es.Commit(0, nil)
 	suite.Require().NoError(err)
 	suite.blockHash = blockRes.BlockHash
@@ -211,6 +216,43 @@ func (suite *SuiteDbgContracts) TestGetContract() {
 	})
 }
 
+func (suite *SuiteDbgContracts) TestGetPendingRequests() {
+	ctx := context.Background()
+	res, err := suite.debugApi.GetPendingRequests(
+		ctx,
+		suite.smcAddr,
+		transport.BlockNumberOrHash{BlockNumber: transport.LatestBlock.BlockNumber})
+	suite.Require().NoError(err)
+	suite.Equal([]*RPCPendingRequest{
+		{RequestId: 1, IsAwait: true, ResponseProcessingGas: 60_000},
+		{RequestId: 2, ResponseProcessingGas: 50_000, Deadline: 10},
+	}, res)
+
+	// The requests with deadlines are indexed for the proposer
+	tx, err := suite.db.CreateRoTx(ctx)
+	suite.Require().NoError(err)
+	defer tx.Rollback()
+
+	expired, err := db.ReadExpiredRequests(tx, suite.smcAddr.ShardId(), 10, 10)
+	suite.Require().NoError(err)
+	suite.Equal([]db.RequestDeadline{{Deadline: 10, Caller: suite.smcAddr, RequestId: 2}}, expired)
+}
+
+func (suite *SuiteDbgContracts) TestGetStateDiff() {
+	ctx := context.Background()
+
+	// The zero-state block has no previous state to re-execute it on top of
+	_, err := suite.debugApi.GetStateDiff(
+		ctx,
+		suite.smcAddr.ShardId(),
+		transport.BlockNumberOrHash{BlockHash: &suite.blockHash})
+	suite.Require().ErrorContains(err, execution.ErrStateDiffUnavailable.Error())
+
+	_, err = suite.debugApi.GetTransactionStateDiff(
+		ctx, types.ToShardedHash(common.HexToHash("0x1234"), suite.smcAddr.ShardId()))
+	suite.Require().ErrorIs(err, db.ErrKeyNotFound)
+}
+
 func TestSuiteDbgContracts(t *testing.T) {
 	t.Parallel()
 
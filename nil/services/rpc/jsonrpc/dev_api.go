@@ -1,26 +1,166 @@
 package jsonrpc
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"slices"
 
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 )
 
 type DevAPI interface {
 	DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uint64, error)
+
+	SetBalance(ctx context.Context, address types.Address, balance types.Value) (common.Hash, error)
+	SetCode(ctx context.Context, address types.Address, code hexutil.Bytes) (common.Hash, error)
+	SetStorageAt(ctx context.Context, address types.Address, key common.Hash, value common.Hash) (common.Hash, error)
+	SetTokens(ctx context.Context, address types.Address, tokens map[types.TokenId]types.Value) (common.Hash, error)
+	ImpersonateAccount(ctx context.Context, address types.Address) error
+	StopImpersonatingAccount(ctx context.Context, address types.Address) error
+
+	Mine(ctx context.Context, shardId types.ShardId, blocks uint64) (types.BlockNumber, error)
+	SetNextBlockTimestamp(ctx context.Context, shardId types.ShardId, timestamp uint64) error
+}
+
+// Miner produces blocks of a shard on demand.
+type Miner interface {
+	// Mine produces the given number of blocks and returns the number of the last one.
+	Mine(ctx context.Context, n uint64) (types.BlockNumber, error)
+}
+
+// DevShard holds the means of manipulating a shard run by the node. Any of them may be nil.
+type DevShard struct {
+	Cheats *execution.DevCheats
+	Miner  Miner
 }
 
 type DevAPIImpl struct {
 	rawApi rawapi.NodeApi
+	shards map[types.ShardId]DevShard
 }
 
-func NewDevAPI(rawApi rawapi.NodeApi) DevAPI {
+// NewDevAPI creates the development API. The shards are manipulated by the cheat and mining methods,
+// these methods return an error for the shards not run by the node.
+func NewDevAPI(rawApi rawapi.NodeApi, shards map[types.ShardId]DevShard) DevAPI {
 	return &DevAPIImpl{
 		rawApi: rawApi,
+		shards: shards,
 	}
 }
 
 func (d *DevAPIImpl) DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uint64, error) {
 	return d.rawApi.DoPanicOnShard(ctx, shardId)
 }
+
+func (d *DevAPIImpl) getCheats(address types.Address) (*execution.DevCheats, error) {
+	return d.getShardCheats(address.ShardId())
+}
+
+func (d *DevAPIImpl) getShardCheats(shardId types.ShardId) (*execution.DevCheats, error) {
+	cheats := d.shards[shardId].Cheats
+	if cheats == nil {
+		return nil, fmt.Errorf("dev cheats are not available for shard %d", shardId)
+	}
+	return cheats, nil
+}
+
+// SetBalance implements dev_setBalance. It sets the balance of the account in the next block of its shard
+// and returns the hash of the transaction applying the change.
+func (d *DevAPIImpl) SetBalance(_ context.Context, address types.Address, balance types.Value) (common.Hash, error) {
+	cheats, err := d.getCheats(address)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return cheats.SetBalance(address, balance), nil
+}
+
+// SetCode implements dev_setCode. It replaces the code of the account in the next block of its shard.
+func (d *DevAPIImpl) SetCode(_ context.Context, address types.Address, code hexutil.Bytes) (common.Hash, error) {
+	cheats, err := d.getCheats(address)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return cheats.SetCode(address, types.Code(code)), nil
+}
+
+// SetStorageAt implements dev_setStorageAt. It sets the storage slot of the account in the next block of its shard.
+func (d *DevAPIImpl) SetStorageAt(
+	_ context.Context, address types.Address, key common.Hash, value common.Hash,
+) (common.Hash, error) {
+	cheats, err := d.getCheats(address)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return cheats.SetStorageAt(address, key, value), nil
+}
+
+// SetTokens implements dev_setTokens. It sets the balances of the given tokens of the account
+// in the next block of its shard. The balances of other tokens aren't changed.
+func (d *DevAPIImpl) SetTokens(
+	_ context.Context, address types.Address, tokens map[types.TokenId]types.Value,
+) (common.Hash, error) {
+	cheats, err := d.getCheats(address)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+
+	balances := make([]types.TokenBalance, 0, len(tokens))
+	for id, balance := range tokens {
+		balances = append(balances, types.TokenBalance{Token: id, Balance: balance})
+	}
+	slices.SortFunc(balances, func(a, b types.TokenBalance) int {
+		return bytes.Compare(a.Token[:], b.Token[:])
+	})
+	return cheats.SetTokens(address, balances), nil
+}
+
+// ImpersonateAccount implements dev_impersonateAccount.
+// External transactions to the account are accepted without signature verification until
+// dev_stopImpersonatingAccount is called.
+func (d *DevAPIImpl) ImpersonateAccount(_ context.Context, address types.Address) error {
+	cheats, err := d.getCheats(address)
+	if err != nil {
+		return err
+	}
+	cheats.Impersonate(address, true)
+	return nil
+}
+
+// StopImpersonatingAccount implements dev_stopImpersonatingAccount.
+func (d *DevAPIImpl) StopImpersonatingAccount(_ context.Context, address types.Address) error {
+	cheats, err := d.getCheats(address)
+	if err != nil {
+		return err
+	}
+	cheats.Impersonate(address, false)
+	return nil
+}
+
+// Mine implements dev_mine. It produces the given number of blocks of the shard right away
+// and returns the number of the last one.
+func (d *DevAPIImpl) Mine(ctx context.Context, shardId types.ShardId, blocks uint64) (types.BlockNumber, error) {
+	miner := d.shards[shardId].Miner
+	if miner == nil {
+		return 0, fmt.Errorf("mining is not available for shard %d", shardId)
+	}
+	if blocks == 0 {
+		blocks = 1
+	}
+	return miner.Mine(ctx, blocks)
+}
+
+// SetNextBlockTimestamp implements dev_setNextBlockTimestamp. The next block of the shard gets the timestamp,
+// the following blocks continue from it.
+func (d *DevAPIImpl) SetNextBlockTimestamp(_ context.Context, shardId types.ShardId, timestamp uint64) error {
+	cheats, err := d.getShardCheats(shardId)
+	if err != nil {
+		return err
+	}
+	cheats.SetNextBlockTimestamp(timestamp)
+	return nil
+}
@@ -0,0 +1,53 @@
+package jsonrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testMiner struct {
+	lastBlock types.BlockNumber
+}
+
+func (m *testMiner) Mine(_ context.Context, n uint64) (types.BlockNumber, error) {
+	m.lastBlock += types.BlockNumber(n)
+	return m.lastBlock, nil
+}
+
+func TestDevMine(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	miner := &testMiner{}
+	cheats := execution.NewDevCheats()
+	api := NewDevAPI(nil, map[types.ShardId]DevShard{
+		types.BaseShardId: {Cheats: cheats, Miner: miner},
+	})
+
+	// A single block is mined by default
+	id, err := api.Mine(ctx, types.BaseShardId, 0)
+	require.NoError(t, err)
+	assert.Equal(t, types.BlockNumber(1), id)
+
+	id, err = api.Mine(ctx, types.BaseShardId, 3)
+	require.NoError(t, err)
+	assert.Equal(t, types.BlockNumber(4), id)
+
+	_, err = api.Mine(ctx, types.MainShardId, 1)
+	require.Error(t, err)
+
+	// The timestamp is applied by the transaction included into the next block
+	assert.Nil(t, cheats.TimestampCheat())
+	require.NoError(t, api.SetNextBlockTimestamp(ctx, types.BaseShardId, 1_000))
+	txn := cheats.TimestampCheat()
+	require.NotNil(t, txn)
+	assert.True(t, execution.IsDevCheat(txn))
+
+	require.Error(t, api.SetNextBlockTimestamp(ctx, types.MainShardId, 1_000))
+}
@@ -31,6 +31,10 @@ package jsonrpc
 // @component GasShardId shardId integer "The ID of the shard whose gas price is requested."
 // @component BaseFee baseFee integer "The current base fee the given shard."
 // @component GasPrice gasPrice integer "The current gas price in the given shard."
+// @component MaxPriorityFeePerGas maxPriorityFeePerGas integer "The priority fee suggested for the transactions of the given shard."
+// @component BlockCount blockCount integer "The number of the blocks to return the fee history for."
+// @component NewestBlock newestBlock integer "The newest block of the range."
+// @component RewardPercentiles rewardPercentiles array "(Optional) The ascending percentiles of the gas used to return the priority fees at."
 // @component ChainId chainId integer "The chain ID of the network."
 // @component ReturnedValue returnedValue string "The returned value of the executed contract."
 // @component FullTx fullTx boolean "The flag that determines whether full transaction information is returned in the output."
@@ -39,3 +43,8 @@ package jsonrpc
 // @componentprop BlockHash blockHash string false "(Optional) The hash of the block. Either this or BlockNumber is required."
 // @componentprop BlockNumber blockNumber integer false "(Optional) The number of the block. Either this or BlockHash is required."
 // @component StateOverrides stateOverrides object "(Optional) Map of address-state pairs to be overrided."
+// @component CallsArgs calls array "The arguments of the transaction calls to be executed in the given order."
+// @component FromBlock fromBlock integer "The first block of the range."
+// @component ToBlock toBlock integer "The last block of the range, the range is not limited if it is set to latest."
+// @component TransactionsLimit limit integer "The maximum number of transactions to return, a default value is used if it is zero."
+// @component TransactionsCursor cursor string "(Optional) The cursor returned with the previous page of transactions."
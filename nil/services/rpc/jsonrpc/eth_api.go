@@ -153,6 +153,27 @@ type EthAPIRo interface {
 	*/
 	GetInTransactionReceipt(ctx context.Context, hash common.Hash) (*RPCReceipt, error)
 
+	/*
+		@name GetTransactionsByAddress
+		@summary Returns transactions sent or received by the given address in the given range of blocks.
+		@description Implements eth_getTransactionsByAddress. Requires the address transaction index to be enabled on the node.
+		@tags [Transactions]
+		@param address Address
+		@param fromBlock FromBlock
+		@param toBlock ToBlock
+		@param limit TransactionsLimit
+		@param cursor TransactionsCursor
+		@returns rpcAddressTransactions RPCAddressTransactions
+	*/
+	GetTransactionsByAddress(
+		ctx context.Context,
+		address types.Address,
+		fromBlock transport.BlockNumber,
+		toBlock transport.BlockNumber,
+		limit hexutil.Uint64,
+		cursor *hexutil.Bytes,
+	) (*RPCAddressTransactions, error)
+
 	/*
 		@name GetBalance
 		@summary Returns the balance of the account with the given address and at the given block.
@@ -175,6 +196,35 @@ type EthAPIRo interface {
 	*/
 	GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error)
 
+	/*
+		@name FeeHistory
+		@summary Returns base fees, gas usage and priority fees of the recent blocks of the shard.
+		@description Implements eth_feeHistory.
+		@tags [Transactions]
+		@param shardId GasShardId
+		@param blockCount BlockCount
+		@param newestBlock NewestBlock
+		@param rewardPercentiles RewardPercentiles
+		@returns feeHistoryRes FeeHistoryRes
+	*/
+	FeeHistory(
+		ctx context.Context,
+		shardId types.ShardId,
+		blockCount hexutil.Uint64,
+		newestBlock transport.BlockNumber,
+		rewardPercentiles []float64,
+	) (*FeeHistoryRes, error)
+
+	/*
+		@name MaxPriorityFeePerGas
+		@summary Returns the priority fee suggested for the transactions of the shard.
+		@description Implements eth_maxPriorityFeePerGas.
+		@tags [Transactions]
+		@param shardId GasShardId
+		@returns maxPriorityFeePerGas MaxPriorityFeePerGas
+	*/
+	MaxPriorityFeePerGas(ctx context.Context, shardId types.ShardId) (types.Value, error)
+
 	/*
 		@name GetTransactionCount
 		@summary Returns the transaction count of the account with the given address and at the given block.
@@ -292,9 +342,27 @@ type EthAPIRo interface {
 		overrides *StateOverrides,
 	) (*CallRes, error)
 
+	/*
+		@name SimulateCalls
+		@summary Executes a sequence of transaction calls on top of each other without creating transactions.
+		@description The outbound transactions are executed on their destination shards recursively,
+		including responses and bounces. Nothing is committed.
+		@tags [Calls]
+		@param calls CallsArgs
+		@param mainBlockNrOrHash BlockNumberOrHash
+		@param overrides StateOverrides
+		@returns simulateCallsRes SimulateCallsRes
+	*/
+	SimulateCalls(
+		ctx context.Context,
+		calls []CallArgs,
+		mainBlockNrOrHash transport.BlockNumberOrHash,
+		overrides *StateOverrides,
+	) (*SimulateCallsRes, error)
+
 	/*
 		@name EstimateFee
-		@summary Executes a new transaction call and returns recommended feeCredit.
+		@summary Executes a new transaction call and returns the minimal feeCredit for it and its outbound transactions.
 		@description Implements eth_estimateGas.
 		@tags [Calls]
 		@param args CallArgs
@@ -355,6 +423,8 @@ type APIImplRo struct {
 	logger          logging.Logger
 	clientEventsLog logging.Logger
 	rawapi          rawapi.NodeApi
+	// feeCalculator predicts the base fees of the next blocks
+	feeCalculator execution.FeeCalculator
 }
 
 // APIImpl is implementation of the EthAPI interface based on remote Db access
@@ -373,13 +443,18 @@ func NewEthAPIRo(
 	db db.ReadOnlyDB,
 	pollBlocksForLogs bool,
 	logClientEvents bool,
+	feeCalculator execution.FeeCalculator,
 ) *APIImplRo {
+	if feeCalculator == nil {
+		feeCalculator = &execution.MainFeeCalculator{}
+	}
 	accessor := execution.NewStateAccessor()
 	api := &APIImplRo{
 		logger:          logging.NewLogger("eth-api"),
 		accessor:        accessor,
 		rawapi:          rawapi,
 		clientEventsLog: logging.NewLogger("eth-api-rpc-requests"),
+		feeCalculator:   feeCalculator,
 	}
 	api.logs = NewLogsAggregator(ctx, db, pollBlocksForLogs)
 	if !logClientEvents {
@@ -395,8 +470,9 @@ func NewEthAPI(
 	db db.ReadOnlyDB,
 	pollBlocksForLogs bool,
 	logClientEvents bool,
+	feeCalculator execution.FeeCalculator,
 ) *APIImpl {
-	roApi := NewEthAPIRo(ctx, rawapi, db, pollBlocksForLogs, logClientEvents)
+	roApi := NewEthAPIRo(ctx, rawapi, db, pollBlocksForLogs, logClientEvents, feeCalculator)
 	return &APIImpl{roApi}
 }
 
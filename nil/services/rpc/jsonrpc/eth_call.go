@@ -6,7 +6,6 @@ import (
 	"fmt"
 
 	"github.com/NilFoundation/nil/nil/common/check"
-	"github.com/NilFoundation/nil/nil/internal/params"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
@@ -32,30 +31,53 @@ func (api *APIImplRo) Call(
 	return toCallRes(res)
 }
 
-// Add some gap (20%) to be sure that it's enough for transaction processing.
-// For now it's just heuristic function without any mathematical rationality.
-func refineResult(input types.Value) types.Value {
-	return input.Mul64(12).Div64(10)
-}
-
-// SstoreSentryGasEIP2200 is a requirement to execute SSTORE opcode.
-// But actually we can spend less amount of gas.
-// Let's try to specify reasonable upper bound for fee estimation.
-const SstoreSentryGas = types.Gas(params.SstoreSentryGasEIP2200)
+// SimulateCalls implements eth_simulateCalls.
+// Executes the calls one after another on top of the changes made by the previous ones.
+func (api *APIImplRo) SimulateCalls(
+	ctx context.Context,
+	calls []CallArgs,
+	mainBlockNrOrHash transport.BlockNumberOrHash,
+	overrides *StateOverrides,
+) (*SimulateCallsRes, error) {
+	// All the calls must be executed on top of the same blocks, even if a new block is generated meanwhile
+	mainBlockData, err := api.rawapi.GetFullBlockData(ctx, types.MainShardId, toBlockReference(mainBlockNrOrHash))
+	if err != nil {
+		return nil, err
+	}
+	mainBlock, err := mainBlockData.DecodeSSZ()
+	if err != nil {
+		return nil, err
+	}
+	blockRef := rawapitypes.BlockHashWithChildrenAsBlockReferenceOrHashWithChildren(
+		mainBlock.Hash(types.MainShardId), mainBlockData.ChildBlocks)
 
-func refineOutTxnResult(txns []*rpctypes.OutTransaction) types.Value {
-	result := types.NewZeroValue()
-	if len(txns) == 0 {
-		return result
+	stateOverrides := make(StateOverrides)
+	if overrides != nil {
+		stateOverrides.Merge(*overrides)
 	}
 
-	for _, txn := range txns {
-		result = result.
-			Add(txn.CoinsUsed).
-			Add(SstoreSentryGas.ToValue(txn.BaseFee)).
-			Add(refineOutTxnResult(txn.OutTransactions))
+	result := &SimulateCallsRes{Calls: make([]*CallRes, len(calls))}
+	for i, args := range calls {
+		if args.Fee.FeeCredit.IsZero() {
+			args.Fee = types.NewFeePackFromGas(1_000_000_000_000_000_000)
+		}
+		res, err := api.rawapi.SimulateCall(ctx, args, blockRef, &stateOverrides)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		// The state overrides of the call include the given ones
+		if res.StateOverrides != nil {
+			stateOverrides = res.StateOverrides
+		}
+
+		if result.Calls[i], err = toCallRes(res); err != nil {
+			return nil, err
+		}
+		// The state is returned once for all the calls
+		result.Calls[i].StateOverrides = nil
 	}
-	return result
+	result.StateOverrides = stateOverrides
+	return result, nil
 }
 
 // Call implements eth_estimateGas.
@@ -69,53 +91,72 @@ func (api *APIImplRo) EstimateFee(
 	feeCreditCap, err := types.NewValueFromDecimal("500000000000000000000000") // 0.5 MEther
 	check.PanicIfErr(err)
 
-	blockRef := rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(toBlockReference(mainBlockNrOrHash))
-	execute := func(balance, feeCredit types.Value) (*rpctypes.CallResWithGasPrice, error) {
-		args.Fee = types.NewFeePackFromFeeCredit(feeCredit)
-
-		stateOverrides := &StateOverrides{
-			args.To: Contract{
-				Balance: &balance,
-			},
-		}
-
-		// Root transaction considered here as external since we anyway override contract balance.
-		res, err := api.rawapi.Call(ctx, args, blockRef, stateOverrides)
-		if err != nil {
-			return nil, fmt.Errorf("failed to estimate call fee: %s", err.Error())
-		}
-
-		if res.Error != "" {
-			return nil, errors.New(res.Error)
-		}
-		return res, nil
+	args.Fee = types.NewFeePackFromFeeCredit(feeCreditCap)
+	stateOverrides := &StateOverrides{
+		args.To: Contract{
+			Balance: &balanceCap,
+		},
 	}
 
-	// Check that it's possible to run transaction with Max balance and feeCredit
-	res, err := execute(balanceCap, feeCreditCap)
+	// Root transaction considered here as external since we anyway override contract balance.
+	blockRef := rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(toBlockReference(mainBlockNrOrHash))
+	res, err := api.rawapi.EstimateFee(ctx, args, blockRef, stateOverrides)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to estimate call fee: %w", err)
+	}
+	if res.Error != "" {
+		return nil, errors.New(res.Error)
 	}
 
-	result := res.CoinsUsed.
-		Add(args.Value).
-		Add(SstoreSentryGas.ToValue(res.BaseFee)).
-		Add(refineOutTxnResult(res.OutTransactions))
-
-	if !args.Flags.GetBit(types.TransactionFlagInternal) {
-		// Heuristic price for external transaction verification for the smart account.
-		const externalVerificationGas = types.Gas(10_000)
-		result = result.Add(externalVerificationGas.ToValue(res.BaseFee))
+	hops := []*FeeEstimationHop{{
+		ShardId:   args.To.ShardId(),
+		To:        args.To,
+		BaseFee:   res.BaseFee,
+		FeeCredit: res.MinFeeCredit,
+	}}
+	if hops, err = appendFeeEstimationHops(hops, res.OutTransactions); err != nil {
+		return nil, err
 	}
-	maxBaseFee := res.BaseFee
-	for _, txn := range res.OutTransactions {
-		if txn.BaseFee.Cmp(maxBaseFee) > 0 {
-			maxBaseFee = txn.BaseFee
+
+	result := types.NewZeroValue()
+	maxBaseFee := types.NewZeroValue()
+	for _, hop := range hops {
+		result = result.Add(hop.FeeCredit)
+		if hop.BaseFee.Cmp(maxBaseFee) > 0 {
+			maxBaseFee = hop.BaseFee
 		}
 	}
+
+	// The value is paid from the same balance as the fee, so the estimation covers it as well
+	result = result.Add(args.Value)
 	return &EstimateFeeRes{
-		FeeCredit:          refineResult(result),
+		FeeCredit:          result,
 		AveragePriorityFee: types.Value0,
 		MaxBasFee:          maxBaseFee,
+		Hops:               hops,
 	}, nil
 }
+
+// appendFeeEstimationHops appends the outbound transactions to the hops in the order of their execution.
+func appendFeeEstimationHops(
+	hops []*FeeEstimationHop, txns []*rpctypes.OutTransaction,
+) ([]*FeeEstimationHop, error) {
+	for _, txn := range txns {
+		var decoded types.Transaction
+		if err := decoded.UnmarshalSSZ(txn.TransactionSSZ); err != nil {
+			return nil, err
+		}
+		hops = append(hops, &FeeEstimationHop{
+			ShardId:   decoded.To.ShardId(),
+			To:        decoded.To,
+			BaseFee:   txn.BaseFee,
+			FeeCredit: txn.MinFeeCredit,
+		})
+
+		var err error
+		if hops, err = appendFeeEstimationHops(hops, txn.OutTransactions); err != nil {
+			return nil, err
+		}
+	}
+	return hops, nil
+}
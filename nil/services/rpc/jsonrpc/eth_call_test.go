@@ -24,6 +24,10 @@ type SuiteEthCall struct {
 	contracts     map[string]*compiler.Contract
 	from          types.Address
 	simple        types.Address
+
+	asyncContracts map[string]*compiler.Contract
+	asyncCaller    types.Address
+	asyncCallee    types.Address
 }
 
 var latestBlockId = transport.BlockNumberOrHash{BlockNumber: transport.LatestBlock.BlockNumber}
@@ -50,6 +54,9 @@ func (s *SuiteEthCall) SetupSuite() {
 	s.contracts, err = solc.CompileSource("../../../internal/execution/testdata/call.sol")
 	s.Require().NoError(err)
 
+	s.asyncContracts, err = solc.CompileSource(common.GetAbsolutePath("../../../tests/contracts/async_call.sol"))
+	s.Require().NoError(err)
+
 	mainBlock := execution.GenerateZeroState(s.T(), types.MainShardId, s.db)
 
 	m1 := execution.NewDeployTransaction(
@@ -70,8 +77,31 @@ func (s *SuiteEthCall) SetupSuite() {
 
 	s.simple = m2.To
 
+	m3 := execution.NewDeployTransaction(
+		types.BuildDeployPayload(hexutil.FromHex(s.asyncContracts["Caller"].Code), common.EmptyHash),
+		shardId,
+		types.GenerateRandomAddress(shardId),
+		0,
+		types.GasToValue(100_000_000))
+
+	s.asyncCaller = m3.To
+
+	// The callee is on the other shard, so the calls to it are cross-shard ones
+	calleeShardId := shardId + 1
+	m4 := execution.NewDeployTransaction(
+		types.BuildDeployPayload(hexutil.FromHex(s.asyncContracts["Callee"].Code), common.EmptyHash),
+		calleeShardId,
+		types.GenerateRandomAddress(calleeShardId),
+		0,
+		types.Value{})
+
+	s.asyncCallee = m4.To
+
 	s.lastBlockHash = execution.GenerateBlockFromTransactions(
-		s.T(), ctx, shardId, 0, s.lastBlockHash, s.db, nil, m1, m2)
+		s.T(), ctx, shardId, 0, s.lastBlockHash, s.db, nil, m1, m2, m3)
+
+	calleeBlockHash := execution.GenerateBlockFromTransactions(
+		s.T(), ctx, calleeShardId, 0, common.EmptyHash, s.db, nil, m4)
 
 	execution.GenerateBlockFromTransactions(
 		s.T(),
@@ -80,9 +110,9 @@ func (s *SuiteEthCall) SetupSuite() {
 		0,
 		mainBlock.Hash(types.MainShardId),
 		s.db,
-		map[types.ShardId]common.Hash{shardId: s.lastBlockHash})
+		map[types.ShardId]common.Hash{shardId: s.lastBlockHash, calleeShardId: calleeBlockHash})
 
-	s.api = NewTestEthAPI(s.T(), ctx, s.db, 2)
+	s.api = NewTestEthAPI(s.T(), ctx, s.db, 3)
 }
 
 func (s *SuiteEthCall) TearDownSuite() {
@@ -166,6 +196,165 @@ func (s *SuiteEthCall) TestChainCall() {
 	s.EqualValues(0x7b, s.unpackGetValue(res.Data))
 }
 
+func (s *SuiteEthCall) TestSimulateCalls() {
+	ctx := s.T().Context()
+
+	abi := solc.ExtractABI(s.contracts["SimpleContract"])
+	getCalldata, err := abi.Pack("getValue")
+	s.Require().NoError(err)
+	setCalldata, err := abi.Pack("setValue", big.NewInt(123))
+	s.Require().NoError(err)
+	revertCalldata, err := solc.ExtractABI(s.contracts["Caller"]).Pack("callSetAndRevert", s.simple, big.NewInt(7))
+	s.Require().NoError(err)
+
+	getData := hexutil.Bytes(getCalldata)
+	setData := hexutil.Bytes(setCalldata)
+	revertData := hexutil.Bytes(revertCalldata)
+	fee := types.NewFeePackFromGas(100_000)
+	calls := []CallArgs{
+		{Data: &getData, To: s.simple, Fee: fee},
+		{Data: &setData, To: s.simple, Fee: fee},
+		{Data: &revertData, To: s.from, Fee: fee},
+		{Data: &getData, To: s.simple, Fee: fee},
+	}
+
+	res, err := s.api.SimulateCalls(ctx, calls, latestBlockId, nil)
+	s.Require().NoError(err)
+	s.Require().Len(res.Calls, len(calls))
+	s.EqualValues(0x2a, s.unpackGetValue(res.Calls[0].Data))
+	s.Empty(res.Calls[1].Error)
+	s.NotEmpty(res.Calls[2].Error)
+	s.EqualValues(0x7b, s.unpackGetValue(res.Calls[3].Data))
+	s.Require().Contains(res.StateOverrides, s.simple)
+	s.Nil(res.Calls[1].StateOverrides)
+
+	// The state of the simulation can be continued
+	res, err = s.api.SimulateCalls(ctx, calls[3:], latestBlockId, &res.StateOverrides)
+	s.Require().NoError(err)
+	s.EqualValues(0x7b, s.unpackGetValue(res.Calls[0].Data))
+
+	// Nothing is committed
+	callRes, err := s.api.Call(ctx, calls[0], latestBlockId, nil)
+	s.Require().NoError(err)
+	s.EqualValues(0x2a, s.unpackGetValue(callRes.Data))
+}
+
+func (s *SuiteEthCall) TestSimulateCrossShardCalls() {
+	ctx := s.T().Context()
+
+	callerAbi := solc.ExtractABI(s.asyncContracts["Caller"])
+	calleeAbi := solc.ExtractABI(s.asyncContracts["Callee"])
+	addCalldata, err := callerAbi.Pack("call", s.asyncCallee, int32(11))
+	s.Require().NoError(err)
+	failCalldata, err := callerAbi.Pack("call", s.asyncCallee, int32(0))
+	s.Require().NoError(err)
+	bounceErrCalldata, err := callerAbi.Pack("get_bounce_err")
+	s.Require().NoError(err)
+
+	addData := hexutil.Bytes(addCalldata)
+	failData := hexutil.Bytes(failCalldata)
+	bounceErrData := hexutil.Bytes(bounceErrCalldata)
+	fee := types.NewFeePackFromGas(1_000_000)
+	calls := []CallArgs{
+		{Data: &addData, To: s.asyncCaller, Fee: fee},
+		{Data: &addData, To: s.asyncCaller, Fee: fee},
+		{Data: &failData, To: s.asyncCaller, Fee: fee},
+		{Data: &bounceErrData, To: s.asyncCaller, Fee: fee},
+	}
+
+	res, err := s.api.SimulateCalls(ctx, calls, latestBlockId, nil)
+	s.Require().NoError(err)
+	s.Require().Len(res.Calls, len(calls))
+
+	unpackAdd := func(data []byte) int32 {
+		s.T().Helper()
+
+		out, err := calleeAbi.Unpack("add", data)
+		s.Require().NoError(err)
+		v, ok := out[0].(int32)
+		s.Require().True(ok)
+		return v
+	}
+
+	// The cross-shard transactions are executed on top of the changes made by the previous calls
+	for i, expected := range []int32{11, 22} {
+		call := res.Calls[i]
+		s.Require().Empty(call.Error)
+		s.Require().Len(call.OutTransactions, 1)
+		outTxn := call.OutTransactions[0]
+		s.Equal(s.asyncCallee, outTxn.Transaction.To)
+		s.Empty(outTxn.Error)
+		s.Equal(expected, unpackAdd(outTxn.Data))
+	}
+
+	// The failed cross-shard transaction is bounced back to the caller
+	failed := res.Calls[2]
+	s.Require().Empty(failed.Error)
+	s.Require().Len(failed.OutTransactions, 1)
+	s.Require().NotEmpty(failed.OutTransactions[0].Error)
+	var bounce *OutTransaction
+	for _, outTxn := range failed.OutTransactions[0].OutTransactions {
+		if outTxn.Transaction.IsBounce() {
+			bounce = outTxn
+		}
+	}
+	s.Require().NotNil(bounce)
+	s.Equal(s.asyncCaller, bounce.Transaction.To)
+	s.Empty(bounce.Error)
+
+	// The bounce is handled by the caller
+	s.Require().Empty(res.Calls[3].Error)
+	out, err := callerAbi.Unpack("get_bounce_err", res.Calls[3].Data)
+	s.Require().NoError(err)
+	s.Require().Len(out, 1)
+	s.Contains(out[0], "Value must be non-zero")
+	s.Require().Contains(res.StateOverrides, s.asyncCaller)
+	s.Require().Contains(res.StateOverrides, s.asyncCallee)
+}
+
+func (s *SuiteEthCall) TestEstimateFee() {
+	ctx := s.T().Context()
+
+	setCalldata, err := solc.ExtractABI(s.contracts["SimpleContract"]).Pack("setValue", big.NewInt(123))
+	s.Require().NoError(err)
+	setData := hexutil.Bytes(setCalldata)
+	args := CallArgs{
+		Flags: types.NewTransactionFlags(types.TransactionFlagInternal),
+		From:  &s.from,
+		Data:  &setData,
+		To:    s.simple,
+	}
+
+	res, err := s.api.EstimateFee(ctx, args, latestBlockId)
+	s.Require().NoError(err)
+	s.Require().Len(res.Hops, 1)
+	hop := res.Hops[0]
+	s.Equal(s.simple, hop.To)
+	s.Equal(types.BaseShardId, hop.ShardId)
+	s.Equal(res.FeeCredit, hop.FeeCredit)
+	s.Equal(res.MaxBasFee, hop.BaseFee)
+
+	// The estimated fee credit is enough
+	args.Fee = types.NewFeePackFromFeeCredit(hop.FeeCredit)
+	callRes, err := s.api.Call(ctx, args, latestBlockId, nil)
+	s.Require().NoError(err)
+	s.Empty(callRes.Error)
+
+	// And it's the minimal one
+	args.Fee = types.NewFeePackFromFeeCredit(hop.FeeCredit.Sub(hop.BaseFee))
+	callRes, err = s.api.Call(ctx, args, latestBlockId, nil)
+	s.Require().NoError(err)
+	s.NotEmpty(callRes.Error)
+
+	// The transferred value is paid along with the fee
+	args.Data = nil
+	args.Value = types.NewValueFromUint64(1_000)
+	res, err = s.api.EstimateFee(ctx, args, latestBlockId)
+	s.Require().NoError(err)
+	s.Require().Len(res.Hops, 1)
+	s.Equal(res.Hops[0].FeeCredit.Add(args.Value), res.FeeCredit)
+}
+
 func TestSuiteEthCall(t *testing.T) {
 	t.Parallel()
 
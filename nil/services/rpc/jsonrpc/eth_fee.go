@@ -0,0 +1,211 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+)
+
+const (
+	// maxFeeHistoryBlocks limits the number of blocks processed by a single eth_feeHistory request.
+	maxFeeHistoryBlocks = 1024
+	// priorityFeeBlocks is the number of the recent blocks the suggested priority fee is computed from.
+	priorityFeeBlocks = 20
+	// priorityFeePercentile is the percentile of the recent effective tips suggested as the priority fee.
+	priorityFeePercentile = 60
+)
+
+var errInvalidRewardPercentile = errors.New("invalid reward percentile")
+
+// FeeHistory implements eth_feeHistory.
+// Returns base fees, gas usage and effective tips percentiles of the recent blocks of the shard.
+func (api *APIImplRo) FeeHistory(
+	ctx context.Context,
+	shardId types.ShardId,
+	blockCount hexutil.Uint64,
+	newestBlock transport.BlockNumber,
+	rewardPercentiles []float64,
+) (*FeeHistoryRes, error) {
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 || (i > 0 && p < rewardPercentiles[i-1]) {
+			return nil, fmt.Errorf("%w: %f", errInvalidRewardPercentile, p)
+		}
+	}
+
+	blocks, err := api.getRecentBlocks(ctx, shardId, min(uint64(blockCount), maxFeeHistoryBlocks), newestBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FeeHistoryRes{
+		BaseFeePerGas: make([]types.Value, 0, len(blocks)+1),
+		GasUsedRatio:  make([]float64, 0, len(blocks)),
+	}
+	if len(blocks) == 0 {
+		return result, nil
+	}
+	if len(rewardPercentiles) > 0 {
+		result.Reward = make([][]types.Value, 0, len(blocks))
+	}
+
+	gasLimits := make(map[common.Hash]types.Gas)
+	result.OldestBlock = blocks[0].Id
+	for _, block := range blocks {
+		gasLimit, err := api.getBlockGasLimit(ctx, shardId, block, gasLimits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get gas limit of block %d: %w", block.Id, err)
+		}
+
+		result.BaseFeePerGas = append(result.BaseFeePerGas, block.BaseFee)
+		result.GasUsedRatio = append(result.GasUsedRatio, float64(block.GasUsed.Uint64())/float64(gasLimit.Uint64()))
+		if len(rewardPercentiles) > 0 {
+			result.Reward = append(result.Reward, tipsPercentiles(blockTips(block), rewardPercentiles))
+		}
+	}
+
+	// The base fee of the block following the newest one
+	result.BaseFeePerGas = append(result.BaseFeePerGas, api.feeCalculator.CalculateBaseFee(blocks[len(blocks)-1].Block))
+	return result, nil
+}
+
+// MaxPriorityFeePerGas implements eth_maxPriorityFeePerGas.
+// Returns the priority fee suggested for the transactions of the shard based on the recent blocks.
+func (api *APIImplRo) MaxPriorityFeePerGas(ctx context.Context, shardId types.ShardId) (types.Value, error) {
+	blocks, err := api.getRecentBlocks(ctx, shardId, priorityFeeBlocks, transport.LatestBlockNumber)
+	if err != nil {
+		return types.Value0, err
+	}
+
+	var tips []feeTip
+	for _, block := range blocks {
+		tips = append(tips, blockTips(block)...)
+	}
+	return tipsPercentiles(tips, []float64{priorityFeePercentile})[0], nil
+}
+
+// getRecentBlocks returns up to count blocks of the shard ending with the newest one, the oldest block goes first.
+func (api *APIImplRo) getRecentBlocks(
+	ctx context.Context, shardId types.ShardId, count uint64, newestBlock transport.BlockNumber,
+) ([]*types.BlockWithExtractedData, error) {
+	if count == 0 {
+		return nil, nil
+	}
+
+	raw, err := api.rawapi.GetFullBlockData(ctx, shardId, blockNrToBlockReference(newestBlock))
+	if err != nil {
+		return nil, err
+	}
+	newest, err := raw.DecodeSSZ()
+	if err != nil {
+		return nil, err
+	}
+
+	count = min(count, uint64(newest.Id)+1)
+	blocks := make([]*types.BlockWithExtractedData, count)
+	blocks[count-1] = newest
+	for i := range count - 1 {
+		number := newest.Id - types.BlockNumber(i+1)
+		raw, err := api.rawapi.GetFullBlockData(ctx, shardId, blockNrToBlockReference(transport.BlockNumber(number)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block %d: %w", number, err)
+		}
+		if blocks[count-2-i], err = raw.DecodeSSZ(); err != nil {
+			return nil, err
+		}
+	}
+	return blocks, nil
+}
+
+// getBlockGasLimit returns the gas limit from the config params the block was generated with.
+// The config is read from the main shard block the block refers to, the limits are cached by its hash.
+func (api *APIImplRo) getBlockGasLimit(
+	ctx context.Context,
+	shardId types.ShardId,
+	block *types.BlockWithExtractedData,
+	cache map[common.Hash]types.Gas,
+) (types.Gas, error) {
+	mainShardHash := block.GetMainShardHash(shardId)
+	if gasLimit, ok := cache[mainShardHash]; ok {
+		return gasLimit, nil
+	}
+
+	// The genesis blocks do not refer to the main shard, the main shard one holds the config itself
+	configData := common.TransformMap(block.Config, func(k string, v hexutil.Bytes) (string, []byte) {
+		return k, v
+	})
+	if !mainShardHash.Empty() {
+		raw, err := api.rawapi.GetFullBlockData(
+			ctx, types.MainShardId, rawapitypes.BlockHashAsBlockReference(mainShardHash))
+		if err != nil {
+			return 0, err
+		}
+		configData = raw.Config
+	}
+
+	gasLimit, err := config.GetBlockGasLimit(config.NewConfigAccessorFromMap(configData))
+	if err != nil {
+		return 0, err
+	}
+	cache[mainShardHash] = gasLimit
+	return gasLimit, nil
+}
+
+type feeTip struct {
+	tip     types.Value
+	gasUsed types.Gas
+}
+
+// blockTips returns the effective tips of the block transactions.
+func blockTips(block *types.BlockWithExtractedData) []feeTip {
+	tips := make([]feeTip, 0, len(block.InTransactions))
+	for i, txn := range block.InTransactions {
+		tip, ok := execution.GetEffectivePriorityFee(block.BaseFee, txn)
+		if !ok || i >= len(block.Receipts) {
+			continue
+		}
+		tips = append(tips, feeTip{tip: tip, gasUsed: block.Receipts[i].GasUsed})
+	}
+	return tips
+}
+
+// tipsPercentiles returns the tips at the given percentiles of the gas used by the transactions.
+// The percentiles must be sorted in the ascending order, the result is zero if there are no tips.
+func tipsPercentiles(tips []feeTip, percentiles []float64) []types.Value {
+	slices.SortStableFunc(tips, func(a, b feeTip) int {
+		return a.tip.Cmp(b.tip)
+	})
+
+	result := make([]types.Value, len(percentiles))
+	if len(tips) == 0 {
+		for i := range result {
+			result[i] = types.Value0
+		}
+		return result
+	}
+
+	var totalGas types.Gas
+	for _, tip := range tips {
+		totalGas += tip.gasUsed
+	}
+
+	var index int
+	sumGas := tips[0].gasUsed
+	for i, p := range percentiles {
+		threshold := types.Gas(float64(totalGas) * p / 100)
+		for sumGas < threshold && index < len(tips)-1 {
+			index++
+			sumGas += tips[index].gasUsed
+		}
+		result[i] = tips[index].tip
+	}
+	return result
+}
@@ -0,0 +1,172 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SuiteEthFee struct {
+	suite.Suite
+	db  db.DB
+	api *APIImpl
+}
+
+func (s *SuiteEthFee) SetupSuite() {
+	ctx := s.T().Context()
+
+	var err error
+	s.db, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+
+	execution.GenerateZeroState(s.T(), types.MainShardId, s.db)
+	zeroState := execution.GenerateZeroState(s.T(), types.BaseShardId, s.db)
+	execution.GenerateBlockFromTransactions(
+		s.T(), ctx, types.BaseShardId, 1, zeroState.Hash(types.BaseShardId), s.db, nil)
+
+	s.api = NewTestEthAPI(s.T(), ctx, s.db, 2)
+}
+
+func (s *SuiteEthFee) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *SuiteEthFee) TestFeeHistory() {
+	ctx := s.T().Context()
+
+	res, err := s.api.FeeHistory(ctx, types.BaseShardId, 10, transport.LatestBlockNumber, []float64{10, 50})
+	s.Require().NoError(err)
+	s.EqualValues(0, res.OldestBlock)
+	s.Len(res.BaseFeePerGas, 3)
+	s.Len(res.GasUsedRatio, 2)
+	s.Require().Len(res.Reward, 2)
+	s.Equal([]types.Value{types.Value0, types.Value0}, res.Reward[1])
+	for _, baseFee := range res.BaseFeePerGas {
+		s.False(baseFee.IsZero())
+	}
+
+	res, err = s.api.FeeHistory(ctx, types.BaseShardId, 1, transport.LatestBlockNumber, nil)
+	s.Require().NoError(err)
+	s.EqualValues(1, res.OldestBlock)
+	s.Len(res.BaseFeePerGas, 2)
+	s.Nil(res.Reward)
+
+	res, err = s.api.FeeHistory(ctx, types.BaseShardId, 0, transport.LatestBlockNumber, nil)
+	s.Require().NoError(err)
+	s.Empty(res.BaseFeePerGas)
+
+	_, err = s.api.FeeHistory(ctx, types.BaseShardId, 1, transport.LatestBlockNumber, []float64{50, 10})
+	s.Require().ErrorIs(err, errInvalidRewardPercentile)
+	// The base fee of the next block is predicted by the configured calculator
+	api := *s.api.APIImplRo
+	nextBaseFee := types.NewValueFromUint64(12345)
+	api.feeCalculator = &execution.ConstFeeCalculator{Value: nextBaseFee}
+	res, err = api.FeeHistory(ctx, types.BaseShardId, 1, transport.LatestBlockNumber, nil)
+	s.Require().NoError(err)
+	s.Require().Len(res.BaseFeePerGas, 2)
+	s.Equal(nextBaseFee, res.BaseFeePerGas[1])
+}
+
+func (s *SuiteEthFee) TestMaxPriorityFeePerGas() {
+	fee, err := s.api.MaxPriorityFeePerGas(s.T().Context(), types.BaseShardId)
+	s.Require().NoError(err)
+	s.True(fee.IsZero())
+}
+
+func TestFeeHistoryBlockGasLimit(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	generateBlock := func(
+		shardId types.ShardId, blockId types.BlockNumber, prevBlock *types.Block, mainShardHash common.Hash,
+		gasLimit uint64, gasUsed types.Gas,
+	) *types.Block {
+		t.Helper()
+
+		tx, err := database.CreateRwTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		configAccessor := config.GetStubAccessor()
+		if shardId.IsMainShard() {
+			configAccessor, err = config.NewConfigAccessorFromBlockWithTx(tx, prevBlock, shardId)
+			require.NoError(t, err)
+			require.NoError(t, config.SetParamBlockGasLimit(configAccessor, &config.ParamBlockGasLimit{
+				GasLimit: gasLimit,
+			}))
+		}
+
+		es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+			Block:          prevBlock,
+			ConfigAccessor: configAccessor,
+		})
+		require.NoError(t, err)
+		es.BaseFee = types.DefaultGasPrice
+		es.GasUsed = gasUsed
+		es.MainShardHash = mainShardHash
+
+		blockRes, err := es.Commit(blockId, nil)
+		require.NoError(t, err)
+		require.NoError(t, execution.PostprocessBlock(tx, shardId, blockRes, execution.ModeVerify))
+		require.NoError(t, db.WriteBlockTimestamp(tx, shardId, blockRes.BlockHash, 0))
+		require.NoError(t, tx.Commit())
+		return blockRes.Block
+	}
+
+	// The main shard block uses the config of the previous one, so the limit change applies to the next block
+	mainBlock := generateBlock(types.MainShardId, 0, nil, common.EmptyHash, 1000, 0)
+	mainBlock = generateBlock(types.MainShardId, 1, mainBlock, common.EmptyHash, 2000, 500)
+	mainBlockHash := mainBlock.Hash(types.MainShardId)
+
+	block := generateBlock(types.BaseShardId, 0, nil, common.EmptyHash, 0, 0)
+	generateBlock(types.BaseShardId, 1, block, mainBlockHash, 0, 500)
+
+	api := NewTestEthAPI(t, ctx, database, 2)
+
+	res, err := api.FeeHistory(ctx, types.MainShardId, 2, transport.LatestBlockNumber, nil)
+	require.NoError(t, err)
+	require.Equal(t, []float64{0, 0.5}, res.GasUsedRatio)
+
+	res, err = api.FeeHistory(ctx, types.BaseShardId, 2, transport.LatestBlockNumber, nil)
+	require.NoError(t, err)
+	require.Equal(t, []float64{0, 0.25}, res.GasUsedRatio)
+}
+
+func TestTipsPercentiles(t *testing.T) {
+	t.Parallel()
+
+	tips := []feeTip{
+		{tip: types.NewValueFromUint64(30), gasUsed: 100},
+		{tip: types.NewValueFromUint64(10), gasUsed: 300},
+		{tip: types.NewValueFromUint64(20), gasUsed: 600},
+	}
+	res := tipsPercentiles(tips, []float64{0, 30, 31, 90, 91, 100})
+	require.Equal(t, []types.Value{
+		types.NewValueFromUint64(10),
+		types.NewValueFromUint64(10),
+		types.NewValueFromUint64(20),
+		types.NewValueFromUint64(20),
+		types.NewValueFromUint64(30),
+		types.NewValueFromUint64(30),
+	}, res)
+
+	require.Equal(t, []types.Value{types.Value0}, tipsPercentiles(nil, []float64{50}))
+}
+
+func TestSuiteEthFee(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(SuiteEthFee))
+}
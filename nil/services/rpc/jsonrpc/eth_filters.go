@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/NilFoundation/nil/nil/common/concurrent"
@@ -77,6 +78,34 @@ func (l *LogsAggregator) RemoveBlocksListener(id filters.SubscriptionID) error {
 	return errors.New("cannot remove blocks listener")
 }
 
+// Rewind drops the logs and blocks of the rolled back blocks which weren't polled yet,
+// and continues processing from the current last block.
+func (l *LogsAggregator) Rewind() error {
+	lastId, err := l.filters.Rewind()
+	if err != nil {
+		return err
+	}
+	rewind(l.logsMap, func(log *filters.MetaLog) bool {
+		return log.BlockId > lastId
+	})
+	rewind(l.blocksMap, func(block *types.Block) bool {
+		return block.Id > lastId
+	})
+	return nil
+}
+
+func rewind[T any](m *concurrent.Map[filters.SubscriptionID, []T], reverted func(T) bool) {
+	var ids []filters.SubscriptionID
+	for id := range m.Iterate() {
+		ids = append(ids, id)
+	}
+	for _, id := range ids {
+		m.Do(id, func(items []T, ok bool) ([]T, bool) {
+			return slices.DeleteFunc(items, reverted), ok
+		})
+	}
+}
+
 func (l *LogsAggregator) GetLogs(id filters.SubscriptionID) ([]*filters.MetaLog, bool) {
 	return l.logsMap.Delete(id)
 }
@@ -3,6 +3,7 @@ package jsonrpc
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
@@ -126,3 +127,51 @@ func (api *APIImplRo) GetRawInTransactionByHash(ctx context.Context, hash common
 	}
 	return res.TransactionSSZ, nil
 }
+
+const (
+	defaultAddressTransactionsLimit = 100
+	maxAddressTransactionsLimit     = 1000
+)
+
+// GetTransactionsByAddress implements eth_getTransactionsByAddress.
+// Returns transactions sent or received by the address page by page.
+func (api *APIImplRo) GetTransactionsByAddress(
+	ctx context.Context,
+	address types.Address,
+	fromBlock transport.BlockNumber,
+	toBlock transport.BlockNumber,
+	limit hexutil.Uint64,
+	cursor *hexutil.Bytes,
+) (*RPCAddressTransactions, error) {
+	if fromBlock < transport.EarliestBlockNumber {
+		return nil, fmt.Errorf("fromBlock must be a block number, got %s", fromBlock)
+	}
+	request := rawapitypes.AddressTransactionsRequest{
+		Address:   address,
+		FromBlock: types.BlockNumber(fromBlock),
+		ToBlock:   types.BlockNumber(toBlock),
+		Limit:     uint64(limit),
+	}
+	// Named blocks are not resolved, the range is just left open
+	if toBlock < transport.EarliestBlockNumber {
+		request.ToBlock = math.MaxUint64
+	}
+	if request.FromBlock > request.ToBlock {
+		return nil, fmt.Errorf("fromBlock %d is greater than toBlock %d", request.FromBlock, request.ToBlock)
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultAddressTransactionsLimit
+	}
+	if request.Limit > maxAddressTransactionsLimit {
+		return nil, fmt.Errorf("limit %d exceeds the maximum of %d", request.Limit, maxAddressTransactionsLimit)
+	}
+	if cursor != nil {
+		request.Cursor = *cursor
+	}
+
+	res, err := api.rawapi.GetTransactionsByAddress(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return NewRPCAddressTransactions(res), nil
+}
@@ -5,9 +5,13 @@ import (
 	"testing"
 
 	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -124,3 +128,106 @@ func TestSuiteEthTransaction(t *testing.T) {
 
 	suite.Run(t, new(SuiteEthTransaction))
 }
+
+func TestGetTransactionsByAddress(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	api := NewTestEthAPI(t, ctx, database, 2)
+
+	address := types.GenerateRandomAddress(types.BaseShardId)
+	newTxn := func(to types.Address, data string) *types.Transaction {
+		txn := types.NewEmptyTransaction()
+		txn.To = to
+		txn.Data = []byte(data)
+		return txn
+	}
+	inTxn1 := newTxn(address, "in1")
+	inTxn2 := newTxn(address, "in2")
+	outTxn2 := newTxn(types.GenerateRandomAddress(types.MainShardId), "out2")
+	outTxn2.From = address
+	otherTxn3 := newTxn(types.GenerateRandomAddress(types.BaseShardId), "other3")
+	inTxn3 := newTxn(address, "in3")
+
+	blocks := []struct {
+		inTxns, outTxns []*types.Transaction
+	}{
+		{},
+		{inTxns: []*types.Transaction{inTxn1}},
+		{inTxns: []*types.Transaction{inTxn2}, outTxns: []*types.Transaction{outTxn2}},
+		{inTxns: []*types.Transaction{otherTxn3, inTxn3}},
+	}
+	for i, block := range blocks {
+		tx, err := database.CreateRwTx(ctx)
+		require.NoError(t, err)
+
+		receipts := make([]*types.Receipt, len(block.inTxns))
+		for j, txn := range block.inTxns {
+			receipts[j] = &types.Receipt{TxnHash: txn.Hash()}
+		}
+		blockRes := writeTestBlock(
+			t, tx, types.BaseShardId, types.BlockNumber(i), block.inTxns, receipts, block.outTxns)
+
+		// The first blocks are produced before the index is enabled and get indexed by the backfill,
+		// which skips the genesis block
+		if i <= 1 {
+			require.NoError(t, execution.PostprocessBlock(tx, types.BaseShardId, blockRes, execution.ModeVerify))
+		} else {
+			require.NoError(t, execution.PostprocessBlockWithAddressIndex(
+				tx, types.BaseShardId, blockRes, execution.ModeVerify))
+		}
+		require.NoError(t, tx.Commit())
+
+		if i == 1 {
+			_, err = api.GetTransactionsByAddress(ctx, address, 0, transport.LatestBlockNumber, 0, nil)
+			require.ErrorContains(t, err, "address transaction index is not enabled")
+
+			require.NoError(t, execution.BackfillAddressTransactionIndex(
+				ctx, database, types.BaseShardId, logging.Nop()))
+		}
+	}
+
+	expected := func(txn *types.Transaction, blockId types.BlockNumber, outbound bool) *RPCAddressTransaction {
+		return &RPCAddressTransaction{Hash: txn.Hash(), BlockNumber: blockId, TxnIndex: 0, Outbound: outbound}
+	}
+	inTxn3Expected := expected(inTxn3, 3, false)
+	inTxn3Expected.TxnIndex = 1
+
+	t.Run("Paginate", func(t *testing.T) {
+		page, err := api.GetTransactionsByAddress(ctx, address, 0, transport.LatestBlockNumber, 2, nil)
+		require.NoError(t, err)
+		require.Equal(t, []*RPCAddressTransaction{expected(inTxn1, 1, false), expected(inTxn2, 2, false)},
+			page.Transactions)
+		require.NotEmpty(t, page.NextCursor)
+
+		page, err = api.GetTransactionsByAddress(ctx, address, 0, transport.LatestBlockNumber, 2, &page.NextCursor)
+		require.NoError(t, err)
+		require.Equal(t, []*RPCAddressTransaction{expected(outTxn2, 2, true), inTxn3Expected}, page.Transactions)
+		require.Empty(t, page.NextCursor)
+	})
+
+	t.Run("BlockRange", func(t *testing.T) {
+		page, err := api.GetTransactionsByAddress(ctx, address, 2, 2, 0, nil)
+		require.NoError(t, err)
+		require.Equal(t, []*RPCAddressTransaction{expected(inTxn2, 2, false), expected(outTxn2, 2, true)},
+			page.Transactions)
+		require.Empty(t, page.NextCursor)
+	})
+
+	t.Run("InvalidArguments", func(t *testing.T) {
+		_, err := api.GetTransactionsByAddress(ctx, address, 2, 1, 0, nil)
+		require.ErrorContains(t, err, "greater than toBlock")
+
+		_, err = api.GetTransactionsByAddress(ctx, address, 0, 2, maxAddressTransactionsLimit+1, nil)
+		require.ErrorContains(t, err, "exceeds the maximum")
+
+		cursor := hexutil.Bytes{0x01}
+		_, err = api.GetTransactionsByAddress(ctx, address, 0, 2, 0, &cursor)
+		require.ErrorContains(t, err, db.ErrInvalidAddressTransactionCursor.Error())
+	})
+}
@@ -0,0 +1,54 @@
+package jsonrpc
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/common/logging"
+)
+
+// ChainSnapshots saves the state of the chain and rolls it back.
+type ChainSnapshots interface {
+	Snapshot(ctx context.Context) (uint64, error)
+	Revert(ctx context.Context, id uint64) (bool, error)
+}
+
+type EvmAPI interface {
+	Snapshot(ctx context.Context) (hexutil.Uint64, error)
+	Revert(ctx context.Context, id hexutil.Uint64) (bool, error)
+}
+
+type EvmAPIImpl struct {
+	snapshots ChainSnapshots
+	logs      *LogsAggregator
+	logger    logging.Logger
+}
+
+// NewEvmAPI creates the API of development networks for saving and restoring the chain.
+// The filters of the eth API are rewound to the restored chain.
+func NewEvmAPI(snapshots ChainSnapshots, ethApi *APIImplRo, logger logging.Logger) EvmAPI {
+	return &EvmAPIImpl{
+		snapshots: snapshots,
+		logs:      ethApi.logs,
+		logger:    logger,
+	}
+}
+
+// Snapshot implements evm_snapshot. It saves the state of all shards and returns the id of the snapshot.
+func (api *EvmAPIImpl) Snapshot(ctx context.Context) (hexutil.Uint64, error) {
+	id, err := api.snapshots.Snapshot(ctx)
+	return hexutil.Uint64(id), err
+}
+
+// Revert implements evm_revert. It rolls all shards back to the snapshot with the given id.
+// The snapshot and the ones saved after it can't be used again. It returns false if there is no such snapshot.
+func (api *EvmAPIImpl) Revert(ctx context.Context, id hexutil.Uint64) (bool, error) {
+	reverted, err := api.snapshots.Revert(ctx, uint64(id))
+	if err != nil || !reverted {
+		return false, err
+	}
+	if err := api.logs.Rewind(); err != nil {
+		api.logger.Warn().Err(err).Msg("Failed to rewind filters")
+	}
+	return true, nil
+}
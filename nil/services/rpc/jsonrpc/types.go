@@ -1,8 +1,10 @@
 package jsonrpc
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
@@ -101,9 +103,10 @@ type DebugRPCBlock struct {
 }
 
 type ChainConfig struct {
-	Validators  *config.ParamValidators  `json:"validators"`
-	GasPrices   *config.ParamGasPrice    `json:"gasPrices"`
-	L1BlockInfo *config.ParamL1BlockInfo `json:"l1BlockInfo"`
+	Validators    *config.ParamValidators    `json:"validators"`
+	GasPrices     *config.ParamGasPrice      `json:"gasPrices"`
+	L1BlockInfo   *config.ParamL1BlockInfo   `json:"l1BlockInfo"`
+	BlockGasLimit *config.ParamBlockGasLimit `json:"blockGasLimit"`
 }
 
 func NewChainConfigFromMap(data map[string][]byte) (*ChainConfig, error) {
@@ -123,10 +126,15 @@ func NewChainConfigFromMap(data map[string][]byte) (*ChainConfig, error) {
 	if err != nil && !errors.Is(err, config.ErrParamNotFound) {
 		return nil, err
 	}
+	blockGasLimit, err := config.GetParamBlockGasLimit(configAccessor)
+	if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+		return nil, err
+	}
 	return &ChainConfig{
-		Validators:  validators,
-		GasPrices:   gasPrices,
-		L1BlockInfo: l1BlockInfo,
+		Validators:    validators,
+		GasPrices:     gasPrices,
+		L1BlockInfo:   l1BlockInfo,
+		BlockGasLimit: blockGasLimit,
 	}, nil
 }
 
@@ -153,6 +161,13 @@ func (c *ChainConfig) ToMap() (map[string][]byte, error) {
 		}
 		result[config.NameL1Block] = l1BlockInfo
 	}
+	if c.BlockGasLimit != nil {
+		blockGasLimit, err := c.BlockGasLimit.MarshalSSZ()
+		if err != nil {
+			return nil, err
+		}
+		result[config.NameBlockGasLimit] = blockGasLimit
+	}
 	return result, nil
 }
 
@@ -253,6 +268,37 @@ type RPCReceipt struct {
 	ErrorMessage    string                 `json:"errorMessage,omitempty"`
 }
 
+// @component RPCAddressTransactions rpcAddressTransactions object "The page of transactions sent or received by the address."
+// @componentprop Transactions transactions array true "The transactions in the order of their execution."
+// @componentprop NextCursor nextCursor string false "The cursor for requesting the next page, it is not set if there are no more transactions."
+type RPCAddressTransactions struct {
+	Transactions []*RPCAddressTransaction `json:"transactions"`
+	NextCursor   hexutil.Bytes            `json:"nextCursor,omitempty"`
+}
+
+type RPCAddressTransaction struct {
+	Hash        common.Hash            `json:"hash"`
+	BlockNumber types.BlockNumber      `json:"blockNumber"`
+	TxnIndex    types.TransactionIndex `json:"transactionIndex"`
+	Outbound    bool                   `json:"outbound"`
+}
+
+func NewRPCAddressTransactions(txns *rawapitypes.AddressTransactions) *RPCAddressTransactions {
+	res := &RPCAddressTransactions{
+		Transactions: make([]*RPCAddressTransaction, len(txns.Transactions)),
+		NextCursor:   txns.NextCursor,
+	}
+	for i, txn := range txns.Transactions {
+		res.Transactions[i] = &RPCAddressTransaction{
+			Hash:        txn.Hash,
+			BlockNumber: txn.BlockId,
+			TxnIndex:    txn.Index,
+			Outbound:    txn.Outbound,
+		}
+	}
+	return res
+}
+
 type RPCLog struct {
 	*types.Log
 	BlockNumber types.BlockNumber `json:"blockNumber"`
@@ -483,6 +529,102 @@ type DebugRPCContract struct {
 	AsyncContext map[types.TransactionIndex]types.AsyncContext `json:"asyncContext"`
 }
 
+// @component RPCPendingRequest rpcPendingRequest object "The request of the contract waiting for the response."
+// @componentprop RequestId requestId integer true "The identifier of the request."
+// @componentprop IsAwait isAwait boolean true "The flag that shows whether the contract awaits the response."
+// @componentprop ResponseProcessingGas responseProcessingGas integer true "The gas reserved to process the response."
+// @componentprop Deadline deadline integer false "The main shard height at which the request fails if it's set."
+type RPCPendingRequest struct {
+	RequestId             types.TransactionIndex `json:"requestId"`
+	IsAwait               bool                   `json:"isAwait"`
+	ResponseProcessingGas types.Gas              `json:"responseProcessingGas"`
+	Deadline              types.BlockNumber      `json:"deadline,omitempty"`
+}
+
+func NewRPCPendingRequests(asyncContext map[types.TransactionIndex]types.AsyncContext) []*RPCPendingRequest {
+	res := make([]*RPCPendingRequest, 0, len(asyncContext))
+	for id, ctx := range asyncContext {
+		res = append(res, &RPCPendingRequest{
+			RequestId:             id,
+			IsAwait:               ctx.IsAwait,
+			ResponseProcessingGas: ctx.ResponseProcessingGas,
+			Deadline:              ctx.Deadline,
+		})
+	}
+	slices.SortFunc(res, func(a, b *RPCPendingRequest) int {
+		return cmp.Compare(a.RequestId, b.RequestId)
+	})
+	return res
+}
+
+// @component RPCDiff rpcDiff object "The values of an account field before and after the changes."
+// @componentprop From from string true "The value before the changes."
+// @componentprop To to string true "The value after the changes."
+type RPCDiff[T any] struct {
+	From T `json:"from"`
+	To   T `json:"to"`
+}
+
+// @component RPCAccountDiff rpcAccountDiff object "The changes of an account. The unchanged fields are omitted."
+// @componentprop Address address string true "The address of the account."
+// @componentprop Balance balance rpcDiff false "The balance of the account."
+// @componentprop Seqno seqno rpcDiff false "The sequence number of the account."
+// @componentprop ExtSeqno extSeqno rpcDiff false "The external sequence number of the account."
+// @componentprop Code code rpcDiff false "The HEX-encoded code of the account."
+// @componentprop Tokens tokens object false "The changed token balances of the account."
+// @componentprop Storage storage object false "The changed storage slots of the account."
+type RPCAccountDiff struct {
+	Address  types.Address                          `json:"address"`
+	Balance  *RPCDiff[types.Value]                  `json:"balance,omitempty"`
+	Seqno    *RPCDiff[hexutil.Uint64]               `json:"seqno,omitempty"`
+	ExtSeqno *RPCDiff[hexutil.Uint64]               `json:"extSeqno,omitempty"`
+	Code     *RPCDiff[hexutil.Bytes]                `json:"code,omitempty"`
+	Tokens   map[types.TokenId]RPCDiff[types.Value] `json:"tokens,omitempty"`
+	Storage  map[common.Hash]RPCDiff[common.Hash]   `json:"storage,omitempty"`
+}
+
+func NewRPCStateDiff(diff types.StateDiff) []*RPCAccountDiff {
+	res := make([]*RPCAccountDiff, len(diff))
+	for i, account := range diff {
+		accountDiff := &RPCAccountDiff{Address: account.Address}
+		if account.Balance != nil {
+			accountDiff.Balance = &RPCDiff[types.Value]{From: account.Balance.From, To: account.Balance.To}
+		}
+		if account.Seqno != nil {
+			accountDiff.Seqno = &RPCDiff[hexutil.Uint64]{
+				From: hexutil.Uint64(account.Seqno.From),
+				To:   hexutil.Uint64(account.Seqno.To),
+			}
+		}
+		if account.ExtSeqno != nil {
+			accountDiff.ExtSeqno = &RPCDiff[hexutil.Uint64]{
+				From: hexutil.Uint64(account.ExtSeqno.From),
+				To:   hexutil.Uint64(account.ExtSeqno.To),
+			}
+		}
+		if account.Code != nil {
+			accountDiff.Code = &RPCDiff[hexutil.Bytes]{
+				From: hexutil.Bytes(account.Code.From),
+				To:   hexutil.Bytes(account.Code.To),
+			}
+		}
+		if account.Tokens != nil {
+			accountDiff.Tokens = make(map[types.TokenId]RPCDiff[types.Value], len(account.Tokens))
+			for id, diff := range account.Tokens {
+				accountDiff.Tokens[id] = RPCDiff[types.Value]{From: diff.From, To: diff.To}
+			}
+		}
+		if account.Storage != nil {
+			accountDiff.Storage = make(map[common.Hash]RPCDiff[common.Hash], len(account.Storage))
+			for key, diff := range account.Storage {
+				accountDiff.Storage[key] = RPCDiff[common.Hash]{From: diff.From, To: diff.To}
+			}
+		}
+		res[i] = accountDiff
+	}
+	return res
+}
+
 // @component OutTransaction outTransaction object "Outbound transaction produced by eth_call and result of its execution."
 // @componentprop Transaction transaction object true "Transaction data"
 // @componentprop Data data string false "Result of VM execution."
@@ -565,8 +707,41 @@ func toCallRes(input *rpctypes.CallResWithGasPrice) (*CallRes, error) {
 	return output, err
 }
 
+// @component SimulateCallsRes simulateCallsRes object "Response for eth_simulateCalls."
+// @componentprop Calls calls array true "Results of the calls in the order of execution."
+// @componentprop StateOverrides stateOverrides object false "Contracts state after all the calls."
+type SimulateCallsRes struct {
+	Calls          []*CallRes     `json:"calls"`
+	StateOverrides StateOverrides `json:"stateOverrides,omitempty"`
+}
+
+// @component FeeHistoryRes feeHistoryRes object "Response for eth_feeHistory."
+// @componentprop OldestBlock oldestBlock integer true "The number of the oldest block of the range."
+// @componentprop BaseFeePerGas baseFeePerGas array true "Base fees of the blocks and of the block following the newest one."
+// @componentprop GasUsedRatio gasUsedRatio array true "Ratios of the gas used by the blocks to the block gas limit."
+// @componentprop Reward reward array false "Effective priority fees of the blocks at the requested percentiles of the gas used."
+type FeeHistoryRes struct {
+	OldestBlock   types.BlockNumber `json:"oldestBlock"`
+	BaseFeePerGas []types.Value     `json:"baseFeePerGas"`
+	GasUsedRatio  []float64         `json:"gasUsedRatio"`
+	Reward        [][]types.Value   `json:"reward,omitempty"`
+}
+
+// @component FeeEstimationHop feeEstimationHop object "Fee estimation of a transaction on its shard."
+// @componentprop ShardId shardId integer true "The shard the transaction is executed on."
+// @componentprop To to string true "The destination address of the transaction."
+// @componentprop BaseFee baseFee string true "The base fee of the shard."
+// @componentprop FeeCredit feeCredit string true "The minimal fee credit the transaction succeeds with, including the verification fee of an external transaction."
+type FeeEstimationHop struct {
+	ShardId   types.ShardId `json:"shardId"`
+	To        types.Address `json:"to"`
+	BaseFee   types.Value   `json:"baseFee"`
+	FeeCredit types.Value   `json:"feeCredit"`
+}
+
 type EstimateFeeRes struct {
-	FeeCredit          types.Value `json:"feeCredit"`
-	AveragePriorityFee types.Value `json:"averagePriorityFee"`
-	MaxBasFee          types.Value `json:"maxBaseFee"`
+	FeeCredit          types.Value         `json:"feeCredit"`
+	AveragePriorityFee types.Value         `json:"averagePriorityFee"`
+	MaxBasFee          types.Value         `json:"maxBaseFee"`
+	Hops               []*FeeEstimationHop `json:"hops,omitempty"`
 }
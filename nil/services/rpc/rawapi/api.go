@@ -34,6 +34,8 @@ type NodeApiRo interface {
 	) (*rawapitypes.TransactionInfo, error)
 	GetInTransactionReceipt(
 		ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.ReceiptInfo, error)
+	GetTransactionsByAddress(
+		ctx context.Context, request rawapitypes.AddressTransactionsRequest) (*rawapitypes.AddressTransactions, error)
 
 	GetBalance(
 		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error)
@@ -50,6 +52,9 @@ type NodeApiRo interface {
 		address types.Address,
 		blockReference rawapitypes.BlockReference,
 	) (*rawapitypes.SmartContract, error)
+	GetStateDiff(
+		ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (types.StateDiff, error)
+	GetTransactionStateDiff(ctx context.Context, shardId types.ShardId, hash common.Hash) (types.StateDiff, error)
 
 	Call(
 		ctx context.Context,
@@ -57,6 +62,18 @@ type NodeApiRo interface {
 		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 		overrides *rpctypes.StateOverrides,
 	) (*rpctypes.CallResWithGasPrice, error)
+	SimulateCall(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+	) (*rpctypes.CallResWithGasPrice, error)
+	EstimateFee(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+	) (*rpctypes.CallResWithGasPrice, error)
 
 	GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error)
 	GetShardIdList(ctx context.Context) ([]types.ShardId, error)
@@ -80,6 +97,8 @@ type ShardApiRo interface {
 	GetInTransaction(
 		ctx context.Context, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error)
 	GetInTransactionReceipt(ctx context.Context, hash common.Hash) (*rawapitypes.ReceiptInfo, error)
+	GetTransactionsByAddress(
+		ctx context.Context, request rawapitypes.AddressTransactionsRequest) (*rawapitypes.AddressTransactions, error)
 
 	GetBalance(
 		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error)
@@ -96,6 +115,8 @@ type ShardApiRo interface {
 		address types.Address,
 		blockReference rawapitypes.BlockReference,
 	) (*rawapitypes.SmartContract, error)
+	GetStateDiff(ctx context.Context, blockReference rawapitypes.BlockReference) (types.StateDiff, error)
+	GetTransactionStateDiff(ctx context.Context, hash common.Hash) (types.StateDiff, error)
 
 	Call(
 		ctx context.Context,
@@ -103,6 +124,18 @@ type ShardApiRo interface {
 		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 		overrides *rpctypes.StateOverrides,
 	) (*rpctypes.CallResWithGasPrice, error)
+	SimulateCall(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+	) (*rpctypes.CallResWithGasPrice, error)
+	EstimateFee(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+	) (*rpctypes.CallResWithGasPrice, error)
 
 	GasPrice(ctx context.Context) (types.Value, error)
 	GetShardIdList(ctx context.Context) ([]types.ShardId, error)
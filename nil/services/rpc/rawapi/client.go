@@ -36,9 +36,25 @@ type ShardApiAccessor struct {
 
 var _ ShardApi = (*ShardApiAccessor)(nil)
 
-func NewNetworkRawApiAccessor(shardId types.ShardId, networkManager *network.Manager) (*ShardApiAccessor, error) {
+// NewNetworkRawApiAccessor creates an accessor of the shard served by other nodes.
+// Immutable responses are cached if the cache is set.
+func NewNetworkRawApiAccessor(
+	shardId types.ShardId,
+	networkManager *network.Manager,
+	routingConfig *NetworkRoutingConfig,
+	cache *ResponseCache,
+) (*ShardApiAccessor, error) {
+	router := newPeerRouter(
+		routingConfig,
+		reportFailuresToReputationTracker(network.TryGetPeerReputationTracker(networkManager)),
+		reflect.TypeFor[ShardApiRo]())
 	return newNetworkRawApiAccessor(
-		shardId, networkManager, reflect.TypeFor[ShardApi](), reflect.TypeFor[NetworkTransportProtocol]())
+		shardId,
+		networkManager,
+		router,
+		cache,
+		reflect.TypeFor[ShardApi](),
+		reflect.TypeFor[NetworkTransportProtocol]())
 }
 
 func NewLocalRawApiAccessor(shardId types.ShardId, rawapi *LocalShardApi) (*ShardApiAccessor, error) {
@@ -49,6 +65,8 @@ func NewLocalRawApiAccessor(shardId types.ShardId, rawapi *LocalShardApi) (*Shar
 func newNetworkRawApiAccessor(
 	shardId types.ShardId,
 	networkManager *network.Manager,
+	router *peerRouter,
+	cache *ResponseCache,
 	apiType reflect.Type,
 	transportType reflect.Type,
 ) (*ShardApiAccessor, error) {
@@ -58,8 +76,9 @@ func newNetworkRawApiAccessor(
 	}
 
 	return &ShardApiAccessor{
-		codec:        codec,
-		doApiRequest: makeDoNetworkRawApiRequestFunction(networkManager, shardId, "rawapi"),
+		codec: codec,
+		doApiRequest: makeCachingDoApiRequestFunction(
+			cache, makeDoNetworkRawApiRequestFunction(networkManager, router, shardId, "rawapi")),
 		onSetNodeApi: func(NodeApi) {},
 		onSetAsP2pRequestHandlersIfAllowed: func(
 			ctx context.Context, networkManager *network.Manager, readonly bool, logger logging.Logger,
@@ -96,12 +115,13 @@ func newDirectRawApiAccessor(
 
 func makeDoNetworkRawApiRequestFunction(
 	networkManager *network.Manager,
+	router *peerRouter,
 	shardId types.ShardId,
 	apiName string,
 ) doApiRequestFunction {
 	return func(codec *methodCodec, methodName string, ctx context.Context, args ...any) ([]byte, error) {
 		protocol := network.ProtocolID(fmt.Sprintf("/shard/%d/%s/%s", shardId, apiName, methodName))
-		serverPeerId, err := discoverAppropriatePeer(networkManager, shardId, protocol)
+		peers, err := discoverAppropriatePeers(networkManager, shardId, protocol)
 		if err != nil {
 			return nil, err
 		}
@@ -111,7 +131,10 @@ func makeDoNetworkRawApiRequestFunction(
 			return nil, err
 		}
 
-		return networkManager.SendRequestAndGetResponse(ctx, serverPeerId, protocol, requestBody)
+		doRequest := func(ctx context.Context, peer network.PeerID) ([]byte, error) {
+			return networkManager.SendRequestAndGetResponse(ctx, peer, protocol, requestBody)
+		}
+		return router.send(ctx, methodName, args, peers, doRequest)
 	}
 }
 
@@ -185,6 +208,16 @@ func (api *ShardApiAccessor) GetContract(
 		ctx, api, "GetContract", address, blockReference)
 }
 
+func (api *ShardApiAccessor) GetStateDiff(
+	ctx context.Context, blockReference rawapitypes.BlockReference,
+) (types.StateDiff, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[types.StateDiff](ctx, api, "GetStateDiff", blockReference)
+}
+
+func (api *ShardApiAccessor) GetTransactionStateDiff(ctx context.Context, hash common.Hash) (types.StateDiff, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[types.StateDiff](ctx, api, "GetTransactionStateDiff", hash)
+}
+
 func (api *ShardApiAccessor) Call(
 	ctx context.Context,
 	args rpctypes.CallArgs,
@@ -195,6 +228,26 @@ func (api *ShardApiAccessor) Call(
 		ctx, api, "Call", args, mainBlockReferenceOrHashWithChildren, overrides)
 }
 
+func (api *ShardApiAccessor) SimulateCall(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rpctypes.CallResWithGasPrice](
+		ctx, api, "SimulateCall", args, mainBlockReferenceOrHashWithChildren, overrides)
+}
+
+func (api *ShardApiAccessor) EstimateFee(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rpctypes.CallResWithGasPrice](
+		ctx, api, "EstimateFee", args, mainBlockReferenceOrHashWithChildren, overrides)
+}
+
 func (api *ShardApiAccessor) GetInTransaction(
 	ctx context.Context, request rawapitypes.TransactionRequest,
 ) (*rawapitypes.TransactionInfo, error) {
@@ -209,6 +262,13 @@ func (api *ShardApiAccessor) GetInTransactionReceipt(
 		ctx, api, "GetInTransactionReceipt", hash)
 }
 
+func (api *ShardApiAccessor) GetTransactionsByAddress(
+	ctx context.Context, request rawapitypes.AddressTransactionsRequest,
+) (*rawapitypes.AddressTransactions, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.AddressTransactions](
+		ctx, api, "GetTransactionsByAddress", request)
+}
+
 func (api *ShardApiAccessor) GasPrice(ctx context.Context) (types.Value, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[types.Value](ctx, api, "GasPrice")
 }
@@ -269,16 +329,16 @@ func sendRequestAndGetResponseWithCallerMethodName[ResponseType any](
 	return sendRequestAndGetResponse[ResponseType](api.doApiRequest, api.codec, methodName, ctx, args...)
 }
 
-func discoverAppropriatePeer(
+func discoverAppropriatePeers(
 	networkManager *network.Manager,
 	shardId types.ShardId,
 	protocol network.ProtocolID,
-) (network.PeerID, error) {
+) ([]network.PeerID, error) {
 	peersWithSpecifiedShard := networkManager.GetPeersForProtocol(protocol)
 	if len(peersWithSpecifiedShard) == 0 {
-		return "", fmt.Errorf("No peers with shard %d found", shardId)
+		return nil, fmt.Errorf("No peers with shard %d found", shardId)
 	}
-	return peersWithSpecifiedShard[0], nil
+	return peersWithSpecifiedShard, nil
 }
 
 func sendRequestAndGetResponse[ResponseType any](
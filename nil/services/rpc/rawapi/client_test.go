@@ -43,7 +43,11 @@ func newGeneratedApiClient(networkManager *network.Manager, serverPeerId network
 		apiCodec:       apiCodec,
 		networkManager: networkManager,
 		serverPeerId:   serverPeerId,
-		doApiRequest:   makeDoNetworkRawApiRequestFunction(networkManager, types.BaseShardId, "testapi"),
+		doApiRequest: makeDoNetworkRawApiRequestFunction(
+			networkManager,
+			newPeerRouter(nil, func(network.PeerID) {}, reflect.TypeFor[generatedApiClientIface]()),
+			types.BaseShardId,
+			"testapi"),
 	}, nil
 }
 
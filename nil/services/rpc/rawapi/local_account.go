@@ -198,25 +198,43 @@ func makeProofBuilder(root *mpt.Reader, key []byte) proofBuilder {
 	}
 }
 
-func (api *LocalShardApi) getRawSmartContract(
+func (api *LocalShardApi) getSmartContractsRoot(
 	tx db.RoTx,
-	address types.Address,
 	blockReference rawapitypes.BlockReference,
-) ([]byte, proofBuilder, error) {
+) (common.Hash, error) {
 	rawBlock, err := api.getBlockByReference(tx, blockReference, false)
 	if err != nil {
-		return nil, nil, err
+		return common.EmptyHash, err
 	}
 	if rawBlock == nil {
-		return nil, nil, errBlockNotFound
+		return common.EmptyHash, errBlockNotFound
 	}
 	var block types.Block
 	if err := block.UnmarshalSSZ(rawBlock.Block); err != nil {
+		return common.EmptyHash, err
+	}
+	return block.SmartContractsRoot, nil
+}
+
+func (api *LocalShardApi) getRawSmartContract(
+	tx db.RoTx,
+	address types.Address,
+	blockReference rawapitypes.BlockReference,
+) ([]byte, proofBuilder, error) {
+	contractsRoot, err := api.getSmartContractsRoot(tx, blockReference)
+	if err != nil {
 		return nil, nil, err
 	}
+	return api.getRawSmartContractAt(tx, address, contractsRoot)
+}
 
+func (api *LocalShardApi) getRawSmartContractAt(
+	tx db.RoTx,
+	address types.Address,
+	contractsRoot common.Hash,
+) ([]byte, proofBuilder, error) {
 	root := mpt.NewDbReader(tx, api.ShardId, db.ContractTrieTable)
-	root.SetRootHash(block.SmartContractsRoot)
+	root.SetRootHash(contractsRoot)
 	addressBytes := address.Hash().Bytes()
 	contractRaw, err := root.Get(addressBytes)
 	if err != nil {
@@ -235,7 +253,20 @@ func (api *LocalShardApi) getSmartContract(
 	address types.Address,
 	blockReference rawapitypes.BlockReference,
 ) (*types.SmartContract, error) {
-	contractRaw, _, err := api.getRawSmartContract(tx, address, blockReference)
+	contractsRoot, err := api.getSmartContractsRoot(tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+	// The snapshot is available only for the latest block, the trie is used for the older ones.
+	snapshot, err := execution.NewStateSnapshot(tx, api.ShardId, contractsRoot)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot != nil {
+		return snapshot.GetContract(address)
+	}
+
+	contractRaw, _, err := api.getRawSmartContractAt(tx, address, contractsRoot)
 	if err != nil {
 		return nil, err
 	}
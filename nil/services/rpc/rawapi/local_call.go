@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"maps"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
@@ -83,6 +84,7 @@ func (api *LocalShardApi) handleOutTransactions(
 	mainBlockHash common.Hash,
 	childBlocks []common.Hash,
 	overrides *rpctypes.StateOverrides,
+	opts callOptions,
 ) ([]*rpctypes.OutTransaction, error) {
 	outTransactions := make([]*rpctypes.OutTransaction, len(outTxns))
 
@@ -96,7 +98,14 @@ func (api *LocalShardApi) handleOutTransactions(
 			Transaction: (*hexutil.Bytes)(&raw),
 		}
 
-		res, err := api.nodeApi.Call(
+		call := api.nodeApi.Call
+		switch {
+		case opts.simulate:
+			call = api.nodeApi.SimulateCall
+		case opts.estimateFee:
+			call = api.nodeApi.EstimateFee
+		}
+		res, err := call(
 			ctx,
 			args,
 			rawapitypes.BlockHashWithChildrenAsBlockReferenceOrHashWithChildren(mainBlockHash, childBlocks),
@@ -114,6 +123,7 @@ func (api *LocalShardApi) handleOutTransactions(
 			BaseFee:         res.BaseFee,
 			Error:           res.Error,
 			Logs:            res.Logs,
+			MinFeeCredit:    res.MinFeeCredit,
 		}
 
 		if overrides != nil {
@@ -130,6 +140,43 @@ func (api *LocalShardApi) Call(
 	ctx context.Context, args rpctypes.CallArgs,
 	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 	overrides *rpctypes.StateOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	return api.call(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, callOptions{})
+}
+
+// SimulateCall works like Call, but it also executes the outbound transactions of the failed transactions
+// (bounces and responses to the failed requests), so the whole async flow can be followed.
+// The returned state overrides include the given ones.
+func (api *LocalShardApi) SimulateCall(
+	ctx context.Context, args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	return api.call(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, callOptions{simulate: true})
+}
+
+// EstimateFee works like Call, but it also finds the minimal fee credit for the transaction
+// and for each of its outbound transactions on their shards.
+func (api *LocalShardApi) EstimateFee(
+	ctx context.Context, args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	return api.call(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, callOptions{estimateFee: true})
+}
+
+type callOptions struct {
+	// simulate enables the execution of the outbound transactions of the failed transactions.
+	simulate bool
+	// estimateFee enables the search of the minimal fee credit for the transactions.
+	estimateFee bool
+}
+
+func (api *LocalShardApi) call(
+	ctx context.Context, args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	opts callOptions,
 ) (*rpctypes.CallResWithGasPrice, error) {
 	tx, err := api.db.CreateRoTx(ctx)
 	if err != nil {
@@ -188,49 +235,66 @@ func (api *LocalShardApi) Call(
 		return nil, fmt.Errorf("failed to create config accessor: %w", err)
 	}
 
-	es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
-		Block:          block,
-		ConfigAccessor: configAccessor,
-		Mode:           execution.ModeReadOnly,
-	})
-	if err != nil {
-		return nil, err
-	}
-	es.MainShardHash = mainBlockHash
-
-	if overrides != nil {
-		if err := overrides.Override(es); err != nil {
+	if txn.IsDeploy() {
+		if err := execution.ValidateDeployTransaction(txn); err != nil {
 			return nil, err
 		}
 	}
 
-	if txn.IsDeploy() {
-		if err := execution.ValidateDeployTransaction(txn); err != nil {
+	newState := func() (*execution.ExecutionState, error) {
+		es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+			Block:          block,
+			ConfigAccessor: configAccessor,
+			Mode:           execution.ModeReadOnly,
+		})
+		if err != nil {
 			return nil, err
 		}
+		es.MainShardHash = mainBlockHash
+
+		if overrides != nil {
+			if err := overrides.Override(es); err != nil {
+				return nil, err
+			}
+		}
+		return es, nil
 	}
 
-	var payer execution.Payer
-	switch {
-	case args.Transaction == nil:
-		// "args.Transaction == nil" mean that it's a root transaction
-		// and we don't want to withdraw any payment for it.
-		// Because it's quite useful for read-only methods.
-		payer = execution.NewDummyPayer()
-	case txn.IsInternal():
-		payer = execution.NewTransactionPayer(txn, es)
-	default:
-		var toAs *execution.AccountState
-		if toAs, err = es.GetAccount(txn.To); err != nil {
-			return nil, err
-		} else if toAs == nil {
-			return nil, rpctypes.ErrToAccNotFound
+	// execute runs the transaction on top of a new state, so it can be run several times
+	execute := func(txn *types.Transaction) (*execution.ExecutionState, *execution.ExecutionResult, error) {
+		es, err := newState()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var payer execution.Payer
+		switch {
+		case args.Transaction == nil:
+			// "args.Transaction == nil" mean that it's a root transaction
+			// and we don't want to withdraw any payment for it.
+			// Because it's quite useful for read-only methods.
+			payer = execution.NewDummyPayer()
+		case txn.IsInternal():
+			payer = execution.NewTransactionPayer(txn, es)
+		default:
+			toAs, err := es.GetAccount(txn.To)
+			if err != nil {
+				return nil, nil, err
+			} else if toAs == nil {
+				return nil, nil, rpctypes.ErrToAccNotFound
+			}
+			payer = execution.NewAccountPayer(toAs, txn)
 		}
-		payer = execution.NewAccountPayer(toAs, txn)
+
+		es.AddInTransaction(txn)
+		return es, es.HandleTransaction(ctx, txn, payer), nil
 	}
 
-	txnHash := es.AddInTransaction(txn)
-	res := es.HandleTransaction(ctx, txn, payer)
+	txnHash := txn.Hash()
+	es, res, err := execute(txn)
+	if err != nil {
+		return nil, err
+	}
 
 	result := &rpctypes.CallResWithGasPrice{
 		Data:      res.ReturnData,
@@ -239,9 +303,27 @@ func (api *LocalShardApi) Call(
 		DebugLogs: es.DebugLogs[txnHash],
 	}
 
+	if opts.estimateFee {
+		if res.Failed() {
+			result.MinFeeCredit = result.CoinsUsed
+		} else if result.MinFeeCredit, err = searchMinFeeCredit(txn, res, execute); err != nil {
+			return nil, err
+		}
+
+		if txn.IsExternal() && txn.IsExecution() {
+			verificationFee, err := estimateVerifyExternalFee(txn, res.GasPrice, newState)
+			if err != nil {
+				return nil, err
+			}
+			result.MinFeeCredit = result.MinFeeCredit.Add(verificationFee)
+		}
+	}
+
 	if res.Failed() {
 		result.Error = res.GetError().Error()
-		return result, nil
+		if !opts.simulate || res.IsFatal() {
+			return result, nil
+		}
 	}
 
 	esOld, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
@@ -256,6 +338,13 @@ func (api *LocalShardApi) Call(
 	if err != nil {
 		return nil, err
 	}
+	if opts.simulate && overrides != nil {
+		// The nested calls need the given overrides as well, since they may be on the other shards
+		merged := make(rpctypes.StateOverrides, len(*overrides))
+		maps.Copy(merged, *overrides)
+		merged.Merge(stateOverrides)
+		stateOverrides = merged
+	}
 
 	execOutTransactions := es.OutTransactions[txnHash]
 	outTransactions, err := api.handleOutTransactions(
@@ -264,6 +353,7 @@ func (api *LocalShardApi) Call(
 		mainBlockHash,
 		childBlocks,
 		&stateOverrides,
+		opts,
 	)
 	if err != nil {
 		return nil, err
@@ -274,3 +364,82 @@ func (api *LocalShardApi) Call(
 	result.BaseFee = es.BaseFee
 	return result, nil
 }
+
+// estimateVerifyExternalFee returns the fee the destination account pays for the verification of the external
+// transaction. It's charged before the transaction is executed, so the minimal fee credit doesn't cover it.
+func estimateVerifyExternalFee(
+	txn *types.Transaction,
+	gasPrice types.Value,
+	newState func() (*execution.ExecutionState, error),
+) (types.Value, error) {
+	es, err := newState()
+	if err != nil {
+		return types.Value0, err
+	}
+
+	account, err := es.GetAccount(txn.To)
+	if err != nil {
+		return types.Value0, err
+	}
+	if account == nil || len(account.Code) == 0 {
+		// Nothing verifies the transaction sending the value to a contract that doesn't exist yet
+		return types.Value0, nil
+	}
+
+	gas, err := es.EstimateVerifyExternalGas(txn, account)
+	if err != nil {
+		return types.Value0, err
+	}
+	return gas.ToValue(gasPrice), nil
+}
+
+// searchMinFeeCredit finds the minimal fee credit the transaction succeeds with by running it with different ones.
+// It's more than the used gas if the transaction needs some gas left, e.g., for the nested calls or storage writes.
+func searchMinFeeCredit(
+	txn *types.Transaction,
+	res *execution.ExecutionResult,
+	execute func(*types.Transaction) (*execution.ExecutionState, *execution.ExecutionResult, error),
+) (types.Value, error) {
+	succeeds := func(gas types.Gas) (bool, error) {
+		probe := *txn
+		probe.FeeCredit = gas.ToValue(res.GasPrice)
+		_, probeRes, err := execute(&probe)
+		if err != nil {
+			return false, err
+		}
+		return !probeRes.Failed(), nil
+	}
+
+	// The transaction succeeds with its own fee credit and can't succeed with less than the used gas
+	lo, hi := res.GasUsed, txn.FeeCredit.ToGas(res.GasPrice)
+	if ok, err := succeeds(lo); err != nil || ok {
+		return lo.ToValue(res.GasPrice), err
+	}
+
+	// The required gas is usually close to the used one, so the upper bound is looked for near it first
+	for step := max(lo, 1); lo+step < hi; step *= 2 {
+		ok, err := succeeds(lo + step)
+		if err != nil {
+			return types.Value0, err
+		}
+		if ok {
+			hi = lo + step
+			break
+		}
+		lo += step
+	}
+
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		ok, err := succeeds(mid)
+		if err != nil {
+			return types.Value0, err
+		}
+		if ok {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi.ToValue(res.GasPrice), nil
+}
@@ -0,0 +1,82 @@
+package rawapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+func (api *LocalShardApi) GetStateDiff(
+	ctx context.Context,
+	blockReference rawapitypes.BlockReference,
+) (types.StateDiff, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockHash, err := api.getBlockHashByReference(tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := api.collectBlockStateDiff(ctx, tx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return diff.Block, nil
+}
+
+func (api *LocalShardApi) GetTransactionStateDiff(ctx context.Context, hash common.Hash) (types.StateDiff, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	value, err := tx.GetFromShard(api.ShardId, db.BlockHashAndInTransactionIndexByTransactionHash, hash.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transaction %s: %w", hash, err)
+	}
+	var index db.BlockHashAndTransactionIndex
+	if err := index.UnmarshalSSZ(value); err != nil {
+		return nil, err
+	}
+
+	diff, err := api.collectBlockStateDiff(ctx, tx, index.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	txnDiff, ok := diff.Transactions[hash]
+	if !ok {
+		return nil, fmt.Errorf("transaction %s isn't executed by block %s", hash, index.BlockHash)
+	}
+	return txnDiff, nil
+}
+
+// collectBlockStateDiff re-executes the block to find the changes made by it and by its transactions.
+func (api *LocalShardApi) collectBlockStateDiff(
+	ctx context.Context,
+	tx db.RoTx,
+	blockHash common.Hash,
+) (*execution.BlockStateDiff, error) {
+	rawBlock, err := api.getBlockByHash(tx, blockHash, true)
+	if err != nil {
+		return nil, err
+	}
+	if rawBlock == nil {
+		return nil, fmt.Errorf("block %s: %w", blockHash, db.ErrKeyNotFound)
+	}
+
+	block, err := rawBlock.DecodeSSZ()
+	if err != nil {
+		return nil, err
+	}
+	return execution.CollectBlockStateDiff(ctx, tx, api.ShardId, block, api.enableDevApi)
+}
@@ -2,6 +2,7 @@ package rawapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -11,6 +12,11 @@ import (
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 )
 
+var (
+	errAddressTransactionIndexDisabled   = errors.New("address transaction index is not enabled")
+	errAddressTransactionIndexIncomplete = errors.New("address transaction index is being backfilled")
+)
+
 func (api *LocalShardApi) getTransactionByHash(tx db.RoTx, hash common.Hash) (*rawapitypes.TransactionInfo, error) {
 	data, err := api.accessor.Access(tx, api.ShardId).GetInTransaction().WithReceipt().ByHash(hash)
 	if err != nil {
@@ -113,3 +119,45 @@ func (api *LocalShardApi) GetInTransaction(
 	return api.getInTransactionByBlockRefAndIndex(
 		tx, request.ByBlockRefAndIndex.BlockRef, request.ByBlockRefAndIndex.Index)
 }
+
+func (api *LocalShardApi) GetTransactionsByAddress(
+	ctx context.Context, request rawapitypes.AddressTransactionsRequest,
+) (*rawapitypes.AddressTransactions, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open tx to read address transactions: %w", err)
+	}
+	defer tx.Rollback()
+
+	state, err := db.ReadAddressTransactionIndexState(tx, api.ShardId)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, errAddressTransactionIndexDisabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !state.IsIndexed(request.FromBlock, request.ToBlock) {
+		return nil, fmt.Errorf("%w: blocks from %d to %d are not indexed yet",
+			errAddressTransactionIndexIncomplete, state.NextBackfillBlock, state.BackfillTarget)
+	}
+
+	txns, cursor, err := db.ReadAddressTransactions(
+		tx, api.ShardId, request.Address, request.FromBlock, request.ToBlock, request.Cursor, request.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &rawapitypes.AddressTransactions{
+		Transactions: make([]rawapitypes.AddressTransaction, len(txns)),
+		NextCursor:   cursor,
+	}
+	for i, txn := range txns {
+		res.Transactions[i] = rawapitypes.AddressTransaction{
+			Hash:     txn.Hash,
+			BlockId:  txn.BlockId,
+			Index:    txn.Index,
+			Outbound: txn.Direction == db.AddressTransactionOutbound,
+		}
+	}
+	return res, nil
+}
@@ -179,6 +179,40 @@ func (api *NodeApiOverShardApis) GetContract(
 	return result, nil
 }
 
+func (api *NodeApiOverShardApis) GetStateDiff(
+	ctx context.Context,
+	shardId types.ShardId,
+	blockReference rawapitypes.BlockReference,
+) (types.StateDiff, error) {
+	methodName := methodNameChecked("GetStateDiff")
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetStateDiff(ctx, blockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *NodeApiOverShardApis) GetTransactionStateDiff(
+	ctx context.Context,
+	shardId types.ShardId,
+	hash common.Hash,
+) (types.StateDiff, error) {
+	methodName := methodNameChecked("GetTransactionStateDiff")
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetTransactionStateDiff(ctx, hash)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *NodeApiOverShardApis) Call(
 	ctx context.Context,
 	args rpctypes.CallArgs,
@@ -204,6 +238,56 @@ func (api *NodeApiOverShardApis) Call(
 	return result, nil
 }
 
+func (api *NodeApiOverShardApis) SimulateCall(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	methodName := methodNameChecked("SimulateCall")
+
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	shardId := txn.To.ShardId()
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.SimulateCall(ctx, args, mainBlockReferenceOrHashWithChildren, overrides)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *NodeApiOverShardApis) EstimateFee(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	methodName := methodNameChecked("EstimateFee")
+
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	shardId := txn.To.ShardId()
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.EstimateFee(ctx, args, mainBlockReferenceOrHashWithChildren, overrides)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *NodeApiOverShardApis) GetInTransaction(
 	ctx context.Context,
 	shardId types.ShardId,
@@ -238,6 +322,23 @@ func (api *NodeApiOverShardApis) GetInTransactionReceipt(
 	return result, nil
 }
 
+func (api *NodeApiOverShardApis) GetTransactionsByAddress(
+	ctx context.Context,
+	request rawapitypes.AddressTransactionsRequest,
+) (*rawapitypes.AddressTransactions, error) {
+	methodName := methodNameChecked("GetTransactionsByAddress")
+	shardId := request.Address.ShardId()
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetTransactionsByAddress(ctx, request)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *NodeApiOverShardApis) GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error) {
 	methodName := methodNameChecked("GasPrice")
 	shardApi, ok := api.Apis[shardId]
@@ -496,6 +496,7 @@ func (ac *AsyncContext) PackProtoMessage(context *types.AsyncContext) {
 	ac.IsAwait = context.IsAwait
 	ac.Data = context.Data
 	ac.ResponseProcessingGas = context.ResponseProcessingGas.Uint64()
+	ac.Deadline = uint64(context.Deadline)
 }
 
 func (rc *AsyncContext) UnpackProtoMessage() types.AsyncContext {
@@ -506,6 +507,7 @@ func (rc *AsyncContext) UnpackProtoMessage() types.AsyncContext {
 		IsAwait:               rc.IsAwait,
 		Data:                  rc.Data,
 		ResponseProcessingGas: types.Gas(rc.ResponseProcessingGas),
+		Deadline:              types.BlockNumber(rc.Deadline),
 	}
 }
 
@@ -607,6 +609,141 @@ func (rcr *RawContractResponse) UnpackProtoMessage() (*rawapitypes.SmartContract
 	return nil, errors.New("unexpected response type")
 }
 
+// StateDiff converters
+
+func packValueDiff(diff types.Diff[types.Value]) *Uint256Diff {
+	res := &Uint256Diff{From: new(Uint256), To: new(Uint256)}
+	if diff.From.Uint256 != nil {
+		res.From.PackProtoMessage(*diff.From.Uint256)
+	}
+	if diff.To.Uint256 != nil {
+		res.To.PackProtoMessage(*diff.To.Uint256)
+	}
+	return res
+}
+
+func (d *Uint256Diff) UnpackProtoMessage() types.Diff[types.Value] {
+	return types.Diff[types.Value]{From: newValueFromUint256(d.From), To: newValueFromUint256(d.To)}
+}
+
+func (ad *AccountDiff) PackProtoMessage(diff *types.AccountDiff) error {
+	ad.Address = new(Address).PackProtoMessage(diff.Address)
+
+	if diff.Balance != nil {
+		ad.Balance = packValueDiff(*diff.Balance)
+	}
+	if diff.Seqno != nil {
+		ad.Seqno = &Uint64Diff{From: uint64(diff.Seqno.From), To: uint64(diff.Seqno.To)}
+	}
+	if diff.ExtSeqno != nil {
+		ad.ExtSeqno = &Uint64Diff{From: uint64(diff.ExtSeqno.From), To: uint64(diff.ExtSeqno.To)}
+	}
+	if diff.Code != nil {
+		ad.Code = &BytesDiff{From: diff.Code.From, To: diff.Code.To}
+	}
+
+	if diff.Tokens != nil {
+		ad.Tokens = make(map[string]*Uint256Diff, len(diff.Tokens))
+		for k, v := range diff.Tokens {
+			ad.Tokens[k.String()] = packValueDiff(v)
+		}
+	}
+
+	if diff.Storage != nil {
+		ad.Storage = make(map[string]*HashDiff, len(diff.Storage))
+		for k, v := range diff.Storage {
+			hashDiff := &HashDiff{From: new(Hash), To: new(Hash)}
+			if err := hashDiff.From.PackProtoMessage(v.From); err != nil {
+				return err
+			}
+			if err := hashDiff.To.PackProtoMessage(v.To); err != nil {
+				return err
+			}
+			ad.Storage[k.Hex()] = hashDiff
+		}
+	}
+
+	return nil
+}
+
+func (ad *AccountDiff) UnpackProtoMessage() (*types.AccountDiff, error) {
+	diff := &types.AccountDiff{Address: ad.Address.UnpackProtoMessage()}
+
+	if ad.Balance != nil {
+		balance := ad.Balance.UnpackProtoMessage()
+		diff.Balance = &balance
+	}
+	if ad.Seqno != nil {
+		diff.Seqno = &types.Diff[types.Seqno]{From: types.Seqno(ad.Seqno.From), To: types.Seqno(ad.Seqno.To)}
+	}
+	if ad.ExtSeqno != nil {
+		diff.ExtSeqno = &types.Diff[types.Seqno]{From: types.Seqno(ad.ExtSeqno.From), To: types.Seqno(ad.ExtSeqno.To)}
+	}
+	if ad.Code != nil {
+		diff.Code = &types.Diff[types.Code]{From: ad.Code.From, To: ad.Code.To}
+	}
+
+	if len(ad.Tokens) > 0 {
+		diff.Tokens = make(map[types.TokenId]types.Diff[types.Value], len(ad.Tokens))
+		for k, v := range ad.Tokens {
+			diff.Tokens[types.TokenId(types.HexToAddress(k))] = v.UnpackProtoMessage()
+		}
+	}
+
+	if len(ad.Storage) > 0 {
+		diff.Storage = make(map[common.Hash]types.Diff[common.Hash], len(ad.Storage))
+		for k, v := range ad.Storage {
+			from, err := v.From.UnpackProtoMessage()
+			if err != nil {
+				return nil, err
+			}
+			to, err := v.To.UnpackProtoMessage()
+			if err != nil {
+				return nil, err
+			}
+			diff.Storage[common.HexToHash(k)] = types.Diff[common.Hash]{From: from, To: to}
+		}
+	}
+
+	return diff, nil
+}
+
+func (sdr *StateDiffResponse) PackProtoMessage(diff types.StateDiff, err error) error {
+	if err != nil {
+		sdr.Result = &StateDiffResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	accounts := make([]*AccountDiff, len(diff))
+	for i, accountDiff := range diff {
+		accounts[i] = new(AccountDiff)
+		if err := accounts[i].PackProtoMessage(accountDiff); err != nil {
+			return err
+		}
+	}
+	sdr.Result = &StateDiffResponse_Data{Data: &StateDiff{Accounts: accounts}}
+	return nil
+}
+
+func (sdr *StateDiffResponse) UnpackProtoMessage() (types.StateDiff, error) {
+	switch sdr.Result.(type) {
+	case *StateDiffResponse_Error:
+		return nil, sdr.GetError().UnpackProtoMessage()
+
+	case *StateDiffResponse_Data:
+		accounts := sdr.GetData().Accounts
+		diff := make(types.StateDiff, len(accounts))
+		for i, account := range accounts {
+			var err error
+			if diff[i], err = account.UnpackProtoMessage(); err != nil {
+				return nil, err
+			}
+		}
+		return diff, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
 func (c *Contract) PackProtoMessage(contract rpctypes.Contract) *Contract {
 	if contract.Seqno != nil {
 		c.Seqno = (*uint64)(contract.Seqno)
@@ -873,6 +1010,9 @@ func (m *OutTransaction) PackProtoMessage(txn *rpctypes.OutTransaction) *OutTran
 		Logs:           packLogs(txn.Logs),
 		DebugLogs:      packDebugLogs(txn.DebugLogs),
 	}
+	if txn.MinFeeCredit.Uint256 != nil {
+		out.MinFeeCredit = new(Uint256).PackProtoMessage(*txn.MinFeeCredit.Uint256)
+	}
 
 	if len(txn.OutTransactions) > 0 {
 		out.OutTransactions = make([]*OutTransaction, len(txn.OutTransactions))
@@ -892,6 +1032,14 @@ func newValueFromUint256(v *Uint256) types.Value {
 	return types.Value{Uint256: &value}
 }
 
+// newOptionalValueFromUint256 keeps the absent value absent, unlike newValueFromUint256.
+func newOptionalValueFromUint256(v *Uint256) types.Value {
+	if v == nil {
+		return types.Value{}
+	}
+	return newValueFromUint256(v)
+}
+
 func (m *OutTransaction) UnpackProtoMessage() *rpctypes.OutTransaction {
 	txn := &rpctypes.OutTransaction{
 		TransactionSSZ: m.TransactionSSZ,
@@ -904,6 +1052,7 @@ func (m *OutTransaction) UnpackProtoMessage() *rpctypes.OutTransaction {
 
 	txn.CoinsUsed = newValueFromUint256(m.CoinsUsed)
 	txn.BaseFee = newValueFromUint256(m.GasPrice)
+	txn.MinFeeCredit = newOptionalValueFromUint256(m.MinFeeCredit)
 
 	if len(m.OutTransactions) > 0 {
 		txn.OutTransactions = make([]*rpctypes.OutTransaction, len(m.OutTransactions))
@@ -1017,6 +1166,9 @@ func (cr *CallResponse) PackProtoMessage(args *rpctypes.CallResWithGasPrice, err
 	if args.BaseFee.Uint256 != nil {
 		res.GasPrice = new(Uint256).PackProtoMessage(*args.BaseFee.Uint256)
 	}
+	if args.MinFeeCredit.Uint256 != nil {
+		res.MinFeeCredit = new(Uint256).PackProtoMessage(*args.MinFeeCredit.Uint256)
+	}
 
 	cr.Result = &CallResponse_Data{Data: res}
 	return nil
@@ -1037,6 +1189,7 @@ func (cr *CallResponse) UnpackProtoMessage() (*rpctypes.CallResWithGasPrice, err
 	res.Data = data.Data
 	res.CoinsUsed = newValueFromUint256(data.CoinsUsed)
 	res.BaseFee = newValueFromUint256(data.GasPrice)
+	res.MinFeeCredit = newOptionalValueFromUint256(data.MinFeeCredit)
 	res.Logs = unpackLogs(data.Logs)
 	res.DebugLogs = unpackDebugLogs(data.DebugLogs)
 
@@ -1277,6 +1430,79 @@ func (r *ReceiptResponse) UnpackProtoMessage() (*rawapitypes.ReceiptInfo, error)
 	return r.GetData().UnpackProtoMessage(), nil
 }
 
+// Address transactions converters
+func (r *AddressTransactionsRequest) PackProtoMessage(request rawapitypes.AddressTransactionsRequest) error {
+	r.Address = new(Address).PackProtoMessage(request.Address)
+	r.FromBlock = uint64(request.FromBlock)
+	r.ToBlock = uint64(request.ToBlock)
+	r.Limit = request.Limit
+	r.Cursor = request.Cursor
+	return nil
+}
+
+func (r *AddressTransactionsRequest) UnpackProtoMessage() (rawapitypes.AddressTransactionsRequest, error) {
+	return rawapitypes.AddressTransactionsRequest{
+		Address:   r.Address.UnpackProtoMessage(),
+		FromBlock: types.BlockNumber(r.FromBlock),
+		ToBlock:   types.BlockNumber(r.ToBlock),
+		Limit:     r.Limit,
+		Cursor:    r.Cursor,
+	}, nil
+}
+
+func (r *AddressTransactionsResponse) PackProtoMessage(txns *rawapitypes.AddressTransactions, err error) error {
+	if err != nil {
+		r.Result = &AddressTransactionsResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &AddressTransactions{
+		Transactions: make([]*AddressTransaction, len(txns.Transactions)),
+		NextCursor:   txns.NextCursor,
+	}
+	for i, txn := range txns.Transactions {
+		hash := &Hash{}
+		if err := hash.PackProtoMessage(txn.Hash); err != nil {
+			return err
+		}
+		data.Transactions[i] = &AddressTransaction{
+			Hash:     hash,
+			BlockId:  uint64(txn.BlockId),
+			Index:    uint64(txn.Index),
+			Outbound: txn.Outbound,
+		}
+	}
+	r.Result = &AddressTransactionsResponse_Data{Data: data}
+	return nil
+}
+
+func (r *AddressTransactionsResponse) UnpackProtoMessage() (*rawapitypes.AddressTransactions, error) {
+	switch r.Result.(type) {
+	case *AddressTransactionsResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+	case *AddressTransactionsResponse_Data:
+		data := r.GetData()
+		res := &rawapitypes.AddressTransactions{
+			Transactions: make([]rawapitypes.AddressTransaction, len(data.Transactions)),
+			NextCursor:   data.NextCursor,
+		}
+		for i, txn := range data.Transactions {
+			hash, err := txn.Hash.UnpackProtoMessage()
+			if err != nil {
+				return nil, err
+			}
+			res.Transactions[i] = rawapitypes.AddressTransaction{
+				Hash:     hash,
+				BlockId:  types.BlockNumber(txn.BlockId),
+				Index:    types.TransactionIndex(txn.Index),
+				Outbound: txn.Outbound,
+			}
+		}
+		return res, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
 func (r *GasPriceResponse) PackProtoMessage(v types.Value, err error) error {
 	if err != nil {
 		r.Result = &GasPriceResponse_Error{Error: new(Error).PackProtoMessage(err)}
@@ -255,3 +255,39 @@ func TestErrorMap_PackUnpack(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, &Error{Message: "<invalid UTF-8 string>"}, val)
 }
+
+func TestStateDiffResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	diff := types.StateDiff{
+		{
+			Address: types.GenerateRandomAddress(1),
+			Balance: &types.Diff[types.Value]{From: types.Value0, To: types.NewValueFromUint64(100)},
+			Seqno:   &types.Diff[types.Seqno]{From: 1, To: 2},
+			Code:    &types.Diff[types.Code]{From: nil, To: types.Code{0x60, 0x00}},
+			Tokens: map[types.TokenId]types.Diff[types.Value]{
+				types.TokenId(types.GenerateRandomAddress(1)): {From: types.Value10, To: types.Value0},
+			},
+			Storage: map[common.Hash]types.Diff[common.Hash]{
+				common.HexToHash("0x01"): {From: common.EmptyHash, To: common.HexToHash("0xabcd")},
+			},
+		},
+		{
+			Address:  types.GenerateRandomAddress(1),
+			ExtSeqno: &types.Diff[types.Seqno]{From: 0, To: 1},
+		},
+	}
+
+	response := new(StateDiffResponse)
+	require.NoError(t, response.PackProtoMessage(diff, nil))
+
+	data, err := proto.Marshal(response)
+	require.NoError(t, err)
+
+	var unpacked StateDiffResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedDiff, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, diff, unpackedDiff)
+}
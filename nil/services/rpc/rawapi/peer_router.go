@@ -0,0 +1,260 @@
+package rawapi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	cm "github.com/NilFoundation/nil/nil/internal/network/connection_manager"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+const (
+	// peerLatencySmoothing is the weight of the last request in the average latency of a peer
+	peerLatencySmoothing = 0.2
+	// peerFailurePenaltyPeriod is the time during which failed peers are tried after the others
+	peerFailurePenaltyPeriod = time.Minute
+)
+
+// NetworkRoutingConfig configures how requests to shards served by other nodes are routed to peers.
+type NetworkRoutingConfig struct {
+	// ArchivePeers are preferred for requests referring to historical blocks.
+	ArchivePeers []network.PeerID
+	// MaxAttempts is the number of peers a read request is sent to before giving up.
+	MaxAttempts int
+	// HedgeDelay is the time after which a read request is also sent to the next peer
+	// unless it's completed. Zero disables hedging.
+	HedgeDelay time.Duration
+}
+
+func NewDefaultNetworkRoutingConfig() *NetworkRoutingConfig {
+	return &NetworkRoutingConfig{
+		MaxAttempts: 3,
+	}
+}
+
+type peerStats struct {
+	latency     time.Duration // smoothed latency of successful requests
+	failures    int           // number of consecutive failures
+	lastFailure time.Time
+}
+
+// peerRouter chooses peers for requests according to their statistics and retries read requests on failures.
+type peerRouter struct {
+	config        *NetworkRoutingConfig
+	archivePeers  map[network.PeerID]struct{}
+	readMethods   map[string]struct{}
+	onPeerFailure func(network.PeerID)
+
+	mu    sync.Mutex
+	stats map[network.PeerID]*peerStats // +checklocks:mu
+}
+
+// reportFailuresToReputationTracker returns a failure handler which lowers the reputation of failed peers.
+func reportFailuresToReputationTracker(tracker cm.PeerReputationTracker) func(network.PeerID) {
+	if tracker == nil {
+		return func(network.PeerID) {}
+	}
+	return func(peer network.PeerID) {
+		tracker.ReportPeer(peer, cm.ReputationChangeRequestFailed)
+	}
+}
+
+func newPeerRouter(
+	config *NetworkRoutingConfig, onPeerFailure func(network.PeerID), readApiType reflect.Type,
+) *peerRouter {
+	if config == nil {
+		config = NewDefaultNetworkRoutingConfig()
+	}
+
+	archivePeers := make(map[network.PeerID]struct{}, len(config.ArchivePeers))
+	for _, peer := range config.ArchivePeers {
+		archivePeers[peer] = struct{}{}
+	}
+
+	readMethods := make(map[string]struct{}, readApiType.NumMethod())
+	for i := range readApiType.NumMethod() {
+		readMethods[readApiType.Method(i).Name] = struct{}{}
+	}
+
+	return &peerRouter{
+		config:        config,
+		archivePeers:  archivePeers,
+		readMethods:   readMethods,
+		onPeerFailure: onPeerFailure,
+		stats:         make(map[network.PeerID]*peerStats),
+	}
+}
+
+// orderPeers sorts the peers from the most to the least preferable one.
+// Archive peers go first for historical requests, peers failed recently go last,
+// the rest are sorted by latency (peers without statistics are tried first to collect it).
+func (r *peerRouter) orderPeers(peers []network.PeerID, historical bool) []network.PeerID {
+	type peerRank struct {
+		peer     network.PeerID
+		archive  bool
+		failures int
+		latency  time.Duration
+	}
+
+	now := time.Now()
+	ranks := make([]peerRank, len(peers))
+
+	r.mu.Lock()
+	for i, peer := range peers {
+		ranks[i].peer = peer
+		if historical {
+			_, ranks[i].archive = r.archivePeers[peer]
+		}
+		if stats, ok := r.stats[peer]; ok {
+			if now.Sub(stats.lastFailure) < peerFailurePenaltyPeriod {
+				ranks[i].failures = stats.failures
+			}
+			ranks[i].latency = stats.latency
+		}
+	}
+	r.mu.Unlock()
+
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].archive != ranks[j].archive {
+			return ranks[i].archive
+		}
+		if ranks[i].failures != ranks[j].failures {
+			return ranks[i].failures < ranks[j].failures
+		}
+		return ranks[i].latency < ranks[j].latency
+	})
+
+	res := make([]network.PeerID, len(ranks))
+	for i, rank := range ranks {
+		res[i] = rank.peer
+	}
+	return res
+}
+
+func (r *peerRouter) reportSuccess(peer network.PeerID, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.stats[peer]
+	if !ok {
+		r.stats[peer] = &peerStats{latency: latency}
+		return
+	}
+	stats.failures = 0
+	if stats.latency == 0 {
+		stats.latency = latency
+	} else {
+		stats.latency += time.Duration(peerLatencySmoothing * float64(latency-stats.latency))
+	}
+}
+
+func (r *peerRouter) reportFailure(peer network.PeerID) {
+	r.mu.Lock()
+	stats, ok := r.stats[peer]
+	if !ok {
+		stats = &peerStats{}
+		r.stats[peer] = stats
+	}
+	stats.failures++
+	stats.lastFailure = time.Now()
+	r.mu.Unlock()
+
+	r.onPeerFailure(peer)
+}
+
+// send sends the request to the peers in the order of preference until it succeeds.
+// Write requests are sent to the first peer only.
+func (r *peerRouter) send(
+	ctx context.Context,
+	methodName string,
+	args []any,
+	peers []network.PeerID,
+	doRequest func(ctx context.Context, peer network.PeerID) ([]byte, error),
+) ([]byte, error) {
+	_, isRead := r.readMethods[methodName]
+
+	peers = r.orderPeers(peers, isRead && refersToHistoricalBlock(args))
+	if !isRead {
+		peers = peers[:1]
+	} else if r.config.MaxAttempts > 0 && len(peers) > r.config.MaxAttempts {
+		peers = peers[:r.config.MaxAttempts]
+	}
+
+	// Cancels the requests which are still in flight when the result is obtained
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		response []byte
+		err      error
+	}
+	results := make(chan result, len(peers))
+	next, inFlight := 0, 0
+	sendToNextPeer := func() {
+		peer := peers[next]
+		next++
+		inFlight++
+		go func() {
+			start := time.Now()
+			response, err := doRequest(ctx, peer)
+			switch {
+			case err == nil:
+				r.reportSuccess(peer, time.Since(start))
+			case ctx.Err() == nil:
+				r.reportFailure(peer)
+			}
+			results <- result{response, err}
+		}()
+	}
+
+	sendToNextPeer()
+	var errs []error
+	for inFlight > 0 {
+		var hedge <-chan time.Time
+		if isRead && r.config.HedgeDelay > 0 && next < len(peers) {
+			hedge = time.After(r.config.HedgeDelay)
+		}
+
+		select {
+		case res := <-results:
+			inFlight--
+			if res.err == nil {
+				return res.response, nil
+			}
+			errs = append(errs, res.err)
+			if inFlight == 0 && next < len(peers) {
+				sendToNextPeer()
+			}
+		case <-hedge:
+			sendToNextPeer()
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// refersToHistoricalBlock checks whether the request refers to a particular block rather than the latest one.
+func refersToHistoricalBlock(args []any) bool {
+	isHistorical := func(ref rawapitypes.BlockReference) bool {
+		return ref.Type() != rawapitypes.NamedBlockIdentifierReference ||
+			ref.NamedBlockIdentifier() == rawapitypes.EarliestBlock
+	}
+
+	for _, arg := range args {
+		switch arg := arg.(type) {
+		case rawapitypes.BlockReference:
+			if isHistorical(arg) {
+				return true
+			}
+		case rawapitypes.BlockReferenceOrHashWithChildren:
+			if !arg.IsReference() || isHistorical(arg.Reference()) {
+				return true
+			}
+		}
+	}
+	return false
+}
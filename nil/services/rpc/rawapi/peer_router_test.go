@@ -0,0 +1,203 @@
+package rawapi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type peerRouterTestApi interface {
+	Read(ctx context.Context) error
+}
+
+type failedPeers struct {
+	mu    sync.Mutex
+	peers []network.PeerID
+}
+
+func (f *failedPeers) report(peer network.PeerID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.peers = append(f.peers, peer)
+}
+
+func (f *failedPeers) get() []network.PeerID {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.peers
+}
+
+var (
+	peerA = network.PeerID("a")
+	peerB = network.PeerID("b")
+	peerC = network.PeerID("c")
+)
+
+func TestPeerRouterOrder(t *testing.T) {
+	t.Parallel()
+
+	router := newPeerRouter(
+		&NetworkRoutingConfig{ArchivePeers: []network.PeerID{peerC}},
+		func(network.PeerID) {},
+		reflect.TypeFor[peerRouterTestApi]())
+	peers := []network.PeerID{peerA, peerB, peerC}
+
+	// Peers without statistics keep their order
+	assert.Equal(t, peers, router.orderPeers(peers, false))
+
+	router.reportSuccess(peerA, 100*time.Millisecond)
+	router.reportSuccess(peerB, 10*time.Millisecond)
+	router.reportSuccess(peerC, 50*time.Millisecond)
+	assert.Equal(t, []network.PeerID{peerB, peerC, peerA}, router.orderPeers(peers, false))
+
+	router.reportFailure(peerB)
+	assert.Equal(t, []network.PeerID{peerC, peerA, peerB}, router.orderPeers(peers, false))
+
+	router.reportSuccess(peerB, 10*time.Millisecond)
+	assert.Equal(t, []network.PeerID{peerB, peerC, peerA}, router.orderPeers(peers, false))
+
+	// The archive peer goes first for historical requests
+	assert.Equal(t, []network.PeerID{peerC, peerB, peerA}, router.orderPeers(peers, true))
+}
+
+func TestPeerRouterSend(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("failed")
+	response := []byte("response")
+
+	type call struct {
+		peer network.PeerID
+		ctx  context.Context
+	}
+	newRequest := func(delays map[network.PeerID]time.Duration, failing ...network.PeerID) (
+		func(ctx context.Context, peer network.PeerID) ([]byte, error), func() []network.PeerID,
+	) {
+		var mu sync.Mutex
+		var calls []call
+		doRequest := func(ctx context.Context, peer network.PeerID) ([]byte, error) {
+			mu.Lock()
+			calls = append(calls, call{peer, ctx})
+			mu.Unlock()
+
+			select {
+			case <-time.After(delays[peer]):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			for _, p := range failing {
+				if p == peer {
+					return nil, errFailed
+				}
+			}
+			return append([]byte(nil), response...), nil
+		}
+		calledPeers := func() []network.PeerID {
+			mu.Lock()
+			defer mu.Unlock()
+			res := make([]network.PeerID, len(calls))
+			for i, c := range calls {
+				res[i] = c.peer
+			}
+			return res
+		}
+		return doRequest, calledPeers
+	}
+
+	peers := []network.PeerID{peerA, peerB, peerC}
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+
+		failed := &failedPeers{}
+		router := newPeerRouter(
+			&NetworkRoutingConfig{MaxAttempts: 3}, failed.report, reflect.TypeFor[peerRouterTestApi]())
+		doRequest, calledPeers := newRequest(nil, peerA, peerB)
+
+		res, err := router.send(t.Context(), "Read", nil, peers, doRequest)
+		require.NoError(t, err)
+		assert.Equal(t, response, res)
+		assert.Equal(t, peers, calledPeers())
+		assert.Equal(t, []network.PeerID{peerA, peerB}, failed.get())
+
+		// Failed peers are tried last
+		doRequest, calledPeers = newRequest(nil)
+		_, err = router.send(t.Context(), "Read", nil, peers, doRequest)
+		require.NoError(t, err)
+		assert.Equal(t, []network.PeerID{peerC}, calledPeers())
+	})
+
+	t.Run("MaxAttempts", func(t *testing.T) {
+		t.Parallel()
+
+		router := newPeerRouter(
+			&NetworkRoutingConfig{MaxAttempts: 2}, func(network.PeerID) {}, reflect.TypeFor[peerRouterTestApi]())
+		doRequest, calledPeers := newRequest(nil, peerA, peerB)
+
+		_, err := router.send(t.Context(), "Read", nil, peers, doRequest)
+		require.ErrorIs(t, err, errFailed)
+		assert.Equal(t, []network.PeerID{peerA, peerB}, calledPeers())
+	})
+
+	t.Run("WriteIsNotRetried", func(t *testing.T) {
+		t.Parallel()
+
+		router := newPeerRouter(
+			&NetworkRoutingConfig{MaxAttempts: 3}, func(network.PeerID) {}, reflect.TypeFor[peerRouterTestApi]())
+		doRequest, calledPeers := newRequest(nil, peerA)
+
+		_, err := router.send(t.Context(), "Write", nil, peers, doRequest)
+		require.ErrorIs(t, err, errFailed)
+		assert.Equal(t, []network.PeerID{peerA}, calledPeers())
+	})
+
+	t.Run("Hedge", func(t *testing.T) {
+		t.Parallel()
+
+		failed := &failedPeers{}
+		router := newPeerRouter(
+			&NetworkRoutingConfig{MaxAttempts: 3, HedgeDelay: 10 * time.Millisecond},
+			failed.report,
+			reflect.TypeFor[peerRouterTestApi]())
+		doRequest, calledPeers := newRequest(map[network.PeerID]time.Duration{peerA: time.Minute})
+
+		res, err := router.send(t.Context(), "Read", nil, peers, doRequest)
+		require.NoError(t, err)
+		assert.Equal(t, response, res)
+		assert.Equal(t, []network.PeerID{peerA, peerB}, calledPeers())
+
+		// The cancelled request doesn't affect the reputation
+		assert.Empty(t, failed.get())
+	})
+}
+
+func TestRefersToHistoricalBlock(t *testing.T) {
+	t.Parallel()
+
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	earliest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.EarliestBlock)
+	number := rawapitypes.BlockNumberAsBlockReference(types.BlockNumber(10))
+	hash := rawapitypes.BlockHashAsBlockReference(common.EmptyHash)
+
+	assert.False(t, refersToHistoricalBlock(nil))
+	assert.False(t, refersToHistoricalBlock([]any{types.EmptyAddress, latest}))
+	assert.False(t, refersToHistoricalBlock([]any{
+		rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(latest),
+	}))
+	assert.True(t, refersToHistoricalBlock([]any{earliest}))
+	assert.True(t, refersToHistoricalBlock([]any{types.EmptyAddress, number}))
+	assert.True(t, refersToHistoricalBlock([]any{hash}))
+	assert.True(t, refersToHistoricalBlock([]any{
+		rawapitypes.BlockHashWithChildrenAsBlockReferenceOrHashWithChildren(common.EmptyHash, nil),
+	}))
+}
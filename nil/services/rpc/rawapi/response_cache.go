@@ -0,0 +1,275 @@
+package rawapi
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// Prefixes of the response cache keys
+const (
+	blockResponseCachePrefix   byte = 'b' // block hash -> block data
+	receiptResponseCachePrefix byte = 'r' // transaction hash -> receipt
+	codeRefResponseCachePrefix byte = 'a' // block hash | address -> code hash
+	codeResponseCachePrefix    byte = 'c' // code hash -> code
+)
+
+// ResponseCacheConfig configures caching of the responses of shards served by other nodes.
+// Only responses which can never change are cached: blocks and code requested by block hash
+// and receipts of transactions included in the main chain.
+type ResponseCacheConfig struct {
+	// Size is the maximum number of responses kept in memory per shard. Zero disables the cache.
+	Size int `yaml:"size,omitempty"`
+	// Persistent enables keeping the responses in the local database, so that they survive restarts.
+	// The database grows with the amount of history requested, since persistent responses are never evicted.
+	Persistent bool `yaml:"persistent,omitempty"`
+}
+
+func NewDefaultResponseCacheConfig() *ResponseCacheConfig {
+	return &ResponseCacheConfig{
+		Size: 10_000,
+	}
+}
+
+// ResponseCache keeps encoded immutable responses of a shard.
+type ResponseCache struct {
+	shardId  types.ShardId
+	memory   *lru.Cache[string, []byte]
+	database db.DB // nil if the cache is not persistent
+
+	hits   telemetry.Counter
+	misses telemetry.Counter
+}
+
+// NewResponseCache creates a cache of the shard responses, database is used only by persistent caches.
+// It returns nil if caching is disabled.
+func NewResponseCache(shardId types.ShardId, config *ResponseCacheConfig, database db.DB) (*ResponseCache, error) {
+	if config == nil || config.Size <= 0 {
+		return nil, nil
+	}
+
+	memory, err := lru.New[string, []byte](config.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	meter := telemetry.NewMeter("github.com/NilFoundation/nil/nil/services/rpc/rawapi")
+	hits, err := meter.Int64Counter("response_cache_hits")
+	if err != nil {
+		return nil, err
+	}
+	misses, err := meter.Int64Counter("response_cache_misses")
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &ResponseCache{
+		shardId: shardId,
+		memory:  memory,
+		hits:    hits,
+		misses:  misses,
+	}
+	if config.Persistent {
+		check.PanicIfNot(database != nil)
+		cache.database = database
+	}
+	return cache, nil
+}
+
+func (c *ResponseCache) get(ctx context.Context, key []byte) ([]byte, bool) {
+	if value, ok := c.memory.Get(string(key)); ok {
+		return value, true
+	}
+	if c.database == nil {
+		return nil, false
+	}
+
+	tx, err := c.database.CreateRoTx(ctx)
+	if err != nil {
+		return nil, false
+	}
+	defer tx.Rollback()
+
+	value, err := tx.GetFromShard(c.shardId, db.RpcResponseCacheTable, key)
+	if err != nil {
+		return nil, false
+	}
+	c.memory.Add(string(key), value)
+	return value, true
+}
+
+func (c *ResponseCache) add(ctx context.Context, key []byte, value []byte) {
+	c.memory.Add(string(key), value)
+	if c.database == nil {
+		return
+	}
+
+	// Failing to persist a response is not critical: it will be requested from the network once again
+	tx, err := c.database.CreateRwTx(ctx)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	if err := tx.PutToShard(c.shardId, db.RpcResponseCacheTable, key, value); err != nil {
+		return
+	}
+	_ = tx.Commit()
+}
+
+func (c *ResponseCache) countLookup(ctx context.Context, methodName string, hit bool) {
+	counter := c.misses
+	if hit {
+		counter = c.hits
+	}
+	counter.Add(ctx, 1, telattr.With(telattr.ShardId(c.shardId), telattr.RpcMethod(methodName)))
+}
+
+func makeResponseCacheKey(prefix byte, parts ...[]byte) []byte {
+	key := []byte{prefix}
+	for _, part := range parts {
+		key = append(key, part...)
+	}
+	return key
+}
+
+// makeCachingDoApiRequestFunction returns a function which serves immutable responses from the cache
+// and performs the request with doApiRequest otherwise.
+func makeCachingDoApiRequestFunction(cache *ResponseCache, doApiRequest doApiRequestFunction) doApiRequestFunction {
+	if cache == nil {
+		return doApiRequest
+	}
+
+	return func(codec *methodCodec, methodName string, ctx context.Context, args ...any) ([]byte, error) {
+		switch methodName {
+		case "GetFullBlockData":
+			blockReference, ok := args[0].(rawapitypes.BlockReference)
+			check.PanicIfNot(ok)
+			if blockReference.Type() != rawapitypes.HashBlockReference {
+				break
+			}
+			key := makeResponseCacheKey(blockResponseCachePrefix, blockReference.Hash().Bytes())
+			return cache.getOrRequest(ctx, key, methodName, func() ([]byte, bool, error) {
+				response, err := doApiRequest(codec, methodName, ctx, args...)
+				if err != nil {
+					return nil, false, err
+				}
+				_, err = unpackResponse[*types.RawBlockWithExtractedData](codec, response)
+				return response, err == nil, nil
+			})
+
+		case "GetInTransactionReceipt":
+			hash, ok := args[0].(common.Hash)
+			check.PanicIfNot(ok)
+			key := makeResponseCacheKey(receiptResponseCachePrefix, hash.Bytes())
+			return cache.getOrRequest(ctx, key, methodName, func() ([]byte, bool, error) {
+				response, err := doApiRequest(codec, methodName, ctx, args...)
+				if err != nil {
+					return nil, false, err
+				}
+				receipt, err := unpackResponse[*rawapitypes.ReceiptInfo](codec, response)
+				return response, err == nil && isReceiptFinal(receipt), nil
+			})
+
+		case "GetCode":
+			address, ok := args[0].(types.Address)
+			check.PanicIfNot(ok)
+			blockReference, ok := args[1].(rawapitypes.BlockReference)
+			check.PanicIfNot(ok)
+			if blockReference.Type() != rawapitypes.HashBlockReference {
+				break
+			}
+			return cache.getCodeOrRequest(ctx, address, blockReference.Hash(), func() ([]byte, types.Code, error) {
+				response, err := doApiRequest(codec, methodName, ctx, args...)
+				if err != nil {
+					return nil, nil, err
+				}
+				code, err := unpackResponse[types.Code](codec, response)
+				if err != nil {
+					return response, nil, nil
+				}
+				return response, code, nil
+			})
+		}
+
+		return doApiRequest(codec, methodName, ctx, args...)
+	}
+}
+
+// getOrRequest returns the cached response or performs the request and caches its response if it's immutable.
+func (c *ResponseCache) getOrRequest(
+	ctx context.Context,
+	key []byte,
+	methodName string,
+	request func() (response []byte, immutable bool, err error),
+) ([]byte, error) {
+	if response, ok := c.get(ctx, key); ok {
+		c.countLookup(ctx, methodName, true)
+		return response, nil
+	}
+	c.countLookup(ctx, methodName, false)
+
+	response, immutable, err := request()
+	if err != nil {
+		return nil, err
+	}
+	if immutable {
+		c.add(ctx, key, response)
+	}
+	return response, nil
+}
+
+// getCodeOrRequest caches code by its hash, so that the code of a contract is stored once
+// no matter how many blocks it's requested at. The request returns nil code for error responses.
+func (c *ResponseCache) getCodeOrRequest(
+	ctx context.Context,
+	address types.Address,
+	blockHash common.Hash,
+	request func() ([]byte, types.Code, error),
+) ([]byte, error) {
+	const methodName = "GetCode"
+
+	refKey := makeResponseCacheKey(codeRefResponseCachePrefix, blockHash.Bytes(), address.Bytes())
+	if codeHash, ok := c.get(ctx, refKey); ok {
+		if response, ok := c.get(ctx, makeResponseCacheKey(codeResponseCachePrefix, codeHash)); ok {
+			c.countLookup(ctx, methodName, true)
+			return response, nil
+		}
+	}
+	c.countLookup(ctx, methodName, false)
+
+	response, code, err := request()
+	if err != nil {
+		return nil, err
+	}
+	if code != nil {
+		codeHash := code.Hash().Bytes()
+		c.add(ctx, makeResponseCacheKey(codeResponseCachePrefix, codeHash), response)
+		c.add(ctx, refKey, codeHash)
+	}
+	return response, nil
+}
+
+// isReceiptFinal checks that the receipt and the receipts of all the transactions it has produced
+// are included in the main chain, so the receipt won't change anymore.
+func isReceiptFinal(receipt *rawapitypes.ReceiptInfo) bool {
+	if receipt == nil || receipt.Temporary || !receipt.IncludedInMain || receipt.BlockHash == common.EmptyHash {
+		return false
+	}
+	if len(receipt.OutReceipts) != len(receipt.OutTransactions) {
+		return false
+	}
+	for _, outReceipt := range receipt.OutReceipts {
+		if !isReceiptFinal(outReceipt) {
+			return false
+		}
+	}
+	return true
+}
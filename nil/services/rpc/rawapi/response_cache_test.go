@@ -0,0 +1,166 @@
+package rawapi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type responseCacheTestClient struct {
+	codec    apiCodec
+	requests map[string]int
+	results  map[string]any
+}
+
+func newResponseCacheTestClient(t *testing.T) *responseCacheTestClient {
+	t.Helper()
+
+	codec, err := newApiCodec(reflect.TypeFor[ShardApi](), reflect.TypeFor[NetworkTransportProtocol]())
+	require.NoError(t, err)
+	return &responseCacheTestClient{
+		codec:    codec,
+		requests: make(map[string]int),
+		results:  make(map[string]any),
+	}
+}
+
+func (c *responseCacheTestClient) doApiRequest(
+	codec *methodCodec, methodName string, _ context.Context, _ ...any,
+) ([]byte, error) {
+	c.requests[methodName]++
+	result := c.results[methodName]
+	if err, ok := result.(error); ok {
+		return codec.packError(err), nil
+	}
+	return codec.packResponse(reflect.ValueOf(result), reflect.Zero(reflect.TypeFor[error]()))
+}
+
+func (c *responseCacheTestClient) request(
+	t *testing.T, doApiRequest doApiRequestFunction, methodName string, args ...any,
+) []byte {
+	t.Helper()
+
+	response, err := doApiRequest(c.codec[methodName], methodName, t.Context(), args...)
+	require.NoError(t, err)
+	return response
+}
+
+func TestResponseCache(t *testing.T) {
+	t.Parallel()
+
+	blockHash := common.HexToHash("0x01")
+	byHash := rawapitypes.BlockHashAsBlockReference(blockHash)
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	address := types.ShardAndHexToAddress(types.BaseShardId, "0x02")
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewResponseCache(types.BaseShardId, &ResponseCacheConfig{}, nil)
+		require.NoError(t, err)
+		assert.Nil(t, cache)
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		t.Parallel()
+
+		client := newResponseCacheTestClient(t)
+		client.results["GetFullBlockData"] = &types.RawBlockWithExtractedData{Block: []byte{1}}
+		cache, err := NewResponseCache(types.BaseShardId, NewDefaultResponseCacheConfig(), nil)
+		require.NoError(t, err)
+		doApiRequest := makeCachingDoApiRequestFunction(cache, client.doApiRequest)
+
+		first := client.request(t, doApiRequest, "GetFullBlockData", byHash)
+		second := client.request(t, doApiRequest, "GetFullBlockData", byHash)
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, client.requests["GetFullBlockData"])
+
+		// Blocks referred to by anything but hash may change
+		client.request(t, doApiRequest, "GetFullBlockData", latest)
+		client.request(t, doApiRequest, "GetFullBlockData", latest)
+		assert.Equal(t, 3, client.requests["GetFullBlockData"])
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+
+		client := newResponseCacheTestClient(t)
+		client.results["GetFullBlockData"] = errors.New("not found")
+		cache, err := NewResponseCache(types.BaseShardId, NewDefaultResponseCacheConfig(), nil)
+		require.NoError(t, err)
+		doApiRequest := makeCachingDoApiRequestFunction(cache, client.doApiRequest)
+
+		client.request(t, doApiRequest, "GetFullBlockData", byHash)
+		client.request(t, doApiRequest, "GetFullBlockData", byHash)
+		assert.Equal(t, 2, client.requests["GetFullBlockData"])
+	})
+
+	t.Run("Receipt", func(t *testing.T) {
+		t.Parallel()
+
+		client := newResponseCacheTestClient(t)
+		receipt := &rawapitypes.ReceiptInfo{
+			ReceiptSSZ:      []byte{1},
+			BlockHash:       blockHash,
+			IncludedInMain:  true,
+			OutTransactions: []common.Hash{common.HexToHash("0x03")},
+			OutReceipts:     []*rawapitypes.ReceiptInfo{nil},
+		}
+		client.results["GetInTransactionReceipt"] = receipt
+		cache, err := NewResponseCache(types.BaseShardId, NewDefaultResponseCacheConfig(), nil)
+		require.NoError(t, err)
+		doApiRequest := makeCachingDoApiRequestFunction(cache, client.doApiRequest)
+
+		// The outgoing transaction isn't processed yet
+		txnHash := common.HexToHash("0x04")
+		client.request(t, doApiRequest, "GetInTransactionReceipt", txnHash)
+		client.request(t, doApiRequest, "GetInTransactionReceipt", txnHash)
+		assert.Equal(t, 2, client.requests["GetInTransactionReceipt"])
+
+		receipt.OutReceipts = []*rawapitypes.ReceiptInfo{
+			{ReceiptSSZ: []byte{2}, BlockHash: blockHash, IncludedInMain: true},
+		}
+		client.request(t, doApiRequest, "GetInTransactionReceipt", txnHash)
+		client.request(t, doApiRequest, "GetInTransactionReceipt", txnHash)
+		assert.Equal(t, 3, client.requests["GetInTransactionReceipt"])
+	})
+
+	t.Run("Code", func(t *testing.T) {
+		t.Parallel()
+
+		client := newResponseCacheTestClient(t)
+		client.results["GetCode"] = types.Code{1, 2, 3}
+		database, err := db.NewBadgerDbInMemory()
+		require.NoError(t, err)
+		defer database.Close()
+
+		config := &ResponseCacheConfig{Size: 10, Persistent: true}
+		cache, err := NewResponseCache(types.BaseShardId, config, database)
+		require.NoError(t, err)
+		doApiRequest := makeCachingDoApiRequestFunction(cache, client.doApiRequest)
+
+		first := client.request(t, doApiRequest, "GetCode", address, byHash)
+		assert.Equal(t, first, client.request(t, doApiRequest, "GetCode", address, byHash))
+		assert.Equal(t, 1, client.requests["GetCode"])
+
+		// The code of the same contract is stored once for all the blocks
+		otherBlock := rawapitypes.BlockHashAsBlockReference(common.HexToHash("0x05"))
+		client.request(t, doApiRequest, "GetCode", address, otherBlock)
+		assert.Equal(t, 2, client.requests["GetCode"])
+
+		// Persistent responses survive restarts
+		cache, err = NewResponseCache(types.BaseShardId, config, database)
+		require.NoError(t, err)
+		doApiRequest = makeCachingDoApiRequestFunction(cache, client.doApiRequest)
+		assert.Equal(t, first, client.request(t, doApiRequest, "GetCode", address, otherBlock))
+		assert.Equal(t, 2, client.requests["GetCode"])
+	})
+}
@@ -23,14 +23,19 @@ type NetworkTransportProtocolRo interface {
 
 	GetInTransaction(pb.TransactionRequest) pb.TransactionResponse
 	GetInTransactionReceipt(pb.Hash) pb.ReceiptResponse
+	GetTransactionsByAddress(pb.AddressTransactionsRequest) pb.AddressTransactionsResponse
 
 	GetBalance(request pb.AccountRequest) pb.BalanceResponse
 	GetCode(request pb.AccountRequest) pb.CodeResponse
 	GetTokens(request pb.AccountRequest) pb.TokensResponse
 	GetTransactionCount(pb.AccountRequest) pb.Uint64Response
 	GetContract(request pb.AccountRequest) pb.RawContractResponse
+	GetStateDiff(request pb.BlockRequest) pb.StateDiffResponse
+	GetTransactionStateDiff(pb.Hash) pb.StateDiffResponse
 
 	Call(pb.CallRequest) pb.CallResponse
+	SimulateCall(pb.CallRequest) pb.CallResponse
+	EstimateFee(pb.CallRequest) pb.CallResponse
 
 	GasPrice() pb.GasPriceResponse
 	GetShardIdList() pb.ShardIdListResponse
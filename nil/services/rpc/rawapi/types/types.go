@@ -152,3 +152,23 @@ type SmartContract struct {
 	Tokens       map[types.TokenId]types.Value
 	AsyncContext map[types.TransactionIndex]types.AsyncContext
 }
+
+type AddressTransactionsRequest struct {
+	Address   types.Address
+	FromBlock types.BlockNumber
+	ToBlock   types.BlockNumber
+	Limit     uint64
+	Cursor    []byte
+}
+
+type AddressTransaction struct {
+	Hash     common.Hash
+	BlockId  types.BlockNumber
+	Index    types.TransactionIndex
+	Outbound bool
+}
+
+type AddressTransactions struct {
+	Transactions []AddressTransaction
+	NextCursor   []byte
+}
@@ -27,6 +27,13 @@ func StartRpcServer(
 
 	defer srv.Stop()
 
+	if cfg.RateLimit != nil {
+		if err := cfg.RateLimit.Validate(); err != nil {
+			return fmt.Errorf("invalid RPC rate limit config: %w", err)
+		}
+		srv.SetRateLimit(cfg.RateLimit)
+	}
+
 	var defaultAPIList []transport.API
 
 	for _, api := range rpcAPI {
@@ -41,11 +48,16 @@ func StartRpcServer(
 
 	httpEndpoint := cfg.HttpURL
 
-	basicHttpSrv := http.NewServer(srv, rpccfg.ContentType, rpccfg.AcceptedContentTypes)
-	var httpHandler net_http.Handler = basicHttpSrv
+	var httpHandler net_http.Handler = http.NewServer(srv, rpccfg.ContentType, rpccfg.AcceptedContentTypes)
+	if cfg.Auth != nil {
+		var err error
+		if httpHandler, err = http.NewAuthHandler(cfg.Auth, httpHandler); err != nil {
+			return fmt.Errorf("could not configure RPC authentication: %w", err)
+		}
+	}
 	if !strings.HasPrefix(httpEndpoint, "unix://") {
 		httpHandler = http.NewHTTPHandlerStack(
-			basicHttpSrv,
+			httpHandler,
 			cfg.HttpCORSDomain,
 			nil,
 			cfg.HttpCompression)
@@ -4,6 +4,7 @@ import "fmt"
 
 var (
 	_ Error = new(methodNotFoundError)
+	_ Error = new(methodNotAllowedError)
 	_ Error = new(parseError)
 	_ Error = new(invalidRequestError)
 	_ Error = new(invalidMessageError)
@@ -21,6 +22,15 @@ func (e *methodNotFoundError) Error() string {
 	return fmt.Sprintf("the method %s does not exist/is not available", e.method)
 }
 
+// the caller is not allowed to call the method
+type methodNotAllowedError struct{ method string }
+
+func (e *methodNotAllowedError) ErrorCode() int { return -32006 }
+
+func (e *methodNotAllowedError) Error() string {
+	return fmt.Sprintf("the method %s is not allowed", e.method)
+}
+
 // Invalid JSON was received by the server.
 type parseError struct{ message string }
 
@@ -14,6 +14,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
+	nil_http "github.com/NilFoundation/nil/nil/services/rpc/internal/http"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport/rpccfg"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/rs/zerolog"
@@ -36,6 +37,7 @@ type handler struct {
 
 	maxBatchConcurrency uint
 	traceRequests       bool
+	rateLimiter         *rateLimiter // nil if rate limiting is disabled
 
 	// slow requests
 	slowLogThreshold time.Duration
@@ -86,6 +88,7 @@ func newHandler(
 	logger logging.Logger,
 	rpcSlowLogThreshold time.Duration,
 	mh *metricsHandler,
+	rateLimiter *rateLimiter,
 ) *handler {
 	rootCtx, cancelRoot := context.WithCancel(connCtx)
 
@@ -99,6 +102,7 @@ func newHandler(
 
 		maxBatchConcurrency: maxBatchConcurrency,
 		traceRequests:       traceRequests,
+		rateLimiter:         rateLimiter,
 
 		slowLogThreshold:  rpcSlowLogThreshold,
 		slowLogBlacklist:  rpccfg.SlowLogBlackList,
@@ -257,6 +261,14 @@ func (h *handler) handleCallMsg(ctx context.Context, msg *Message, stream *jsoni
 
 // handleCall processes method calls.
 func (h *handler) handleCall(ctx context.Context, msg *Message, stream *jsoniter.Stream) *Message {
+	if !nil_http.IsMethodAllowed(ctx, msg.Method) {
+		return msg.errorResponse(&methodNotAllowedError{method: msg.Method})
+	}
+	if h.rateLimiter != nil {
+		if err := h.rateLimiter.take(ctx, msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 	callb := h.reg.callback(msg.Method)
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
@@ -0,0 +1,101 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
+	nil_http "github.com/NilFoundation/nil/nil/services/rpc/internal/http"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport/rpccfg"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/time/rate"
+)
+
+// maxRateLimitedClients bounds the number of tracked clients, the least recently seen ones are forgotten
+const maxRateLimitedClients = 10_000
+
+// rateLimiter charges calls of each client to its token bucket.
+type rateLimiter struct {
+	rate    rate.Limit
+	burst   int
+	costs   map[string]int
+	clients *lru.Cache[string, *rate.Limiter]
+}
+
+func newRateLimiter(cfg *httpcfg.RateLimitCfg) *rateLimiter {
+	costs := maps.Clone(rpccfg.DefaultMethodCosts)
+	maps.Copy(costs, cfg.MethodCosts)
+
+	clients, err := lru.New[string, *rate.Limiter](maxRateLimitedClients)
+	check.PanicIfErr(err)
+
+	return &rateLimiter{
+		rate:    rate.Limit(cfg.Rate),
+		burst:   cfg.Burst,
+		costs:   costs,
+		clients: clients,
+	}
+}
+
+func (l *rateLimiter) methodCost(method string) int {
+	if cost, ok := l.costs[method]; ok {
+		return cost
+	}
+	return 1
+}
+
+func (l *rateLimiter) clientLimiter(clientId string) *rate.Limiter {
+	if limiter, ok := l.clients.Get(clientId); ok {
+		return limiter
+	}
+	limiter := rate.NewLimiter(l.rate, l.burst)
+	if prev, ok, _ := l.clients.PeekOrAdd(clientId, limiter); ok {
+		return prev
+	}
+	return limiter
+}
+
+// take charges the cost of the method to the caller of the request.
+// It returns an error with the hint when to retry if the caller has exceeded its rate.
+func (l *rateLimiter) take(ctx context.Context, method string) error {
+	cost := l.methodCost(method)
+	now := time.Now()
+	reservation := l.clientLimiter(nil_http.ClientId(ctx)).ReserveN(now, cost)
+	if !reservation.OK() {
+		return &rateLimitError{
+			message: fmt.Sprintf("the cost %d of the method %s exceeds the limit %d", cost, method, l.burst),
+		}
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return &rateLimitError{message: "rate limit exceeded", retryAfter: delay}
+	}
+	return nil
+}
+
+var (
+	_ Error     = new(rateLimitError)
+	_ DataError = new(rateLimitError)
+)
+
+type rateLimitError struct {
+	message    string
+	retryAfter time.Duration // zero if the request can't succeed at all
+}
+
+// ErrorCode returns the "limit exceeded" code of EIP-1474.
+func (e *rateLimitError) ErrorCode() int { return -32005 }
+
+func (e *rateLimitError) Error() string { return e.message }
+
+// ErrorData returns the number of seconds to wait before retrying the request.
+func (e *rateLimitError) ErrorData() interface{} {
+	if e.retryAfter == 0 {
+		return nil
+	}
+	return map[string]int64{"retryAfter": int64(math.Ceil(e.retryAfter.Seconds()))}
+}
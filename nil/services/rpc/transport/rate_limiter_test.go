@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
+	nil_http "github.com/NilFoundation/nil/nil/services/rpc/internal/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	limiter := newRateLimiter(&httpcfg.RateLimitCfg{
+		Rate:        0.001,
+		Burst:       20,
+		MethodCosts: map[string]int{"test_heavy": 8, "test_huge": 100},
+	})
+	assert.Equal(t, 10, limiter.methodCost("eth_call"))
+	assert.Equal(t, 8, limiter.methodCost("test_heavy"))
+	assert.Equal(t, 1, limiter.methodCost("eth_getBalance"))
+
+	ctx1 := nil_http.WithClientId(t.Context(), "client1")
+	ctx2 := nil_http.WithClientId(t.Context(), "client2")
+
+	require.NoError(t, limiter.take(ctx1, "test_heavy"))
+	require.NoError(t, limiter.take(ctx1, "test_heavy"))
+
+	// The bucket is exhausted, the retry hint is the time to refill it
+	var rateErr *rateLimitError
+	require.ErrorAs(t, limiter.take(ctx1, "test_heavy"), &rateErr)
+	assert.Equal(t, -32005, rateErr.ErrorCode())
+	assert.Equal(t, map[string]int64{"retryAfter": 4000}, rateErr.ErrorData())
+
+	// Cheap calls still fit into the bucket since the failed call took nothing
+	for range 4 {
+		require.NoError(t, limiter.take(ctx1, "test_cheap"))
+	}
+	require.Error(t, limiter.take(ctx1, "test_cheap"))
+
+	// Other clients have their own buckets
+	require.NoError(t, limiter.take(ctx2, "test_heavy"))
+
+	// A call which exceeds the burst never succeeds
+	require.ErrorAs(t, limiter.take(ctx2, "test_huge"), &rateErr)
+	assert.Nil(t, rateErr.ErrorData())
+}
+
+type rateLimitTestService struct{}
+
+func (s *rateLimitTestService) Ping(_ context.Context) (string, error) {
+	return "pong", nil
+}
+
+func TestServerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(false, false, logging.NewLogger("Test server"), 0, nil)
+	require.NoError(t, server.RegisterName("test", &rateLimitTestService{}))
+	server.SetRateLimit(&httpcfg.RateLimitCfg{
+		Rate:         0.001,
+		Burst:        2,
+		MaxBatchSize: 3,
+	})
+
+	serve := func(clientId, body string) string {
+		t.Helper()
+
+		request := httptest.NewRequest(http.MethodPost, "http://url.com", strings.NewReader(body))
+		recorder := httptest.NewRecorder()
+		server.ServeSingleRequest(nil_http.WithClientId(t.Context(), clientId), request, recorder)
+		return recorder.Body.String()
+	}
+
+	call := `{"jsonrpc":"2.0","id":1,"method":"test_ping","params":[]}`
+	batch := func(n int) string {
+		return "[" + strings.TrimSuffix(strings.Repeat(call+",", n), ",") + "]"
+	}
+
+	assert.Contains(t, serve("client1", call), `"result":"pong"`)
+
+	response := serve("client1", batch(2))
+	assert.Contains(t, response, `"result":"pong"`)
+	assert.Contains(t, response, `"error":{"code":-32005,"message":"rate limit exceeded","data":{"retryAfter":1000}}`)
+
+	assert.Contains(t, serve("client2", batch(4)), "batch limit 3 exceeded")
+	assert.Contains(t, serve("client2", call), `"result":"pong"`)
+}
@@ -23,6 +23,23 @@ var SlowLogBlackList = []string{
 var HeavyLogMethods = map[string]struct{}{
 	"cometa_registerContract": {},
 	"eth_call":                {},
+	"eth_simulateCalls":       {},
 	"eth_estimateGas":         {},
 	"eth_sendRawTransaction":  {},
 }
+
+// DefaultMethodCosts are the rate limiting costs of methods heavier than a simple state read,
+// the cost of other methods is 1.
+var DefaultMethodCosts = map[string]int{
+	"eth_call":                     10,
+	"eth_simulateCalls":            20,
+	"eth_estimateFee":              10,
+	"eth_getTransactionsByAddress": 5,
+	"eth_feeHistory":               5,
+	"eth_maxPriorityFeePerGas":     2,
+	"eth_getFilterLogs":            5,
+	"eth_sendRawTransaction":       2,
+	"debug_getBlockByNumber":       2,
+	"debug_getBlockByHash":         2,
+	"debug_getContract":            5,
+}
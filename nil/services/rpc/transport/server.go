@@ -16,6 +16,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
 	nil_http "github.com/NilFoundation/nil/nil/services/rpc/internal/http"
 	mapset "github.com/deckarep/golang-set"
 )
@@ -49,6 +50,7 @@ type Server struct {
 	logger              logging.Logger
 	rpcSlowLogThreshold time.Duration
 	mh                  *metricsHandler
+	rateLimiter         *rateLimiter
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -98,6 +100,14 @@ func (s *Server) SetBatchLimit(limit int) {
 	s.batchLimit = limit
 }
 
+// SetRateLimit enables rate limiting of the calls of each client.
+func (s *Server) SetRateLimit(cfg *httpcfg.RateLimitCfg) {
+	s.rateLimiter = newRateLimiter(cfg)
+	if cfg.MaxBatchSize > 0 {
+		s.batchLimit = cfg.MaxBatchSize
+	}
+}
+
 func newHTTPServerConn(r *http.Request, w http.ResponseWriter) ServerCodec {
 	conn := &nil_http.HttpServerConn{Writer: w, Request: r}
 	// if the request is a GET request, and the body is empty, we turn the request into fake json rpc request, see below
@@ -157,7 +167,8 @@ func (s *Server) ServeSingleRequest(ctx context.Context, r *http.Request, w http
 		s.traceRequests,
 		s.logger,
 		s.rpcSlowLogThreshold,
-		s.mh)
+		s.mh,
+		s.rateLimiter)
 
 	reqs, batch, err := codec.Read()
 	if err != nil {
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"maps"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
@@ -79,3 +80,40 @@ func (overrides *StateOverrides) Override(state *execution.ExecutionState) error
 
 	return nil
 }
+
+// Merge applies the changes on top of the overrides, e.g., the state overrides returned by a call.
+// The storage of the changes is added to the overriding storage instead of replacing it.
+func (overrides StateOverrides) Merge(changes StateOverrides) {
+	for addr, change := range changes {
+		contract := overrides[addr]
+		if change.Seqno != nil {
+			contract.Seqno = change.Seqno
+		}
+		if change.ExtSeqno != nil {
+			contract.ExtSeqno = change.ExtSeqno
+		}
+		if change.Code != nil {
+			contract.Code = change.Code
+		}
+		if change.Balance != nil {
+			contract.Balance = change.Balance
+		}
+		if change.State != nil {
+			contract.State = change.State
+			contract.StateDiff = nil
+		}
+		if change.StateDiff != nil {
+			target := &contract.StateDiff
+			if contract.State != nil {
+				target = &contract.State
+			}
+			storage := make(map[common.Hash]common.Hash)
+			if *target != nil {
+				maps.Copy(storage, **target)
+			}
+			maps.Copy(storage, *change.StateDiff)
+			*target = &storage
+		}
+		overrides[addr] = contract
+	}
+}
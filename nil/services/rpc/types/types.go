@@ -101,6 +101,7 @@ type OutTransaction struct {
 	Error           string
 	Logs            []*types.Log
 	DebugLogs       []*types.DebugLog
+	MinFeeCredit    types.Value
 }
 
 type CallResWithGasPrice struct {
@@ -112,4 +113,7 @@ type CallResWithGasPrice struct {
 	BaseFee         types.Value
 	Logs            []*types.Log
 	DebugLogs       []*types.DebugLog
+	// MinFeeCredit is the minimal fee credit the transaction succeeds with, it's set only by the fee estimation.
+	// For an external transaction it includes the fee of its verification by the destination account.
+	MinFeeCredit types.Value
 }
@@ -37,20 +37,26 @@ type AggregatorBlockStorage interface {
 	TryGetLatestBatchId(ctx context.Context) (*types.BatchId, error)
 	SetBlockBatch(ctx context.Context, batch *types.BlockBatch) error
 	GetFreeSpaceBatchCount(ctx context.Context) (uint32, error)
+	SetBatchDataAvailability(ctx context.Context, batchId types.BatchId, mode types.DataAvailabilityMode) error
 }
 
 type AggregatorConfig struct {
-	RpcPollingInterval time.Duration
+	RpcPollingInterval   time.Duration
+	DataAvailabilityMode types.DataAvailabilityMode
 }
 
-func NewAggregatorConfig(rpcPollingInterval time.Duration) AggregatorConfig {
+func NewAggregatorConfig(
+	rpcPollingInterval time.Duration,
+	dataAvailabilityMode types.DataAvailabilityMode,
+) AggregatorConfig {
 	return AggregatorConfig{
-		RpcPollingInterval: rpcPollingInterval,
+		RpcPollingInterval:   rpcPollingInterval,
+		DataAvailabilityMode: dataAvailabilityMode,
 	}
 }
 
 func NewDefaultAggregatorConfig() AggregatorConfig {
-	return NewAggregatorConfig(time.Second)
+	return NewAggregatorConfig(time.Second, types.DataAvailabilityAuto)
 }
 
 type aggregator struct {
@@ -86,7 +92,7 @@ func NewAggregator(
 			blob.NewBuilder(),
 			nil, // TODO
 			logger,
-			batches.DefaultCommitOptions(),
+			batches.NewCommitOptions(config.DataAvailabilityMode),
 		),
 		resetter: resetter,
 		clock:    clock,
@@ -366,10 +372,15 @@ func (agg *aggregator) handleBlockBatch(ctx context.Context, batch *types.BlockB
 	}
 
 	prunedBatch := types.NewPrunedBatch(batch)
-	if err := agg.batchCommitter.Commit(ctx, prunedBatch); err != nil {
+	daMode, err := agg.batchCommitter.Commit(ctx, prunedBatch)
+	if err != nil {
 		return err
 	}
 
+	if err := agg.blockStorage.SetBatchDataAvailability(ctx, batch.Id, daMode); err != nil {
+		return fmt.Errorf("error storing data availability mode, batchId=%s: %w", batch.Id, err)
+	}
+
 	if err := agg.createProofTasks(ctx, batch); err != nil {
 		return fmt.Errorf("error creating proof tasks, latestMainHash=%s: %w", batch.LatestMainBlock().Hash, err)
 	}
@@ -48,7 +48,7 @@ func (s *AggregatorTestSuite) SetupSuite() {
 	s.Require().NoError(err)
 	clock := clockwork.NewRealClock()
 	s.blockStorage = s.newTestBlockStorage(storage.DefaultBlockStorageConfig())
-	s.taskStorage = storage.NewTaskStorage(s.db, clock, s.metrics, logger)
+	s.taskStorage = storage.NewTaskStorage(s.db, storage.DefaultTaskStorageConfig(), clock, s.metrics, logger)
 	s.rpcClientMock = &client.ClientMock{}
 
 	s.aggregator = s.newTestAggregator(s.blockStorage)
@@ -254,7 +254,7 @@ func (s *AggregatorTestSuite) Test_Latest_Fetched_Does_Not_Exist_On_Chain() {
 // requireNoNewTasks asserts that there are no new tasks available for execution
 func (s *AggregatorTestSuite) requireNoNewTasks() {
 	s.T().Helper()
-	task, err := s.taskStorage.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	task, err := s.taskStorage.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), nil)
 	s.Require().NoError(err)
 	s.Require().Nil(task, "expected no new tasks available for execution, but got one")
 }
@@ -278,7 +278,7 @@ func (s *AggregatorTestSuite) requireMainBlockHandled(mainBlock *scTypes.Block)
 	}
 
 	// one ProofBatch task created
-	taskToExecute, err := s.taskStorage.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	taskToExecute, err := s.taskStorage.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), nil)
 	s.Require().NoError(err)
 	s.Require().NotNil(taskToExecute)
 	s.Require().Equal(scTypes.ProofBatch, taskToExecute.TaskType)
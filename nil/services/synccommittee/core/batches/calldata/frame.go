@@ -0,0 +1,47 @@
+package calldata
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// FrameVersion is the version of the layout of batch data committed via calldata.
+// Must match CALLDATA_FRAME_VERSION of the NilRollup contract.
+const FrameVersion uint8 = 1
+
+// HeaderSize is the size of the frame header: the version followed by the big-endian length of the data
+const HeaderSize = 1 + 4
+
+var (
+	ErrInvalidFrameVersion = errors.New("invalid calldata frame version")
+	ErrInvalidFrameLength  = errors.New("invalid calldata frame length")
+)
+
+// NewFrame wraps the encoded batch into the versioned and length-prefixed frame,
+// so that the batch data is unambiguously extracted from the commit transaction input.
+func NewFrame(data []byte) ([]byte, error) {
+	if uint64(len(data)) > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("%w: data size %d exceeds frame limit", ErrInvalidFrameLength, len(data))
+	}
+	frame := make([]byte, 0, HeaderSize+len(data))
+	frame = append(frame, FrameVersion)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(data)))
+	return append(frame, data...), nil
+}
+
+// ReadFrame extracts the encoded batch from the frame, checking its version and length
+func ReadFrame(frame []byte) ([]byte, error) {
+	if len(frame) < HeaderSize {
+		return nil, fmt.Errorf("%w: frame size %d is less than header size", ErrInvalidFrameLength, len(frame))
+	}
+	if frame[0] != FrameVersion {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidFrameVersion, frame[0])
+	}
+	length := binary.BigEndian.Uint32(frame[1:HeaderSize])
+	if uint64(length) != uint64(len(frame)-HeaderSize) {
+		return nil, fmt.Errorf(
+			"%w: header declares %d bytes, frame carries %d", ErrInvalidFrameLength, length, len(frame)-HeaderSize)
+	}
+	return frame[HeaderSize:], nil
+}
@@ -0,0 +1,56 @@
+package calldata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrame(t *testing.T) {
+	t.Parallel()
+
+	data := []byte{0xDE, 0xFA, 0x01, 0x00, 0x42}
+
+	frame, err := NewFrame(data)
+	require.NoError(t, err)
+	require.Equal(t, []byte{FrameVersion, 0, 0, 0, 5}, frame[:HeaderSize])
+
+	read, err := ReadFrame(frame)
+	require.NoError(t, err)
+	require.Equal(t, data, read)
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		frame, err := NewFrame(nil)
+		require.NoError(t, err)
+		read, err := ReadFrame(frame)
+		require.NoError(t, err)
+		require.Empty(t, read)
+	})
+
+	t.Run("TooShort", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ReadFrame(frame[:HeaderSize-1])
+		require.ErrorIs(t, err, ErrInvalidFrameLength)
+	})
+
+	t.Run("InvalidVersion", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := append([]byte{FrameVersion + 1}, frame[1:]...)
+		_, err := ReadFrame(invalid)
+		require.ErrorIs(t, err, ErrInvalidFrameVersion)
+	})
+
+	t.Run("LengthMismatch", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ReadFrame(frame[:len(frame)-1])
+		require.ErrorIs(t, err, ErrInvalidFrameLength)
+
+		_, err = ReadFrame(append(frame, 0x00))
+		require.ErrorIs(t, err, ErrInvalidFrameLength)
+	})
+}
@@ -3,16 +3,21 @@ package batches
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"math/big"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/rollupcontract"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 )
 
 type BatchCommitter interface {
-	Commit(ctx context.Context, batch *types.PrunedBatch) error
+	// Commit publishes the batch to L1 and returns the data availability mode which was used for it
+	Commit(ctx context.Context, batch *types.PrunedBatch) (types.DataAvailabilityMode, error)
 }
 
 type batchEncoder interface {
@@ -25,12 +30,15 @@ type blobBuilder interface {
 
 type ethCommitter interface {
 	CommitBatch(ctx context.Context, blobs []kzg4844.Blob, batchIndex string) (*ethtypes.Transaction, error)
+	CommitBatchCalldata(ctx context.Context, batchData []byte, batchIndex string) (*ethtypes.Transaction, error)
+	EstimateBlobCommitCost(ctx context.Context, blobCount int) (*big.Int, error)
+	EstimateCalldataCommitCost(ctx context.Context, batchData []byte) (*big.Int, error)
 }
 
 type batchCommitter struct {
 	encoder      batchEncoder
-	blobBuilder  blobBuilder
 	ethCommitter ethCommitter
+	publishers   []daPublisher
 	logger       logging.Logger
 	options      *commitOptions
 }
@@ -44,38 +52,134 @@ func NewBatchCommitter(
 ) BatchCommitter {
 	return &batchCommitter{
 		encoder:      encoder,
-		blobBuilder:  blobBuilder,
 		ethCommitter: ethCommitter,
-		logger:       logger,
-		options:      options,
+		publishers: []daPublisher{
+			newBlobPublisher(blobBuilder, ethCommitter, options.maxBlobCount),
+			newCalldataPublisher(ethCommitter, options.maxCalldataSize),
+		},
+		logger:  logger,
+		options: options,
 	}
 }
 
 type commitOptions struct {
-	maxBlobCount int
+	maxBlobCount    int
+	maxCalldataSize int
+	daMode          types.DataAvailabilityMode
 }
 
-func DefaultCommitOptions() *commitOptions {
+func NewCommitOptions(daMode types.DataAvailabilityMode) *commitOptions {
 	return &commitOptions{
 		maxBlobCount: 6,
+		// keep the commit transaction below the default 128 KiB transaction size limit of L1 nodes
+		maxCalldataSize: 120 * 1024,
+		daMode:          daMode,
 	}
 }
 
-func (bc *batchCommitter) Commit(ctx context.Context, batch *types.PrunedBatch) error {
+func DefaultCommitOptions() *commitOptions {
+	return NewCommitOptions(types.DataAvailabilityAuto)
+}
+
+func (bc *batchCommitter) Commit(ctx context.Context, batch *types.PrunedBatch) (types.DataAvailabilityMode, error) {
 	var binTransactions bytes.Buffer
 	if err := bc.encoder.Encode(batch, &binTransactions); err != nil {
-		return err
+		return types.DataAvailabilityNone, err
 	}
 	bc.logger.Debug().Int("compressed_batch_len", binTransactions.Len()).Msg("encoded transaction")
 
-	blobs, err := bc.blobBuilder.MakeBlobs(&binTransactions, bc.options.maxBlobCount)
+	publisher, prepared, err := bc.selectPublisher(ctx, binTransactions.Bytes())
+	if err != nil {
+		return types.DataAvailabilityNone, err
+	}
+	bc.logger.Debug().
+		Stringer("da_mode", prepared.mode).
+		Int("batch_blob_count", len(prepared.blobs)).
+		Int("batch_calldata_len", len(prepared.calldata)).
+		Msg("packed batch data")
+
+	if bc.ethCommitter == nil {
+		// TODO remove when ethCommitter is passed by the aggregator
+		bc.logger.Warn().Stringer("da_mode", prepared.mode).Msg("L1 committer is not configured, batch is not sent")
+		return prepared.mode, nil
+	}
+
+	tx, err := publisher.Publish(ctx, prepared, batch.BatchId.String())
+	if errors.Is(err, rollupcontract.ErrBatchAlreadyCommitted) {
+		bc.logger.Warn().Stringer("da_mode", prepared.mode).Msg("batch is already committed, skipping commit tx")
+		return prepared.mode, nil
+	}
 	if err != nil {
-		return err
+		return types.DataAvailabilityNone, fmt.Errorf("failed to commit batch in %s mode: %w", prepared.mode, err)
 	}
-	bc.logger.Debug().Int("batch_blob_count", len(blobs)).Msg("packed batch blobs")
 
-	// TODO add ethCommiter.CommitBatch() call
-	bc.logger.Info().Int("blob_count", len(blobs)).Msg("committed batch")
+	bc.logger.Info().
+		Stringer("da_mode", prepared.mode).
+		Stringer("tx_hash", tx.Hash()).
+		Int("blob_count", len(prepared.blobs)).
+		Msg("committed batch")
+
+	return prepared.mode, nil
+}
 
-	return nil
+// selectPublisher packs the encoded batch according to the configured data availability mode.
+// In DataAvailabilityAuto mode all modes able to fit the batch are compared and the cheapest one is chosen.
+func (bc *batchCommitter) selectPublisher(ctx context.Context, encoded []byte) (daPublisher, *preparedData, error) {
+	if bc.options.daMode != types.DataAvailabilityAuto {
+		publisher, err := bc.getPublisher(bc.options.daMode)
+		if err != nil {
+			return nil, nil, err
+		}
+		prepared, err := publisher.Prepare(encoded)
+		if err != nil {
+			return nil, nil, err
+		}
+		return publisher, prepared, nil
+	}
+
+	var (
+		bestPublisher daPublisher
+		bestPrepared  *preparedData
+		bestCost      *big.Int
+	)
+	for _, publisher := range bc.publishers {
+		prepared, err := publisher.Prepare(encoded)
+		if errors.Is(err, ErrDataDoesNotFit) {
+			bc.logger.Debug().Err(err).Stringer("da_mode", publisher.Mode()).Msg("batch does not fit, mode skipped")
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if bc.ethCommitter == nil {
+			// no way to compare costs, the first suitable mode is used
+			return publisher, prepared, nil
+		}
+
+		cost, err := publisher.EstimateCost(ctx, prepared)
+		if err != nil {
+			bc.logger.Warn().Err(err).Stringer("da_mode", publisher.Mode()).Msg("failed to estimate commit cost")
+			continue
+		}
+		bc.logger.Debug().Stringer("da_mode", publisher.Mode()).Stringer("cost", cost).Msg("estimated commit cost")
+
+		if bestCost == nil || cost.Cmp(bestCost) < 0 {
+			bestPublisher, bestPrepared, bestCost = publisher, prepared, cost
+		}
+	}
+
+	if bestPublisher == nil {
+		return nil, nil, errors.New("no suitable data availability mode found for the batch")
+	}
+	return bestPublisher, bestPrepared, nil
+}
+
+func (bc *batchCommitter) getPublisher(mode types.DataAvailabilityMode) (daPublisher, error) {
+	for _, publisher := range bc.publishers {
+		if publisher.Mode() == mode {
+			return publisher, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported data availability mode: %s", mode)
 }
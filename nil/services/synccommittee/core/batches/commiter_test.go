@@ -0,0 +1,160 @@
+package batches
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/calldata"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/rollupcontract"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+type rawEncoder struct {
+	data []byte
+}
+
+func (e *rawEncoder) Encode(_ *types.PrunedBatch, out io.Writer) error {
+	_, err := out.Write(e.data)
+	return err
+}
+
+type ethCommitterStub struct {
+	blobCost     *big.Int
+	blobCostErr  error
+	calldataCost *big.Int
+	commitErr    error
+
+	blobCommits     int
+	calldataCommits int
+	lastCalldata    []byte
+}
+
+func (s *ethCommitterStub) CommitBatch(context.Context, []kzg4844.Blob, string) (*ethtypes.Transaction, error) {
+	s.blobCommits++
+	return ethtypes.NewTx(&ethtypes.BlobTx{}), s.commitErr
+}
+
+func (s *ethCommitterStub) CommitBatchCalldata(
+	_ context.Context, batchData []byte, _ string,
+) (*ethtypes.Transaction, error) {
+	s.calldataCommits++
+	s.lastCalldata = batchData
+	return ethtypes.NewTx(&ethtypes.DynamicFeeTx{}), s.commitErr
+}
+
+func (s *ethCommitterStub) EstimateBlobCommitCost(context.Context, int) (*big.Int, error) {
+	return s.blobCost, s.blobCostErr
+}
+
+func (s *ethCommitterStub) EstimateCalldataCommitCost(context.Context, []byte) (*big.Int, error) {
+	return s.calldataCost, nil
+}
+
+func commitWith(
+	t *testing.T, data []byte, committer *ethCommitterStub, options *commitOptions,
+) (types.DataAvailabilityMode, error) {
+	t.Helper()
+
+	var ethCommitter ethCommitter
+	if committer != nil {
+		ethCommitter = committer
+	}
+
+	bc := NewBatchCommitter(
+		&rawEncoder{data: data},
+		blob.NewBuilder(),
+		ethCommitter,
+		logging.NewLogger("batch_committer_test"),
+		options,
+	)
+	return bc.Commit(t.Context(), types.NewPrunedBatch(testaide.NewBlockBatch(1)))
+}
+
+func TestCommitAutoChoosesCheapestMode(t *testing.T) {
+	t.Parallel()
+
+	data := []byte{0x01, 0x02, 0x03}
+
+	committer := &ethCommitterStub{blobCost: big.NewInt(100), calldataCost: big.NewInt(10)}
+	mode, err := commitWith(t, data, committer, DefaultCommitOptions())
+	require.NoError(t, err)
+	require.Equal(t, types.DataAvailabilityCalldata, mode)
+	require.Equal(t, 1, committer.calldataCommits)
+	require.Zero(t, committer.blobCommits)
+
+	committer = &ethCommitterStub{blobCost: big.NewInt(10), calldataCost: big.NewInt(100)}
+	mode, err = commitWith(t, data, committer, DefaultCommitOptions())
+	require.NoError(t, err)
+	require.Equal(t, types.DataAvailabilityBlob, mode)
+	require.Equal(t, 1, committer.blobCommits)
+	require.Zero(t, committer.calldataCommits)
+}
+
+func TestCommitAutoFallsBackWhenBlobsUnsupported(t *testing.T) {
+	t.Parallel()
+
+	committer := &ethCommitterStub{
+		blobCostErr:  rollupcontract.ErrBlobsNotSupported,
+		calldataCost: big.NewInt(1_000_000),
+	}
+	mode, err := commitWith(t, []byte{0x01}, committer, DefaultCommitOptions())
+	require.NoError(t, err)
+	require.Equal(t, types.DataAvailabilityCalldata, mode)
+	require.Equal(t, 1, committer.calldataCommits)
+}
+
+func TestCommitAutoSkipsModesDataDoesNotFit(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultCommitOptions()
+	options.maxCalldataSize = 2
+
+	committer := &ethCommitterStub{blobCost: big.NewInt(100), calldataCost: big.NewInt(1)}
+	mode, err := commitWith(t, []byte{0x01, 0x02, 0x03}, committer, options)
+	require.NoError(t, err)
+	require.Equal(t, types.DataAvailabilityBlob, mode)
+}
+
+func TestCommitFixedMode(t *testing.T) {
+	t.Parallel()
+
+	committer := &ethCommitterStub{blobCost: big.NewInt(1), calldataCost: big.NewInt(100)}
+	mode, err := commitWith(t, []byte{0x01}, committer, NewCommitOptions(types.DataAvailabilityCalldata))
+	require.NoError(t, err)
+	require.Equal(t, types.DataAvailabilityCalldata, mode)
+	require.Equal(t, 1, committer.calldataCommits)
+
+	batchData, err := calldata.ReadFrame(committer.lastCalldata)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x01}, batchData)
+
+	options := NewCommitOptions(types.DataAvailabilityCalldata)
+	options.maxCalldataSize = 0
+	_, err = commitWith(t, []byte{0x01}, committer, options)
+	require.ErrorIs(t, err, ErrDataDoesNotFit)
+}
+
+func TestCommitAlreadyCommitted(t *testing.T) {
+	t.Parallel()
+
+	committer := &ethCommitterStub{commitErr: rollupcontract.ErrBatchAlreadyCommitted}
+	mode, err := commitWith(t, []byte{0x01}, committer, NewCommitOptions(types.DataAvailabilityBlob))
+	require.NoError(t, err)
+	require.Equal(t, types.DataAvailabilityBlob, mode)
+}
+
+func TestCommitWithoutEthCommitter(t *testing.T) {
+	t.Parallel()
+
+	mode, err := commitWith(t, []byte{0x01}, nil, DefaultCommitOptions())
+	require.NoError(t, err)
+	require.Equal(t, types.DataAvailabilityBlob, mode)
+}
@@ -0,0 +1,106 @@
+package batches
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/calldata"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+// ErrDataDoesNotFit is returned by daPublisher.Prepare when the encoded batch exceeds the capacity of the mode
+var ErrDataDoesNotFit = errors.New("batch data does not fit into data availability limits")
+
+// preparedData is the encoded batch data packed according to the specific data availability mode
+type preparedData struct {
+	mode     types.DataAvailabilityMode
+	blobs    []kzg4844.Blob
+	calldata []byte
+}
+
+// daPublisher packs encoded batches and publishes them to L1 using a single data availability mode
+type daPublisher interface {
+	Mode() types.DataAvailabilityMode
+	Prepare(encoded []byte) (*preparedData, error)
+	EstimateCost(ctx context.Context, prepared *preparedData) (*big.Int, error)
+	Publish(ctx context.Context, prepared *preparedData, batchIndex string) (*ethtypes.Transaction, error)
+}
+
+type blobPublisher struct {
+	blobBuilder  blobBuilder
+	ethCommitter ethCommitter
+	maxBlobCount int
+}
+
+func newBlobPublisher(blobBuilder blobBuilder, ethCommitter ethCommitter, maxBlobCount int) *blobPublisher {
+	return &blobPublisher{
+		blobBuilder:  blobBuilder,
+		ethCommitter: ethCommitter,
+		maxBlobCount: maxBlobCount,
+	}
+}
+
+func (*blobPublisher) Mode() types.DataAvailabilityMode {
+	return types.DataAvailabilityBlob
+}
+
+func (p *blobPublisher) Prepare(encoded []byte) (*preparedData, error) {
+	blobs, err := p.blobBuilder.MakeBlobs(bytes.NewReader(encoded), p.maxBlobCount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDataDoesNotFit, err)
+	}
+	return &preparedData{mode: p.Mode(), blobs: blobs}, nil
+}
+
+func (p *blobPublisher) EstimateCost(ctx context.Context, prepared *preparedData) (*big.Int, error) {
+	return p.ethCommitter.EstimateBlobCommitCost(ctx, len(prepared.blobs))
+}
+
+func (p *blobPublisher) Publish(
+	ctx context.Context, prepared *preparedData, batchIndex string,
+) (*ethtypes.Transaction, error) {
+	return p.ethCommitter.CommitBatch(ctx, prepared.blobs, batchIndex)
+}
+
+type calldataPublisher struct {
+	ethCommitter    ethCommitter
+	maxCalldataSize int
+}
+
+func newCalldataPublisher(ethCommitter ethCommitter, maxCalldataSize int) *calldataPublisher {
+	return &calldataPublisher{
+		ethCommitter:    ethCommitter,
+		maxCalldataSize: maxCalldataSize,
+	}
+}
+
+func (*calldataPublisher) Mode() types.DataAvailabilityMode {
+	return types.DataAvailabilityCalldata
+}
+
+func (p *calldataPublisher) Prepare(encoded []byte) (*preparedData, error) {
+	frame, err := calldata.NewFrame(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDataDoesNotFit, err)
+	}
+	if len(frame) > p.maxCalldataSize {
+		return nil, fmt.Errorf(
+			"%w: batch size %d exceeds calldata limit %d bytes", ErrDataDoesNotFit, len(frame), p.maxCalldataSize)
+	}
+	return &preparedData{mode: p.Mode(), calldata: frame}, nil
+}
+
+func (p *calldataPublisher) EstimateCost(ctx context.Context, prepared *preparedData) (*big.Int, error) {
+	return p.ethCommitter.EstimateCalldataCommitCost(ctx, prepared.calldata)
+}
+
+func (p *calldataPublisher) Publish(
+	ctx context.Context, prepared *preparedData, batchIndex string,
+) (*ethtypes.Transaction, error) {
+	return p.ethCommitter.CommitBatchCalldata(ctx, prepared.calldata, batchIndex)
+}
@@ -25,8 +25,9 @@ type BlockTasksIntegrationTestSuite struct {
 	db    db.DB
 	clock clockwork.Clock
 
-	taskStorage  *storage.TaskStorage
-	blockStorage *storage.BlockStorage
+	taskStorage   *storage.TaskStorage
+	blockStorage  *storage.BlockStorage
+	resetLauncher *StateResetLauncherMock
 
 	scheduler scheduler.TaskScheduler
 }
@@ -48,12 +49,14 @@ func (s *BlockTasksIntegrationTestSuite) SetupSuite() {
 	logger := logging.NewLogger("block_tasks_test_suite")
 
 	s.clock = testaide.NewTestClock()
-	s.taskStorage = storage.NewTaskStorage(s.db, s.clock, metricsHandler, logger)
+	s.taskStorage = storage.NewTaskStorage(s.db, storage.DefaultTaskStorageConfig(), s.clock, metricsHandler, logger)
 	s.blockStorage = storage.NewBlockStorage(s.db, storage.DefaultBlockStorageConfig(), s.clock, metricsHandler, logger)
 
+	s.resetLauncher = &StateResetLauncherMock{}
 	s.scheduler = scheduler.New(
+		scheduler.DefaultConfig(),
 		s.taskStorage,
-		newTaskStateChangeHandler(s.blockStorage, &StateResetLauncherMock{}, logger),
+		newTaskStateChangeHandler(s.blockStorage, s.resetLauncher, logger),
 		metricsHandler,
 		logger,
 	)
@@ -66,6 +69,7 @@ func (s *BlockTasksIntegrationTestSuite) TearDownSuite() {
 func (s *BlockTasksIntegrationTestSuite) SetupTest() {
 	err := s.db.DropAll()
 	s.Require().NoError(err, "failed to clear database in SetUpTest")
+	s.resetLauncher.ResetCalls()
 }
 
 func (s *BlockTasksIntegrationTestSuite) Test_Provide_Tasks_And_Handle_Success_Result() {
@@ -86,13 +90,13 @@ func (s *BlockTasksIntegrationTestSuite) Test_Provide_Tasks_And_Handle_Success_R
 	executorId := testaide.RandomExecutorId()
 
 	// requesting batch proof task for execution
-	taskToExecute, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId))
+	taskToExecute, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId, nil))
 	s.Require().NoError(err)
 	s.Require().NotNil(taskToExecute)
 	s.Require().Equal(types.ProofBatch, taskToExecute.TaskType)
 
 	// no new tasks available yet
-	nonAvailableTask, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId))
+	nonAvailableTask, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId, nil))
 	s.Require().NoError(err)
 	s.Require().Nil(nonAvailableTask)
 
@@ -126,7 +130,7 @@ func (s *BlockTasksIntegrationTestSuite) Test_Provide_Tasks_And_Handle_Failure_R
 	executorId := testaide.RandomExecutorId()
 
 	// requesting batch proof task
-	taskToExecute, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId))
+	taskToExecute, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId, nil))
 	s.Require().NoError(err)
 	s.Require().NotNil(taskToExecute)
 	s.Require().Equal(types.ProofBatch, taskToExecute.TaskType)
@@ -153,6 +157,55 @@ func (s *BlockTasksIntegrationTestSuite) Test_Provide_Tasks_And_Handle_Failure_R
 	s.Require().Equal(types.Failed, batchProofEntry.Status)
 }
 
+func (s *BlockTasksIntegrationTestSuite) Test_Retryable_Failure_Escalated_After_Retries_Exhausted() {
+	batch := testaide.NewBlockBatch(1)
+
+	err := s.blockStorage.SetBlockBatch(s.ctx, batch)
+	s.Require().NoError(err)
+
+	proofTask, err := batch.CreateProofTask(s.clock.Now())
+	s.Require().NoError(err)
+	proofTask.RetryPolicy = &types.TaskRetryPolicy{MaxRetries: 1}
+
+	err = s.taskStorage.AddTaskEntries(s.ctx, proofTask)
+	s.Require().NoError(err)
+
+	executorId := testaide.RandomExecutorId()
+	transientErr := types.NewTaskExecError(types.TaskErrRpc, "rpc is not available")
+
+	// first failure is retried without state reset
+	taskToExecute, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId, nil))
+	s.Require().NoError(err)
+	s.Require().NotNil(taskToExecute)
+
+	err = s.scheduler.SetTaskResult(
+		s.ctx, types.NewFailureProviderTaskResult(taskToExecute.Id, executorId, transientErr))
+	s.Require().NoError(err)
+	s.Require().Empty(s.resetLauncher.LaunchPartialResetWithSuspensionCalls())
+
+	entry, err := s.taskStorage.TryGetTaskEntry(s.ctx, taskToExecute.Id)
+	s.Require().NoError(err)
+	s.Require().Equal(types.WaitingForExecutor, entry.Status)
+	s.Require().NotNil(entry.NextRetryAt)
+
+	// second failure exceeds the retry budget, state reset is launched
+	taskToExecute, err = s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId, nil))
+	s.Require().NoError(err)
+	s.Require().NotNil(taskToExecute)
+
+	err = s.scheduler.SetTaskResult(
+		s.ctx, types.NewFailureProviderTaskResult(taskToExecute.Id, executorId, transientErr))
+	s.Require().NoError(err)
+
+	resetCalls := s.resetLauncher.LaunchPartialResetWithSuspensionCalls()
+	s.Require().Len(resetCalls, 1)
+	s.Require().Equal(batch.Id, resetCalls[0].FailedBatchId)
+
+	entry, err = s.taskStorage.TryGetTaskEntry(s.ctx, taskToExecute.Id)
+	s.Require().NoError(err)
+	s.Require().Equal(types.Failed, entry.Status)
+}
+
 func newTestSuccessProviderResult(taskToExecute *types.Task, executorId types.TaskExecutorId) *types.TaskResult {
 	return types.NewSuccessProviderTaskResult(
 		taskToExecute.Id,
@@ -2,6 +2,7 @@ package core
 
 import (
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/scheduler"
 )
 
 const (
@@ -13,6 +14,7 @@ type Config struct {
 	TaskListenerRpcEndpoint string
 	AggregatorConfig        AggregatorConfig
 	ProposerParams          ProposerParams
+	TaskSchedulerConfig     scheduler.Config
 	Telemetry               *telemetry.Config
 }
 
@@ -22,6 +24,7 @@ func NewDefaultConfig() *Config {
 		TaskListenerRpcEndpoint: DefaultTaskRpcEndpoint,
 		AggregatorConfig:        NewDefaultAggregatorConfig(),
 		ProposerParams:          NewDefaultProposerParams(),
+		TaskSchedulerConfig:     scheduler.DefaultConfig(),
 		Telemetry: &telemetry.Config{
 			ServiceName: "sync_committee",
 		},
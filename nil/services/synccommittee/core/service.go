@@ -40,7 +40,16 @@ func New(cfg *Config, database db.DB, ethClient rollupcontract.EthClient) (*Sync
 	clock := clockwork.NewRealClock()
 	blockStorage := storage.NewBlockStorage(
 		database, storage.DefaultBlockStorageConfig(), clock, metricsHandler, logger)
-	taskStorage := storage.NewTaskStorage(database, clock, metricsHandler, logger)
+	if err := cfg.TaskSchedulerConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid task scheduler config: %w", err)
+	}
+	taskStorage := storage.NewTaskStorage(
+		database,
+		storage.NewTaskStorageConfig(cfg.TaskSchedulerConfig.RetryPolicies),
+		clock,
+		metricsHandler,
+		logger,
+	)
 
 	// todo: add reset logic to TaskStorage (implement StateResetter interface)
 	//  and pass it here in https://github.com/NilFoundation/nil/pull/419
@@ -77,6 +86,7 @@ func New(cfg *Config, database db.DB, ethClient rollupcontract.EthClient) (*Sync
 	resetLauncher := reset.NewResetLauncher(agg, stateResetter, syncCommittee, logger)
 
 	taskScheduler := scheduler.New(
+		cfg.TaskSchedulerConfig,
 		taskStorage,
 		newTaskStateChangeHandler(blockStorage, resetLauncher, logger),
 		metricsHandler,
@@ -14,10 +14,12 @@ const (
 
 type TaskRequest struct {
 	ExecutorId types.TaskExecutorId `json:"executorId"`
+	// Capabilities restrict the set of tasks which can be assigned to the executor, nil means no restrictions
+	Capabilities *types.ExecutorCapabilities `json:"capabilities,omitempty"`
 }
 
-func NewTaskRequest(executorId types.TaskExecutorId) *TaskRequest {
-	return &TaskRequest{ExecutorId: executorId}
+func NewTaskRequest(executorId types.TaskExecutorId, capabilities *types.ExecutorCapabilities) *TaskRequest {
+	return &TaskRequest{ExecutorId: executorId, Capabilities: capabilities}
 }
 
 type TaskRequestHandler interface {
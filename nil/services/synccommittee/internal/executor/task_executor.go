@@ -22,6 +22,8 @@ const (
 
 type Config struct {
 	TaskPollingInterval time.Duration
+	// Capabilities are sent along with each task request, nil means that executor accepts any task
+	Capabilities *types.ExecutorCapabilities
 }
 
 func DefaultConfig() *Config {
@@ -96,7 +98,7 @@ func (p *taskExecutorImpl) fetchAndHandleTask(ctx context.Context) error {
 		return nil
 	}
 
-	taskRequest := api.NewTaskRequest(p.nonceId)
+	taskRequest := api.NewTaskRequest(p.nonceId, p.config.Capabilities)
 	task, err := p.requestHandler.GetTask(ctx, taskRequest)
 	if err != nil {
 		return err
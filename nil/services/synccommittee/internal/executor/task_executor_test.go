@@ -69,7 +69,7 @@ func (s *TestSuite) Test_TaskExecutor_Executes_Tasks() {
 	err := testaide.WaitFor(s.context, started, 10*time.Second)
 	s.Require().NoError(err, "task executor did not start in time")
 
-	expectedTaskRequest := api.NewTaskRequest(s.taskExecutor.Id())
+	expectedTaskRequest := api.NewTaskRequest(s.taskExecutor.Id(), nil)
 	const tasksThreshold = 5
 
 	s.Require().Eventually(
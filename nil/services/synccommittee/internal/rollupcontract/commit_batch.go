@@ -70,6 +70,90 @@ func (r *Wrapper) CommitBatch(
 	return signedTx, nil
 }
 
+// CommitBatchCalldata sends `CommitBatchCalldata` contract method transaction with batch data passed as calldata.
+// Used as a fallback for `CommitBatch` when blobs are too expensive or not supported by L1.
+// If such `batchIndex` is already submitted, returns `nil, ErrBatchAlreadyCommitted`.
+func (r *Wrapper) CommitBatchCalldata(
+	ctx context.Context,
+	batchData []byte,
+	batchIndex string,
+) (*ethtypes.Transaction, error) {
+	callOpts, cancel := r.getEthCallOpts(ctx)
+	defer cancel()
+	isCommited, err := r.rollupContract.IsBatchCommitted(callOpts, batchIndex)
+	if err != nil {
+		return nil, err
+	}
+	if isCommited {
+		return nil, ErrBatchAlreadyCommitted
+	}
+
+	transactOpts, cancel, err := r.getEthTransactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	return r.rollupContract.CommitBatchCalldata(transactOpts, batchIndex, batchData)
+}
+
+// EstimateBlobCommitCost returns the expected data availability cost (in wei) of committing a batch packed
+// into `blobCount` blobs. Returns ErrBlobsNotSupported if L1 does not support EIP-4844.
+func (r *Wrapper) EstimateBlobCommitCost(ctx context.Context, blobCount int) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.requestTimeout)
+	defer cancel()
+
+	head, err := r.ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting header: %w", err)
+	}
+	if head.ExcessBlobGas == nil {
+		return nil, ErrBlobsNotSupported
+	}
+
+	blobFee := eip4844.CalcBlobFee(*head.ExcessBlobGas)
+	blobGas := new(big.Int).SetUint64(ethparams.BlobTxBlobGasPerBlob * uint64(blobCount))
+	return blobGas.Mul(blobGas, blobFee), nil
+}
+
+// EstimateCalldataCommitCost returns the expected data availability cost (in wei) of committing `batchData`
+// as transaction calldata, i.e. the intrinsic calldata gas multiplied by the current gas price.
+// Returns ErrBaseFeeNotSupported if L1 does not support EIP-1559.
+func (r *Wrapper) EstimateCalldataCommitCost(ctx context.Context, batchData []byte) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.requestTimeout)
+	defer cancel()
+
+	gasTipCap, err := r.ethClient.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("suggesting gas tip cap: %w", err)
+	}
+
+	head, err := r.ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting header: %w", err)
+	}
+	if head.BaseFee == nil {
+		return nil, ErrBaseFeeNotSupported
+	}
+
+	gasPrice := new(big.Int).Add(head.BaseFee, gasTipCap)
+	dataGas := new(big.Int).SetUint64(calldataGas(batchData))
+	return dataGas.Mul(dataGas, gasPrice), nil
+}
+
+// calldataGas computes the intrinsic gas charged for the transaction input data (EIP-2028 pricing)
+func calldataGas(data []byte) uint64 {
+	var gas uint64
+	for _, b := range data {
+		if b == 0 {
+			gas += ethparams.TxDataZeroGas
+		} else {
+			gas += ethparams.TxDataNonZeroGasEIP2028
+		}
+	}
+	return gas
+}
+
 // computeSidecar handles all KZG commitment related computations
 func computeSidecar(blobs []kzg4844.Blob) (*ethtypes.BlobTxSidecar, error) {
 	commitments := make([]kzg4844.Commitment, 0, len(blobs))
@@ -133,6 +217,10 @@ func (r *Wrapper) computeTxParams(ctx context.Context, from ethcommon.Address, b
 		return nil, fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", gasFeeCap, gasTipCap)
 	}
 
+	if head.ExcessBlobGas == nil {
+		return nil, ErrBlobsNotSupported
+	}
+
 	blobFee := eip4844.CalcBlobFee(*head.ExcessBlobGas)
 	gas := ethparams.BlobTxBlobGasPerBlob * uint64(blobCount)
 
@@ -5,4 +5,6 @@ import "errors"
 var (
 	ErrBatchAlreadyFinalized = errors.New("batch already finalized")
 	ErrBatchAlreadyCommitted = errors.New("batch already committed")
+	ErrBlobsNotSupported     = errors.New("L1 chain does not support blob transactions")
+	ErrBaseFeeNotSupported   = errors.New("L1 chain does not support base fee")
 )
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/common/logging"
@@ -39,3 +40,14 @@ func (c *taskDebugRpcClient) GetTaskTree(ctx context.Context, taskId types.TaskI
 		taskId,
 	)
 }
+
+func (c *taskDebugRpcClient) GetTaskQueues(ctx context.Context) ([]*public.TaskQueueView, error) {
+	rawResponse, err := c.client.RawCall(ctx, public.DebugGetTaskQueues)
+	if err != nil {
+		return nil, err
+	}
+
+	var queues []*public.TaskQueueView
+	err = json.Unmarshal(rawResponse, &queues)
+	return queues, err
+}
@@ -76,12 +76,14 @@ func (s *TaskSchedulerDebugRpcTestSuite) SetupSuite() {
 
 	s.storage = storage.NewTaskStorage(
 		s.database,
+		storage.DefaultTaskStorageConfig(),
 		s.clock,
 		metricsHandler,
 		logger,
 	)
 
 	s.scheduler = scheduler.New(
+		scheduler.DefaultConfig(),
 		s.storage,
 		&api.TaskStateChangeHandlerMock{},
 		metricsHandler,
@@ -352,7 +354,7 @@ func (s *TaskSchedulerDebugRpcTestSuite) requestAndSendResult(
 ) {
 	s.T().Helper()
 
-	taskToExec, err := s.storage.RequestTaskToExecute(s.context, executor)
+	taskToExec, err := s.storage.RequestTaskToExecute(s.context, executor, nil)
 	s.Require().NoError(err)
 	s.Require().NotNil(taskToExec)
 	s.Require().Equal(expected, taskToExec)
@@ -455,3 +457,24 @@ func (s *TaskSchedulerDebugRpcTestSuite) requireAllPass(assertions []bool, id ty
 		s.FailNowf("", "assertion for task with id=%s failed", id.String())
 	}
 }
+
+func (s *TaskSchedulerDebugRpcTestSuite) Test_Get_Task_Queues() {
+	entries := newTaskEntries(s.clock.Now())
+	err := s.storage.AddTaskEntries(s.context, entries...)
+	s.Require().NoError(err)
+
+	// Register executor which is able to handle only ProofBatch tasks
+	capabilities := types.NewExecutorCapabilities([]types.TaskType{proofBatchType}, nil, types.MemoryClassNone, 0)
+	task, err := s.scheduler.GetTask(s.context, api.NewTaskRequest(testaide.RandomExecutorId(), capabilities))
+	s.Require().NoError(err)
+	s.Require().Nil(task)
+
+	queues, err := s.rpcClient.GetTaskQueues(s.context)
+	s.Require().NoError(err)
+
+	expected := []*public.TaskQueueView{
+		{TaskType: proofBatchType, ActiveCount: 0, PendingCount: 1, CapableExecutors: 1},
+		{TaskType: types.PartialProve, ActiveCount: 3, PendingCount: 1, CapableExecutors: 0},
+	}
+	s.Require().Equal(expected, queues)
+}
@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+type executorRecord struct {
+	capabilities *types.ExecutorCapabilities
+	lastSeen     time.Time
+}
+
+// executorRegistry keeps capabilities of executors which recently requested tasks from the scheduler
+type executorRegistry struct {
+	mutex     sync.Mutex
+	executors map[types.TaskExecutorId]executorRecord
+	ttl       time.Duration
+}
+
+func newExecutorRegistry(ttl time.Duration) *executorRegistry {
+	return &executorRegistry{
+		executors: make(map[types.TaskExecutorId]executorRecord),
+		ttl:       ttl,
+	}
+}
+
+func (r *executorRegistry) register(
+	executor types.TaskExecutorId,
+	capabilities *types.ExecutorCapabilities,
+	now time.Time,
+) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.executors[executor] = executorRecord{
+		capabilities: capabilities,
+		lastSeen:     now,
+	}
+}
+
+// countCapable returns the number of active executors able to handle tasks from the given queue.
+// Executors which haven't requested tasks for longer than ttl are evicted.
+func (r *executorRegistry) countCapable(key types.TaskQueueKey, now time.Time) uint32 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var count uint32
+	for id, record := range r.executors {
+		if now.Sub(record.lastSeen) > r.ttl {
+			delete(r.executors, id)
+			continue
+		}
+		if record.capabilities.CanExecute(key.TaskType, key.CircuitType) {
+			count++
+		}
+	}
+	return count
+}
@@ -1,9 +1,11 @@
 package scheduler
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common/heap"
@@ -20,17 +22,28 @@ import (
 var ErrFailedToProcessTaskResult = errors.New("failed to process task result")
 
 type Config struct {
+	// RetryPolicies define the retry budget and the backoff of the tasks failed with retryable errors,
+	// they are applied by the task storage the scheduler operates on
+	RetryPolicies types.TaskRetryPolicies
+
 	taskCheckInterval    time.Duration
 	taskExecutionTimeout time.Duration
+	executorActivityTTL  time.Duration
 }
 
 func DefaultConfig() Config {
 	return Config{
+		RetryPolicies:        types.DefaultTaskRetryPolicies(),
 		taskCheckInterval:    time.Minute,
 		taskExecutionTimeout: time.Hour,
+		executorActivityTTL:  5 * time.Minute,
 	}
 }
 
+func (c *Config) Validate() error {
+	return c.RetryPolicies.Validate()
+}
+
 type TaskScheduler interface {
 	srv.Worker
 	api.TaskRequestHandler
@@ -48,11 +61,17 @@ type Storage interface {
 
 	GetTaskTreeView(ctx context.Context, taskId types.TaskId) (*public.TaskTreeView, error)
 
-	RequestTaskToExecute(ctx context.Context, executor types.TaskExecutorId) (*types.Task, error)
+	GetTaskStats(ctx context.Context) (*types.TaskStats, error)
+
+	RequestTaskToExecute(
+		ctx context.Context,
+		executor types.TaskExecutorId,
+		capabilities *types.ExecutorCapabilities,
+	) (*types.Task, error)
 
 	ProcessTaskResult(ctx context.Context, res *types.TaskResult) error
 
-	RescheduleHangingTasks(ctx context.Context, taskExecutionTimeout time.Duration) error
+	RescheduleHangingTasks(ctx context.Context, taskExecutionTimeout time.Duration) ([]*types.TerminatedTask, error)
 }
 
 type Metrics interface {
@@ -60,6 +79,7 @@ type Metrics interface {
 }
 
 func New(
+	config Config,
 	storage Storage,
 	stateHandler api.TaskStateChangeHandler,
 	metrics Metrics,
@@ -68,7 +88,8 @@ func New(
 	scheduler := &taskSchedulerImpl{
 		storage:      storage,
 		stateHandler: stateHandler,
-		config:       DefaultConfig(),
+		config:       config,
+		executors:    newExecutorRegistry(config.executorActivityTTL),
 		metrics:      metrics,
 	}
 
@@ -84,22 +105,38 @@ type taskSchedulerImpl struct {
 	storage      Storage
 	stateHandler api.TaskStateChangeHandler
 	config       Config
+	executors    *executorRegistry
 	metrics      Metrics
 	logger       logging.Logger
 }
 
 func (s *taskSchedulerImpl) runIteration(ctx context.Context) {
-	err := s.storage.RescheduleHangingTasks(ctx, s.config.taskExecutionTimeout)
+	terminated, err := s.storage.RescheduleHangingTasks(ctx, s.config.taskExecutionTimeout)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("failed to reschedule hanging tasks")
 		s.recordError(ctx)
+		return
+	}
+
+	for _, t := range terminated {
+		log.NewTaskResultEvent(s.logger, zerolog.WarnLevel, t.Result).
+			Msg("hanging task has exhausted its retries and was terminated")
+
+		if err := s.stateHandler.OnTaskTerminated(ctx, &t.Task, t.Result); err != nil {
+			log.NewTaskResultEvent(s.logger, zerolog.ErrorLevel, t.Result).
+				Err(err).
+				Msg("failed to handle termination of hanging task")
+			s.recordError(ctx)
+		}
 	}
 }
 
 func (s *taskSchedulerImpl) GetTask(ctx context.Context, request *api.TaskRequest) (*types.Task, error) {
 	s.logger.Debug().Stringer(logging.FieldTaskExecutorId, request.ExecutorId).Msg("received new task request")
 
-	task, err := s.storage.RequestTaskToExecute(ctx, request.ExecutorId)
+	s.executors.register(request.ExecutorId, request.Capabilities, time.Now())
+
+	task, err := s.storage.RequestTaskToExecute(ctx, request.ExecutorId, request.Capabilities)
 	if err != nil {
 		s.logger.Error().
 			Err(err).
@@ -141,6 +178,13 @@ func (s *taskSchedulerImpl) SetTaskResult(ctx context.Context, result *types.Tas
 		return s.onTaskResultError(ctx, err, result)
 	}
 
+	if result.HasRetryableError() && !entry.HasRetriesLeft() {
+		log.NewTaskResultEvent(s.logger, zerolog.WarnLevel, result).
+			Int("retryCount", entry.RetryCount).
+			Msg("task has exhausted its retries, error is considered permanent")
+		result = result.WithRetriesExhausted(entry.RetryCount)
+	}
+
 	if err := s.stateHandler.OnTaskTerminated(ctx, &entry.Task, result); err != nil {
 		return s.onTaskResultError(ctx, err, result)
 	}
@@ -240,6 +284,29 @@ func (s *taskSchedulerImpl) GetTaskTree(ctx context.Context, taskId types.TaskId
 	return s.storage.GetTaskTreeView(ctx, taskId)
 }
 
+func (s *taskSchedulerImpl) GetTaskQueues(ctx context.Context) ([]*public.TaskQueueView, error) {
+	stats, err := s.storage.GetTaskStats(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to get task stats from the storage")
+		return nil, err
+	}
+
+	now := time.Now()
+	queues := make([]*public.TaskQueueView, 0, len(stats.CountPerQueue))
+	for key, numbers := range stats.CountPerQueue {
+		capableExecutors := s.executors.countCapable(key, now)
+		queues = append(queues, public.NewTaskQueueView(key, numbers, capableExecutors))
+	}
+
+	slices.SortFunc(queues, func(l, r *public.TaskQueueView) int {
+		if l.TaskType != r.TaskType {
+			return cmp.Compare(l.TaskType, r.TaskType)
+		}
+		return cmp.Compare(l.CircuitType, r.CircuitType)
+	})
+	return queues, nil
+}
+
 func (s *taskSchedulerImpl) onTaskResultError(ctx context.Context, cause error, result *types.TaskResult) error {
 	log.NewTaskResultEvent(s.logger, zerolog.ErrorLevel, result).Err(cause).Msg("Failed to process task result")
 	s.recordError(ctx)
@@ -251,6 +251,41 @@ func (bs *BlockStorage) setBatchAsProvedImpl(ctx context.Context, batchId scType
 	return true, nil
 }
 
+// SetBatchDataAvailability records the data availability mode which was used to commit the batch to L1.
+func (bs *BlockStorage) SetBatchDataAvailability(
+	ctx context.Context,
+	batchId scTypes.BatchId,
+	mode scTypes.DataAvailabilityMode,
+) error {
+	return bs.retryRunner.Do(ctx, func(ctx context.Context) error {
+		return bs.setBatchDataAvailabilityImpl(ctx, batchId, mode)
+	})
+}
+
+func (bs *BlockStorage) setBatchDataAvailabilityImpl(
+	ctx context.Context,
+	batchId scTypes.BatchId,
+	mode scTypes.DataAvailabilityMode,
+) error {
+	tx, err := bs.database.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	entry, err := bs.ops.getBatch(tx, batchId)
+	if err != nil {
+		return err
+	}
+
+	entry.DataAvailability = mode
+	if err := bs.ops.putBatch(tx, entry); err != nil {
+		return err
+	}
+
+	return bs.commit(tx)
+}
+
 func (bs *BlockStorage) TryGetNextProposalData(ctx context.Context) (*scTypes.ProposalData, error) {
 	tx, err := bs.database.CreateRoTx(ctx)
 	if err != nil {
@@ -23,8 +23,9 @@ type batchEntry struct {
 	LatestMainBlockHash common.Hash                         `json:"latestMainBlockHash"`
 	BlockIds            []scTypes.BlockId                   `json:"blockIds"`
 
-	IsProved  bool      `json:"isProved,omitempty"`
-	CreatedAt time.Time `json:"createdAt"`
+	IsProved         bool                         `json:"isProved,omitempty"`
+	DataAvailability scTypes.DataAvailabilityMode `json:"dataAvailability,omitempty"`
+	CreatedAt        time.Time                    `json:"createdAt"`
 }
 
 func newBatchEntry(batch *scTypes.BlockBatch, createdAt time.Time) *batchEntry {
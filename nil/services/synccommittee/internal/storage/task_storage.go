@@ -36,15 +36,32 @@ type TaskStorageMetrics interface {
 	RecordTaskRescheduled(ctx context.Context, taskType types.TaskType, previousExecutor types.TaskExecutorId)
 }
 
+type TaskStorageConfig struct {
+	// RetryPolicies are assigned to the new task entries which don't have a retry policy set explicitly
+	RetryPolicies types.TaskRetryPolicies
+}
+
+func NewTaskStorageConfig(retryPolicies types.TaskRetryPolicies) TaskStorageConfig {
+	return TaskStorageConfig{
+		RetryPolicies: retryPolicies,
+	}
+}
+
+func DefaultTaskStorageConfig() TaskStorageConfig {
+	return NewTaskStorageConfig(types.DefaultTaskRetryPolicies())
+}
+
 // TaskStorage defines a type for managing tasks and their lifecycle operations.
 type TaskStorage struct {
 	commonStorage
+	config  TaskStorageConfig
 	clock   clockwork.Clock
 	metrics TaskStorageMetrics
 }
 
 func NewTaskStorage(
 	db db.DB,
+	config TaskStorageConfig,
 	clock clockwork.Clock,
 	metrics TaskStorageMetrics,
 	logger logging.Logger,
@@ -55,6 +72,7 @@ func NewTaskStorage(
 			logger,
 			common.DoNotRetryIf(types.ErrTaskWrongExecutor, types.ErrTaskInvalidStatus, ErrTaskAlreadyExists),
 		),
+		config:  config,
 		clock:   clock,
 		metrics: metrics,
 	}
@@ -153,6 +171,11 @@ func (st *TaskStorage) addSingleTaskEntryTx(tx db.RwTx, entry *types.TaskEntry)
 		return fmt.Errorf("%w: taskId=%s", ErrTaskAlreadyExists, entry.Task.Id)
 	}
 
+	if entry.RetryPolicy == nil {
+		policy := st.config.RetryPolicies.For(entry.Task.TaskType)
+		entry.RetryPolicy = &policy
+	}
+
 	return st.putTaskEntry(tx, entry)
 }
 
@@ -254,33 +277,62 @@ func (st *TaskStorage) GetTaskTreeView(ctx context.Context, rootTaskId types.Tas
 	return getTaskTreeRec(rootTaskId, 0)
 }
 
-// Helper to find available task with higher priority
-func (st *TaskStorage) findTopPriorityTask(tx db.RoTx) (*types.TaskEntry, error) {
+// findTopPriorityTask returns the top priority task among the ones the executor is able to handle.
+// Nil is returned if there are no suitable tasks or the executor has reached its concurrency limit.
+func (st *TaskStorage) findTopPriorityTask(
+	tx db.RoTx,
+	executor types.TaskExecutorId,
+	capabilities *types.ExecutorCapabilities,
+) (*types.TaskEntry, error) {
 	var topPriorityTask *types.TaskEntry = nil
+	var runningCount uint32
+	currentTime := st.clock.Now()
 
 	for entry, err := range st.getStoredTasksSeq(tx) {
 		if err != nil {
 			return nil, err
 		}
 
+		if entry.Status == types.Running && entry.Owner == executor {
+			runningCount++
+			continue
+		}
+
 		if entry.Status != types.WaitingForExecutor {
 			continue
 		}
 
+		if !entry.IsBackoffElapsed(currentTime) {
+			continue
+		}
+
+		if !capabilities.CanExecute(entry.Task.TaskType, entry.Task.CircuitType) {
+			continue
+		}
+
 		if entry.HasHigherPriorityThan(topPriorityTask) {
 			topPriorityTask = entry
 		}
 	}
 
+	if !capabilities.HasFreeSlot(runningCount) {
+		return nil, nil
+	}
+
 	return topPriorityTask, nil
 }
 
-// RequestTaskToExecute Find task with no dependencies and higher priority and assign it to the executor
-func (st *TaskStorage) RequestTaskToExecute(ctx context.Context, executor types.TaskExecutorId) (*types.Task, error) {
+// RequestTaskToExecute Find task with no dependencies and higher priority and assign it to the executor.
+// Only tasks matching executor capabilities are considered, nil capabilities allow any task.
+func (st *TaskStorage) RequestTaskToExecute(
+	ctx context.Context,
+	executor types.TaskExecutorId,
+	capabilities *types.ExecutorCapabilities,
+) (*types.Task, error) {
 	var taskEntry *types.TaskEntry
 	err := st.retryRunner.Do(ctx, func(ctx context.Context) error {
 		var err error
-		taskEntry, err = st.requestTaskToExecuteImpl(ctx, executor)
+		taskEntry, err = st.requestTaskToExecuteImpl(ctx, executor, capabilities)
 		return err
 	})
 	if err != nil {
@@ -297,6 +349,7 @@ func (st *TaskStorage) RequestTaskToExecute(ctx context.Context, executor types.
 func (st *TaskStorage) requestTaskToExecuteImpl(
 	ctx context.Context,
 	executor types.TaskExecutorId,
+	capabilities *types.ExecutorCapabilities,
 ) (*types.TaskEntry, error) {
 	tx, err := st.database.CreateRwTx(ctx)
 	if err != nil {
@@ -304,7 +357,7 @@ func (st *TaskStorage) requestTaskToExecuteImpl(
 	}
 	defer tx.Rollback()
 
-	taskEntry, err := st.findTopPriorityTask(tx)
+	taskEntry, err := st.findTopPriorityTask(tx, executor, capabilities)
 	if err != nil {
 		return nil, err
 	}
@@ -356,6 +409,10 @@ func (st *TaskStorage) processTaskResultImpl(ctx context.Context, res *types.Tas
 		return err
 	}
 
+	if res.HasRetryableError() && !entry.HasRetriesLeft() {
+		res = res.WithRetriesExhausted(entry.RetryCount)
+	}
+
 	if res.HasRetryableError() {
 		if err := st.rescheduleTaskTx(tx, entry, res.Error); err != nil {
 			return err
@@ -438,30 +495,46 @@ type rescheduledTask struct {
 }
 
 // RescheduleHangingTasks finds tasks that exceed execution timeout and reschedules them to be re-executed later.
-func (st *TaskStorage) RescheduleHangingTasks(ctx context.Context, taskExecutionTimeout time.Duration) error {
+// Tasks which have exhausted their retry budget are terminated as failed and returned to the caller.
+func (st *TaskStorage) RescheduleHangingTasks(
+	ctx context.Context,
+	taskExecutionTimeout time.Duration,
+) ([]*types.TerminatedTask, error) {
 	var rescheduled []rescheduledTask
+	var terminated []terminatedTask
 	err := st.retryRunner.Do(ctx, func(ctx context.Context) error {
 		var err error
-		rescheduled, err = st.rescheduleHangingTasksImpl(ctx, taskExecutionTimeout)
+		rescheduled, terminated, err = st.rescheduleHangingTasksImpl(ctx, taskExecutionTimeout)
 		return err
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, entry := range rescheduled {
 		st.metrics.RecordTaskRescheduled(ctx, entry.taskType, entry.previousExecutor)
 	}
-	return nil
+
+	terminatedTasks := make([]*types.TerminatedTask, 0, len(terminated))
+	for _, t := range terminated {
+		st.metrics.RecordTaskTerminated(ctx, t.entry, t.result)
+		terminatedTasks = append(terminatedTasks, &types.TerminatedTask{Task: t.entry.Task, Result: t.result})
+	}
+	return terminatedTasks, nil
+}
+
+type terminatedTask struct {
+	entry  *types.TaskEntry
+	result *types.TaskResult
 }
 
 func (st *TaskStorage) rescheduleHangingTasksImpl(
 	ctx context.Context,
 	taskExecutionTimeout time.Duration,
-) (rescheduled []rescheduledTask, err error) {
+) (rescheduled []rescheduledTask, terminated []terminatedTask, err error) {
 	tx, err := st.database.CreateRwTx(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer tx.Rollback()
 
@@ -471,9 +544,9 @@ loop:
 	for entry, err := range st.getStoredTasksSeq(tx) {
 		switch {
 		case err != nil:
-			return nil, err
+			return nil, nil, err
 
-		case len(rescheduled) == rescheduledTasksPerTxLimit:
+		case len(rescheduled)+len(terminated) == rescheduledTasksPerTxLimit:
 			break loop
 
 		case entry.Status != types.Running:
@@ -482,11 +555,22 @@ loop:
 		case *entry.ExecutionTime(currentTime) <= taskExecutionTimeout:
 			continue
 
+		case !entry.HasRetriesLeft():
+			timeoutErr := types.NewTaskErrTimeout(*entry.ExecutionTime(currentTime), taskExecutionTimeout)
+			result := types.NewFailureProverTaskResult(
+				entry.Task.Id, entry.Owner, types.NewTaskErrRetriesExhausted(timeoutErr, entry.RetryCount),
+			)
+			if err := st.terminateTaskTx(tx, entry, result); err != nil {
+				return nil, nil, err
+			}
+
+			terminated = append(terminated, terminatedTask{entry, result})
+
 		default:
 			previousExecutor := entry.Owner
 			timeoutErr := types.NewTaskErrTimeout(*entry.ExecutionTime(currentTime), taskExecutionTimeout)
 			if err := st.rescheduleTaskTx(tx, entry, timeoutErr); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			rescheduled = append(rescheduled, rescheduledTask{entry.Task.TaskType, previousExecutor})
@@ -494,10 +578,10 @@ loop:
 	}
 
 	if err := st.commit(tx); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return rescheduled, nil
+	return rescheduled, terminated, nil
 }
 
 func (st *TaskStorage) rescheduleTaskTx(
@@ -511,7 +595,7 @@ func (st *TaskStorage) rescheduleTaskTx(
 		Int("retryCount", entry.RetryCount).
 		Msg("Task execution error, rescheduling")
 
-	if err := entry.ResetRunning(); err != nil {
+	if err := entry.ResetRunning(st.clock.Now()); err != nil {
 		return fmt.Errorf("failed to reset task: %w", err)
 	}
 
@@ -23,7 +23,7 @@ const (
 type TaskStorageSuite struct {
 	suite.Suite
 	database db.DB
-	clock    clockwork.Clock
+	clock    *clockwork.FakeClock
 	ts       *TaskStorage
 	ctx      context.Context
 }
@@ -45,11 +45,12 @@ func (s *TaskStorageSuite) SetupSuite() {
 	s.Require().NoError(err)
 
 	s.clock = testaide.NewTestClock()
-	s.ts = NewTaskStorage(database, s.clock, metricsHandler, logger)
+	s.ts = NewTaskStorage(database, DefaultTaskStorageConfig(), s.clock, metricsHandler, logger)
 	s.ctx = context.Background()
 }
 
 func (s *TaskStorageSuite) TearDownTest() {
+	testaide.ResetTestClock(s.clock)
 	err := s.database.DropAll()
 	s.Require().NoError(err, "failed to clear database in TearDownTest")
 }
@@ -73,7 +74,7 @@ func (s *TaskStorageSuite) Test_Request_And_Process_Result() {
 	s.Require().NoError(err)
 
 	// No available tasks for executor at this point
-	task, err := s.ts.RequestTaskToExecute(s.ctx, 88)
+	task, err := s.ts.RequestTaskToExecute(s.ctx, 88, nil)
 	s.Require().NoError(err)
 	s.Require().Nil(task)
 
@@ -84,7 +85,7 @@ func (s *TaskStorageSuite) Test_Request_And_Process_Result() {
 			dependency1.Task.Id, dependency1.Owner, types.TaskOutputArtifacts{}, types.TaskResultData{}),
 	)
 	s.Require().NoError(err)
-	task, err = s.ts.RequestTaskToExecute(s.ctx, 88)
+	task, err = s.ts.RequestTaskToExecute(s.ctx, 88, nil)
 	s.Require().NoError(err)
 	s.Require().NotNil(task)
 	s.Equal(task.Id, lowerPriorityEntry.Task.Id)
@@ -97,7 +98,7 @@ func (s *TaskStorageSuite) Test_Request_And_Process_Result() {
 	)
 	s.Require().NoError(err)
 
-	task, err = s.ts.RequestTaskToExecute(s.ctx, 88)
+	task, err = s.ts.RequestTaskToExecute(s.ctx, 88, nil)
 	s.Require().NoError(err)
 	s.Require().NotNil(task)
 	s.Equal(task.Id, higherPriorityEntry.Task.Id)
@@ -134,6 +135,8 @@ func (s *TaskStorageSuite) Test_ProcessTaskResult_Retryable_Error() {
 	s.Equal(types.WaitingForExecutor, failedFromStorage.Status)
 	s.Equal(types.UnknownExecutorId, failedFromStorage.Owner)
 	s.Equal(1, failedFromStorage.RetryCount)
+	s.Require().NotNil(failedFromStorage.NextRetryAt)
+	s.Equal(now.Add(fstChildTask.RetryPolicy.Delay(1)), *failedFromStorage.NextRetryAt)
 
 	// parentTask and sndChildTask should not be affected by the failure of fstChildTask
 
@@ -148,10 +151,11 @@ func (s *TaskStorageSuite) Test_ProcessTaskResult_Retryable_Error() {
 
 func (s *TaskStorageSuite) Test_TaskRescheduling_NoEntries() {
 	executionTimeout := time.Minute
-	err := s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
+	terminated, err := s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
 	s.Require().NoError(err)
+	s.Require().Empty(terminated)
 
-	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), nil)
 	s.Require().NoError(err)
 	s.Require().Nil(taskToExecute)
 }
@@ -168,12 +172,13 @@ func (s *TaskStorageSuite) Test_TaskRescheduling_NoActiveTasks() {
 	err := s.ts.AddTaskEntries(s.ctx, entries...)
 	s.Require().NoError(err)
 
-	err = s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
+	terminated, err := s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
 	s.Require().NoError(err)
+	s.Require().Empty(terminated)
 
 	// All existing tasks are still available for execution
 	for range entries {
-		taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+		taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), nil)
 		s.Require().NoError(err)
 		s.Require().NotNil(taskToExecute)
 	}
@@ -188,11 +193,12 @@ func (s *TaskStorageSuite) Test_TaskRescheduling_SingleActiveTask() {
 	err := s.ts.AddTaskEntries(s.ctx, activeEntry)
 	s.Require().NoError(err)
 
-	err = s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
+	terminated, err := s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
 	s.Require().NoError(err)
+	s.Require().Empty(terminated)
 
 	// Active task wasn't rescheduled
-	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), nil)
 	s.Require().NoError(err)
 	s.Require().Nil(taskToExecute)
 }
@@ -211,21 +217,100 @@ func (s *TaskStorageSuite) Test_TaskRescheduling_MultipleTasks() {
 	)
 	s.Require().NoError(err)
 
-	err = s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
+	terminated, err := s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
 	s.Require().NoError(err)
+	s.Require().Empty(terminated)
 
-	// Outdated task was rescheduled and became available for execution
-	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	// Outdated task was rescheduled, but it is not available for execution until backoff delay has passed
+	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), nil)
+	s.Require().NoError(err)
+	s.Require().Nil(taskToExecute)
+
+	s.clock.Advance(outdatedEntry.RetryPolicy.Delay(1))
+
+	taskToExecute, err = s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), nil)
 	s.Require().NoError(err)
 	s.Require().NotNil(taskToExecute)
 	s.Require().Equal(outdatedEntry.Task, *taskToExecute)
 
 	// Active and failed tasks weren't rescheduled
-	taskToExecute, err = s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	taskToExecute, err = s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), nil)
 	s.Require().NoError(err)
 	s.Require().Nil(taskToExecute)
 }
 
+func (s *TaskStorageSuite) Test_TaskRescheduling_RetriesExhausted() {
+	now := s.clock.Now()
+	executionTimeout := time.Minute
+
+	parentEntry := testaide.NewTaskEntry(now, types.WaitingForInput, types.UnknownExecutorId)
+	hangingEntry := testaide.NewTaskEntry(now.Add(-executionTimeout*2), types.Running, testaide.RandomExecutorId())
+	hangingEntry.RetryPolicy = &types.TaskRetryPolicy{MaxRetries: 2}
+	hangingEntry.RetryCount = 2
+	parentEntry.AddDependency(hangingEntry)
+
+	err := s.ts.AddTaskEntries(s.ctx, parentEntry, hangingEntry)
+	s.Require().NoError(err)
+
+	terminated, err := s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
+	s.Require().NoError(err)
+	s.Require().Len(terminated, 1)
+	s.Equal(hangingEntry.Task.Id, terminated[0].Task.Id)
+	s.Require().NotNil(terminated[0].Result.Error)
+	s.Equal(types.TaskErrTimeout, terminated[0].Result.Error.ErrType)
+	s.Equal(types.TaskErrCategoryPermanent, terminated[0].Result.Error.GetCategory())
+
+	hangingFromStorage, err := s.ts.TryGetTaskEntry(s.ctx, hangingEntry.Task.Id)
+	s.Require().NoError(err)
+	s.Equal(types.Failed, hangingFromStorage.Status)
+
+	parentFromStorage, err := s.ts.TryGetTaskEntry(s.ctx, parentEntry.Task.Id)
+	s.Require().NoError(err)
+	s.Require().Contains(parentFromStorage.Task.DependencyResults, hangingEntry.Task.Id)
+}
+
+func (s *TaskStorageSuite) Test_ProcessTaskResult_RetriesExhausted() {
+	now := s.clock.Now()
+	executorId := testaide.RandomExecutorId()
+
+	entry := testaide.NewTaskEntry(now, types.Running, executorId)
+	entry.RetryPolicy = &types.TaskRetryPolicy{MaxRetries: 1}
+	entry.RetryCount = 1
+
+	err := s.ts.AddTaskEntries(s.ctx, entry)
+	s.Require().NoError(err)
+
+	err = s.ts.ProcessTaskResult(
+		s.ctx,
+		types.NewFailureProverTaskResult(
+			entry.Task.Id, executorId, types.NewTaskExecError(types.TaskErrRpc, "RPC method failed"),
+		),
+	)
+	s.Require().NoError(err)
+
+	// task was not rescheduled as its retry budget is exhausted
+	fromStorage, err := s.ts.TryGetTaskEntry(s.ctx, entry.Task.Id)
+	s.Require().NoError(err)
+	s.Require().NotNil(fromStorage)
+	s.Equal(types.Failed, fromStorage.Status)
+	s.Equal(1, fromStorage.RetryCount)
+}
+
+func (s *TaskStorageSuite) Test_AddTaskEntries_AssignsRetryPolicy() {
+	now := s.clock.Now()
+
+	entry := testaide.NewTaskEntryOfType(types.MergeProof, now, types.WaitingForExecutor, types.UnknownExecutorId)
+	err := s.ts.AddTaskEntries(s.ctx, entry)
+	s.Require().NoError(err)
+
+	fromStorage, err := s.ts.TryGetTaskEntry(s.ctx, entry.Task.Id)
+	s.Require().NoError(err)
+	s.Require().NotNil(fromStorage.RetryPolicy)
+
+	policies := types.DefaultTaskRetryPolicies()
+	s.Equal(policies.For(types.MergeProof), *fromStorage.RetryPolicy)
+}
+
 func (s *TaskStorageSuite) Test_AddSingleTaskEntry_Concurrently() {
 	now := s.clock.Now()
 
@@ -276,13 +361,13 @@ func (s *TaskStorageSuite) requireExactTasksCount(tasksCount int) {
 
 	// All added tasks became available
 	for range tasksCount {
-		task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+		task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), nil)
 		s.Require().NoError(err)
 		s.Require().NotNil(task)
 	}
 
 	// There no more tasks left
-	task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), nil)
 	s.Require().NoError(err)
 	s.Require().Nil(task)
 }
@@ -302,7 +387,7 @@ func (s *TaskStorageSuite) Test_RequestTaskToExecute_Concurrently() {
 	for range degreeOfParallelism {
 		go func() {
 			defer waitGroup.Done()
-			task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+			task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), nil)
 			s.NoError(err)
 
 			if task != nil {
@@ -342,7 +427,7 @@ func (s *TaskStorageSuite) Test_ProcessTaskResult_Concurrently() {
 	waitGroup.Wait()
 
 	// Task was successfully completed and was removed from the storage
-	task, err := s.ts.RequestTaskToExecute(s.ctx, executorId)
+	task, err := s.ts.RequestTaskToExecute(s.ctx, executorId, nil)
 	s.Require().NoError(err)
 	s.Require().Nil(task)
 }
@@ -478,8 +563,12 @@ func (s *TaskStorageSuite) Test_GetStats() {
 	s.Equal(uint32(0), stats.CountPerType[entry.Task.TaskType].ActiveCount)
 	s.Empty(stats.CountPerExecutor)
 
+	queueKey := types.TaskQueueKey{TaskType: entry.Task.TaskType, CircuitType: entry.Task.CircuitType}
+	s.Require().Len(stats.CountPerQueue, 1)
+	s.Equal(uint32(1), stats.CountPerQueue[queueKey].PendingCount)
+
 	executor := testaide.RandomExecutorId()
-	_, err = s.ts.RequestTaskToExecute(s.ctx, executor)
+	_, err = s.ts.RequestTaskToExecute(s.ctx, executor, nil)
 	s.Require().NoError(err)
 
 	stats, err = s.ts.GetTaskStats(s.ctx)
@@ -491,6 +580,7 @@ func (s *TaskStorageSuite) Test_GetStats() {
 	s.Equal(uint32(1), stats.CountPerType[entry.Task.TaskType].ActiveCount)
 	s.Require().Len(stats.CountPerExecutor, 1)
 	s.Equal(uint32(1), stats.CountPerExecutor[executor])
+	s.Equal(uint32(1), stats.CountPerQueue[queueKey].ActiveCount)
 
 	err = s.ts.ProcessTaskResult(
 		s.ctx,
@@ -504,4 +594,65 @@ func (s *TaskStorageSuite) Test_GetStats() {
 
 	s.Empty(stats.CountPerType)
 	s.Empty(stats.CountPerExecutor)
+	s.Empty(stats.CountPerQueue)
+}
+
+func (s *TaskStorageSuite) Test_RequestTaskToExecute_Capabilities() {
+	now := s.clock.Now()
+
+	partialProve := testaide.NewTaskEntryOfType(types.PartialProve, now, types.WaitingForExecutor, types.UnknownExecutorId)
+	partialProve.Task.CircuitType = types.CircuitZKEVM
+	mergeProof := testaide.NewTaskEntryOfType(types.MergeProof, now, types.WaitingForExecutor, types.UnknownExecutorId)
+	err := s.ts.AddTaskEntries(s.ctx, partialProve, mergeProof)
+	s.Require().NoError(err)
+
+	// PartialProve has higher priority, but it requires both a different circuit and a larger memory class
+	smallExecutor := types.NewExecutorCapabilities(
+		nil, []types.CircuitType{types.CircuitBytecode}, types.MemoryClassSmall, 0,
+	)
+	task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), smallExecutor)
+	s.Require().NoError(err)
+	s.Require().NotNil(task)
+	s.Equal(mergeProof.Task.Id, task.Id)
+
+	proofBatchExecutor := types.NewExecutorCapabilities(
+		[]types.TaskType{types.ProofBatch}, nil, types.MemoryClassNone, 0,
+	)
+	task, err = s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), proofBatchExecutor)
+	s.Require().NoError(err)
+	s.Nil(task)
+
+	zkevmExecutor := types.NewExecutorCapabilities(
+		nil, []types.CircuitType{types.CircuitZKEVM}, types.MemoryClassLarge, 0,
+	)
+	task, err = s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), zkevmExecutor)
+	s.Require().NoError(err)
+	s.Require().NotNil(task)
+	s.Equal(partialProve.Task.Id, task.Id)
+}
+
+func (s *TaskStorageSuite) Test_RequestTaskToExecute_MaxConcurrency() {
+	now := s.clock.Now()
+
+	first := testaide.NewTaskEntry(now, types.WaitingForExecutor, types.UnknownExecutorId)
+	second := testaide.NewTaskEntry(now, types.WaitingForExecutor, types.UnknownExecutorId)
+	err := s.ts.AddTaskEntries(s.ctx, first, second)
+	s.Require().NoError(err)
+
+	executor := testaide.RandomExecutorId()
+	capabilities := types.NewExecutorCapabilities(nil, nil, types.MemoryClassNone, 1)
+
+	task, err := s.ts.RequestTaskToExecute(s.ctx, executor, capabilities)
+	s.Require().NoError(err)
+	s.Require().NotNil(task)
+
+	// The executor is busy with the first task
+	task, err = s.ts.RequestTaskToExecute(s.ctx, executor, capabilities)
+	s.Require().NoError(err)
+	s.Nil(task)
+
+	// Other executors are not affected by the limit
+	task, err = s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), capabilities)
+	s.Require().NoError(err)
+	s.NotNil(task)
 }
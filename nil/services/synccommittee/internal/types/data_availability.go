@@ -0,0 +1,43 @@
+package types
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// DataAvailabilityMode defines the way batch data is published to L1
+type DataAvailabilityMode uint8
+
+const (
+	DataAvailabilityNone DataAvailabilityMode = iota
+	// DataAvailabilityBlob publishes batch data as EIP-4844 blobs
+	DataAvailabilityBlob
+	// DataAvailabilityCalldata publishes batch data in the input of the commit transaction
+	DataAvailabilityCalldata
+	// DataAvailabilityAuto is a configuration-only value:
+	// the cheapest of the available modes is chosen for each batch at commit time
+	DataAvailabilityAuto
+)
+
+var DataAvailabilityModes = map[string]DataAvailabilityMode{
+	"Blob":     DataAvailabilityBlob,
+	"Calldata": DataAvailabilityCalldata,
+	"Auto":     DataAvailabilityAuto,
+}
+
+func (m *DataAvailabilityMode) Set(str string) error {
+	if v, ok := DataAvailabilityModes[str]; ok {
+		*m = v
+		return nil
+	}
+	return fmt.Errorf("unknown data availability mode: %s", str)
+}
+
+func (*DataAvailabilityMode) Type() string {
+	return "DataAvailabilityMode"
+}
+
+func (*DataAvailabilityMode) PossibleValues() []string {
+	return slices.Collect(maps.Keys(DataAvailabilityModes))
+}
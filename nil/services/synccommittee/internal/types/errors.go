@@ -54,6 +54,21 @@ const (
 	TaskErrUnknown
 )
 
+// TaskErrCategory defines whether an error can be fixed by re-executing the task
+type TaskErrCategory int8
+
+const (
+	_ TaskErrCategory = iota
+
+	// TaskErrCategoryTransient indicates an error caused by the execution environment
+	// (timeout, executor crash, network issues), the task can be retried.
+	TaskErrCategoryTransient
+
+	// TaskErrCategoryPermanent indicates an error caused by the task itself (e.g. invalid trace),
+	// re-execution is pointless.
+	TaskErrCategoryPermanent
+)
+
 var RetryableErrors = map[TaskErrType]bool{
 	TaskErrTimeout:     true,
 	TaskErrRpc:         true,
@@ -63,25 +78,43 @@ var RetryableErrors = map[TaskErrType]bool{
 	TaskErrUnknown:     true,
 }
 
+// CategoryOf returns the default category of errors with the given type
+func CategoryOf(errType TaskErrType) TaskErrCategory {
+	if RetryableErrors[errType] {
+		return TaskErrCategoryTransient
+	}
+	return TaskErrCategoryPermanent
+}
+
 type TaskExecError struct {
-	ErrType TaskErrType `json:"errCode"`
-	ErrText string      `json:"errText"`
+	ErrType  TaskErrType     `json:"errCode"`
+	ErrText  string          `json:"errText"`
+	Category TaskErrCategory `json:"category,omitempty"`
 }
 
 func (e *TaskExecError) Error() string {
 	return fmt.Sprintf("%s: %s", e.ErrType, e.ErrText)
 }
 
+// GetCategory returns the error category.
+// If the category is not set explicitly (e.g. error is received from an outdated executor), it is derived from ErrType.
+func (e *TaskExecError) GetCategory() TaskErrCategory {
+	if e.Category != 0 {
+		return e.Category
+	}
+	return CategoryOf(e.ErrType)
+}
+
 func (e *TaskExecError) CanBeRetried() bool {
-	return RetryableErrors[e.ErrType]
+	return e.GetCategory() == TaskErrCategoryTransient
 }
 
 func NewTaskExecError(errType TaskErrType, errText string) *TaskExecError {
-	return &TaskExecError{ErrType: errType, ErrText: errText}
+	return &TaskExecError{ErrType: errType, ErrText: errText, Category: CategoryOf(errType)}
 }
 
 func NewTaskExecErrorf(errType TaskErrType, format string, args ...interface{}) *TaskExecError {
-	return &TaskExecError{ErrType: errType, ErrText: fmt.Sprintf(format, args...)}
+	return NewTaskExecError(errType, fmt.Sprintf(format, args...))
 }
 
 func NewTaskErrTimeout(execTime, execTimeout time.Duration) *TaskExecError {
@@ -107,3 +140,13 @@ func NewTaskErrChildFailed(childResult *TaskResult) *TaskExecError {
 func NewTaskErrUnknown(cause error) *TaskExecError {
 	return NewTaskExecErrorf(TaskErrUnknown, "%s", cause)
 }
+
+// NewTaskErrRetriesExhausted turns a transient error into a permanent one
+// after the task has reached the retry limit of its policy
+func NewTaskErrRetriesExhausted(cause *TaskExecError, retryCount int) *TaskExecError {
+	return &TaskExecError{
+		ErrType:  cause.ErrType,
+		ErrText:  fmt.Sprintf("retries exhausted: retryCount=%d, lastError=%s", retryCount, cause.ErrText),
+		Category: TaskErrCategoryPermanent,
+	}
+}
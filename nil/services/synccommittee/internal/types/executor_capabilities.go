@@ -0,0 +1,109 @@
+package types
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// MemoryClass defines the amount of memory available to a task executor
+type MemoryClass uint8
+
+const (
+	// MemoryClassNone means that executor memory is not specified, it is considered to be able to handle any task
+	MemoryClassNone MemoryClass = iota
+	MemoryClassSmall
+	MemoryClassMedium
+	MemoryClassLarge
+)
+
+var MemoryClasses = map[string]MemoryClass{
+	"Small":  MemoryClassSmall,
+	"Medium": MemoryClassMedium,
+	"Large":  MemoryClassLarge,
+}
+
+func (c *MemoryClass) Set(str string) error {
+	if v, ok := MemoryClasses[str]; ok {
+		*c = v
+		return nil
+	}
+	return fmt.Errorf("unknown memory class: %s", str)
+}
+
+func (*MemoryClass) Type() string {
+	return "MemoryClass"
+}
+
+func (*MemoryClass) PossibleValues() []string {
+	return slices.Collect(maps.Keys(MemoryClasses))
+}
+
+// RequiredMemoryClass returns the minimal executor memory class needed to handle a task of the given type
+func (t TaskType) RequiredMemoryClass() MemoryClass {
+	switch t {
+	case PartialProve, AggregatedFRI:
+		return MemoryClassLarge
+	case CombinedQ, FRIConsistencyChecks:
+		return MemoryClassMedium
+	default:
+		return MemoryClassSmall
+	}
+}
+
+// ExecutorCapabilities describes which tasks a particular executor is able to handle.
+// Empty TaskTypes / CircuitTypes mean that any task / circuit type is supported,
+// zero MaxConcurrency means that the number of tasks running simultaneously on the executor is not limited.
+type ExecutorCapabilities struct {
+	TaskTypes      []TaskType    `json:"taskTypes,omitempty"`
+	CircuitTypes   []CircuitType `json:"circuitTypes,omitempty"`
+	MemoryClass    MemoryClass   `json:"memoryClass,omitempty"`
+	MaxConcurrency uint32        `json:"maxConcurrency,omitempty"`
+}
+
+func NewExecutorCapabilities(
+	taskTypes []TaskType,
+	circuitTypes []CircuitType,
+	memoryClass MemoryClass,
+	maxConcurrency uint32,
+) *ExecutorCapabilities {
+	return &ExecutorCapabilities{
+		TaskTypes:      taskTypes,
+		CircuitTypes:   circuitTypes,
+		MemoryClass:    memoryClass,
+		MaxConcurrency: maxConcurrency,
+	}
+}
+
+// CanExecute checks if the executor is able to handle tasks of the given type and circuit.
+// Nil capabilities are treated as unrestricted.
+func (c *ExecutorCapabilities) CanExecute(taskType TaskType, circuitType CircuitType) bool {
+	if c == nil {
+		return true
+	}
+	if len(c.TaskTypes) > 0 && !slices.Contains(c.TaskTypes, taskType) {
+		return false
+	}
+	// tasks without a circuit type are not bound to a particular circuit
+	if circuitType != None && len(c.CircuitTypes) > 0 && !slices.Contains(c.CircuitTypes, circuitType) {
+		return false
+	}
+	if c.MemoryClass != MemoryClassNone && c.MemoryClass < taskType.RequiredMemoryClass() {
+		return false
+	}
+	return true
+}
+
+// HasFreeSlot checks if the executor is able to accept one more task given the number of its running tasks
+func (c *ExecutorCapabilities) HasFreeSlot(runningCount uint32) bool {
+	if c == nil || c.MaxConcurrency == 0 {
+		return true
+	}
+	return runningCount < c.MaxConcurrency
+}
+
+// TaskQueueKey identifies a group of tasks which require the same executor capabilities
+type TaskQueueKey struct {
+	TaskType    TaskType
+	CircuitType CircuitType
+}
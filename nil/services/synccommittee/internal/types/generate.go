@@ -5,3 +5,6 @@ package types
 //go:generate stringer -type=TaskStatus -trimprefix=TaskStatus
 //go:generate stringer -type=CircuitType -trimprefix=Circuit
 //go:generate stringer -type=TaskErrType -trimprefix=TaskErr
+//go:generate stringer -type=TaskErrCategory -trimprefix=TaskErrCategory
+//go:generate stringer -type=DataAvailabilityMode -trimprefix=DataAvailability
+//go:generate stringer -type=MemoryClass -trimprefix=MemoryClass
@@ -35,6 +35,20 @@ func Circuits() iter.Seq[CircuitType] {
 	}
 }
 
+func (c *CircuitType) Set(str string) error {
+	for circuit := range Circuits() {
+		if circuit.String() == str {
+			*c = circuit
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown circuit type: %s", str)
+}
+
+func (*CircuitType) Type() string {
+	return "CircuitType"
+}
+
 // TaskId Unique ID of a task, serves as a key in DB
 type TaskId uuid.UUID
 
@@ -147,6 +161,12 @@ type TaskEntry struct {
 
 	// RetryCount specifies the number of times the task execution has been retried
 	RetryCount int
+
+	// RetryPolicy defines retry budget and backoff of the task, nil means that the default policy is applied
+	RetryPolicy *TaskRetryPolicy
+
+	// NextRetryAt is the earliest time the rescheduled task can be assigned to an executor
+	NextRetryAt *time.Time
 }
 
 // AddDependency adds a dependency to the current task entry and updates the dependents and pending dependencies.
@@ -222,8 +242,8 @@ func (t *TaskEntry) Terminate(result *TaskResult, currentTime time.Time) error {
 }
 
 // ResetRunning resets a task's status from Running to WaitingForExecutor, clearing its start time
-// and executor ownership.
-func (t *TaskEntry) ResetRunning() error {
+// and executor ownership. Next execution attempt is postponed according to the task retry policy.
+func (t *TaskEntry) ResetRunning(currentTime time.Time) error {
 	if t.Status != Running {
 		return errTaskInvalidStatus(t, "ResetRunning")
 	}
@@ -232,9 +252,32 @@ func (t *TaskEntry) ResetRunning() error {
 	t.Status = WaitingForExecutor
 	t.Owner = UnknownExecutorId
 	t.RetryCount++
+
+	policy := t.GetRetryPolicy()
+	nextRetryAt := currentTime.Add(policy.Delay(t.RetryCount))
+	t.NextRetryAt = &nextRetryAt
 	return nil
 }
 
+// GetRetryPolicy returns the retry policy of the task
+func (t *TaskEntry) GetRetryPolicy() TaskRetryPolicy {
+	if t.RetryPolicy == nil {
+		return DefaultTaskRetryPolicy()
+	}
+	return *t.RetryPolicy
+}
+
+// HasRetriesLeft checks if the task can be rescheduled once more after a transient failure
+func (t *TaskEntry) HasRetriesLeft() bool {
+	policy := t.GetRetryPolicy()
+	return policy.HasRetriesLeft(t.RetryCount)
+}
+
+// IsBackoffElapsed checks if the backoff delay of a rescheduled task has passed
+func (t *TaskEntry) IsBackoffElapsed(currentTime time.Time) bool {
+	return t.NextRetryAt == nil || !currentTime.Before(*t.NextRetryAt)
+}
+
 func errTaskInvalidStatus(task *TaskEntry, methodName string) error {
 	return fmt.Errorf("%w: id=%s, status=%s, operation=%s", ErrTaskInvalidStatus, task.Task.Id, task.Status, methodName)
 }
@@ -0,0 +1,158 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TaskRetryPolicy defines how many times a task failed with a transient error can be rescheduled
+// and how long it should wait before the next attempt.
+// Delay before the N-th retry is InitialDelay * 2^(N-1), capped by MaxDelay.
+type TaskRetryPolicy struct {
+	MaxRetries   int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+func NewTaskRetryPolicy(maxRetries int, initialDelay, maxDelay time.Duration) TaskRetryPolicy {
+	return TaskRetryPolicy{
+		MaxRetries:   maxRetries,
+		InitialDelay: initialDelay,
+		MaxDelay:     maxDelay,
+	}
+}
+
+func DefaultTaskRetryPolicy() TaskRetryPolicy {
+	return NewTaskRetryPolicy(5, 10*time.Second, 10*time.Minute)
+}
+
+// Validate checks that the retry budget and the delays of the policy are not negative
+func (p *TaskRetryPolicy) Validate() error {
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("max retries must not be negative, got %d", p.MaxRetries)
+	}
+	if p.InitialDelay < 0 || p.MaxDelay < 0 {
+		return fmt.Errorf("retry delays must not be negative, got %s and %s", p.InitialDelay, p.MaxDelay)
+	}
+	if p.InitialDelay > p.MaxDelay {
+		return fmt.Errorf("initial retry delay %s exceeds max retry delay %s", p.InitialDelay, p.MaxDelay)
+	}
+	return nil
+}
+
+// HasRetriesLeft checks if a task which has already been retried retryCount times can be retried once more
+func (p *TaskRetryPolicy) HasRetriesLeft(retryCount int) bool {
+	return retryCount < p.MaxRetries
+}
+
+// Delay returns the backoff delay before the retry with the given number (starting from 1)
+func (p *TaskRetryPolicy) Delay(retryNumber int) time.Duration {
+	if retryNumber <= 0 || p.InitialDelay <= 0 {
+		return 0
+	}
+
+	delay := p.InitialDelay
+	for range retryNumber - 1 {
+		if delay >= p.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+// TaskRetryPolicies holds retry policies for each task type
+type TaskRetryPolicies struct {
+	Default TaskRetryPolicy
+	PerType map[TaskType]TaskRetryPolicy
+}
+
+func DefaultTaskRetryPolicies() TaskRetryPolicies {
+	return TaskRetryPolicies{
+		Default: DefaultTaskRetryPolicy(),
+		PerType: map[TaskType]TaskRetryPolicy{
+			// Aggregation tasks are cheap compared to the partial proof generation, it's ok to retry them more often
+			AggregatedChallenge: NewTaskRetryPolicy(10, 5*time.Second, 5*time.Minute),
+			AggregatedFRI:       NewTaskRetryPolicy(10, 5*time.Second, 5*time.Minute),
+			MergeProof:          NewTaskRetryPolicy(10, 5*time.Second, 5*time.Minute),
+		},
+	}
+}
+
+// For returns the retry policy for the given task type
+func (p *TaskRetryPolicies) For(taskType TaskType) TaskRetryPolicy {
+	if policy, ok := p.PerType[taskType]; ok {
+		return policy
+	}
+	return p.Default
+}
+
+// Validate checks the default policy and the policies of all task types
+func (p *TaskRetryPolicies) Validate() error {
+	if err := p.Default.Validate(); err != nil {
+		return fmt.Errorf("invalid default retry policy: %w", err)
+	}
+	for taskType, policy := range p.PerType {
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("invalid retry policy of %s tasks: %w", taskType, err)
+		}
+	}
+	return nil
+}
+
+// Set overrides the policy of a single task type, so that the policies can be passed as a repeatable flag.
+// Expected format is "<TaskType>=<MaxRetries>:<InitialDelay>:<MaxDelay>", e.g. "AggregatedFRI=10:5s:5m".
+func (p *TaskRetryPolicies) Set(str string) error {
+	typeStr, policyStr, ok := strings.Cut(str, "=")
+	if !ok {
+		return fmt.Errorf("invalid retry policy %q: expected <TaskType>=<MaxRetries>:<InitialDelay>:<MaxDelay>", str)
+	}
+	var taskType TaskType
+	if err := taskType.Set(typeStr); err != nil {
+		return err
+	}
+
+	parts := strings.Split(policyStr, ":")
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid retry policy %q: expected <MaxRetries>:<InitialDelay>:<MaxDelay>", policyStr)
+	}
+	maxRetries, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return fmt.Errorf("invalid max retries: %w", err)
+	}
+	initialDelay, err := time.ParseDuration(parts[1])
+	if err != nil {
+		return fmt.Errorf("invalid initial retry delay: %w", err)
+	}
+	maxDelay, err := time.ParseDuration(parts[2])
+	if err != nil {
+		return fmt.Errorf("invalid max retry delay: %w", err)
+	}
+
+	policy := NewTaskRetryPolicy(maxRetries, initialDelay, maxDelay)
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("invalid retry policy of %s tasks: %w", taskType, err)
+	}
+	if p.PerType == nil {
+		p.PerType = make(map[TaskType]TaskRetryPolicy)
+	}
+	p.PerType[taskType] = policy
+	return nil
+}
+
+func (*TaskRetryPolicies) Type() string {
+	return "TaskRetryPolicy"
+}
+
+func (p *TaskRetryPolicies) String() string {
+	overrides := make([]string, 0, len(p.PerType))
+	for taskType, policy := range p.PerType {
+		overrides = append(overrides, fmt.Sprintf(
+			"%s=%d:%s:%s", taskType, policy.MaxRetries, policy.InitialDelay, policy.MaxDelay))
+	}
+	slices.Sort(overrides)
+	return strings.Join(overrides, ",")
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRetryPolicies_Set(t *testing.T) {
+	t.Parallel()
+
+	policies := DefaultTaskRetryPolicies()
+	require.NoError(t, policies.Set("PartialProve=3:1s:1m"))
+	require.NoError(t, policies.Set("AggregatedFRI=0:0s:0s"))
+	require.NoError(t, policies.Validate())
+
+	require.Equal(t, NewTaskRetryPolicy(3, time.Second, time.Minute), policies.For(PartialProve))
+	require.Equal(t, NewTaskRetryPolicy(0, 0, 0), policies.For(AggregatedFRI))
+	require.Equal(t, DefaultTaskRetryPolicy(), policies.For(ProofBatch))
+
+	for _, invalid := range []string{
+		"PartialProve",
+		"UnknownTask=1:1s:1m",
+		"PartialProve=1:1s",
+		"PartialProve=x:1s:1m",
+		"PartialProve=1:x:1m",
+		"PartialProve=-1:1s:1m",
+		"PartialProve=1:1m:1s",
+	} {
+		require.Error(t, policies.Set(invalid), invalid)
+	}
+}
+
+func TestTaskRetryPolicies_Validate(t *testing.T) {
+	t.Parallel()
+
+	policies := DefaultTaskRetryPolicies()
+	require.NoError(t, policies.Validate())
+
+	policies.Default.MaxRetries = -1
+	require.Error(t, policies.Validate())
+
+	policies = DefaultTaskRetryPolicies()
+	policies.PerType[MergeProof] = NewTaskRetryPolicy(1, -time.Second, time.Second)
+	require.Error(t, policies.Validate())
+}
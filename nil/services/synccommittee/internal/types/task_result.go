@@ -52,6 +52,15 @@ func (r *TaskResult) HasRetryableError() bool {
 	return !r.IsSuccess() && r.Error.CanBeRetried()
 }
 
+// WithRetriesExhausted returns a copy of the result with the transient error turned into a permanent one.
+func (r *TaskResult) WithRetriesExhausted(retryCount int) *TaskResult {
+	check.PanicIff(!r.HasRetryableError(), "result has no retryable error")
+
+	exhausted := *r
+	exhausted.Error = NewTaskErrRetriesExhausted(r.Error, retryCount)
+	return &exhausted
+}
+
 // ValidateForTask checks the correctness of the TaskResult
 // against the given TaskEntry and returns an error if invalid.
 func (r *TaskResult) ValidateForTask(entry *TaskEntry) error {
@@ -145,3 +154,10 @@ func NewTaskResultDetails(result *TaskResult, taskEntry *TaskEntry, currentTime
 		ExecutionTime: *taskEntry.ExecutionTime(currentTime),
 	}
 }
+
+// TerminatedTask holds a task which was terminated without an executor result,
+// e.g. a hanging task which has exhausted its retry budget
+type TerminatedTask struct {
+	Task   Task
+	Result *TaskResult
+}
@@ -13,26 +13,35 @@ type TaskStatNumbers struct {
 type TaskStats struct {
 	CountPerType     map[TaskType]TaskStatNumbers
 	CountPerExecutor map[TaskExecutorId]uint32
+	// CountPerQueue contains numbers of tasks grouped by the executor capabilities they require
+	CountPerQueue map[TaskQueueKey]TaskStatNumbers
 }
 
 func NewEmptyTaskStats() *TaskStats {
 	return &TaskStats{
 		CountPerType:     make(map[TaskType]TaskStatNumbers),
 		CountPerExecutor: make(map[TaskExecutorId]uint32),
+		CountPerQueue:    make(map[TaskQueueKey]TaskStatNumbers),
 	}
 }
 
 func (s *TaskStats) Add(entry *TaskEntry) {
 	statNumbersByType := s.CountPerType[entry.Task.TaskType]
+	queueKey := TaskQueueKey{TaskType: entry.Task.TaskType, CircuitType: entry.Task.CircuitType}
+	statNumbersByQueue := s.CountPerQueue[queueKey]
 	switch entry.Status {
 	case Running:
 		statNumbersByType.ActiveCount++
 		s.CountPerType[entry.Task.TaskType] = statNumbersByType
+		statNumbersByQueue.ActiveCount++
+		s.CountPerQueue[queueKey] = statNumbersByQueue
 		s.CountPerExecutor[entry.Owner]++
 
 	case WaitingForExecutor, WaitingForInput:
 		statNumbersByType.PendingCount++
 		s.CountPerType[entry.Task.TaskType] = statNumbersByType
+		statNumbersByQueue.PendingCount++
+		s.CountPerQueue[queueKey] = statNumbersByQueue
 
 	case Failed, Completed:
 		return
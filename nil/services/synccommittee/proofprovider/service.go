@@ -13,6 +13,7 @@ import (
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/scheduler"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/srv"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/storage"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
 	"github.com/jonboulle/clockwork"
 )
 
@@ -21,6 +22,7 @@ type Config struct {
 	TaskListenerRpcEndpoint  string
 	SkipRate                 int
 	MaxConcurrentBatches     uint32
+	TaskSchedulerConfig      scheduler.Config
 	Telemetry                *telemetry.Config
 }
 
@@ -30,6 +32,7 @@ func NewDefaultConfig() *Config {
 		TaskListenerRpcEndpoint:  "tcp://127.0.0.1:8531",
 		SkipRate:                 0,
 		MaxConcurrentBatches:     1,
+		TaskSchedulerConfig:      scheduler.DefaultConfig(),
 		Telemetry: &telemetry.Config{
 			ServiceName: "proof_provider",
 		},
@@ -58,10 +61,24 @@ func New(config *Config, database db.DB) (*ProofProvider, error) {
 	taskResultStorage := storage.NewTaskResultStorage(database, logger)
 	taskResultSender := scheduler.NewTaskResultSender(taskRpcClient, taskResultStorage, logger)
 
-	taskStorage := storage.NewTaskStorage(database, clock, metricsHandler, logger)
+	if err := config.TaskSchedulerConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid task scheduler config: %w", err)
+	}
+	taskStorage := storage.NewTaskStorage(
+		database,
+		storage.NewTaskStorageConfig(config.TaskSchedulerConfig.RetryPolicies),
+		clock,
+		metricsHandler,
+		logger,
+	)
+
+	executorConfig := executor.DefaultConfig()
+	executorConfig.Capabilities = types.NewExecutorCapabilities(
+		[]types.TaskType{types.ProofBatch}, nil, types.MemoryClassNone, 0,
+	)
 
 	taskExecutor, err := executor.New(
-		executor.DefaultConfig(),
+		executorConfig,
 		taskRpcClient,
 		newTaskHandler(taskStorage, taskResultStorage, config.SkipRate, config.MaxConcurrentBatches, clock, logger),
 		metricsHandler,
@@ -72,6 +89,7 @@ func New(config *Config, database db.DB) (*ProofProvider, error) {
 	}
 
 	taskScheduler := scheduler.New(
+		config.TaskSchedulerConfig,
 		taskStorage,
 		newTaskStateChangeHandler(taskResultStorage, taskExecutor.Id(), logger),
 		metricsHandler,
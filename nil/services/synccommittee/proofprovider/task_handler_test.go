@@ -37,7 +37,7 @@ func (s *TaskHandlerTestSuite) SetupSuite() {
 	metricsHandler, err := metrics.NewProofProviderMetrics()
 	s.Require().NoError(err)
 
-	s.taskStorage = storage.NewTaskStorage(s.database, clockwork.NewRealClock(), metricsHandler, logger)
+	s.taskStorage = storage.NewTaskStorage(s.database, storage.DefaultTaskStorageConfig(), clockwork.NewRealClock(), metricsHandler, logger)
 	taskResultStorage := storage.NewTaskResultStorage(s.database, logger)
 	s.clock = testaide.NewTestClock()
 	maxConcurrentBatches := uint32(1) // enough to handle only one batch
@@ -156,7 +156,7 @@ func (s *TaskHandlerTestSuite) requestTask(
 	expectedType types.TaskType,
 ) *types.Task {
 	s.T().Helper()
-	t, err := s.taskStorage.RequestTaskToExecute(s.context, executorId)
+	t, err := s.taskStorage.RequestTaskToExecute(s.context, executorId, nil)
 	s.Require().NoError(err)
 	if !available {
 		s.Require().Nil(t)
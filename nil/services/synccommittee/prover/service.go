@@ -3,6 +3,7 @@ package prover
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/common/logging"
@@ -14,13 +15,18 @@ import (
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/scheduler"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/srv"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/storage"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
 	"github.com/jonboulle/clockwork"
 )
 
 type Config struct {
 	ProofProviderRpcEndpoint string
 	NilRpcEndpoint           string
-	Telemetry                *telemetry.Config
+	// CircuitTypes restricts circuits the prover is able to handle, empty list means all circuits
+	CircuitTypes   []types.CircuitType
+	MemoryClass    types.MemoryClass
+	MaxConcurrency uint32
+	Telemetry      *telemetry.Config
 }
 
 func NewDefaultConfig() *Config {
@@ -60,8 +66,13 @@ func New(config Config, database db.DB) (*Prover, error) {
 		newTaskHandlerConfig(config.NilRpcEndpoint),
 	)
 
+	executorConfig := executor.DefaultConfig()
+	executorConfig.Capabilities = types.NewExecutorCapabilities(
+		proverTaskTypes(), config.CircuitTypes, config.MemoryClass, config.MaxConcurrency,
+	)
+
 	taskExecutor, err := executor.New(
-		executor.DefaultConfig(),
+		executorConfig,
 		taskRpcClient,
 		handler,
 		metricsHandler,
@@ -82,3 +93,15 @@ func New(config Config, database db.DB) (*Prover, error) {
 func NewRPCClient(endpoint string, logger logging.Logger) client.Client {
 	return rpc.NewRetryClient(endpoint, logger)
 }
+
+// proverTaskTypes returns all task types except ProofBatch, which is handled by the proof provider
+func proverTaskTypes() []types.TaskType {
+	taskTypes := make([]types.TaskType, 0, len(types.TaskTypes))
+	for _, taskType := range types.TaskTypes {
+		if taskType != types.ProofBatch {
+			taskTypes = append(taskTypes, taskType)
+		}
+	}
+	slices.Sort(taskTypes)
+	return taskTypes
+}
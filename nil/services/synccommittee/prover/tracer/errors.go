@@ -10,4 +10,5 @@ var (
 	ErrTracedBlockHashMismatch = errors.New("generated traced block and fetched block hashes are not equal")
 	ErrClientReturnedNilBlock  = errors.New("client returned nil block")
 	ErrBlocksNotSequential     = errors.New("blocks being traced are not sequential")
+	ErrTraceInconsistent       = errors.New("execution traces are inconsistent")
 )
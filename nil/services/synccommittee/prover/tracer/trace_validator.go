@@ -0,0 +1,362 @@
+package tracer
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/params"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/prover/tracer/internal/mpttracer"
+	"github.com/holiman/uint256"
+)
+
+// TraceViolation describes the first trace entry which breaks internal consistency of ExecutionTraces
+type TraceViolation struct {
+	Trace  string // name of the violated trace section, e.g. "stack" or "mpt/contract"
+	Index  int    // index of the violating entry inside the section
+	Entry  any    // violating entry itself
+	Reason string
+}
+
+func (v *TraceViolation) Error() string {
+	return fmt.Sprintf("%s trace entry #%d: %s", v.Trace, v.Index, v.Reason)
+}
+
+func (v *TraceViolation) Unwrap() error {
+	return ErrTraceInconsistent
+}
+
+func newTraceViolation(trace string, index int, entry any, format string, args ...any) *TraceViolation {
+	return &TraceViolation{
+		Trace:  trace,
+		Index:  index,
+		Entry:  entry,
+		Reason: fmt.Sprintf(format, args...),
+	}
+}
+
+// ValidateTraces checks internal consistency of execution traces without access to the chain state.
+// Validation stops on the first violation which is returned as *TraceViolation.
+func ValidateTraces(traces *ExecutionTraces) error {
+	checks := []func(*ExecutionTraces) error{
+		validateRwOrdering,
+		validateStackOps,
+		validateMemoryOps,
+		validateCopyEvents,
+		validateExpOps,
+		validateKeccakTraces,
+		validateZKEVMStates,
+		validateMPTTraces,
+	}
+	for _, check := range checks {
+		if err := check(traces); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRwOrdering checks that stack, memory and storage operations share rw counter without collisions
+func validateRwOrdering(traces *ExecutionTraces) error {
+	owners := make(map[uint]string, len(traces.StackOps)+len(traces.MemoryOps)+len(traces.StorageOps))
+	claim := func(trace string, index int, entry any, rwIdx uint) error {
+		if owner, ok := owners[rwIdx]; ok {
+			return newTraceViolation(trace, index, entry, "rw index %d is already used by %s trace", rwIdx, owner)
+		}
+		owners[rwIdx] = trace
+		return nil
+	}
+
+	for i, op := range traces.StackOps {
+		if i > 0 && op.RwIdx <= traces.StackOps[i-1].RwIdx {
+			return newTraceViolation("stack", i, op, "rw index %d is not increasing", op.RwIdx)
+		}
+		if err := claim("stack", i, op, op.RwIdx); err != nil {
+			return err
+		}
+	}
+	for i, op := range traces.MemoryOps {
+		if i > 0 && op.RwIdx <= traces.MemoryOps[i-1].RwIdx {
+			return newTraceViolation("memory", i, op, "rw index %d is not increasing", op.RwIdx)
+		}
+		if err := claim("memory", i, op, op.RwIdx); err != nil {
+			return err
+		}
+	}
+	for i, op := range traces.StorageOps {
+		if i > 0 && op.RwIdx <= traces.StorageOps[i-1].RwIdx {
+			return newTraceViolation("storage", i, op, "rw index %d is not increasing", op.RwIdx)
+		}
+		if err := claim("storage", i, op, op.RwIdx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateStackOps(traces *ExecutionTraces) error {
+	for i, op := range traces.StackOps {
+		if op.Idx < 0 || uint64(op.Idx) >= params.StackLimit {
+			return newTraceViolation("stack", i, op, "stack index %d is out of bounds", op.Idx)
+		}
+		if i > 0 && op.TxnId < traces.StackOps[i-1].TxnId {
+			return newTraceViolation("stack", i, op, "transaction id %d is decreasing", op.TxnId)
+		}
+	}
+	return nil
+}
+
+func validateMemoryOps(traces *ExecutionTraces) error {
+	for i, op := range traces.MemoryOps {
+		if op.Idx < 0 {
+			return newTraceViolation("memory", i, op, "memory index %d is negative", op.Idx)
+		}
+		if i > 0 && op.TxnId < traces.MemoryOps[i-1].TxnId {
+			return newTraceViolation("memory", i, op, "transaction id %d is decreasing", op.TxnId)
+		}
+	}
+	return nil
+}
+
+// validateCopyEvents checks that every copy event is backed by memory operations of the same length and content
+func validateCopyEvents(traces *ExecutionTraces) error {
+	memOps := traces.MemoryOps
+	for i, event := range traces.CopyEvents {
+		if len(event.Data) == 0 {
+			return newTraceViolation("copy", i, event, "empty copy event")
+		}
+		if i > 0 && event.RwIdx < traces.CopyEvents[i-1].RwIdx {
+			return newTraceViolation("copy", i, event, "rw index %d is decreasing", event.RwIdx)
+		}
+
+		if event.To.Location == CopyLocationKeccak {
+			if event.To.KeccakHash == nil {
+				return newTraceViolation("copy", i, event, "keccak hash is not set")
+			}
+			if hash := common.Keccak256Hash(event.Data); hash != *event.To.KeccakHash {
+				return newTraceViolation("copy", i, event, "keccak hash mismatch: expected %s, got %s",
+					hash, *event.To.KeccakHash)
+			}
+		}
+
+		// memory operations of the opcode start at the rw index of the event: reads go first, writes follow them
+		opIdx := sort.Search(len(memOps), func(j int) bool { return memOps[j].RwIdx >= event.RwIdx })
+		if event.From.Location == CopyLocationMemory {
+			if err := checkCopiedMemory(memOps, opIdx, true, i, event, event.From.MemAddress); err != nil {
+				return err
+			}
+			opIdx += len(event.Data)
+		}
+		if event.To.Location == CopyLocationMemory {
+			if err := checkCopiedMemory(memOps, opIdx, false, i, event, event.To.MemAddress); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkCopiedMemory(memOps []MemoryOp, start int, isRead bool, eventIdx int, event CopyEvent, addr uint64) error {
+	if start+len(event.Data) > len(memOps) {
+		return newTraceViolation("copy", eventIdx, event, "%d bytes copied, but only %d memory ops left",
+			len(event.Data), len(memOps)-start)
+	}
+	for j, value := range event.Data {
+		op := memOps[start+j]
+		if op.IsRead != isRead || uint64(op.Idx) != addr+uint64(j) || op.Value != value {
+			return newTraceViolation("copy", eventIdx, event, "byte %d does not match memory op with rw index %d",
+				j, op.RwIdx)
+		}
+	}
+	return nil
+}
+
+func validateExpOps(traces *ExecutionTraces) error {
+	for i, op := range traces.ExpOps {
+		if op.Base == nil || op.Exponent == nil || op.Result == nil {
+			return newTraceViolation("exp", i, op, "operand is not set")
+		}
+		if expected := new(uint256.Int).Exp(op.Base, op.Exponent); !expected.Eq(op.Result) {
+			return newTraceViolation("exp", i, op, "result mismatch: expected %s, got %s", expected, op.Result)
+		}
+	}
+	return nil
+}
+
+func validateKeccakTraces(traces *ExecutionTraces) error {
+	for i, trace := range traces.KeccakTraces {
+		if hash := common.Keccak256Hash(trace.buf); hash != trace.hash {
+			return newTraceViolation("keccak", i, trace, "hash mismatch: expected %s, got %s", hash, trace.hash)
+		}
+	}
+	return nil
+}
+
+// opcodes which pass control to another frame, so the next traced state belongs to a different gas context
+var frameSwitchingOpCodes = map[vm.OpCode]struct{}{
+	vm.CALL:         {},
+	vm.CALLCODE:     {},
+	vm.DELEGATECALL: {},
+	vm.STATICCALL:   {},
+	vm.CREATE:       {},
+	vm.CREATE2:      {},
+	vm.RETURN:       {},
+	vm.REVERT:       {},
+	vm.STOP:         {},
+	vm.SELFDESTRUCT: {},
+	vm.INVALID:      {},
+}
+
+// isSequentialStep checks that the state `next` is the direct continuation of `cur` within the same frame
+func isSequentialStep(cur, next *ZKEVMState) bool {
+	if cur.TxId != next.TxId || cur.BytecodeHash != next.BytecodeHash {
+		return false
+	}
+	if _, ok := frameSwitchingOpCodes[cur.OpCode]; ok {
+		return false
+	}
+	switch cur.OpCode {
+	case vm.JUMP:
+		return next.OpCode == vm.JUMPDEST
+	case vm.JUMPI:
+		return next.OpCode == vm.JUMPDEST || next.PC == cur.PC+1
+	}
+	nextPC := cur.PC + 1
+	if cur.OpCode.IsPush() {
+		nextPC += uint64(cur.OpCode - vm.PUSH0)
+	}
+	return next.PC == nextPC
+}
+
+// validateZKEVMStates checks state ordering and that every opcode was charged at least its static gas
+func validateZKEVMStates(traces *ExecutionTraces) error {
+	states := traces.ZKEVMStates
+	for i := range states {
+		state := &states[i]
+		if state.StackSize > params.StackLimit {
+			return newTraceViolation("zkevm", i, *state, "stack size %d exceeds limit", state.StackSize)
+		}
+		if required := vm.CancunInstructionSet.GetNumRequiredStackItems(state.OpCode); len(state.StackSlice) != required {
+			return newTraceViolation("zkevm", i, *state, "%d stack items saved for %s, expected %d",
+				len(state.StackSlice), state.OpCode, required)
+		}
+		if i == 0 {
+			continue
+		}
+
+		prev := &states[i-1]
+		if state.TxId < prev.TxId {
+			return newTraceViolation("zkevm", i, *state, "transaction id %d is decreasing", state.TxId)
+		}
+		if state.RwIdx < prev.RwIdx {
+			return newTraceViolation("zkevm", i, *state, "rw index %d is decreasing", state.RwIdx)
+		}
+		if !isSequentialStep(prev, state) {
+			continue
+		}
+		if state.Gas > prev.Gas {
+			return newTraceViolation("zkevm", i, *state, "gas increased from %d to %d after %s",
+				prev.Gas, state.Gas, prev.OpCode)
+		}
+		if spent, static := prev.Gas-state.Gas, vm.CancunInstructionSet.GetConstantGas(prev.OpCode); spent < static {
+			return newTraceViolation("zkevm", i, *state, "%s spent %d gas, static cost is %d",
+				prev.OpCode, spent, static)
+		}
+	}
+	return nil
+}
+
+type sszValue[V any] interface {
+	*V
+	MarshalSSZ() ([]byte, error)
+}
+
+func validateMPTTraces(traces *ExecutionTraces) error {
+	if traces.MPTTraces == nil {
+		return nil
+	}
+
+	addresses := slices.SortedFunc(maps.Keys(traces.MPTTraces.StorageTracesByAccount), func(a, b types.Address) int {
+		return bytes.Compare(a.Bytes(), b.Bytes())
+	})
+	for _, addr := range addresses {
+		storageTraces := traces.MPTTraces.StorageTracesByAccount[addr]
+		if err := validateTrieTraces("mpt/storage/"+addr.Hex(), storageTraces); err != nil {
+			return err
+		}
+	}
+	return validateTrieTraces("mpt/contract", traces.MPTTraces.ContractTrieTraces)
+}
+
+// validateTrieTraces checks that each update trace proves its value before against RootBefore,
+// leads to RootAfter and that consecutive updates of the trie are chained
+func validateTrieTraces[V any, VPtr sszValue[V]](trace string, updates []mpttracer.GenericTrieUpdateTrace[VPtr]) error {
+	for i, update := range updates {
+		if i > 0 && updates[i-1].RootAfter != update.RootBefore {
+			return newTraceViolation(trace, i, update, "root before %s does not match previous root after %s",
+				update.RootBefore, updates[i-1].RootAfter)
+		}
+
+		valueBefore, err := encodeTrieValue(update.ValueBefore)
+		if err != nil {
+			return newTraceViolation(trace, i, update, "failed to encode value before: %s", err)
+		}
+		valueAfter, err := encodeTrieValue(update.ValueAfter)
+		if err != nil {
+			return newTraceViolation(trace, i, update, "failed to encode value after: %s", err)
+		}
+		if valueBefore == nil && valueAfter == nil {
+			return newTraceViolation(trace, i, update, "both values before and after are empty")
+		}
+
+		key := update.Key.Bytes()
+		if ok, err := update.Proof.VerifyRead(key, valueBefore, update.RootBefore); err != nil || !ok {
+			return newTraceViolation(trace, i, update, "proof does not match value before and root %s (err: %v)",
+				update.RootBefore, err)
+		}
+
+		rootAfter, err := applyTrieUpdate(&update.Proof, key, valueAfter)
+		if err != nil {
+			return newTraceViolation(trace, i, update, "failed to apply update to proof: %s", err)
+		}
+		if rootAfter != update.RootAfter {
+			return newTraceViolation(trace, i, update, "root after update is %s, expected %s",
+				rootAfter, update.RootAfter)
+		}
+	}
+	return nil
+}
+
+// encodeTrieValue returns SSZ representation of the value stored in trie, nil stands for a missing value
+func encodeTrieValue[V any, VPtr sszValue[V]](value VPtr) ([]byte, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return value.MarshalSSZ()
+}
+
+// applyTrieUpdate restores sparse trie from the proof, sets the key (deletes it if value is nil)
+// and returns the resulting root
+func applyTrieUpdate(proof *mpt.Proof, key []byte, value []byte) (common.Hash, error) {
+	trie := mpt.NewInMemMPT()
+	if err := mpt.PopulateMptWithProof(trie, proof); err != nil {
+		return common.EmptyHash, err
+	}
+
+	var err error
+	if value == nil {
+		err = trie.Delete(key)
+	} else {
+		err = trie.Set(key, value)
+	}
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return trie.RootHash(), nil
+}
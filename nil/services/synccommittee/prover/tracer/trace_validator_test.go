@@ -0,0 +1,172 @@
+package tracer
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/prover/tracer/internal/mpttracer"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+// newConsistentTraces builds traces of `PUSH1 PUSH1 KECCAK256` followed by a single storage slot update
+func newConsistentTraces(t *testing.T) *ExecutionTraces {
+	t.Helper()
+
+	data := []byte{0x01, 0x02}
+	hash := common.Keccak256Hash(data)
+	txId := uint(0)
+
+	traces := NewExecutionTraces()
+	traces.StackOps = []StackOp{
+		{IsRead: false, Idx: 0, RwIdx: 0},
+		{IsRead: false, Idx: 1, RwIdx: 1},
+		{IsRead: true, Idx: 1, RwIdx: 2},
+		{IsRead: true, Idx: 0, RwIdx: 3},
+		{IsRead: false, Idx: 0, RwIdx: 6},
+	}
+	traces.MemoryOps = []MemoryOp{
+		{IsRead: true, Idx: 0, Value: data[0], RwIdx: 4},
+		{IsRead: true, Idx: 1, Value: data[1], RwIdx: 5},
+	}
+	traces.CopyEvents = []CopyEvent{{
+		From:  CopyParticipant{Location: CopyLocationMemory, TxId: &txId, MemAddress: 0},
+		To:    CopyParticipant{Location: CopyLocationKeccak, KeccakHash: &hash},
+		RwIdx: 4,
+		Data:  data,
+	}}
+	traces.KeccakTraces = []KeccakBuffer{{buf: data, hash: hash}}
+	traces.ExpOps = []ExpOp{{Base: uint256.NewInt(2), Exponent: uint256.NewInt(10), Result: uint256.NewInt(1024)}}
+	traces.ZKEVMStates = []ZKEVMState{
+		{PC: 0, Gas: 100, RwIdx: 0, OpCode: vm.PUSH1, StackSlice: []types.Uint256{}},
+		{PC: 2, Gas: 97, RwIdx: 1, OpCode: vm.PUSH1, StackSlice: []types.Uint256{}},
+		{PC: 4, Gas: 94, RwIdx: 2, OpCode: vm.KECCAK256, StackSlice: make([]types.Uint256, 2)},
+	}
+	traces.MPTTraces = &mpttracer.MPTTraces{
+		StorageTracesByAccount: map[types.Address][]mpttracer.StorageTrieUpdateTrace{
+			types.HexToAddress("0x0001"): {newStorageUpdateTrace(t)},
+		},
+	}
+	return traces
+}
+
+func newStorageUpdateTrace(t *testing.T) mpttracer.StorageTrieUpdateTrace {
+	t.Helper()
+
+	trie := execution.NewStorageTrie(mpt.NewInMemMPT())
+	key := common.BytesToHash([]byte("key"))
+	before, after := types.NewUint256(1), types.NewUint256(2)
+	require.NoError(t, trie.Update(common.BytesToHash([]byte("other")), types.NewUint256(3)))
+	require.NoError(t, trie.Update(key, before))
+
+	proof, err := mpt.BuildProof(trie.Reader, key.Bytes(), mpt.ReadMPTOperation)
+	require.NoError(t, err)
+	rootBefore := trie.RootHash()
+	require.NoError(t, trie.Update(key, after))
+
+	return mpttracer.StorageTrieUpdateTrace{
+		Key:         key,
+		RootBefore:  rootBefore,
+		RootAfter:   trie.RootHash(),
+		ValueBefore: before,
+		ValueAfter:  after,
+		Proof:       proof,
+	}
+}
+
+func TestValidateTraces_Consistent(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateTraces(newConsistentTraces(t)))
+}
+
+func TestValidateTraces_ReportsFirstViolation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		corrupt       func(traces *ExecutionTraces)
+		expectedTrace string
+		expectedIndex int
+	}{
+		{
+			name:          "DuplicatedRwIndex",
+			corrupt:       func(traces *ExecutionTraces) { traces.MemoryOps[0].RwIdx = 3 },
+			expectedTrace: "memory",
+			expectedIndex: 0,
+		},
+		{
+			name:          "UnorderedStackOps",
+			corrupt:       func(traces *ExecutionTraces) { traces.StackOps[2].RwIdx = 0 },
+			expectedTrace: "stack",
+			expectedIndex: 2,
+		},
+		{
+			name:          "CopyLengthMismatch",
+			corrupt:       func(traces *ExecutionTraces) { traces.MemoryOps = traces.MemoryOps[:1] },
+			expectedTrace: "copy",
+			expectedIndex: 0,
+		},
+		{
+			name:          "CopyDataMismatch",
+			corrupt:       func(traces *ExecutionTraces) { traces.MemoryOps[1].Value = 0xff },
+			expectedTrace: "copy",
+			expectedIndex: 0,
+		},
+		{
+			name:          "WrongExpResult",
+			corrupt:       func(traces *ExecutionTraces) { traces.ExpOps[0].Result = uint256.NewInt(1000) },
+			expectedTrace: "exp",
+			expectedIndex: 0,
+		},
+		{
+			name:          "WrongKeccakHash",
+			corrupt:       func(traces *ExecutionTraces) { traces.KeccakTraces[0].hash = common.EmptyHash },
+			expectedTrace: "keccak",
+			expectedIndex: 0,
+		},
+		{
+			name:          "GasBelowStaticCost",
+			corrupt:       func(traces *ExecutionTraces) { traces.ZKEVMStates[2].Gas = 96 },
+			expectedTrace: "zkevm",
+			expectedIndex: 2,
+		},
+		{
+			name: "WrongMPTRootAfter",
+			corrupt: func(traces *ExecutionTraces) {
+				traces.MPTTraces.StorageTracesByAccount[types.HexToAddress("0x0001")][0].RootAfter = common.EmptyHash
+			},
+			expectedTrace: "mpt/storage/" + types.HexToAddress("0x0001").Hex(),
+			expectedIndex: 0,
+		},
+		{
+			name: "WrongMPTValueBefore",
+			corrupt: func(traces *ExecutionTraces) {
+				traces.MPTTraces.StorageTracesByAccount[types.HexToAddress("0x0001")][0].ValueBefore = types.NewUint256(5)
+			},
+			expectedTrace: "mpt/storage/" + types.HexToAddress("0x0001").Hex(),
+			expectedIndex: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			traces := newConsistentTraces(t)
+			testCase.corrupt(traces)
+
+			err := ValidateTraces(traces)
+			require.ErrorIs(t, err, ErrTraceInconsistent)
+
+			var violation *TraceViolation
+			require.ErrorAs(t, err, &violation)
+			require.Equal(t, testCase.expectedTrace, violation.Trace)
+			require.Equal(t, testCase.expectedIndex, violation.Index)
+		})
+	}
+}
@@ -270,6 +270,7 @@ func (s *TracerMockClientTestSuite) simpleContractCallTrace(code []byte) *Execut
 
 	err = esTracer.saveTransactionTraces()
 	s.Require().NoError(err)
+	s.Require().NoError(ValidateTraces(esTracer.Traces))
 	return esTracer.Traces
 }
 
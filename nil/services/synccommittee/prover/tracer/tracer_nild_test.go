@@ -47,7 +47,7 @@ func (s *TracerNildTestSuite) waitTwoBlocks() {
 func (s *TracerNildTestSuite) initTracer() RemoteTracesCollector {
 	s.T().Helper()
 	var err error
-	tracer, err := NewRemoteTracesCollector(s.Context, s.Client, logging.NewLogger("tracer-test"))
+	tracer, err := NewRemoteTracesCollector(s.Context, s.Client, logging.NewLogger("tracer-test"), false)
 	s.Require().NoError(err)
 	return tracer
 }
@@ -152,6 +152,10 @@ func (s *TracerNildTestSuite) TestCounterContract() {
 		s.checkBlocksRangeTracesSerialization(latestBlocks, false)
 		s.checkBlocksRangeTracesSerialization(latestBlocks, true)
 	})
+
+	s.Run("LocalCollectorMatchesRemote", func() {
+		s.checkLocalTracesMatchRemote(latestBlocks)
+	})
 }
 
 func (s *TracerNildTestSuite) TestTestContract() {
@@ -305,3 +309,27 @@ func (s *TracerNildTestSuite) checkBlocksRangeTracesSerialization(from []types.B
 		}
 	}
 }
+
+// checkLocalTracesMatchRemote collects traces for the same blocks over RPC and directly from the node database
+// and checks that results are identical
+func (s *TracerNildTestSuite) checkLocalTracesMatchRemote(from []types.BlockNumber) {
+	s.T().Helper()
+	latestBlocksForShards := s.getLatestBlocksForShards()
+	for shardId, latestBlockNum := range latestBlocksForShards {
+		blockIds := make([]BlockId, 0, latestBlockNum-from[shardId]+1)
+		for blockNum := max(from[shardId], 1); blockNum <= latestBlockNum; blockNum++ {
+			blockIds = append(
+				blockIds, BlockId{types.ShardId(shardId), transport.Uint64BlockReference(blockNum.Uint64())},
+			)
+		}
+		cfg := &TraceConfig{BlockIDs: blockIds}
+
+		remoteTraces, err := CollectTraces(s.Context, s.Client, cfg)
+		s.Require().NoError(err)
+
+		localTraces, err := CollectLocalTraces(s.Context, s.Db, cfg)
+		s.Require().NoError(err)
+
+		s.Require().Equal(remoteTraces, localTraces)
+	}
+}
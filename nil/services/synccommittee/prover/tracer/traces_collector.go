@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/config"
@@ -13,6 +14,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/mpt"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/prover/tracer/api"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/prover/tracer/internal/mpttracer"
@@ -33,6 +35,9 @@ type TraceConfig struct {
 	BlockIDs     []BlockId
 	BaseFileName string
 	MarshalMode  MarshalMode
+	// EnableDevCheats must be set for the networks running with the dev API,
+	// so that the dev cheats included into the blocks are applied as the validators do.
+	EnableDevCheats bool
 }
 
 // remoteTracesCollectorImpl implements RemoteTracesCollector interface
@@ -42,6 +47,7 @@ type remoteTracesCollectorImpl struct {
 	mptTracer       *mpttracer.MPTTracer
 	rwTx            db.RwTx
 	lastTracedBlock *types.BlockNumber
+	enableDevCheats bool
 }
 
 var _ RemoteTracesCollector = (*remoteTracesCollectorImpl)(nil)
@@ -51,6 +57,7 @@ func NewRemoteTracesCollector(
 	ctx context.Context,
 	client api.RpcClient,
 	logger logging.Logger,
+	enableDevCheats bool,
 ) (RemoteTracesCollector, error) {
 	localDb, err := db.NewBadgerDbInMemory()
 	if err != nil {
@@ -63,12 +70,44 @@ func NewRemoteTracesCollector(
 	}
 
 	return &remoteTracesCollectorImpl{
-		client: client,
-		logger: logger,
-		rwTx:   rwTx,
+		client:          client,
+		logger:          logger,
+		rwTx:            rwTx,
+		enableDevCheats: enableDevCheats,
 	}, nil
 }
 
+// NewLocalTracesCollector creates a collector which reads blocks, config and state tries directly
+// from the node database instead of fetching them over RPC.
+// Database is accessed through the same API implementation as RPC server uses,
+// thus, traces are identical to the ones collected by the remote collector.
+// The set of shards is read from the latest main shard block of the database.
+func NewLocalTracesCollector(
+	ctx context.Context,
+	database db.ReadOnlyDB,
+	logger logging.Logger,
+	enableDevCheats bool,
+) (RemoteTracesCollector, error) {
+	mainShardApi := rawapi.NewLocalShardApi(types.MainShardId, database, nil, false)
+	nShards, err := mainShardApi.GetNumShards(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read number of shards from database: %w", err)
+	}
+
+	shardApis := make(map[types.ShardId]rawapi.ShardApi, nShards)
+	shardApis[types.MainShardId] = mainShardApi
+	for shardId := types.MainShardId + 1; shardId < types.ShardId(nShards); shardId++ {
+		shardApis[shardId] = rawapi.NewLocalShardApi(shardId, database, nil, false)
+	}
+
+	localClient, err := client.NewEthClient(ctx, database, rawapi.NewNodeApiOverShardApis(shardApis), logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create local client: %w", err)
+	}
+
+	return NewRemoteTracesCollector(ctx, localClient, logger, enableDevCheats)
+}
+
 // initMptTracer initializes the MPT tracer with the given block number and contract trie root
 func (tc *remoteTracesCollectorImpl) initMptTracer(
 	shardId types.ShardId,
@@ -174,12 +213,17 @@ func (tc *remoteTracesCollectorImpl) executeBlockAndCollectTraces(
 ) (*ExecutionTraces, error) {
 	configAccessor := config.NewConfigAccessorFromMap(configMap)
 
+	var devCheats *execution.DevCheats
+	if tc.enableDevCheats {
+		devCheats = execution.NewDevCheats()
+	}
 	es, err := execution.NewExecutionState(
 		tc.rwTx,
 		shardId,
 		execution.StateParams{
 			Block:          prevBlock.Block,
 			ConfigAccessor: configAccessor,
+			DevCheats:      devCheats,
 		},
 	)
 	if err != nil {
@@ -231,6 +275,11 @@ func (tc *remoteTracesCollectorImpl) executeBlockAndCollectTraces(
 		return nil, esTracer.TracingError
 	}
 
+	// MPT traces are read from the tries in the database, so the nodes stored by the block are written to it
+	if err := es.FlushTrieNodes(); err != nil {
+		return nil, fmt.Errorf("failed to flush trie nodes: %w", err)
+	}
+
 	// Validate generated block hash matches expected
 	expectedHash := currentBlock.Hash(shardId)
 	if generatedBlock.BlockHash != expectedHash {
@@ -360,13 +409,32 @@ func (tc *remoteTracesCollectorImpl) GetMPTTraces() (mpttracer.MPTTraces, error)
 // thus, `MarshalMode` and `BaseFileName` fields of the config are not used and could be omitted.
 // Blocks in `BlockIDs` config field must be sequential, otherwise, `ErrBlocksNotSequential` will be raised.
 func CollectTraces(ctx context.Context, rpcClient api.RpcClient, cfg *TraceConfig) (*ExecutionTraces, error) {
-	remoteTracesCollector, err := NewRemoteTracesCollector(ctx, rpcClient, logging.NewLogger("tracer"))
+	remoteTracesCollector, err := NewRemoteTracesCollector(
+		ctx, rpcClient, logging.NewLogger("tracer"), cfg.EnableDevCheats)
 	if err != nil {
 		return nil, err
 	}
+	return collectTraces(ctx, remoteTracesCollector, cfg)
+}
+
+// CollectLocalTraces does the same as CollectTraces, but reads blocks and state from the node database.
+func CollectLocalTraces(
+	ctx context.Context,
+	database db.ReadOnlyDB,
+	cfg *TraceConfig,
+) (*ExecutionTraces, error) {
+	localTracesCollector, err := NewLocalTracesCollector(
+		ctx, database, logging.NewLogger("tracer"), cfg.EnableDevCheats)
+	if err != nil {
+		return nil, err
+	}
+	return collectTraces(ctx, localTracesCollector, cfg)
+}
+
+func collectTraces(ctx context.Context, collector RemoteTracesCollector, cfg *TraceConfig) (*ExecutionTraces, error) {
 	aggregatedTraces := NewExecutionTraces()
 	for _, blockID := range cfg.BlockIDs {
-		traces, err := remoteTracesCollector.GetBlockTraces(ctx, blockID)
+		traces, err := collector.GetBlockTraces(ctx, blockID)
 		if err != nil {
 			return nil, err
 		}
@@ -375,7 +443,7 @@ func CollectTraces(ctx context.Context, rpcClient api.RpcClient, cfg *TraceConfi
 
 	// FIXME: MPT trace aggregates changes from multiple sequential blocks,
 	// and can't be constructed from multiple shards.
-	mptTraces, err := remoteTracesCollector.GetMPTTraces()
+	mptTraces, err := collector.GetMPTTraces()
 	if err != nil {
 		return nil, err
 	}
@@ -392,3 +460,12 @@ func CollectTracesToFile(ctx context.Context, rpcClient api.RpcClient, cfg *Trac
 
 	return SerializeToFile(traces, cfg.MarshalMode, cfg.BaseFileName)
 }
+
+func CollectLocalTracesToFile(ctx context.Context, database db.ReadOnlyDB, cfg *TraceConfig) error {
+	traces, err := CollectLocalTraces(ctx, database, cfg)
+	if err != nil {
+		return err
+	}
+
+	return SerializeToFile(traces, cfg.MarshalMode, cfg.BaseFileName)
+}
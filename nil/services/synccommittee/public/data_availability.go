@@ -0,0 +1,10 @@
+package public
+
+import "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+
+type DataAvailabilityMode = types.DataAvailabilityMode
+
+const (
+	DataAvailabilityBlob     = types.DataAvailabilityBlob
+	DataAvailabilityCalldata = types.DataAvailabilityCalldata
+)
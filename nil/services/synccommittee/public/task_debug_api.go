@@ -10,9 +10,10 @@ import (
 )
 
 const (
-	DebugNamespace   = "Debug"
-	DebugGetTasks    = DebugNamespace + "_getTasks"
-	DebugGetTaskTree = DebugNamespace + "_getTaskTree"
+	DebugNamespace     = "Debug"
+	DebugGetTasks      = DebugNamespace + "_getTasks"
+	DebugGetTaskTree   = DebugNamespace + "_getTaskTree"
+	DebugGetTaskQueues = DebugNamespace + "_getTaskQueues"
 )
 
 const (
@@ -133,4 +134,7 @@ type TaskDebugApi interface {
 
 	// GetTaskTree retrieves the task tree structure for a specific task identified by taskId
 	GetTaskTree(ctx context.Context, taskId TaskId) (*TaskTreeView, error)
+
+	// GetTaskQueues retrieves the number of tasks grouped by the executor capabilities they require
+	GetTaskQueues(ctx context.Context) ([]*TaskQueueView, error)
 }
@@ -0,0 +1,32 @@
+package public
+
+import "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+
+type (
+	MemoryClass          = types.MemoryClass
+	ExecutorCapabilities = types.ExecutorCapabilities
+)
+
+// TaskQueueView represents tasks requiring the same executor capabilities
+type TaskQueueView struct {
+	TaskType     TaskType    `json:"taskType"`
+	CircuitType  CircuitType `json:"circuitType"`
+	ActiveCount  uint32      `json:"activeCount"`
+	PendingCount uint32      `json:"pendingCount"`
+	// CapableExecutors is the number of recently active executors able to handle tasks from the queue
+	CapableExecutors uint32 `json:"capableExecutors"`
+}
+
+func NewTaskQueueView(
+	key types.TaskQueueKey,
+	numbers types.TaskStatNumbers,
+	capableExecutors uint32,
+) *TaskQueueView {
+	return &TaskQueueView{
+		TaskType:         key.TaskType,
+		CircuitType:      key.CircuitType,
+		ActiveCount:      numbers.ActiveCount,
+		PendingCount:     numbers.PendingCount,
+		CapableExecutors: capableExecutors,
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
@@ -30,3 +34,83 @@ func PublishPendingTransaction(
 
 	return networkManager.PubSub().Publish(ctx, topicPendingTransactions(shardId), data)
 }
+
+// validateNetworkTxn rejects the transactions received from the network that the pool would never accept
+// or that fail the auth data verification, so that they are not relayed to the other peers.
+// The transactions already known to the pool are ignored.
+func (p *TxnPool) validateNetworkTxn(ctx context.Context, msg network.PubSubMessage) network.ValidationResult {
+	txn := &types.Transaction{}
+	if err := txn.UnmarshalSSZ(msg.Data); err != nil {
+		return network.ValidationReject
+	}
+
+	if txn.To.ShardId() != p.cfg.ShardId || txn.ChainId != types.DefaultChainId {
+		return network.ValidationReject
+	}
+	if txn.IsDeploy() && execution.ValidateDeployTransaction(txn) != nil {
+		return network.ValidationReject
+	}
+	if known, err := p.IdHashKnown(txn.Hash()); err != nil || known {
+		return network.ValidationIgnore
+	}
+	return p.verifyNetworkTxnAuth(ctx, txn)
+}
+
+// verifyNetworkTxnAuth verifies the auth data of the transaction against the latest state of the shard.
+// Only the failed verification is a reason to reject the transaction. The other failures depend on the state,
+// which the sender may see differently, so such transactions are ignored.
+func (p *TxnPool) verifyNetworkTxnAuth(ctx context.Context, txn *types.Transaction) network.ValidationResult {
+	if p.cfg.Database == nil {
+		return network.ValidationAccept
+	}
+
+	res, err := p.verifyTxnAuth(ctx, txn)
+	if err != nil {
+		p.logger.Error().Err(err).
+			Stringer(logging.FieldTransactionHash, txn.Hash()).
+			Msg("Failed to verify transaction from network")
+		return network.ValidationIgnore
+	}
+
+	switch {
+	case res.Error == nil:
+		return network.ValidationAccept
+	case res.Error.Code() == types.ErrorExternalVerificationFailed:
+		return network.ValidationReject
+	default:
+		return network.ValidationIgnore
+	}
+}
+
+func (p *TxnPool) verifyTxnAuth(ctx context.Context, txn *types.Transaction) (*execution.ExecutionResult, error) {
+	tx, err := p.cfg.Database.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	block, _, err := db.ReadLastBlock(tx, p.cfg.ShardId)
+	if err != nil {
+		return nil, err
+	}
+
+	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, block, p.cfg.ShardId)
+	if err != nil {
+		return nil, err
+	}
+
+	es, err := execution.NewExecutionState(tx, p.cfg.ShardId, execution.StateParams{
+		Block:          block,
+		ConfigAccessor: configAccessor,
+		Mode:           execution.ModeReadOnly,
+		DevCheats:      p.cfg.DevCheats,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := execution.VerifyExternalTransactionAuth(es, txn)
+	if res.IsFatal() {
+		return nil, res.FatalError
+	}
+	return res, nil
+}
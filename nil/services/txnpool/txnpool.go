@@ -45,6 +45,9 @@ type TxnPool struct {
 	all    *ByReceiverAndSeqno // from => (sorted map of txn seqno => *txn)
 	queue  *TxnQueue
 	logger logging.Logger
+
+	// added receives a signal when transactions are added to the pool
+	added chan struct{}
 }
 
 func New(ctx context.Context, cfg Config, networkManager *network.Manager) (*TxnPool, error) {
@@ -62,6 +65,8 @@ func New(ctx context.Context, cfg Config, networkManager *network.Manager) (*Txn
 		all:    NewBySenderAndSeqno(logger),
 		queue:  &TxnQueue{},
 		logger: logger,
+
+		added: make(chan struct{}, 1),
 	}
 
 	if networkManager == nil {
@@ -69,7 +74,7 @@ func New(ctx context.Context, cfg Config, networkManager *network.Manager) (*Txn
 		return res, nil
 	}
 
-	sub, err := networkManager.PubSub().Subscribe(topicPendingTransactions(cfg.ShardId))
+	sub, err := networkManager.PubSub().Subscribe(topicPendingTransactions(cfg.ShardId), res.validateNetworkTxn)
 	if err != nil {
 		return nil, err
 	}
@@ -168,6 +173,7 @@ func (p *TxnPool) add(txns ...*metaTxn) ([]DiscardReason, error) {
 			continue
 		}
 		discardReasons[i] = NotSet // unnecessary
+		p.notifyAdded()
 		p.logger.Debug().
 			Uint64(logging.FieldShardId, uint64(txn.To.ShardId())).
 			Stringer(logging.FieldTransactionHash, txn.Hash()).
@@ -444,3 +450,57 @@ func (p *TxnPool) Peek(n int) ([]*types.TxnWithHash, error) {
 
 	return res, nil
 }
+
+// Snapshot holds the transactions of the pool saved by TxnPool.Snapshot.
+type Snapshot struct {
+	baseFee types.Value
+	txns    []*types.Transaction
+}
+
+// Snapshot saves the transactions of the pool, so that they can be brought back by Restore.
+func (p *TxnPool) Snapshot() *Snapshot {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	res := &Snapshot{baseFee: p.baseFee}
+	p.all.ascendAll(func(txn *metaTxn) bool {
+		res.txns = append(res.txns, txn.Transaction)
+		return true
+	})
+	return res
+}
+
+// Restore replaces the transactions of the pool with the ones of the snapshot.
+// The transactions aren't published to the network again.
+func (p *TxnPool) Restore(snapshot *Snapshot) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.baseFee = snapshot.baseFee
+	p.byHash = map[string]*metaTxn{}
+	p.all = NewBySenderAndSeqno(p.logger)
+	p.queue = &TxnQueue{}
+	for _, txn := range snapshot.txns {
+		if reason := p.addLocked(newMetaTxn(txn, p.baseFee)); reason != NotSet {
+			p.logger.Debug().
+				Stringer(logging.FieldTransactionHash, txn.Hash()).
+				Msgf("Discarded restored transaction with reason %s", reason)
+		}
+	}
+	if len(snapshot.txns) > 0 {
+		p.notifyAdded()
+	}
+}
+
+// Added returns the channel receiving a signal when transactions are added to the pool.
+// Signals aren't queued while the previous one isn't received, so the channel is meant for a single reader.
+func (p *TxnPool) Added() <-chan struct{} {
+	return p.added
+}
+
+func (p *TxnPool) notifyAdded() {
+	select {
+	case p.added <- struct{}{}:
+	default:
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/rs/zerolog"
@@ -339,6 +342,113 @@ func (s *SuiteTxnPool) TestNetwork() {
 	}, 20*time.Second, 200*time.Millisecond)
 }
 
+func (s *SuiteTxnPool) TestValidateNetworkTxn() {
+	validate := func(txn *types.Transaction) network.ValidationResult {
+		s.T().Helper()
+
+		data, err := txn.MarshalSSZ()
+		s.Require().NoError(err)
+		return s.pool.validateNetworkTxn(s.ctx, network.PubSubMessage{Data: data})
+	}
+
+	s.Run("Accept", func() {
+		s.Equal(network.ValidationAccept, validate(newTransaction(defaultAddress, 0, 123)))
+	})
+
+	s.Run("RejectMalformed", func() {
+		s.Equal(network.ValidationReject, s.pool.validateNetworkTxn(s.ctx, network.PubSubMessage{Data: []byte{1, 2, 3}}))
+	})
+
+	s.Run("RejectOtherShard", func() {
+		txn := newTransaction(types.ShardAndHexToAddress(1, "11"), 0, 123)
+		s.Equal(network.ValidationReject, validate(txn))
+	})
+
+	s.Run("RejectChainId", func() {
+		txn := newTransaction(defaultAddress, 0, 123)
+		txn.ChainId = types.DefaultChainId + 1
+		s.Equal(network.ValidationReject, validate(txn))
+	})
+
+	s.Run("RejectDeploy", func() {
+		// The deployment to the main shard is never accepted
+		payload := types.BuildDeployPayload([]byte{1}, common.EmptyHash)
+		txn := newTransaction(types.CreateAddress(0, payload), 0, 123)
+		txn.Flags = types.NewTransactionFlags(types.TransactionFlagDeploy)
+		txn.Data = payload.Bytes()
+		s.Equal(network.ValidationReject, validate(txn))
+	})
+
+	s.Run("IgnoreKnown", func() {
+		txn := newTransaction(defaultAddress, 1, 123)
+		s.addTransactionsSuccessfully(txn)
+		s.Equal(network.ValidationIgnore, validate(txn))
+	})
+}
+
+func (s *SuiteTxnPool) TestValidateNetworkTxnAuth() {
+	database, err := db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	defer database.Close()
+
+	// verifyExternal returns the pushed value
+	verifierCode := func(result byte) []byte {
+		return []byte{
+			0x60, result, // PUSH1 result
+			0x60, 0x00, // PUSH1 0
+			0x52,       // MSTORE
+			0x60, 0x20, // PUSH1 32
+			0x60, 0x00, // PUSH1 0
+			0xf3, // RETURN
+		}
+	}
+	verified := types.ShardAndHexToAddress(0, "21")
+	unverified := types.ShardAndHexToAddress(0, "22")
+	noBalance := types.ShardAndHexToAddress(0, "23")
+
+	tx, err := database.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	es, err := execution.NewExecutionState(tx, 0, execution.StateParams{ConfigAccessor: config.GetStubAccessor()})
+	s.Require().NoError(err)
+	for addr, code := range map[types.Address][]byte{
+		verified:   verifierCode(1),
+		unverified: verifierCode(0),
+		noBalance:  verifierCode(1),
+	} {
+		s.Require().NoError(es.CreateAccount(addr))
+		s.Require().NoError(es.SetCode(addr, code))
+		if addr != noBalance {
+			s.Require().NoError(es.SetBalance(addr, types.GasToValue(1_000_000_000)))
+		}
+	}
+	blockRes, err := es.Commit(0, nil)
+	s.Require().NoError(err)
+	s.Require().NoError(execution.PostprocessBlock(tx, 0, blockRes, execution.ModeVerify))
+	s.Require().NoError(tx.Commit())
+
+	cfg := NewConfig(0)
+	cfg.Database = database
+	pool, err := New(s.ctx, cfg, nil)
+	s.Require().NoError(err)
+
+	validate := func(address types.Address) network.ValidationResult {
+		s.T().Helper()
+
+		data, err := newTransaction(address, 0, 123).MarshalSSZ()
+		s.Require().NoError(err)
+		return pool.validateNetworkTxn(s.ctx, network.PubSubMessage{Data: data})
+	}
+
+	s.Equal(network.ValidationAccept, validate(verified))
+	s.Equal(network.ValidationReject, validate(unverified))
+	// The verification depends on the state
+	s.Equal(network.ValidationIgnore, validate(noBalance))
+	// There is nothing to verify the transaction with
+	s.Equal(network.ValidationAccept, validate(defaultAddress))
+}
+
 func (s *SuiteTxnPool) TestUnverifiedDuplicates() {
 	txn1 := newTransaction(defaultAddress, 0, 123)
 	txn2 := newTransaction(defaultAddress, 1, 123)
@@ -349,6 +459,46 @@ func (s *SuiteTxnPool) TestUnverifiedDuplicates() {
 	s.Require().NoError(err)
 }
 
+func (s *SuiteTxnPool) TestSnapshotRestore() {
+	address2 := types.ShardAndHexToAddress(0, "deadbeef02")
+
+	txn11 := newTransaction(defaultAddress, 0, 123)
+	txn12 := newTransaction(defaultAddress, 1, 123)
+	txn21 := newTransaction(address2, 0, 123)
+
+	s.addTransactionsSuccessfully(txn11, txn12, txn21)
+	snapshot := s.pool.Snapshot()
+
+	err := s.pool.OnCommitted(s.ctx, defaultBaseFee, []*types.Transaction{txn11, txn21})
+	s.Require().NoError(err)
+	s.addTransactionsSuccessfully(newTransaction(address2, 1, 123))
+
+	s.pool.Restore(snapshot)
+	s.Equal(3, s.getTransactionCount(s.pool))
+	for _, txn := range []*types.Transaction{txn11, txn12, txn21} {
+		known, err := s.pool.IdHashKnown(txn.Hash())
+		s.Require().NoError(err)
+		s.True(known)
+	}
+	seqno, inPool := s.pool.SeqnoToAddress(address2)
+	s.True(inPool)
+	s.Equal(types.Seqno(0), seqno)
+}
+
+func (s *SuiteTxnPool) TestAdded() {
+	s.Empty(s.pool.Added())
+
+	txn := newTransaction(defaultAddress, 0, 123)
+	s.addTransactionsSuccessfully(txn)
+	s.addTransactionsSuccessfully(newTransaction(defaultAddress, 1, 123))
+	s.Len(s.pool.Added(), 1)
+	<-s.pool.Added()
+
+	// Rejected transactions don't notify
+	s.addTransactionWithDiscardReason(txn, SeqnoTooLow)
+	s.Empty(s.pool.Added())
+}
+
 func (s *SuiteTxnPool) checkTransactionsOrder(vals ...int) {
 	s.T().Helper()
 
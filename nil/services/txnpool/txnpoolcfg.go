@@ -3,6 +3,8 @@ package txnpool
 import (
 	"fmt"
 
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
 
@@ -11,6 +13,11 @@ const defaultPoolSize = 10000
 type Config struct {
 	ShardId types.ShardId
 	Size    uint64
+
+	// Database is used to verify the auth data of the transactions received from the network.
+	// The verification is skipped if it is not set.
+	Database  db.DB
+	DevCheats *execution.DevCheats
 }
 
 func NewConfig(shardId types.ShardId) Config {
@@ -422,6 +422,38 @@ func (s *SuiteAsyncAwait) TestRequestResponse() {
 		s.checkAsyncContextEmpty(s.testAddress0)
 	})
 
+	s.Run("Call Counter.get via callback request", func() {
+		intContext := big.NewInt(789)
+		strContext := "Captured"
+
+		data := s.AbiPack(s.abiTest, "callbackRequestCounterGet", s.counterAddress0, intContext, strContext)
+		receipt := s.SendExternalTransactionNoCheck(data, s.testAddress0)
+		s.Require().True(receipt.AllSuccess())
+
+		tests.CheckContractValueEqual(
+			s.T(), s.Context, s.DefaultClient, s.abiTest, s.testAddress0, "counterValue", int32(123))
+		tests.CheckContractValueEqual(
+			s.T(), s.Context, s.DefaultClient, s.abiTest, s.testAddress0, "intValue", intContext)
+		tests.CheckContractValueEqual(
+			s.T(), s.Context, s.DefaultClient, s.abiTest, s.testAddress0, "strValue", strContext)
+
+		info = s.AnalyzeReceipt(receipt, map[types.Address]string{})
+		initialBalance = s.CheckBalance(info, initialBalance.Add(valueReservedAsync), s.accounts)
+		s.checkAsyncContextEmpty(s.testAddress0)
+	})
+
+	s.Run("Callback request with too large context", func() {
+		data := s.AbiPack(s.abiTest, "callbackRequestTooLargeContext", s.counterAddress0)
+		receipt := s.SendExternalTransactionNoCheck(data, s.testAddress0)
+		s.Require().False(receipt.Success)
+		s.Require().Equal("CallbackContextTooLarge", receipt.Status)
+		s.Empty(receipt.OutReceipts)
+
+		info = s.AnalyzeReceipt(receipt, map[types.Address]string{})
+		initialBalance = s.CheckBalance(info, initialBalance, s.accounts)
+		s.checkAsyncContextEmpty(s.testAddress0)
+	})
+
 	s.Run("Call Counter.add", func() {
 		data := s.AbiPack(s.abiTest, "requestCounterAdd", s.counterAddress0, int32(100))
 		receipt := s.SendExternalTransactionNoCheck(data, s.testAddress0)
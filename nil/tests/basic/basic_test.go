@@ -15,7 +15,6 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
-	"github.com/NilFoundation/nil/nil/internal/params"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/internal/vm"
 	"github.com/NilFoundation/nil/nil/services/nilservice"
@@ -360,12 +359,14 @@ func (s *SuiteRpc) TestRpcCallWithTransactionSend() { //nolint:maintidx
 		s.Require().Empty(res.Error)
 		s.Require().Len(res.OutTransactions, 1)
 
-		value := res.CoinsUsed.
-			Add(res.OutTransactions[0].CoinsUsed).
-			Add(s.GasToValue(3 * params.SstoreSentryGasEIP2200)).
-			Add(s.GasToValue(10_000)). // external transaction verification
-			Mul64(12).Div64(10)        // stock 20%
+		s.Require().Len(estimation.Hops, 3)
+		value := callArgs.Value
+		for _, hop := range estimation.Hops {
+			value = value.Add(hop.FeeCredit)
+		}
 		s.Equal(estimation.FeeCredit.Uint64(), value.Uint64())
+		s.Equal(smartAccountAddr, estimation.Hops[0].To)
+		s.Equal(counterAddr, estimation.Hops[1].To)
 
 		txn := res.OutTransactions[0]
 		s.Equal(smartAccountAddr, txn.Transaction.From)
@@ -51,6 +51,12 @@ contract NilRollup is
     /// @dev Error when commitBatch is called on batchIndex which is already finalized
     error ErrorBatchAlreadyFinalized(string batchIndex);
 
+    /// @dev Error when commitBatchCalldata is called with empty batch data
+    error ErrorEmptyBatchData(string batchIndex);
+
+    /// @dev Error when the batch data passed to commitBatchCalldata has unknown version or wrong length prefix
+    error ErrorInvalidBatchDataFrame(string batchIndex);
+
     /// @dev Error when the versionHash for a blob at blobIndex in invalid
     error ErrorInvalidVersionedHash(string batchIndex, uint256 blobIndex);
 
@@ -116,6 +122,12 @@ contract NilRollup is
     /// @dev Address of the kzg precompiled contract.
     address public constant POINT_EVALUATION_PRECOMPILE_ADDR = address(0x0A);
 
+    /// @dev Version of the batch data frame passed to commitBatchCalldata.
+    uint8 public constant CALLDATA_FRAME_VERSION = 1;
+
+    /// @dev Size of the batch data frame header: 1 byte version followed by 4 bytes big-endian data length.
+    uint256 internal constant CALLDATA_FRAME_HEADER_SIZE = 5;
+
     /*//////////////////////////////////////////////////////////////////////////
                                   STATE VARIABLES
     //////////////////////////////////////////////////////////////////////////*/
@@ -138,8 +150,11 @@ contract NilRollup is
     /// @dev mapping of batchIndex to BatchInformation
     mapping(string => BatchInfo) public batchInfoRecords;
 
+    /// @dev mapping of batchIndex to the keccak256 hash of the batch data committed via calldata
+    mapping(string => bytes32) public batchCalldataHashes;
+
     /// @dev The storage slots for future usage.
-    uint256[50] private __gap;
+    uint256[49] private __gap;
 
     /*//////////////////////////////////////////////////////////////////////////
                                     CONSTRUCTOR
@@ -380,6 +395,44 @@ contract NilRollup is
         emit BatchCommitted(batchIndex);
     }
 
+    /// @inheritdoc INilRollup
+    function commitBatchCalldata(
+        string memory batchIndex,
+        bytes calldata batchData
+    ) external override whenNotPaused onlyProposer {
+        if (bytes(batchIndex).length == 0) {
+            revert ErrorInvalidBatchIndex();
+        }
+
+        if (batchData.length <= CALLDATA_FRAME_HEADER_SIZE) {
+            revert ErrorEmptyBatchData(batchIndex);
+        }
+
+        if (
+            uint8(batchData[0]) != CALLDATA_FRAME_VERSION ||
+            uint256(uint32(bytes4(batchData[1:CALLDATA_FRAME_HEADER_SIZE]))) !=
+            batchData.length - CALLDATA_FRAME_HEADER_SIZE
+        ) {
+            revert ErrorInvalidBatchDataFrame(batchIndex);
+        }
+
+        if (batchInfoRecords[batchIndex].isFinalized) {
+            revert ErrorBatchAlreadyFinalized(batchIndex);
+        }
+
+        if (batchInfoRecords[batchIndex].isCommitted) {
+            revert ErrorBatchAlreadyCommitted(batchIndex);
+        }
+
+        // batch data is available in the transaction input, so no blobs (and no data proofs) are attached
+        batchInfoRecords[batchIndex].isCommitted = true;
+        batchInfoRecords[batchIndex].blobCount = 0;
+        batchCalldataHashes[batchIndex] = keccak256(batchData);
+        lastCommittedBatchIndex = batchIndex;
+
+        emit BatchCommitted(batchIndex);
+    }
+
     function getBlobHash(uint256 index) public view virtual returns (bytes32) {
         bytes32 versionedHash;
         assembly {
@@ -414,8 +467,12 @@ contract NilRollup is
             revert ErrorInvalidValidityProof();
         }
 
-        // Check if dataProofs and validityProof are not zero values
-        if (dataProofs.length == 0) {
+        // Check if dataProofs and validityProof are not zero values,
+        // batches committed via calldata carry no blobs and therefore no data proofs
+        if (
+            dataProofs.length == 0 &&
+            batchCalldataHashes[batchIndex] == bytes32(0)
+        ) {
             revert ErrorEmptyDataProofs();
         }
 
@@ -523,7 +580,17 @@ contract NilRollup is
         string memory batchIndex,
         PublicDataInfo calldata publicDataInfo
     ) public view virtual returns (bytes memory) {
-        return hex'0000dead';
+        bytes memory publicInput = hex'0000dead';
+
+        // batches committed via calldata have no blob versioned hashes checked against the data proofs,
+        // so the hash of the committed data is bound to the proven batch through the public input
+        bytes32 calldataHash = batchCalldataHashes[batchIndex];
+        if (calldataHash != bytes32(0)) {
+            publicInput = abi.encodePacked(
+                keccak256(abi.encodePacked(publicInput, calldataHash))
+            );
+        }
+        return publicInput;
     }
 
     /**
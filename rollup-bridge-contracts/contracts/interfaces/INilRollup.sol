@@ -131,6 +131,17 @@ interface INilRollup is INilAccessControl {
      */
     function commitBatch(string memory batchIndex, uint256 blobCount) external;
 
+    /**
+     * @notice Commits a new batch with its data passed directly in the transaction calldata.
+     * @dev Fallback for the blob commitment when blobs are too expensive or not supported by the chain.
+     * @param batchIndex The index of the batch.
+     * @param batchData The encoded batch data framed as 1 byte version followed by 4 bytes big-endian data length.
+     */
+    function commitBatchCalldata(
+        string memory batchIndex,
+        bytes calldata batchData
+    ) external;
+
     /**
      * @notice Updates the state root for a batch.
      * @dev This function allows an account with the STATE_UPDATER_ROLE to update the state root for a batch.
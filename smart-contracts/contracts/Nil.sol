@@ -30,6 +30,8 @@ library Nil {
     address public constant IS_RESPONSE_TRANSACTION = address(0xd9);
     address public constant LOG = address(0xda);
     address public constant GOVERNANCE = address(0xdb);
+    address private constant SEND_CALLBACK_REQUEST = address(0xdc);
+    address private constant REQUEST_TIMEOUT = address(0xdd);
 
     // The following constants specify from where and how the gas should be taken during async call.
     // Forwarding values are calculated in the following order: FORWARD_VALUE, FORWARD_PERCENTAGE, FORWARD_REMAINING.
@@ -45,6 +47,8 @@ library Nil {
     uint8 public constant FORWARD_NONE = 3;
     // Minimal amount of gas reserved by AWAIT_CALL / SEND_REQUEST
     uint public constant ASYNC_REQUEST_MIN_GAS = 50_000;
+    // Maximal size of the context captured by SEND_CALLBACK_REQUEST
+    uint public constant CALLBACK_CONTEXT_MAX_SIZE = 4096;
 
     // Token is a struct that represents a token with an id and amount.
     struct Token {
@@ -207,6 +211,38 @@ library Nil {
         return __Precompile__(AWAIT_CALL).precompileAwaitCall(dst, responseProcessingGas, callData);
     }
 
+    /**
+     * @dev Makes an asynchronous call to a contract and waits for the result at most `timeout` main shard blocks.
+     *      If the response isn't received in time, the call fails and `success` is false.
+     * @param dst Destination address of the call.
+     * @param responseProcessingGas Amount of gas is being bought and reserved to process the response.
+     *        should be >= `ASYNC_REQUEST_MIN_GAS` to make a call, otherwise `awaitCall` will fail.
+     * @param timeout Number of main shard blocks to wait for the response.
+     * @param callData Calldata for the call.
+     * @return returnData Data returned from the call.
+     * @return success Boolean indicating if the call was successful.
+     */
+    function awaitCallWithTimeout(
+        address dst,
+        uint responseProcessingGas,
+        uint timeout,
+        bytes memory callData
+    ) internal returns(bytes memory, bool) {
+        setRequestTimeout(timeout);
+        return __Precompile__(AWAIT_CALL).precompileAwaitCall(dst, responseProcessingGas, callData);
+    }
+
+    /**
+     * @dev Sets the timeout of the next request sent by the contract in the current transaction
+     *      (`awaitCall`, `sendRequest` or `sendCallbackRequest`). If the response isn't received until
+     *      the main shard advances by `timeout` blocks, the request fails: the awaiting call or the response
+     *      processing is resumed with `success` set to false, and the response received later is rejected.
+     * @param timeout Number of main shard blocks to wait for the response, zero means no timeout.
+     */
+    function setRequestTimeout(uint timeout) internal {
+        __Precompile__(REQUEST_TIMEOUT).precompileSetRequestTimeout(timeout);
+    }
+
     /**
      * @dev Sends a request to a contract.
      * @param dst Destination address of the request.
@@ -248,6 +284,55 @@ library Nil {
         __Precompile__(SEND_REQUEST).precompileSendRequest{value: value}(dst, tokens, responseProcessingGas, context, callData);
     }
 
+    /**
+     * @dev Sends a request to a contract. The response calls the callback of the sender with the captured context:
+     *      `function callback(bool success, bytes memory returnData, bytes memory context) public`.
+     * @param dst Destination address of the request.
+     * @param value Value to be sent with the request.
+     * @param responseProcessingGas Amount of gas is being bought and reserved to call the callback.
+     *        Should be >= `ASYNC_REQUEST_MIN_GAS` to make a call, otherwise `sendCallbackRequest` will fail.
+     * @param callback Selector of the callback, e.g. `this.onResponse.selector`.
+     * @param context Context captured for the callback, at most `CALLBACK_CONTEXT_MAX_SIZE` bytes.
+     * @param callData Calldata for the request.
+     */
+    function sendCallbackRequest(
+        address dst,
+        uint256 value,
+        uint responseProcessingGas,
+        bytes4 callback,
+        bytes memory context,
+        bytes memory callData
+    ) internal {
+        Token[] memory tokens;
+        __Precompile__(SEND_CALLBACK_REQUEST).precompileSendCallbackRequest{value: value}(
+            dst, tokens, responseProcessingGas, callback, context, callData);
+    }
+
+    /**
+     * @dev Sends a request to a contract with tokens. The response calls the callback of the sender
+     *      with the captured context.
+     * @param dst Destination address of the request.
+     * @param value Value to be sent with the request.
+     * @param tokens Array of tokens to be sent with the request.
+     * @param responseProcessingGas Amount of gas is being bought and reserved to call the callback.
+     *        Should be >= `ASYNC_REQUEST_MIN_GAS` to make a call, otherwise `sendCallbackRequest` will fail.
+     * @param callback Selector of the callback, e.g. `this.onResponse.selector`.
+     * @param context Context captured for the callback, at most `CALLBACK_CONTEXT_MAX_SIZE` bytes.
+     * @param callData Calldata for the request.
+     */
+    function sendCallbackRequestWithTokens(
+        address dst,
+        uint256 value,
+        Token[] memory tokens,
+        uint responseProcessingGas,
+        bytes4 callback,
+        bytes memory context,
+        bytes memory callData
+    ) internal {
+        __Precompile__(SEND_CALLBACK_REQUEST).precompileSendCallbackRequest{value: value}(
+            dst, tokens, responseProcessingGas, callback, context, callData);
+    }
+
     /**
      * @dev Sends a raw internal transaction using a special precompiled contract.
      * @param transaction The transaction to be sent.
@@ -433,6 +518,10 @@ library Nil {
         bytes32 hash;
     }
 
+    struct ParamBlockGasLimit {
+        uint64 gasLimit;
+    }
+
     /**
      * @dev Returns the current validators.
      * @return Struct containing the list of validators.
@@ -519,6 +608,8 @@ contract __Precompile__ {
     function precompileAsyncCall(bool, uint8, address, address, address, uint, Nil.Token[] memory, bytes memory) public payable returns(bool) {}
     function precompileAwaitCall(address, uint, bytes memory) public payable returns(bytes memory, bool) {}
     function precompileSendRequest(address, Nil.Token[] memory, uint, bytes memory, bytes memory) public payable returns(bool) {}
+    function precompileSendCallbackRequest(address, Nil.Token[] memory, uint, bytes4, bytes memory, bytes memory) public payable returns(bool) {}
+    function precompileSetRequestTimeout(uint) public returns(bool) {}
     function precompileSendTokens(address, Nil.Token[] memory) public returns(bool) {}
     function precompileGetTransactionTokens() public returns(Nil.Token[] memory) {}
     function precompileGetGasPrice(uint id) public returns(uint256) {}
@@ -532,6 +623,7 @@ contract NilConfigAbi {
     function curr_validators(Nil.ParamValidators memory) public {}
     function gas_price(Nil.ParamGasPrice memory) public {}
     function l1block(Nil.ParamL1BlockInfo memory) public {}
+    function block_gas_limit(Nil.ParamBlockGasLimit memory) public {}
 }
 
 function tokenIdEqual(TokenId a, TokenId b) pure returns (bool) {